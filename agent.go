@@ -1,7 +1,9 @@
 package main
 
 import (
-	"os/exec"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -9,22 +11,61 @@ import (
 
 // AgentManager tracks tmux sessions for all agents.
 type AgentManager struct {
-	mu       sync.RWMutex
-	sessions map[string]*TmuxSession
+	mu        sync.RWMutex
+	sessions  map[string]*TmuxSession
+	lifecycle LifecycleConfig
+	events    *EventLog     // where failed lifecycle commands are logged
+	preSpawn  time.Duration // how long pre_spawn may hold up a spawn
 }
 
 func NewAgentManager() *AgentManager {
 	return &AgentManager{
 		sessions: make(map[string]*TmuxSession),
+		preSpawn: lifecycleTimeout,
 	}
 }
 
-// SpawnAgent creates a tmux session running the agent's backend.
+// LimitPreSpawn caps how long a spawn waits on the user's pre_spawn
+// command, which fails the spawn when it runs longer.
+func (m *AgentManager) LimitPreSpawn(d time.Duration) {
+	m.preSpawn = d
+}
+
+// SetLifecycle sets the user's lifecycle commands, failures of those run
+// in the background going to events.
+func (m *AgentManager) SetLifecycle(c LifecycleConfig, events *EventLog) {
+	m.lifecycle = c
+	m.events = events
+}
+
+// fireLifecycle starts the user's command for event in the background.
+func (m *AgentManager) fireLifecycle(event string, agent *Agent) {
+	m.lifecycle.fire(event, agent, m.events)
+}
+
+// SpawnAgent creates a tmux session running the agent's backend, after the
+// user's pre_spawn command, which can veto it.
 func (m *AgentManager) SpawnAgent(agent *Agent, extraArgs []string) error {
+	timeout := m.preSpawn
+	if timeout <= 0 {
+		timeout = lifecycleTimeout
+	}
+	if err := m.lifecycle.run(LifecyclePreSpawn, agent, timeout); err != nil {
+		return err
+	}
 	sessName := SessionName(agent.ID)
 
 	backend := agent.Backend()
 	command, stripEnv := backend.SpawnCommand(extraArgs)
+	s, streaming := backend.(streamSpawner)
+	if agent.Stream && streaming {
+		dropStream(agent.ID)
+		logPath := streamLogPath(agent.ID)
+		if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+			return err
+		}
+		command, stripEnv = s.StreamSpawnCommand(extraArgs, agent.Prompt, logPath)
+	}
 
 	sess, err := CreateSession(sessName, agent.Dir, command, stripEnv)
 	if err != nil {
@@ -38,16 +79,43 @@ func (m *AgentManager) SpawnAgent(agent *Agent, extraArgs []string) error {
 	// Store session name in agent state
 	agent.SessionName = sessName
 
+	if agent.Stream && streaming {
+		watchStream(agent.ID)
+	}
+	m.fireLifecycle(LifecyclePostSpawn, agent)
 	return nil
 }
 
+// resumeArgs returns the args that pick an agent's conversation back up:
+// its recorded session when the backend can resume by ID, otherwise the
+// backend's most-recent-session args.
+func resumeArgs(agent *Agent) []string {
+	backend := agent.Backend()
+	if r, ok := backend.(sessionResumer); ok && agent.SessionID != "" {
+		return r.ResumeSessionArgs(agent.SessionID)
+	}
+	return backend.ResumeArgs()
+}
+
 // RespawnAgent re-creates the tmux session for a dead agent, resuming its
-// previous conversation via the backend's ResumeArgs.
+// previous conversation (see resumeArgs). A stream-mode agent's print run
+// is over, so it comes back as an interactive session.
 func (m *AgentManager) RespawnAgent(agent *Agent) error {
 	sessName := SessionName(agent.ID)
+	if agent.Stream {
+		agent.Stream = false
+		dropStream(agent.ID)
+	}
+
+	// A killed agent's worktree was removed; check its branch out again
+	if agent.Worktree != nil {
+		if err := restoreWorktree(agent.Worktree, agent.Branch); err != nil {
+			return err
+		}
+	}
 
 	backend := agent.Backend()
-	args := backend.ResumeArgs()
+	args := resumeArgs(agent)
 	if agent.AutoApprove {
 		args = append(args, backend.AutoApproveArgs()...)
 	}
@@ -66,6 +134,30 @@ func (m *AgentManager) RespawnAgent(agent *Agent) error {
 	return nil
 }
 
+// AdoptAgent converts a discovered external agent into a managed one by renaming
+// its tmux session to the tickettok naming scheme. Hook scripts identify agents by
+// session name, so hook-based status starts working once the rename lands.
+func (m *AgentManager) AdoptAgent(agent *Agent) error {
+	if !agent.Discovered {
+		return fmt.Errorf("agent %q is already managed", agent.Name)
+	}
+	if agent.SessionName == "" || !IsSessionAlive(agent.SessionName) {
+		return fmt.Errorf("agent %q has no live tmux session", agent.Name)
+	}
+
+	sessName := SessionName(agent.ID)
+	if IsSessionAlive(sessName) {
+		return fmt.Errorf("session %s already exists", sessName)
+	}
+	if err := RenameSession(agent.SessionName, sessName); err != nil {
+		return err
+	}
+
+	agent.SessionName = sessName
+	agent.Discovered = false
+	return nil
+}
+
 // Kill destroys the tmux session for the given agent.
 func (m *AgentManager) Kill(id string) error {
 	m.mu.Lock()
@@ -74,6 +166,7 @@ func (m *AgentManager) Kill(id string) error {
 		delete(m.sessions, id)
 	}
 	m.mu.Unlock()
+	dropStream(id)
 
 	if ok {
 		return sess.Kill()
@@ -81,6 +174,21 @@ func (m *AgentManager) Kill(id string) error {
 	return nil
 }
 
+// Detach closes tickettok's connection to an agent's session, leaving it
+// running in tmux.
+func (m *AgentManager) Detach(id string) {
+	m.mu.Lock()
+	sess, ok := m.sessions[id]
+	if ok {
+		delete(m.sessions, id)
+	}
+	m.mu.Unlock()
+
+	if ok {
+		sess.closePty()
+	}
+}
+
 // KillBySession kills a tmux session by name (for agents not spawned this session).
 func KillBySession(sessionName string) error {
 	sess := &TmuxSession{Name: sessionName}
@@ -90,6 +198,11 @@ func KillBySession(sessionName string) error {
 // GetSession returns the tmux session for an agent. If not in memory,
 // reconstructs it from the agent's session name.
 func (m *AgentManager) GetSession(agent *Agent) *TmuxSession {
+	// Detached sessions stay that way until zoomed into
+	if agent.Detached {
+		return nil
+	}
+
 	m.mu.RLock()
 	sess, ok := m.sessions[agent.ID]
 	m.mu.RUnlock()
@@ -116,46 +229,111 @@ func (m *AgentManager) GetSession(agent *Agent) *TmuxSession {
 // DetectStatus checks hook-based status first, then falls back to capture-pane scraping.
 // For discovered (external) agents, uses PTY-free capture to avoid detaching the user's terminal.
 // When the scraper is not confident, the agent's current status is preserved to avoid oscillation.
-func (m *AgentManager) DetectStatus(agent *Agent) AgentStatus {
+// For DONE it also says what gave it away, for the agent's exit reason.
+func (m *AgentManager) DetectStatus(agent *Agent) (AgentStatus, string) {
 	backend := agent.Backend()
 
 	if agent.Discovered {
 		// PTY-free path for external sessions
 		if !IsSessionAlive(agent.SessionName) {
-			return StatusDone
+			return StatusDone, "session gone"
 		}
 		content, err := CapturePane(agent.SessionName)
 		if err != nil {
-			return StatusDone
+			return StatusDone, "session gone"
 		}
-		result := backend.DetectStatus(content)
-		if result.Confident {
-			return result.Status
+		if result, ok := detectStatusNow(backend, content); ok && result.Confident {
+			return result.Status, doneReason(result.Status, "goodbye text on screen")
+		}
+		return agent.Status, ""
+	}
+
+	// The event stream is exact where it exists
+	if agent.Stream {
+		if st, ok := streamStatus(agent.ID); ok {
+			return st.Status, doneReason(st.Status, "run finished")
 		}
-		return agent.Status
 	}
 
 	// Try hook-based status first (fast, no subprocess)
 	if status, ok := backend.ReadHookStatus(agent.ID); ok {
-		return status
+		return status, doneReason(status, "session ended (hook)")
 	}
 
 	// Fall back to capture-pane scraping
 	sess := m.GetSession(agent)
 	if sess == nil || !sess.IsAlive() {
-		return StatusDone
+		return StatusDone, "process exited"
 	}
 
 	content, err := sess.CapturePaneContent()
 	if err != nil {
+		return StatusDone, "process exited"
+	}
+
+	if result, ok := detectStatusNow(backend, content); ok && result.Confident {
+		return result.Status, doneReason(result.Status, "goodbye text on screen")
+	}
+	// Not confident, or not known yet: preserve current status instead of
+	// blindly defaulting to RUNNING
+	return agent.Status, ""
+}
+
+// doneReason is why for a DONE status, "" for any other.
+func doneReason(s AgentStatus, why string) string {
+	if s != StatusDone {
+		return ""
+	}
+	return why
+}
+
+// PassiveStatus detects an agent's status without attaching a PTY, so CLI commands
+// can poll alongside a running TUI without stealing its tmux client.
+// Falls back to the stored status when the scraper is not confident.
+func PassiveStatus(agent *Agent) AgentStatus {
+	st := passiveStatus(agent)
+	// Only the TUI tells a question from any other stop
+	if st == StatusIdle && agent.Status == StatusAsk {
+		return StatusAsk
+	}
+	return st
+}
+
+func passiveStatus(agent *Agent) AgentStatus {
+	backend := agent.Backend()
+
+	// Set by the TUI; the session can't tell
+	if agent.Status == StatusPending || agent.Status == StatusPaused || agent.Status == StatusReview ||
+		agent.Status == StatusTimeout && overTime(agent, time.Now()) ||
+		agent.Status == StatusThrottled && time.Now().Before(agent.ResetAt) ||
+		agent.Status == StatusError && !IsSessionAlive(agent.SessionName) {
+		return agent.Status
+	}
+
+	if agent.Stream {
+		if st, ok := readStreamFile(agent.ID); ok {
+			return st.Status
+		}
+	}
+	if !agent.Discovered {
+		if status, ok := backend.ReadHookStatus(agent.ID); ok {
+			return status
+		}
+	}
+
+	if agent.SessionName == "" || !IsSessionAlive(agent.SessionName) {
 		return StatusDone
 	}
 
+	content, err := CapturePane(agent.SessionName)
+	if err != nil {
+		return agent.Status
+	}
+
 	result := backend.DetectStatus(content)
 	if result.Confident {
 		return result.Status
 	}
-	// Not confident: preserve current status instead of blindly defaulting to RUNNING
 	return agent.Status
 }
 
@@ -180,9 +358,13 @@ func (m *AgentManager) GetPreview(agent *Agent, n int) []string {
 
 // PaneInfo holds both preview lines and detected mode from a single pane capture.
 type PaneInfo struct {
-	Preview []string
-	Mode    string
-	Title   string
+	Preview   []string
+	Mode      string
+	Title     string
+	Usage     Usage
+	Todos     TodoProgress
+	Subagents []Subagent
+	Broken    string // output line of a failed build or test run
 }
 
 // GetPaneInfo captures the pane once and returns both preview and mode.
@@ -222,11 +404,80 @@ func (m *AgentManager) GetPaneInfo(agent *Agent, n int) PaneInfo {
 	stripFn := func(lines []string) []string {
 		return backend.StripChrome(lines, waiting)
 	}
+	// The transcript is exact where the pane only shows /cost output when asked
+	usage := backend.DetectUsage(content)
+	if !backend.Capabilities().SupportsCostReporting {
+		// A figure that only looks like /cost output
+		usage.Cost = 0
+	}
+	todos := paneTodos(content)
+	broken, _ := detectBuildFailure(content)
+	var subagents []Subagent
+	preview := PreviewFromContent(content, n, stripFn)
+	if t := liveTranscript(agent); t != "" {
+		tr := readTranscript(t)
+		usage = usage.Max(Usage{Tokens: tr.tokens})
+		if tr.todos.Total > 0 {
+			todos = tr.todos
+		}
+		subagents = tr.subs
+		// The transcript has the model's words without TUI chrome; a
+		// permission prompt only shows in the pane, so keep that while WAITING
+		if p := tr.preview(n); len(p) > 0 && !waiting {
+			preview = p
+		}
+	}
+	var mode string
+	if backend.Capabilities().SupportsModes {
+		mode = backend.DetectMode(content)
+	}
+	// The pane of a stream-mode agent is raw JSON: show the model's last
+	// message and current tool instead
+	if agent.Stream {
+		if st, ok := streamStatus(agent.ID); ok {
+			usage = usage.Max(st.Usage)
+			if st.Todos.Total > 0 {
+				todos = st.Todos
+			}
+			if len(st.Text) > 0 {
+				preview = st.Text
+				if len(preview) > n {
+					preview = preview[len(preview)-n:]
+				}
+			}
+			if st.Tool != "" {
+				title = "Running " + st.Tool
+			}
+		}
+	}
 	return PaneInfo{
-		Preview: PreviewFromContent(content, n, stripFn),
-		Mode:    backend.DetectMode(content),
-		Title:   title,
+		Preview:   preview,
+		Mode:      mode,
+		Title:     title,
+		Usage:     usage,
+		Todos:     todos,
+		Subagents: subagents,
+		Broken:    broken,
+	}
+}
+
+// liveTranscript returns the transcript of an agent's current session: the
+// path its hook reported, else Claude Code's file for the agent's recorded
+// session. Returns "" when there's no transcript to read. (The archive's
+// transcriptPath guesses the newest file in the dir instead, which is
+// fine after the fact but not with several agents in one dir.)
+func liveTranscript(agent *Agent) string {
+	if t := readHookTranscript(agent.ID); t != "" {
+		return t
+	}
+	if agent.SessionID == "" || agent.Backend().ID() != "claude" {
+		return ""
+	}
+	t := claudeTranscriptPath(agent.Dir, agent.SessionID)
+	if _, err := os.Stat(t); err != nil {
+		return ""
 	}
+	return t
 }
 
 // SendKeys sends text input to the agent's tmux pane.
@@ -248,11 +499,12 @@ func (m *AgentManager) CloseAll() {
 }
 
 // SendPromptAfterDelay waits for the agent to start up, then sends the
-// initial prompt via tmux send-keys. Designed for fire-and-forget use from
-// CLI commands that don't keep an AgentManager alive.
+// initial prompt with SendPrompt. Safe to run in a goroutine from long-lived
+// processes; CLI commands must call it synchronously so the process doesn't
+// exit before the prompt is delivered.
 func SendPromptAfterDelay(sessionName, prompt string) {
-	time.Sleep(4 * time.Second)
-	exec.Command("tmux", "send-keys", "-t", sessionName, prompt, "Enter").Run()
+	time.Sleep(promptStartupDelay)
+	SendPrompt(sessionName, prompt)
 }
 
 // shellQuote wraps a string in single quotes for shell safety.
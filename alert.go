@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// sayCommand returns the text-to-speech command for text, or nil when no
+// speech tool is installed. lookPath is exec.LookPath, injectable for tests.
+func sayCommand(goos, text string, lookPath func(string) (string, error)) []string {
+	candidates := [][]string{{"spd-say", text}, {"espeak", text}}
+	if goos == "darwin" {
+		candidates = [][]string{{"say", text}}
+	}
+	for _, c := range candidates {
+		if _, err := lookPath(c[0]); err == nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// waitingAlertText is the spoken alert for agents that started waiting.
+func waitingAlertText(names []string) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0] + " needs input"
+	case 2:
+		return fmt.Sprintf("%s and %s need input", names[0], names[1])
+	default:
+		return fmt.Sprintf("%s and %d others need input", names[0], len(names)-1)
+	}
+}
+
+// speak says text aloud in the background. It is a no-op when no speech
+// tool is available.
+func speak(text string) {
+	c := sayCommand(runtime.GOOS, text, exec.LookPath)
+	if c == nil {
+		return
+	}
+	cmd := exec.Command(c[0], c[1:]...)
+	if cmd.Start() == nil {
+		go cmd.Wait()
+	}
+}
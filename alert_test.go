@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSayCommand(t *testing.T) {
+	only := func(names ...string) func(string) (string, error) {
+		return func(file string) (string, error) {
+			for _, n := range names {
+				if n == file {
+					return "/usr/bin/" + file, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	if got := sayCommand("darwin", "hi", only("say")); !reflect.DeepEqual(got, []string{"say", "hi"}) {
+		t.Errorf("darwin: got %v", got)
+	}
+	if got := sayCommand("linux", "hi", only("espeak")); !reflect.DeepEqual(got, []string{"espeak", "hi"}) {
+		t.Errorf("linux espeak: got %v", got)
+	}
+	if got := sayCommand("linux", "hi", only()); got != nil {
+		t.Errorf("no tools: got %v, want nil", got)
+	}
+}
+
+func TestWaitingAlertText(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{"api"}, "api needs input"},
+		{[]string{"api", "web"}, "api and web need input"},
+		{[]string{"api", "web", "db"}, "api and 2 others need input"},
+	}
+	for _, tt := range tests {
+		if got := waitingAlertText(tt.names); got != tt.want {
+			t.Errorf("waitingAlertText(%v) = %q, want %q", tt.names, got, tt.want)
+		}
+	}
+}
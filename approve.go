@@ -0,0 +1,53 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PromptOption is one numbered choice of a permission prompt, such as
+// "1. Yes" or "2. Allow for this session".
+type PromptOption struct {
+	Key   string // digit that selects the option
+	Label string
+}
+
+// promptOptionRe matches a numbered option line, with or without the
+// selection cursor the backends draw in front of the current choice.
+var promptOptionRe = regexp.MustCompile(`^\s*(?:[❯›>▌●│]\s*)*(\d)[.)]\s+(.+?)\s*│?\s*$`)
+
+// promptScanLines is how far up from the bottom of the pane to look for a
+// prompt's options.
+const promptScanLines = 20
+
+// parsePromptOptions finds the last run of numbered options (1, 2, 3, …)
+// near the bottom of plain pane content. It returns nil when the pane
+// doesn't show a choice prompt.
+func parsePromptOptions(content string) []PromptOption {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if len(lines) > promptScanLines {
+		lines = lines[len(lines)-promptScanLines:]
+	}
+
+	var opts, last []PromptOption
+	for _, line := range lines {
+		m := promptOptionRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		want := string(rune('1' + len(opts)))
+		switch {
+		case m[1] == want:
+			opts = append(opts, PromptOption{Key: m[1], Label: m[2]})
+		case m[1] == "1":
+			// A new list starts; keep the newest one
+			opts = []PromptOption{{Key: m[1], Label: m[2]}}
+		default:
+			continue
+		}
+		if len(opts) >= 2 {
+			last = opts
+		}
+	}
+	return last
+}
@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestParsePromptOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			"claude",
+			"Bash command\n  rm -rf build\nDo you want to proceed?\n❯ 1. Yes\n  2. Yes, and don't ask again for rm commands\n  3. No, and tell Claude what to do differently (esc)\n",
+			[]string{"Yes", "Yes, and don't ask again for rm commands", "No, and tell Claude what to do differently (esc)"},
+		},
+		{
+			"gemini boxed",
+			"│ Allow execution?                 │\n│ ● 1. Allow once                  │\n│   2. Allow always                │\n│   3. No (esc)                    │\n",
+			[]string{"Allow once", "Allow always", "No (esc)"},
+		},
+		{
+			"codex",
+			"Allow command?\n› 1) Yes, proceed\n  2) No, and tell Codex what to do\n",
+			[]string{"Yes, proceed", "No, and tell Codex what to do"},
+		},
+		{
+			"numbered plan is not a prompt",
+			"Plan:\n1. Add tests\n\nDone.\n> ",
+			nil,
+		},
+		{
+			"newest list wins",
+			"1. old a\n2. old b\nsome output\n❯ 1. Yes\n  2. No\n",
+			[]string{"Yes", "No"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parsePromptOptions(tt.content)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d options %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i, o := range got {
+				if o.Label != tt.want[i] || o.Key != string(rune('1'+i)) {
+					t.Errorf("option %d = %+v, want %q", i, o, tt.want[i])
+				}
+			}
+		})
+	}
+}
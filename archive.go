@@ -0,0 +1,299 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ArchivedAgent is an agent that left the board — killed or cleared —
+// kept for the record in archive.json, to look back on with `tickettok
+// archive` or the TUI's archive browser.
+type ArchivedAgent struct {
+	Agent
+	ArchivedAt time.Time `json:"archived_at"`
+	Why        string    `json:"why,omitempty"`        // how it left, e.g. "killed"
+	Transcript string    `json:"transcript,omitempty"` // the backend's own transcript of its session
+	Output     string    `json:"output,omitempty"`     // its scrollback, saved under the transcripts dir
+}
+
+// maxArchived caps how many agents the archive keeps, dropping the oldest.
+const maxArchived = 1000
+
+type archiveFile struct {
+	Agents []ArchivedAgent `json:"agents"`
+}
+
+func archivePath() string {
+	return filepath.Join(boardDir(), "archive.json")
+}
+
+// appendArchive adds agents leaving the board for why to the archive
+// file, creating it if needed. Their saved output is looked up in
+// transcriptDir, "" when transcripts are off.
+func appendArchive(path string, agents []*Agent, why, transcriptDir string, now time.Time) error {
+	if len(agents) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// The TUI and CLI commands both archive
+	unlock, err := lockState(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	entries, err := readArchive(path)
+	if err != nil {
+		return err
+	}
+	af := archiveFile{Agents: entries}
+
+	for _, a := range agents {
+		e := ArchivedAgent{
+			Agent:      *a,
+			ArchivedAt: now,
+			Why:        why,
+			Transcript: transcriptPath(a),
+		}
+		if transcriptDir != "" {
+			if out := transcriptFile(transcriptDir, a); fileExists(out) {
+				e.Output = out
+			}
+		}
+		af.Agents = append(af.Agents, e)
+	}
+	if n := len(af.Agents) - maxArchived; n > 0 {
+		af.Agents = af.Agents[n:]
+	}
+
+	out, err := json.MarshalIndent(af, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal archive: %w", err)
+	}
+	// Replaced in one rename, like the state file, so a crash or a reader
+	// mid-write never sees it half written
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(out); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// readArchive returns the archived agents in path, oldest first; none if
+// there's no archive yet.
+func readArchive(path string) ([]ArchivedAgent, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) || err == nil && len(data) == 0 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var af archiveFile
+	if err := json.Unmarshal(data, &af); err != nil {
+		return nil, fmt.Errorf("parse archive: %w", err)
+	}
+	return af.Agents, nil
+}
+
+// archiveAgents records agents leaving the board in the current board's
+// archive.
+func archiveAgents(agents []*Agent, why, transcriptDir string) error {
+	return appendArchive(archivePath(), agents, why, transcriptDir, time.Now())
+}
+
+// Ran is how long the archived agent was on the board: until it finished,
+// or until it was archived if it never did.
+func (e ArchivedAgent) Ran() time.Duration {
+	end := e.ArchivedAt
+	if e.Ended() && !e.StatusSince.IsZero() && e.StatusSince.Before(end) {
+		end = e.StatusSince
+	}
+	if e.CreatedAt.IsZero() || end.Before(e.CreatedAt) {
+		return 0
+	}
+	return end.Sub(e.CreatedAt)
+}
+
+// searchArchive returns the indexes of the entries matching every word of
+// query in their name, dir, backend, branch, task, summary, failure, note,
+// tags or how they left, newest first. An empty query matches them all.
+func searchArchive(entries []ArchivedAgent, query string) []int {
+	words := strings.Fields(strings.ToLower(query))
+	var out []int
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		text := strings.ToLower(strings.Join(append([]string{e.Name, e.Dir, e.BackendID, e.Branch, e.Prompt, e.Summary, e.Failure, e.Note, e.Why}, e.Tags...), "\n"))
+		match := true
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// archiveTask is the archived agent's task on one line, cut to n runes.
+func archiveTask(e ArchivedAgent, n int) string {
+	task := strings.Join(strings.Fields(e.Prompt), " ")
+	if r := []rune(task); len(r) > n {
+		task = string(r[:max(n-1, 0)]) + "…"
+	}
+	return task
+}
+
+// archiveDetail describes an archived agent in full, a line per field it
+// has.
+func archiveDetail(e ArchivedAgent) []string {
+	lines := []string{
+		fmt.Sprintf("%s (%s, %s)", e.Name, e.ID, e.BackendID),
+		"Dir:      " + e.Dir,
+	}
+	if e.Branch != "" {
+		lines = append(lines, "Branch:   "+e.Branch)
+	}
+	lines = append(lines, fmt.Sprintf("Status:   %s, %s", e.Status, strings.TrimSpace(e.Why)))
+	if e.ExitReason != "" {
+		lines = append(lines, "Ended:    "+e.ExitReason)
+	}
+	lines = append(lines,
+		fmt.Sprintf("Ran:      %s, from %s", formatStatDuration(e.Ran(), true), e.CreatedAt.Local().Format("2006-01-02 15:04")),
+		"Archived: "+e.ArchivedAt.Local().Format("2006-01-02 15:04"),
+	)
+	if len(e.Tags) > 0 {
+		lines = append(lines, "Tags:     "+strings.Join(e.Tags, ", "))
+	}
+	for _, f := range []struct{ label, text string }{
+		{"Task", e.Prompt},
+		{"Summary", e.Summary},
+		{"Failure", e.Failure},
+		{"Note", e.Note},
+	} {
+		if strings.TrimSpace(f.text) != "" {
+			lines = append(lines, "", f.label+":")
+			for _, l := range strings.Split(strings.TrimSpace(f.text), "\n") {
+				lines = append(lines, "  "+l)
+			}
+		}
+	}
+	if len(e.Sent) > 0 {
+		lines = append(lines, "", "Sent:")
+		for _, s := range e.Sent {
+			lines = append(lines, fmt.Sprintf("  %s %s:", s.At.Local().Format("2006-01-02 15:04"), s.Via))
+			for _, l := range strings.Split(strings.TrimSpace(s.Text), "\n") {
+				lines = append(lines, "    "+l)
+			}
+		}
+	}
+	if e.Output != "" || e.Transcript != "" {
+		lines = append(lines, "")
+	}
+	if e.Output != "" {
+		lines = append(lines, "Output:     "+e.Output)
+	}
+	if e.Transcript != "" {
+		lines = append(lines, "Transcript: "+e.Transcript)
+	}
+	return lines
+}
+
+// transcriptPath returns a Claude agent's session transcript — its own
+// session's, or else the most recent for its working directory — or ""
+// when none can be found. Other backends don't keep per-directory
+// transcripts we can locate.
+func transcriptPath(a *Agent) string {
+	if a.BackendID != "claude" || a.Dir == "" {
+		return ""
+	}
+	if a.SessionID != "" {
+		if p := claudeTranscriptPath(a.Dir, a.SessionID); fileExists(p) {
+			return p
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	dir := filepath.Join(home, ".claude", "projects", claudeProjectKey(a.Dir))
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if len(matches) == 0 {
+		return ""
+	}
+
+	type entry struct {
+		path string
+		mod  time.Time
+	}
+	var entries []entry
+	for _, p := range matches {
+		if info, err := os.Stat(p); err == nil {
+			entries = append(entries, entry{p, info.ModTime()})
+		}
+	}
+	if len(entries) == 0 {
+		return ""
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].mod.After(entries[j].mod) })
+	return entries[0].path
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// claudeProjectKey mirrors how Claude Code names its per-project directory:
+// the absolute path with every non-alphanumeric character replaced by '-'.
+func claudeProjectKey(dir string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, dir)
+}
+
+// parseAge parses a duration like "90m" or "24h", plus a "d" suffix for days.
+func parseAge(s string) (time.Duration, error) {
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		days, err := strconv.Atoi(n)
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClearDoneBefore(t *testing.T) {
+	s := newTestStore(t)
+	now := time.Now()
+
+	old := s.Add("old", "/tmp/old")
+	recent := s.Add("recent", "/tmp/recent")
+	s.Add("running", "/tmp/running")
+	s.Update(old.ID, StatusDone)
+	s.Update(recent.ID, StatusDone)
+	old.StatusSince = now.Add(-48 * time.Hour)
+
+	cleared := s.ClearDoneBefore(now.Add(-24 * time.Hour))
+	if len(cleared) != 1 || cleared[0].Name != "old" {
+		t.Fatalf("ClearDoneBefore(24h) = %v, want only old", cleared)
+	}
+	if s.GetByName("recent") == nil {
+		t.Error("recent DONE agent should survive a 24h cutoff")
+	}
+
+	if n := len(s.ClearDoneBefore(time.Time{})); n != 1 {
+		t.Errorf("ClearDoneBefore(zero) cleared %d, want 1", n)
+	}
+	if got := len(s.List()); got != 1 {
+		t.Errorf("List() has %d agents, want 1", got)
+	}
+}
+
+func TestQuitKillsSkipsEndedAgents(t *testing.T) {
+	agents := []*Agent{
+		{Name: "running", Status: StatusRunning},
+		{Name: "idle", Status: StatusIdle},
+		{Name: "killed", Status: StatusDone, ExitReason: "killed"},
+		{Name: "crashed", Status: StatusError},
+		{Name: "outside", Status: StatusRunning, Discovered: true},
+	}
+	var names []string
+	for _, a := range quitKills(agents) {
+		names = append(names, a.Name)
+	}
+	// Already archived, or left for clearing to archive: not twice
+	if fmt.Sprint(names) != "[running idle]" {
+		t.Errorf("quitKills = %v, want [running idle]", names)
+	}
+}
+
+func TestAppendArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.json")
+	now := time.Now()
+
+	a := &Agent{ID: "1", Name: "one", Dir: "/tmp/one", Status: StatusDone, BackendID: "codex"}
+	b := &Agent{ID: "2", Name: "two", Dir: "/tmp/two", Status: StatusDone, BackendID: "codex"}
+	if err := appendArchive(path, []*Agent{a}, "cleared", "", now); err != nil {
+		t.Fatalf("appendArchive() error: %v", err)
+	}
+	if err := appendArchive(path, []*Agent{b}, "killed", "", now); err != nil {
+		t.Fatalf("appendArchive() error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var af archiveFile
+	if err := json.Unmarshal(data, &af); err != nil {
+		t.Fatalf("archive is not valid JSON: %v", err)
+	}
+	if len(af.Agents) != 2 || af.Agents[0].Name != "one" || af.Agents[1].Name != "two" {
+		t.Errorf("archive agents = %+v, want one then two", af.Agents)
+	}
+	if af.Agents[0].Why != "cleared" || af.Agents[1].Why != "killed" {
+		t.Errorf("archive whys = %q, %q; want cleared, killed", af.Agents[0].Why, af.Agents[1].Why)
+	}
+	// Written through a temp file renamed over it; nothing left beside it
+	// but the lock
+	left, _ := filepath.Glob(path + ".*")
+	if len(left) != 1 || left[0] != path+".lock" {
+		t.Errorf("files beside the archive = %v, want just the lock", left)
+	}
+}
+
+func TestAppendArchiveOutputAndTrim(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "archive.json")
+	a := &Agent{ID: "1", Name: "one", Status: StatusDone}
+	if err := os.WriteFile(transcriptFile(dir, a), []byte("scrollback"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := appendArchive(path, []*Agent{a}, "killed", dir, time.Now()); err != nil {
+		t.Fatalf("appendArchive() error: %v", err)
+	}
+	entries, err := readArchive(path)
+	if err != nil || len(entries) != 1 || entries[0].Output != transcriptFile(dir, a) {
+		t.Fatalf("readArchive() = %+v, %v; want one entry pointing at the saved output", entries, err)
+	}
+
+	many := make([]*Agent, maxArchived)
+	for i := range many {
+		many[i] = &Agent{ID: "x", Name: "later"}
+	}
+	if err := appendArchive(path, many, "cleared", "", time.Now()); err != nil {
+		t.Fatalf("appendArchive() error: %v", err)
+	}
+	entries, _ = readArchive(path)
+	if len(entries) != maxArchived || entries[0].Name != "later" {
+		t.Errorf("archive kept %d agents starting with %q, want %d without the oldest", len(entries), entries[0].Name, maxArchived)
+	}
+}
+
+func TestSearchArchive(t *testing.T) {
+	entries := []ArchivedAgent{
+		{Agent: Agent{Name: "auth", Prompt: "Fix the login bug"}, Why: "cleared"},
+		{Agent: Agent{Name: "docs", Prompt: "Write the README", Tags: []string{"bug"}}, Why: "killed"},
+		{Agent: Agent{Name: "api", Summary: "Added the login endpoint"}, Why: "killed"},
+	}
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", []int{2, 1, 0}},
+		{"login", []int{2, 0}},
+		{"LOGIN bug", []int{0}},
+		{"bug", []int{1, 0}},
+		{"killed", []int{2, 1}},
+		{"nothing", nil},
+	}
+	for _, tt := range tests {
+		got := searchArchive(entries, tt.query)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("searchArchive(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestArchivedAgentRan(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	done := ArchivedAgent{Agent: Agent{Status: StatusDone, CreatedAt: start, StatusSince: start.Add(time.Hour)}, ArchivedAt: start.Add(5 * time.Hour)}
+	if got := done.Ran(); got != time.Hour {
+		t.Errorf("Ran() of a finished agent = %v, want 1h until it finished", got)
+	}
+	killed := ArchivedAgent{Agent: Agent{Status: StatusRunning, CreatedAt: start, StatusSince: start.Add(time.Hour)}, ArchivedAt: start.Add(2 * time.Hour)}
+	if got := killed.Ran(); got != 2*time.Hour {
+		t.Errorf("Ran() of a killed agent = %v, want 2h until it was archived", got)
+	}
+}
+
+func TestClaudeProjectKey(t *testing.T) {
+	if got := claudeProjectKey("/home/me/my.app"); got != "-home-me-my-app" {
+		t.Errorf("claudeProjectKey() = %q, want -home-me-my-app", got)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"24h", 24 * time.Hour, true},
+		{"90m", 90 * time.Minute, true},
+		{"7d", 7 * 24 * time.Hour, true},
+		{"3xd", 0, false},
+		{"soon", 0, false},
+		{"-1h", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseAge(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("parseAge(%q) = %v, %v; want %v, ok=%v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// askScanLines is how far from the end of its last message an agent's
+// question can be; only options offered as answers may follow it.
+const askScanLines = 8
+
+// An option listed under a question: "1. …", "b) …", "- …", "**A** …"
+var askOptionRe = regexp.MustCompile(`^(?:\d+[.)]|[a-zA-Z][.)]|[-*•]|\*\*)\s*`)
+
+// askQuestion returns the open question an agent ended its turn on: the
+// last sentence of its summary ending in "?", followed by nothing but
+// options to answer with. "" when it didn't end on one.
+func askQuestion(summary string) string {
+	lines := strings.Split(strings.TrimSpace(summary), "\n")
+	seen := 0
+	for i := len(lines) - 1; i >= 0 && seen < askScanLines; i-- {
+		raw := strings.TrimSpace(lines[i])
+		if raw == "" {
+			continue
+		}
+		seen++
+		l := strings.TrimSpace(strings.TrimLeft(strings.ReplaceAll(raw, "**", ""), "#> "))
+		if strings.HasSuffix(l, "?") {
+			return lastSentence(l)
+		}
+		if !askOptionRe.MatchString(raw) {
+			return ""
+		}
+	}
+	return ""
+}
+
+// lastSentence returns the last sentence of a line of prose.
+func lastSentence(l string) string {
+	body := strings.TrimRight(l, "?!.")
+	start := 0
+	for _, sep := range []string{". ", "! ", "? "} {
+		if i := strings.LastIndex(body, sep); i >= 0 && i+len(sep) > start {
+			start = i + len(sep)
+		}
+	}
+	return strings.TrimSpace(l[start:])
+}
+
+// askNotice is the line an ASK agent's card shows: the question it's
+// waiting on an answer to. "" for agents in any other status.
+func askNotice(a *Agent) string {
+	if a.Status != StatusAsk {
+		return ""
+	}
+	return askQuestion(a.Summary)
+}
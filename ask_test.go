@@ -0,0 +1,46 @@
+package main
+
+import "testing"
+
+func TestAskQuestion(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+		want    string
+	}{
+		{"last sentence", "I looked at both caches. Which approach do you prefer?", "Which approach do you prefer?"},
+		{"options after", "Two ways to do this:\n\nShould I use Redis or keep it in memory?\n\n1. Redis — shared across instances\n2. In memory — simpler", "Should I use Redis or keep it in memory?"},
+		{"bold question", "Done with the parser.\n\n**Do you want me to add tests too?**", "Do you want me to add tests too?"},
+		{"statement", "Fixed the race in the session cache.\nAll tests pass.", ""},
+		{"question before prose", "Why did it fail? The lock was held twice.\nFixed it by releasing early.", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := askQuestion(tt.summary); got != tt.want {
+				t.Errorf("askQuestion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAskNotice(t *testing.T) {
+	a := &Agent{Status: StatusAsk, Summary: "Which approach do you prefer?"}
+	if got := askNotice(a); got != "Which approach do you prefer?" {
+		t.Errorf("askNotice() = %q", got)
+	}
+	a.Status = StatusIdle
+	if got := askNotice(a); got != "" {
+		t.Errorf("IDLE agent notice = %q, want none", got)
+	}
+}
+
+func TestCheckpointDueOnAsk(t *testing.T) {
+	a := &Agent{Checkpoint: true}
+	if !checkpointDue(a, StatusRunning, StatusAsk) {
+		t.Error("RUNNING → ASK should checkpoint")
+	}
+	if checkpointDue(a, StatusAsk, StatusIdle) {
+		t.Error("ASK → IDLE should not checkpoint again")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -23,13 +25,16 @@ type Backend interface {
 
 	// Spawning
 	SpawnCommand(args []string) (command string, stripEnvVars []string)
-	ResumeArgs() []string       // args to pass to SpawnCommand to resume a prior session
-	AutoApproveArgs() []string  // CLI flags to skip permission prompts, or nil if unsupported
+	ResumeArgs() []string      // args to pass to SpawnCommand to resume a prior session
+	AutoApproveArgs() []string // CLI flags to skip permission prompts, or nil if unsupported
 	CheckDeps() error
+	Version() string // installed CLI's version, "" if unknown
+	Capabilities() Capabilities
 
 	// Content analysis (called with ANSI-stripped pane content)
 	DetectStatus(content string) StatusResult
 	DetectMode(content string) string
+	DetectUsage(content string) Usage
 	StripChrome(lines []string, waiting bool) []string
 
 	// Discovery
@@ -38,10 +43,21 @@ type Backend interface {
 
 	// Hooks
 	InstallHooks() error
+	UninstallHooks() error
+	HooksInstalled() bool
 	ReadHookStatus(agentID string) (AgentStatus, bool)
 	CleanHookStatus(agentID string)
 }
 
+// Capabilities says which optional features a backend really implements,
+// so the TUI and CLI can skip or explain actions that would do nothing.
+type Capabilities struct {
+	SupportsResume        bool // ResumeArgs continues the previous conversation
+	SupportsHooks         bool // InstallHooks registers hook-based status
+	SupportsModes         bool // DetectMode reports EDITS/PLAN
+	SupportsCostReporting bool // DetectUsage reports cost, not just tokens
+}
+
 var (
 	registryMu sync.RWMutex
 	backends   = map[string]Backend{}
@@ -73,6 +89,28 @@ func DefaultBackend() Backend {
 	return backends[defaultID]
 }
 
+// backendLabels are the short tags shown on cards for the built-in backends.
+var backendLabels = map[string]string{
+	"claude":      "CC",
+	"codex":       "CX",
+	"gemini":      "GM",
+	"qwen":        "QW",
+	"interpreter": "OI",
+}
+
+// backendLabel returns a two-letter tag for a backend: a fixed one for the
+// built-ins, the first two letters of the ID for plugins.
+func backendLabel(id string) string {
+	if l, ok := backendLabels[id]; ok {
+		return l
+	}
+	r := []rune(strings.ToUpper(id))
+	if len(r) > 2 {
+		r = r[:2]
+	}
+	return string(r)
+}
+
 // AllBackends returns all registered backends.
 func AllBackends() []Backend {
 	registryMu.RLock()
@@ -95,18 +133,76 @@ func AvailableBackends() []Backend {
 	return avail
 }
 
+// discoverAll runs every backend's discovery, tagging each result with the
+// backend that found it. A session several backends claim appears once per
+// backend.
+func discoverAll() []DiscoveredAgent {
+	var found []DiscoveredAgent
+	for _, b := range AllBackends() {
+		for _, d := range b.Discover() {
+			d.BackendID = b.ID()
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+// detectBackendID picks the backend for a discovered session from its pane
+// content and the backends whose discovery found it. A LooksLikeMe match
+// counts double a discovery hit. Ties go to a non-default backend, since
+// the default's signatures (Claude's ❯ prompt) are the broadest, then by
+// ID. With nothing to go on it returns the default.
+func detectBackendID(content string, foundBy []string) string {
+	best, bestScore := DefaultBackend().ID(), 0
+	all := AllBackends()
+	sort.Slice(all, func(i, j int) bool { return all[i].ID() < all[j].ID() })
+	for _, b := range all {
+		score := 0
+		if content != "" && b.LooksLikeMe(content) {
+			score += 2
+		}
+		for _, id := range foundBy {
+			if id == b.ID() {
+				score++
+				break
+			}
+		}
+		if score > bestScore || (score == bestScore && score > 0 && best == DefaultBackend().ID()) {
+			best, bestScore = b.ID(), score
+		}
+	}
+	return best
+}
+
 // --- Shared hook status helpers ---
 
+// hookScriptDir holds the hook scripts the backends' settings run. It's
+// ~/.tickettok whatever TICKETTOK_HOME says: the settings are global, and
+// the scripts read TICKETTOK_HOME as they run, so one copy serves every
+// state directory.
+func hookScriptDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".tickettok")
+}
+
+// sameHookScript reports whether a hook command runs a copy of scriptPath,
+// wherever it was installed — such as under a since-deleted
+// TICKETTOK_HOME, before scripts stayed in hookScriptDir.
+func sameHookScript(cmd, scriptPath string) bool {
+	return filepath.Base(cmd) == filepath.Base(scriptPath)
+}
+
 // hookStatusDir returns the shared status directory for all backends.
 func hookStatusDir() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".tickettok", "status")
+	return filepath.Join(stateDir(), "status")
 }
 
 // hookStatus represents the JSON written by hook scripts (all backends use the same format).
 type hookStatus struct {
-	State string `json:"state"`
-	Ts    int64  `json:"ts"`
+	State      string `json:"state"`
+	Ts         int64  `json:"ts"`
+	Transcript string `json:"transcript,omitempty"` // session transcript path, when the hook payload has one
+	Session    string `json:"session,omitempty"`    // backend session ID, when the hook payload has one
 }
 
 // readHookStatusFile reads and parses a hook-written status file for an agent.
@@ -152,6 +248,62 @@ func readHookStatusFile(agentID string) (AgentStatus, bool) {
 	}
 }
 
+// readHookInfo returns the last status file an agent's hook wrote, or a
+// zero hookStatus. Unlike readHookStatusFile it ignores the file's age.
+func readHookInfo(agentID string) hookStatus {
+	var hs hookStatus
+	data, err := os.ReadFile(filepath.Join(hookStatusDir(), agentID+".json"))
+	if err != nil {
+		return hs
+	}
+	_ = json.Unmarshal(data, &hs)
+	return hs
+}
+
+// readHookTranscript returns the transcript path last reported by an
+// agent's hook, or "" if none.
+func readHookTranscript(agentID string) string {
+	return readHookInfo(agentID).Transcript
+}
+
+// readHookSessionID returns the session ID last reported by an agent's
+// hook: the payload's own, else the transcript's file name, since Claude
+// Code names transcripts <session-id>.jsonl.
+func readHookSessionID(agentID string) string {
+	hs := readHookInfo(agentID)
+	if hs.Session != "" {
+		return hs.Session
+	}
+	if hs.Transcript != "" {
+		return strings.TrimSuffix(filepath.Base(hs.Transcript), ".jsonl")
+	}
+	return ""
+}
+
+// sessionResumer is implemented by backends that can resume a specific
+// session by ID, not just the most recent one.
+type sessionResumer interface {
+	ResumeSessionArgs(id string) []string
+}
+
+// backgroundDetector is implemented by backends whose status detection
+// is too slow to run for every agent on every tick of the TUI. It returns
+// the status for content if it has one, and otherwise works it out in
+// the background for a later tick, returning false.
+type backgroundDetector interface {
+	DetectStatusInBackground(content string) (StatusResult, bool)
+}
+
+// detectStatusNow runs a backend's status detection on content without
+// waiting on one that detects in the background; ok is false while that
+// result isn't in yet.
+func detectStatusNow(b Backend, content string) (result StatusResult, ok bool) {
+	if bd, isBD := b.(backgroundDetector); isBD {
+		return bd.DetectStatusInBackground(content)
+	}
+	return b.DetectStatus(content), true
+}
+
 // cleanHookStatusFile removes the status file for an agent.
 func cleanHookStatusFile(agentID string) {
 	path := filepath.Join(hookStatusDir(), agentID+".json")
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -28,11 +29,37 @@ func (c *ClaudeBackend) SpawnCommand(args []string) (string, []string) {
 	return cmd, []string{"CLAUDECODE"}
 }
 
+// StreamSpawnCommand runs prompt in print mode with stream-json output,
+// teed to logPath so the pane still shows the events as they arrive.
+func (c *ClaudeBackend) StreamSpawnCommand(args []string, prompt, logPath string) (string, []string) {
+	cmd := "claude -p --verbose --output-format stream-json"
+	if len(args) > 0 {
+		cmd += " " + strings.Join(args, " ")
+	}
+	cmd += " " + shellQuote(prompt) + " | tee " + shellQuote(logPath)
+	return cmd, []string{"CLAUDECODE"}
+}
+
+// Version returns the first line of `claude --version`.
+func (c *ClaudeBackend) Version() string {
+	return probeVersion("claude")
+}
+
+// Capabilities reports full support: Claude Code is the reference backend.
+func (c *ClaudeBackend) Capabilities() Capabilities {
+	return Capabilities{SupportsResume: true, SupportsHooks: true, SupportsModes: true, SupportsCostReporting: true}
+}
+
 // ResumeArgs returns the CLI flags to resume the most recent conversation.
 func (c *ClaudeBackend) ResumeArgs() []string {
 	return []string{"--continue"}
 }
 
+// ResumeSessionArgs returns the CLI flags to resume a specific conversation.
+func (c *ClaudeBackend) ResumeSessionArgs(id string) []string {
+	return []string{"--resume", id}
+}
+
 // AutoApproveArgs returns the flag to bypass all permission prompts.
 func (c *ClaudeBackend) AutoApproveArgs() []string {
 	return []string{"--dangerously-skip-permissions"}
@@ -194,6 +221,30 @@ func (c *ClaudeBackend) DetectMode(content string) string {
 	return ""
 }
 
+// claudeModelUsageRe matches a per-model line of /cost output:
+// "claude-sonnet:  45 input, 1.1k output, 120.4k cache read, 12.1k cache write".
+var claudeModelUsageRe = regexp.MustCompile(tokenNum + ` input, ` + tokenNum + ` output(?:, ` + tokenNum + ` cache read, ` + tokenNum + ` cache write)?`)
+
+// DetectUsage reads the last /cost output in the pane: total cost, and
+// input + output + cache-write tokens summed over the models listed.
+// Transcript totals from the hook take over once available.
+func (c *ClaudeBackend) DetectUsage(content string) Usage {
+	content = stripAnsiStr(content)
+	u := Usage{Cost: lastCost(content)}
+	i := strings.LastIndex(content, "Usage by model")
+	if i < 0 {
+		return u
+	}
+	for _, m := range claudeModelUsageRe.FindAllStringSubmatch(content[i:], -1) {
+		for _, g := range []string{m[1], m[2], m[4]} {
+			if n, ok := parseTokenCount(g); ok {
+				u.Tokens += n
+			}
+		}
+	}
+	return u
+}
+
 // StripChrome removes Claude Code's bottom chrome from captured pane lines.
 func (c *ClaudeBackend) StripChrome(lines []string, waiting bool) []string {
 	if waiting {
@@ -419,8 +470,14 @@ func (c *ClaudeBackend) discoverProcesses() []DiscoveredAgent {
 // --- Hook support ---
 
 func claudeHookScriptPath() string {
+	return filepath.Join(hookScriptDir(), "tickettok-hook.sh")
+}
+
+// claudeTranscriptPath returns where Claude Code keeps the transcript of
+// session id started in dir.
+func claudeTranscriptPath(dir, id string) string {
 	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".tickettok", "tickettok-hook.sh")
+	return filepath.Join(home, ".claude", "projects", claudeProjectKey(dir), id+".jsonl")
 }
 
 // InstallHooks installs the hook script and registers hooks in Claude's settings.json.
@@ -471,10 +528,12 @@ set -euo pipefail
 INPUT=$(cat)
 EVENT=$(echo "$INPUT" | jq -r '.hook_event_name // empty')
 NTYPE=$(echo "$INPUT" | jq -r '.notification_type // empty')
+TRANSCRIPT=$(echo "$INPUT" | jq -r '.transcript_path // empty')
+SESSION_ID=$(echo "$INPUT" | jq -r '.session_id // empty')
 SESS=$(tmux display-message -p '#{session_name}' 2>/dev/null || true)
 [[ "$SESS" == tickettok_* ]] || exit 0
 AGENT_ID="${SESS#tickettok_}"
-STATUS_DIR="$HOME/.tickettok/status"
+STATUS_DIR="${TICKETTOK_HOME:-$HOME/.tickettok}/status"
 mkdir -p "$STATUS_DIR"
 STATE=""
 case "$EVENT" in
@@ -489,7 +548,10 @@ case "$EVENT" in
 esac
 [ -z "$STATE" ] && exit 0
 TMP=$(mktemp "$STATUS_DIR/.tmp.XXXXXX")
-echo "{\"state\":\"$STATE\",\"ts\":$(date +%s)}" > "$TMP"
+jq -nc --arg state "$STATE" --argjson ts "$(date +%s)" --arg transcript "$TRANSCRIPT" --arg session "$SESSION_ID" \
+  '{state: $state, ts: $ts}
+   + (if $transcript != "" then {transcript: $transcript} else {} end)
+   + (if $session != "" then {session: $session} else {} end)' > "$TMP"
 mv "$TMP" "$STATUS_DIR/${AGENT_ID}.json"
 `
 
@@ -511,9 +573,11 @@ func (c *ClaudeBackend) registerClaudeHooks() error {
 		}
 	}
 
-	if c.alreadyInstalled(settings) {
+	if hooksCurrent(settings, claudeHookScriptPath()) {
 		return nil
 	}
+	// Copies installed elsewhere make way for this one
+	removeHookEntries(settings, claudeHookScriptPath())
 
 	cmd := claudeHookScriptPath()
 
@@ -550,39 +614,35 @@ func (c *ClaudeBackend) registerClaudeHooks() error {
 	return os.WriteFile(settingsPath, out, 0644)
 }
 
-func (c *ClaudeBackend) alreadyInstalled(settings map[string]interface{}) bool {
-	hooks, ok := settings["hooks"].(map[string]interface{})
-	if !ok {
-		return false
-	}
+// UninstallHooks removes tickettok entries from Claude's settings.json and deletes the hook script.
+func (c *ClaudeBackend) UninstallHooks() error {
+	home, _ := os.UserHomeDir()
+	settingsPath := filepath.Join(home, ".claude", "settings.json")
 
-	scriptPath := claudeHookScriptPath()
-	for _, entries := range hooks {
-		arr, ok := entries.([]interface{})
-		if !ok {
-			continue
-		}
-		for _, entry := range arr {
-			em, ok := entry.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			hookList, ok := em["hooks"].([]interface{})
-			if !ok {
-				continue
-			}
-			for _, h := range hookList {
-				hm, ok := h.(map[string]interface{})
-				if !ok {
-					continue
-				}
-				if cmd, ok := hm["command"].(string); ok && cmd == scriptPath {
-					return true
-				}
-			}
+	settings, err := readSettingsJSON(settingsPath)
+	if err != nil {
+		return err
+	}
+	if removeHookEntries(settings, claudeHookScriptPath()) {
+		if err := writeSettingsJSON(settingsPath, settings); err != nil {
+			return err
 		}
 	}
-	return false
+
+	if err := os.Remove(claudeHookScriptPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// HooksInstalled reports whether tickettok's hook is registered in Claude's settings.json.
+func (c *ClaudeBackend) HooksInstalled() bool {
+	home, _ := os.UserHomeDir()
+	settings, err := readSettingsJSON(filepath.Join(home, ".claude", "settings.json"))
+	if err != nil {
+		return false
+	}
+	return settingsHasHook(settings, claudeHookScriptPath())
 }
 
 // ReadHookStatus reads the hook-written status file for an agent.
@@ -5,6 +5,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -27,9 +29,21 @@ func (c *CodexBackend) SpawnCommand(args []string) (string, []string) {
 	return cmd, nil
 }
 
-// ResumeArgs returns empty — Codex has no --continue equivalent.
+// Version returns the first line of `codex --version`.
+func (c *CodexBackend) Version() string {
+	return probeVersion("codex")
+}
+
+// Capabilities reports resume and the notify hook; Codex shows tokens but
+// no cost and has no modes.
+func (c *CodexBackend) Capabilities() Capabilities {
+	return Capabilities{SupportsResume: true, SupportsHooks: true}
+}
+
+// ResumeArgs resumes the most recent Codex session without the picker.
+// resume is a subcommand, so these must come before any other args.
 func (c *CodexBackend) ResumeArgs() []string {
-	return nil
+	return []string{"resume", "--last"}
 }
 
 // AutoApproveArgs returns the flag for full-auto approval mode.
@@ -117,6 +131,21 @@ func (c *CodexBackend) DetectMode(content string) string {
 	return ""
 }
 
+// Codex reports a running total in its status line ("12.3K tokens used")
+// and a summary on exit ("Token usage: total=12,345 input=…").
+var (
+	codexExitUsageRe   = regexp.MustCompile(`(?i)token usage:\s*total=` + tokenNum)
+	codexStatusUsageRe = regexp.MustCompile(`(?i)` + tokenNum + `\s+tokens used`)
+	codexLabelUsageRe  = regexp.MustCompile(`(?i)tokens used:?\s+` + tokenNum)
+)
+
+// DetectUsage reads the latest token total Codex printed. Codex doesn't
+// report cost.
+func (c *CodexBackend) DetectUsage(content string) Usage {
+	content = stripAnsiStr(content)
+	return Usage{Tokens: lastTokens(content, codexExitUsageRe, codexStatusUsageRe, codexLabelUsageRe)}
+}
+
 // StripChrome returns lines as-is — Codex has minimal chrome to strip.
 func (c *CodexBackend) StripChrome(lines []string, waiting bool) []string {
 	return lines
@@ -235,29 +264,44 @@ func (c *CodexBackend) discoverProcesses() []DiscoveredAgent {
 // --- Hook support (Codex notify) ---
 
 func codexNotifyScriptPath() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".tickettok", "tickettok-codex-notify.sh")
+	return filepath.Join(hookScriptDir(), "tickettok-codex-notify.sh")
 }
 
+// codexInlineNotifyScript records Codex's turn-complete events. Codex runs
+// notify as one command with the event JSON appended, so when the user
+// already has a notify command, tickettok's script goes in front of it and
+// runs it afterwards with the same arguments.
 const codexInlineNotifyScript = `#!/bin/bash
-set -euo pipefail
-# Codex notify passes JSON as argument
-EVENT_TYPE=$(echo "$1" | jq -r '.type // empty')
-SESS=$(tmux display-message -p '#{session_name}' 2>/dev/null || true)
-[[ "$SESS" == tickettok_* ]] || exit 0
-AGENT_ID="${SESS#tickettok_}"
-STATUS_DIR="$HOME/.tickettok/status"
-mkdir -p "$STATUS_DIR"
-STATE=""
-case "$EVENT_TYPE" in
-  agent-turn-complete) STATE="IDLE" ;;
-esac
-[ -z "$STATE" ] && exit 0
-TMP=$(mktemp "$STATUS_DIR/.tmp.XXXXXX")
-echo "{\"state\":\"$STATE\",\"ts\":$(date +%s)}" > "$TMP"
-mv "$TMP" "$STATUS_DIR/${AGENT_ID}.json"
+# Codex notify passes JSON as the last argument
+record() {
+  set -euo pipefail
+  EVENT_TYPE=$(echo "${!#}" | jq -r '.type // empty')
+  SESS=$(tmux display-message -p '#{session_name}' 2>/dev/null || true)
+  [[ "$SESS" == tickettok_* ]] || return 0
+  AGENT_ID="${SESS#tickettok_}"
+  STATUS_DIR="${TICKETTOK_HOME:-$HOME/.tickettok}/status"
+  mkdir -p "$STATUS_DIR"
+  STATE=""
+  case "$EVENT_TYPE" in
+    agent-turn-complete) STATE="IDLE" ;;
+  esac
+  [ -z "$STATE" ] && return 0
+  TMP=$(mktemp "$STATUS_DIR/.tmp.XXXXXX")
+  echo "{\"state\":\"$STATE\",\"ts\":$(date +%s)}" > "$TMP"
+  mv "$TMP" "$STATUS_DIR/${AGENT_ID}.json"
+}
+( record "$@" ) || true
+# Chain to the notify command tickettok was put in front of
+if [ $# -gt 1 ]; then
+  exec "$@"
+fi
 `
 
+func codexConfigPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".codex", "config.toml")
+}
+
 // InstallHooks installs the notify script and registers it in Codex's config.toml.
 func (c *CodexBackend) InstallHooks() error {
 	if err := c.installNotifyScript(); err != nil {
@@ -278,42 +322,115 @@ func (c *CodexBackend) installNotifyScript() error {
 }
 
 func (c *CodexBackend) registerCodexNotify() error {
-	home, _ := os.UserHomeDir()
-	configPath := filepath.Join(home, ".codex", "config.toml")
-
-	scriptPath := codexNotifyScriptPath()
+	configPath := codexConfigPath()
 
-	// Read existing config if present
 	data, err := os.ReadFile(configPath)
 	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
-
-	content := string(data)
-
-	// Check if already registered
-	if strings.Contains(content, scriptPath) {
+	content, changed, err := addCodexNotify(string(data), codexNotifyScriptPath())
+	if err != nil {
+		return fmt.Errorf("%s: %w", configPath, err)
+	}
+	if !changed {
 		return nil
 	}
 
-	// Ensure directory exists
 	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
 		return err
 	}
+	return os.WriteFile(configPath, []byte(content), 0644)
+}
 
-	// Check if a notify line already exists
-	if strings.Contains(content, "notify") {
-		// There's already a notify config — don't overwrite it, user may have custom settings.
-		// Only add if it's a simple array we can append to.
-		// For safety, skip modification if notify already exists but doesn't contain our script.
-		return nil
+// addCodexNotify puts script in front of the top-level notify command in a
+// config.toml, or adds a notify key running just script. Copies of the
+// script installed elsewhere, wherever they are in the command, make way
+// for it. The rest of the file is left byte for byte.
+func addCodexNotify(content, script string) (string, bool, error) {
+	arr, found, err := findTOMLStringArray(content, "notify")
+	if err != nil {
+		return content, false, err
+	}
+	if !found {
+		// Top-level keys must come before the first table, so go first
+		return "notify = " + formatTOMLStringArray([]string{script}) + "\n" + content, true, nil
+	}
+	values := append([]string{script}, withoutNotifyScript(arr.Values, script)...)
+	if slices.Equal(values, arr.Values) {
+		return content, false, nil
+	}
+	return content[:arr.ValueStart] + formatTOMLStringArray(values) + content[arr.ValueEnd:], true, nil
+}
+
+// removeCodexNotify takes every copy of script back out of the notify
+// command, restoring the user's own command or dropping the key if there
+// wasn't one.
+func removeCodexNotify(content, script string) (string, bool, error) {
+	arr, found, err := findTOMLStringArray(content, "notify")
+	if err != nil {
+		return content, false, err
+	}
+	if !found {
+		return content, false, nil
+	}
+	rest := withoutNotifyScript(arr.Values, script)
+	if len(rest) == len(arr.Values) {
+		return content, false, nil
+	}
+	if len(rest) > 0 {
+		return content[:arr.ValueStart] + formatTOMLStringArray(rest) + content[arr.ValueEnd:], true, nil
+	}
+	end := tomlLineEnd(content, arr.ValueEnd)
+	if end < len(content) {
+		end++
+	}
+	return content[:arr.KeyStart] + content[end:], true, nil
+}
+
+// withoutNotifyScript drops the copies of script from a notify command.
+func withoutNotifyScript(values []string, script string) []string {
+	var out []string
+	for _, v := range values {
+		if !sameHookScript(v, script) {
+			out = append(out, v)
+		}
 	}
+	return out
+}
 
-	// Append notify line
-	notifyLine := fmt.Sprintf("\nnotify = [\"%s\"]\n", scriptPath)
-	content += notifyLine
+// UninstallHooks removes tickettok's script from the notify command in
+// Codex's config.toml and deletes the script.
+func (c *CodexBackend) UninstallHooks() error {
+	configPath := codexConfigPath()
 
-	return os.WriteFile(configPath, []byte(content), 0644)
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	content, changed, err := removeCodexNotify(string(data), codexNotifyScriptPath())
+	if err != nil {
+		return fmt.Errorf("%s: %w", configPath, err)
+	}
+	if changed {
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Remove(codexNotifyScriptPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// HooksInstalled reports whether the notify script is registered in Codex's config.toml.
+func (c *CodexBackend) HooksInstalled() bool {
+	data, err := os.ReadFile(codexConfigPath())
+	if err != nil {
+		return false
+	}
+	values, found, err := readTOMLStringArray(string(data), "notify")
+	return err == nil && found && len(values) > 0 && values[0] == codexNotifyScriptPath()
 }
 
 // ReadHookStatus reads the hook-written status file for an agent.
@@ -1,11 +1,11 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -28,6 +28,16 @@ func (g *GeminiBackend) SpawnCommand(args []string) (string, []string) {
 	return cmd, nil
 }
 
+// Version returns the first line of `gemini --version`.
+func (g *GeminiBackend) Version() string {
+	return probeVersion("gemini")
+}
+
+// Capabilities reports hooks only.
+func (g *GeminiBackend) Capabilities() Capabilities {
+	return Capabilities{SupportsHooks: true}
+}
+
 // ResumeArgs returns empty — Gemini has no resume flag.
 func (g *GeminiBackend) ResumeArgs() []string {
 	return nil
@@ -116,6 +126,15 @@ func (g *GeminiBackend) DetectMode(content string) string {
 	return ""
 }
 
+// geminiUsageRe matches the total row of Gemini's /stats table.
+var geminiUsageRe = regexp.MustCompile(`(?i)total tokens:?\s+` + tokenNum)
+
+// DetectUsage reads the token total from the last /stats output. Gemini
+// doesn't report cost.
+func (g *GeminiBackend) DetectUsage(content string) Usage {
+	return Usage{Tokens: lastTokens(stripAnsiStr(content), geminiUsageRe)}
+}
+
 // StripChrome returns lines as-is — Gemini has minimal chrome to strip.
 func (g *GeminiBackend) StripChrome(lines []string, waiting bool) []string {
 	return lines
@@ -234,8 +253,7 @@ func (g *GeminiBackend) discoverProcesses() []DiscoveredAgent {
 // --- Hook support ---
 
 func geminiHookScriptPath() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".tickettok", "tickettok-gemini-hook.sh")
+	return filepath.Join(hookScriptDir(), "tickettok-gemini-hook.sh")
 }
 
 const geminiInlineHookScript = `#!/bin/bash
@@ -245,7 +263,7 @@ EVENT=$(echo "$INPUT" | jq -r '.hook_event_name // empty')
 SESS=$(tmux display-message -p '#{session_name}' 2>/dev/null || true)
 [[ "$SESS" == tickettok_* ]] || exit 0
 AGENT_ID="${SESS#tickettok_}"
-STATUS_DIR="$HOME/.tickettok/status"
+STATUS_DIR="${TICKETTOK_HOME:-$HOME/.tickettok}/status"
 mkdir -p "$STATUS_DIR"
 STATE=""
 case "$EVENT" in
@@ -280,28 +298,30 @@ func (g *GeminiBackend) installHookScript() error {
 }
 
 func (g *GeminiBackend) registerGeminiHooks() error {
+	return registerGeminiStyleHooks(geminiSettingsPath(), geminiHookScriptPath())
+}
+
+func geminiSettingsPath() string {
 	home, _ := os.UserHomeDir()
-	settingsPath := filepath.Join(home, ".gemini", "settings.json")
+	return filepath.Join(home, ".gemini", "settings.json")
+}
+
+// geminiHookEvents are the lifecycle events the hook script maps to statuses.
+var geminiHookEvents = []string{"BeforeAgent", "BeforeTool", "AfterAgent", "Notification", "SessionEnd"}
 
-	var settings map[string]interface{}
-	data, err := os.ReadFile(settingsPath)
+// registerGeminiStyleHooks adds scriptPath for every geminiHookEvents entry
+// in a Gemini CLI-format settings.json (also used by its forks). It does
+// nothing if the script is already registered.
+func registerGeminiStyleHooks(settingsPath, scriptPath string) error {
+	settings, err := readSettingsJSON(settingsPath)
 	if err != nil {
-		if os.IsNotExist(err) {
-			settings = make(map[string]interface{})
-		} else {
-			return err
-		}
-	} else {
-		if err := json.Unmarshal(data, &settings); err != nil {
-			return fmt.Errorf("parse settings: %w", err)
-		}
+		return err
 	}
-
-	if g.alreadyInstalled(settings) {
+	if hooksCurrent(settings, scriptPath) {
 		return nil
 	}
-
-	cmd := geminiHookScriptPath()
+	// Copies installed elsewhere make way for this one
+	removeHookEntries(settings, scriptPath)
 
 	hooks, _ := settings["hooks"].(map[string]interface{})
 	if hooks == nil {
@@ -311,11 +331,10 @@ func (g *GeminiBackend) registerGeminiHooks() error {
 	tickettokHook := map[string]interface{}{
 		"name":    "tickettok",
 		"type":    "command",
-		"command": cmd,
+		"command": scriptPath,
 	}
 
-	events := []string{"BeforeAgent", "BeforeTool", "AfterAgent", "Notification", "SessionEnd"}
-	for _, event := range events {
+	for _, event := range geminiHookEvents {
 		entry := map[string]interface{}{
 			"matcher": "*",
 			"hooks":   []interface{}{tickettokHook},
@@ -330,46 +349,36 @@ func (g *GeminiBackend) registerGeminiHooks() error {
 	if err := os.MkdirAll(filepath.Dir(settingsPath), 0755); err != nil {
 		return err
 	}
-	out, err := json.MarshalIndent(settings, "", "  ")
+	return writeSettingsJSON(settingsPath, settings)
+}
+
+// UninstallHooks removes tickettok entries from Gemini's settings.json and deletes the hook script.
+func (g *GeminiBackend) UninstallHooks() error {
+	settingsPath := geminiSettingsPath()
+
+	settings, err := readSettingsJSON(settingsPath)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(settingsPath, out, 0644)
-}
+	if removeHookEntries(settings, geminiHookScriptPath()) {
+		if err := writeSettingsJSON(settingsPath, settings); err != nil {
+			return err
+		}
+	}
 
-func (g *GeminiBackend) alreadyInstalled(settings map[string]interface{}) bool {
-	hooks, ok := settings["hooks"].(map[string]interface{})
-	if !ok {
-		return false
+	if err := os.Remove(geminiHookScriptPath()); err != nil && !os.IsNotExist(err) {
+		return err
 	}
+	return nil
+}
 
-	scriptPath := geminiHookScriptPath()
-	for _, entries := range hooks {
-		arr, ok := entries.([]interface{})
-		if !ok {
-			continue
-		}
-		for _, entry := range arr {
-			em, ok := entry.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			hookList, ok := em["hooks"].([]interface{})
-			if !ok {
-				continue
-			}
-			for _, h := range hookList {
-				hm, ok := h.(map[string]interface{})
-				if !ok {
-					continue
-				}
-				if cmd, ok := hm["command"].(string); ok && cmd == scriptPath {
-					return true
-				}
-			}
-		}
+// HooksInstalled reports whether tickettok's hook is registered in Gemini's settings.json.
+func (g *GeminiBackend) HooksInstalled() bool {
+	settings, err := readSettingsJSON(geminiSettingsPath())
+	if err != nil {
+		return false
 	}
-	return false
+	return settingsHasHook(settings, geminiHookScriptPath())
 }
 
 // ReadHookStatus reads the hook-written status file for an agent.
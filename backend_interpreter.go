@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// InterpreterBackend implements Backend for Open Interpreter, the usual
+// terminal agent for local models: pointed at Ollama, LM Studio or a
+// llamafile (`interpreter --local`, or a profile), it runs fully offline.
+// It has no hooks, resume or usage output, so status comes from the pane.
+type InterpreterBackend struct{}
+
+func init() {
+	RegisterBackend(&InterpreterBackend{})
+}
+
+func (o *InterpreterBackend) Name() string { return "Open Interpreter" }
+func (o *InterpreterBackend) ID() string   { return "interpreter" }
+
+// SpawnCommand returns the shell command for launching Open Interpreter.
+// The model comes from its default profile, so local-model users set that
+// up once rather than passing flags per agent.
+func (o *InterpreterBackend) SpawnCommand(args []string) (string, []string) {
+	cmd := "interpreter"
+	if len(args) > 0 {
+		cmd = "interpreter " + strings.Join(args, " ")
+	}
+	return cmd, nil
+}
+
+// Version returns the first line of `interpreter --version`.
+func (o *InterpreterBackend) Version() string {
+	return probeVersion("interpreter")
+}
+
+// Capabilities reports none: Open Interpreter has no hooks, no resume and
+// no modes, and local models don't cost anything.
+func (o *InterpreterBackend) Capabilities() Capabilities {
+	return Capabilities{}
+}
+
+// ResumeArgs returns empty — Open Interpreter can't reopen a conversation.
+func (o *InterpreterBackend) ResumeArgs() []string {
+	return nil
+}
+
+// AutoApproveArgs returns the flag that runs code without asking.
+func (o *InterpreterBackend) AutoApproveArgs() []string {
+	return []string{"--auto_run"}
+}
+
+// CheckDeps verifies that the interpreter CLI is installed.
+func (o *InterpreterBackend) CheckDeps() error {
+	if _, err := exec.LookPath("interpreter"); err != nil {
+		return fmt.Errorf("interpreter (pip install open-interpreter)")
+	}
+	return nil
+}
+
+// DetectStatus reads Open Interpreter's pane. It asks before running each
+// code block and shows a bare "> " prompt when it's done; anything else is
+// a model generating or code running, which has no reliable marker.
+func (o *InterpreterBackend) DetectStatus(content string) StatusResult {
+	lines := strings.Split(content, "\n")
+
+	var recent []string
+	for i := len(lines) - 1; i >= 0 && len(recent) < 5; i-- {
+		line := strings.TrimSpace(stripAnsiStr(lines[i]))
+		if line != "" {
+			recent = append(recent, line)
+		}
+	}
+
+	if len(recent) == 0 {
+		return StatusResult{StatusRunning, false}
+	}
+
+	// WAITING — the code confirmation sits just above the cursor
+	for _, line := range recent {
+		if strings.Contains(strings.ToLower(line), "would you like to run this code") {
+			return StatusResult{StatusWaiting, true}
+		}
+	}
+
+	// IDLE — the input prompt is the bottom line
+	if recent[0] == ">" || strings.HasPrefix(recent[0], "> ") {
+		return StatusResult{StatusIdle, true}
+	}
+
+	return StatusResult{StatusRunning, false}
+}
+
+// DetectMode returns empty — Open Interpreter doesn't have modes.
+func (o *InterpreterBackend) DetectMode(content string) string {
+	return ""
+}
+
+// DetectUsage returns nothing — Open Interpreter doesn't print token counts.
+func (o *InterpreterBackend) DetectUsage(content string) Usage {
+	return Usage{}
+}
+
+// StripChrome returns lines as-is — Open Interpreter has no chrome to strip.
+func (o *InterpreterBackend) StripChrome(lines []string, waiting bool) []string {
+	return lines
+}
+
+// LooksLikeMe checks pane content for Open Interpreter's banner and prompts.
+func (o *InterpreterBackend) LooksLikeMe(content string) bool {
+	lower := strings.ToLower(stripAnsiStr(content))
+	for _, sig := range []string{"open interpreter", "would you like to run this code", "model set to"} {
+		if strings.Contains(lower, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInterpreterCommand reports whether a process command line runs Open
+// Interpreter: the interpreter entry point, directly or through python.
+func isInterpreterCommand(cmdline string) bool {
+	for _, field := range strings.Fields(cmdline) {
+		if filepath.Base(field) == "interpreter" {
+			return true
+		}
+	}
+	return false
+}
+
+// Discover finds tmux sessions and processes running Open Interpreter.
+func (o *InterpreterBackend) Discover() []DiscoveredAgent {
+	found := o.discoverTmux()
+	found = append(found, o.discoverProcesses()...)
+	return found
+}
+
+func (o *InterpreterBackend) discoverTmux() []DiscoveredAgent {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return nil
+	}
+
+	out, err := exec.Command("tmux", "list-panes", "-a", "-F", "#{session_name}|#{pane_current_path}|#{pane_current_command}").Output()
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var found []DiscoveredAgent
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "|", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		sessName := parts[0]
+		dir := parts[1]
+		paneCmd := parts[2]
+
+		if strings.HasPrefix(sessName, sessionPrefix) || seen[sessName] {
+			continue
+		}
+
+		// The pane command is python for most installs, so check content too
+		match := isInterpreterCommand(paneCmd)
+		if !match {
+			content, err := CapturePanePlain(sessName)
+			if err != nil {
+				continue
+			}
+			match = o.LooksLikeMe(content)
+		}
+		if match {
+			seen[sessName] = true
+			found = append(found, DiscoveredAgent{
+				Name:        deriveNameFromDir(dir),
+				Dir:         dir,
+				SessionName: sessName,
+			})
+		}
+	}
+
+	return found
+}
+
+func (o *InterpreterBackend) discoverProcesses() []DiscoveredAgent {
+	out, err := exec.Command("pgrep", "-af", "interpreter").Output()
+	if err != nil {
+		return nil
+	}
+
+	var found []DiscoveredAgent
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) < 2 {
+			continue
+		}
+
+		var pid int
+		fmt.Sscanf(parts[0], "%d", &pid)
+
+		if !isInterpreterCommand(parts[1]) {
+			continue
+		}
+
+		dir := getCwd(pid)
+		if dir == "" {
+			dir = "unknown"
+		}
+
+		found = append(found, DiscoveredAgent{
+			Name: fmt.Sprintf("interpreter-%d", pid),
+			Dir:  dir,
+			PID:  pid,
+		})
+	}
+	return found
+}
+
+// InstallHooks is never called (see Capabilities).
+func (o *InterpreterBackend) InstallHooks() error   { return nil }
+func (o *InterpreterBackend) UninstallHooks() error { return nil }
+func (o *InterpreterBackend) HooksInstalled() bool  { return false }
+
+// ReadHookStatus reports no hook status; DetectStatus scrapes the pane.
+func (o *InterpreterBackend) ReadHookStatus(agentID string) (AgentStatus, bool) {
+	return "", false
+}
+
+// CleanHookStatus is a no-op — no status file is ever written.
+func (o *InterpreterBackend) CleanHookStatus(agentID string) {}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// QwenBackend implements Backend for Qwen Code. Qwen Code is a fork of
+// Gemini CLI, so its pane output, /stats table and hook events match
+// Gemini's; only the binary, settings directory and discovery differ.
+type QwenBackend struct{}
+
+func init() {
+	RegisterBackend(&QwenBackend{})
+}
+
+func (q *QwenBackend) Name() string { return "Qwen Code" }
+func (q *QwenBackend) ID() string   { return "qwen" }
+
+// SpawnCommand returns the shell command for launching Qwen Code.
+func (q *QwenBackend) SpawnCommand(args []string) (string, []string) {
+	cmd := "qwen"
+	if len(args) > 0 {
+		cmd = "qwen " + strings.Join(args, " ")
+	}
+	return cmd, nil
+}
+
+// Version returns the first line of `qwen --version`.
+func (q *QwenBackend) Version() string {
+	return probeVersion("qwen")
+}
+
+// Capabilities reports hooks only, like Gemini's.
+func (q *QwenBackend) Capabilities() Capabilities {
+	return Capabilities{SupportsHooks: true}
+}
+
+// ResumeArgs returns empty — Qwen Code has no resume flag.
+func (q *QwenBackend) ResumeArgs() []string {
+	return nil
+}
+
+// AutoApproveArgs returns the flag that approves every tool call.
+func (q *QwenBackend) AutoApproveArgs() []string {
+	return []string{"--yolo"}
+}
+
+// CheckDeps verifies that the qwen CLI is installed.
+func (q *QwenBackend) CheckDeps() error {
+	if _, err := exec.LookPath("qwen"); err != nil {
+		return fmt.Errorf("qwen (npm i -g @qwen-code/qwen-code)")
+	}
+	return nil
+}
+
+// DetectStatus uses Gemini's detection: the fork keeps the "esc to cancel"
+// spinner line, the confirmation prompts and the "Type your message" box.
+func (q *QwenBackend) DetectStatus(content string) StatusResult {
+	return (&GeminiBackend{}).DetectStatus(content)
+}
+
+// DetectMode returns empty — Qwen Code doesn't have EDITS/PLAN modes.
+func (q *QwenBackend) DetectMode(content string) string {
+	return ""
+}
+
+// DetectUsage reads the token total from the last /stats output, which
+// Qwen Code prints in Gemini's format.
+func (q *QwenBackend) DetectUsage(content string) Usage {
+	return Usage{Tokens: lastTokens(stripAnsiStr(content), geminiUsageRe)}
+}
+
+// StripChrome returns lines as-is — Qwen Code has minimal chrome to strip.
+func (q *QwenBackend) StripChrome(lines []string, waiting bool) []string {
+	return lines
+}
+
+// LooksLikeMe checks pane content for Qwen Code UI signatures. A bare
+// "qwen" is too loose: it also matches local model runners like ollama.
+func (q *QwenBackend) LooksLikeMe(content string) bool {
+	lower := strings.ToLower(stripAnsiStr(content))
+	for _, sig := range []string{"qwen code", "qwen-code", "qwen.md"} {
+		if strings.Contains(lower, sig) {
+			return true
+		}
+	}
+	return false
+}
+
+// isQwenCommand reports whether a process command line runs Qwen Code: the
+// qwen binary itself, or node running the @qwen-code package.
+func isQwenCommand(cmdline string) bool {
+	for _, field := range strings.Fields(cmdline) {
+		if filepath.Base(field) == "qwen" || strings.Contains(field, "qwen-code") {
+			return true
+		}
+	}
+	return false
+}
+
+// Discover finds tmux sessions and processes running Qwen Code.
+func (q *QwenBackend) Discover() []DiscoveredAgent {
+	found := q.discoverTmux()
+	found = append(found, q.discoverProcesses()...)
+	return found
+}
+
+func (q *QwenBackend) discoverTmux() []DiscoveredAgent {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return nil
+	}
+
+	out, err := exec.Command("tmux", "list-panes", "-a", "-F", "#{session_name}|#{pane_current_path}|#{pane_current_command}").Output()
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var found []DiscoveredAgent
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "|", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		sessName := parts[0]
+		dir := parts[1]
+		paneCmd := parts[2]
+
+		if strings.HasPrefix(sessName, sessionPrefix) || seen[sessName] {
+			continue
+		}
+
+		if isQwenCommand(paneCmd) {
+			seen[sessName] = true
+			found = append(found, DiscoveredAgent{
+				Name:        deriveNameFromDir(dir),
+				Dir:         dir,
+				SessionName: sessName,
+			})
+			continue
+		}
+
+		content, err := CapturePanePlain(sessName)
+		if err != nil {
+			continue
+		}
+		if q.LooksLikeMe(content) {
+			seen[sessName] = true
+			found = append(found, DiscoveredAgent{
+				Name:        deriveNameFromDir(dir),
+				Dir:         dir,
+				SessionName: sessName,
+			})
+		}
+	}
+
+	return found
+}
+
+func (q *QwenBackend) discoverProcesses() []DiscoveredAgent {
+	out, err := exec.Command("pgrep", "-af", "qwen").Output()
+	if err != nil {
+		return nil
+	}
+
+	var found []DiscoveredAgent
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) < 2 {
+			continue
+		}
+
+		var pid int
+		fmt.Sscanf(parts[0], "%d", &pid)
+
+		if !isQwenCommand(parts[1]) {
+			continue
+		}
+
+		dir := getCwd(pid)
+		if dir == "" {
+			dir = "unknown"
+		}
+
+		found = append(found, DiscoveredAgent{
+			Name: fmt.Sprintf("qwen-%d", pid),
+			Dir:  dir,
+			PID:  pid,
+		})
+	}
+	return found
+}
+
+// --- Hook support ---
+
+func qwenHookScriptPath() string {
+	return filepath.Join(hookScriptDir(), "tickettok-qwen-hook.sh")
+}
+
+func qwenSettingsPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".qwen", "settings.json")
+}
+
+// InstallHooks installs the hook script and registers hooks in Qwen Code's
+// settings.json. The script is Gemini's, under its own name so each
+// backend's hooks can be uninstalled independently.
+func (q *QwenBackend) InstallHooks() error {
+	dest := qwenHookScriptPath()
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("install hook script: %w", err)
+	}
+	if err := os.WriteFile(dest, []byte(geminiInlineHookScript), 0755); err != nil {
+		return fmt.Errorf("install hook script: %w", err)
+	}
+	if err := registerGeminiStyleHooks(qwenSettingsPath(), dest); err != nil {
+		return fmt.Errorf("register hooks: %w", err)
+	}
+	return nil
+}
+
+// UninstallHooks removes tickettok entries from Qwen Code's settings.json and deletes the hook script.
+func (q *QwenBackend) UninstallHooks() error {
+	settingsPath := qwenSettingsPath()
+
+	settings, err := readSettingsJSON(settingsPath)
+	if err != nil {
+		return err
+	}
+	if removeHookEntries(settings, qwenHookScriptPath()) {
+		if err := writeSettingsJSON(settingsPath, settings); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Remove(qwenHookScriptPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// HooksInstalled reports whether tickettok's hook is registered in Qwen Code's settings.json.
+func (q *QwenBackend) HooksInstalled() bool {
+	settings, err := readSettingsJSON(qwenSettingsPath())
+	if err != nil {
+		return false
+	}
+	return settingsHasHook(settings, qwenHookScriptPath())
+}
+
+// ReadHookStatus reads the hook-written status file for an agent.
+func (q *QwenBackend) ReadHookStatus(agentID string) (AgentStatus, bool) {
+	return readHookStatusFile(agentID)
+}
+
+// CleanHookStatus removes the status file for an agent.
+func (q *QwenBackend) CleanHookStatus(agentID string) {
+	cleanHookStatusFile(agentID)
+}
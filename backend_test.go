@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -211,6 +213,39 @@ func TestClaudeDetectMode(t *testing.T) {
 	}
 }
 
+// --- DetectUsage ---
+
+func TestDetectUsage(t *testing.T) {
+	claudeCost := `> /cost
+  ⎿  Total cost:            $0.4312
+     Total duration (API):  1m 2.3s
+     Usage by model:
+         claude-haiku:  1.2k input, 67 output, 0 cache read, 0 cache write
+        claude-sonnet:  45 input, 1.1k output, 120.4k cache read, 12.1k cache write`
+
+	tests := []struct {
+		name    string
+		backend Backend
+		content string
+		want    Usage
+	}{
+		{"claude /cost", &ClaudeBackend{}, claudeCost, Usage{Tokens: 1200 + 67 + 45 + 1100 + 12100, Cost: 0.4312}},
+		{"claude nothing shown", &ClaudeBackend{}, "just output", Usage{}},
+		{"codex status line", &CodexBackend{}, "working\n12.3K tokens used · 87% context left", Usage{Tokens: 12300}},
+		{"codex exit summary wins", &CodexBackend{}, "900 tokens used\nToken usage: total=1,534 input=1,200 output=334", Usage{Tokens: 1534}},
+		{"codex latest status", &CodexBackend{}, "900 tokens used\n1,204 tokens used", Usage{Tokens: 1204}},
+		{"gemini /stats", &GeminiBackend{}, "Input Tokens   1,000\nTotal Tokens   4,210", Usage{Tokens: 4210}},
+		{"qwen /stats", &QwenBackend{}, "Total Tokens   88.1k", Usage{Tokens: 88100}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.backend.DetectUsage(tt.content); got != tt.want {
+				t.Errorf("DetectUsage() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 // --- Claude backend: LooksLikeMe ---
 
 func TestClaudeLooksLikeMe(t *testing.T) {
@@ -347,3 +382,215 @@ func TestHasDingbat(t *testing.T) {
 		})
 	}
 }
+
+// --- Codex backend: resume ---
+
+func TestCodexResumeCommand(t *testing.T) {
+	cb := &CodexBackend{}
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"fresh", nil, "codex"},
+		{"resume", cb.ResumeArgs(), "codex resume --last"},
+		{"resume auto-approved", append(cb.ResumeArgs(), cb.AutoApproveArgs()...), "codex resume --last --approval-mode full-auto"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, strip := cb.SpawnCommand(tt.args)
+			if cmd != tt.want || strip != nil {
+				t.Errorf("SpawnCommand(%v) = %q, %v; want %q, nil", tt.args, cmd, strip, tt.want)
+			}
+		})
+	}
+}
+
+// --- Qwen backend ---
+
+func TestIsQwenCommand(t *testing.T) {
+	tests := []struct {
+		cmdline string
+		want    bool
+	}{
+		{"qwen", true},
+		{"/usr/local/bin/qwen --yolo", true},
+		{"node /usr/lib/node_modules/@qwen-code/qwen-code/dist/index.js", true},
+		{"ollama run qwen2.5-coder", false},
+		{"gemini", false},
+	}
+	for _, tt := range tests {
+		if got := isQwenCommand(tt.cmdline); got != tt.want {
+			t.Errorf("isQwenCommand(%q) = %v, want %v", tt.cmdline, got, tt.want)
+		}
+	}
+}
+
+func TestQwenHooks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	settingsPath := filepath.Join(home, ".qwen", "settings.json")
+	os.MkdirAll(filepath.Dir(settingsPath), 0755)
+	os.WriteFile(settingsPath, []byte(`{"model": {"name": "qwen3-coder-plus"}}`), 0644)
+
+	q := &QwenBackend{}
+	if q.HooksInstalled() {
+		t.Fatal("HooksInstalled() = true before install")
+	}
+	if err := q.InstallHooks(); err != nil {
+		t.Fatalf("InstallHooks() error: %v", err)
+	}
+	if err := q.InstallHooks(); err != nil {
+		t.Fatalf("second InstallHooks() error: %v", err)
+	}
+	if !q.HooksInstalled() {
+		t.Fatal("HooksInstalled() = false after install")
+	}
+
+	settings, _ := readSettingsJSON(settingsPath)
+	hooks := settings["hooks"].(map[string]interface{})
+	if n := len(hooks["AfterAgent"].([]interface{})); n != 1 {
+		t.Errorf("AfterAgent has %d entries, want 1 after installing twice", n)
+	}
+	if settings["model"] == nil {
+		t.Error("existing settings should be preserved")
+	}
+	if (&GeminiBackend{}).HooksInstalled() {
+		t.Error("installing Qwen hooks should not touch Gemini's settings")
+	}
+
+	if err := q.UninstallHooks(); err != nil {
+		t.Fatalf("UninstallHooks() error: %v", err)
+	}
+	if q.HooksInstalled() {
+		t.Error("HooksInstalled() = true after uninstall")
+	}
+	if _, err := os.Stat(qwenHookScriptPath()); !os.IsNotExist(err) {
+		t.Error("hook script should be removed on uninstall")
+	}
+}
+
+func TestDetectBackendID(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		foundBy []string
+		want    string
+	}{
+		{"codex pane", "OpenAI Codex\n› ", []string{"codex"}, "codex"},
+		{"claude pane", "? for shortcuts", []string{"claude"}, "claude"},
+		{"content beats discovery", "Welcome to Claude Code", []string{"gemini"}, "claude"},
+		{"specific backend beats claude's prompt", "❯ gemini", []string{"claude", "gemini"}, "gemini"},
+		{"no content trusts discovery", "", []string{"codex"}, "codex"},
+		{"nothing known", "", nil, "claude"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectBackendID(tt.content, tt.foundBy); got != tt.want {
+				t.Errorf("detectBackendID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadHookSessionID(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	os.MkdirAll(hookStatusDir(), 0755)
+	write := func(body string) {
+		os.WriteFile(filepath.Join(hookStatusDir(), "7.json"), []byte(body), 0644)
+	}
+
+	if got := readHookSessionID("7"); got != "" {
+		t.Errorf("no status file: got %q, want empty", got)
+	}
+	write(`{"state":"IDLE","ts":1,"transcript":"/home/u/.claude/projects/-srv-api/0b6c-41.jsonl"}`)
+	if got := readHookSessionID("7"); got != "0b6c-41" {
+		t.Errorf("from transcript: got %q, want 0b6c-41", got)
+	}
+	write(`{"state":"IDLE","ts":1,"transcript":"/x/old.jsonl","session":"9f2e"}`)
+	if got := readHookSessionID("7"); got != "9f2e" {
+		t.Errorf("from payload: got %q, want 9f2e", got)
+	}
+}
+
+func TestResumeArgs(t *testing.T) {
+	tests := []struct {
+		agent Agent
+		want  string
+	}{
+		{Agent{BackendID: "claude"}, "--continue"},
+		{Agent{BackendID: "claude", SessionID: "9f2e"}, "--resume 9f2e"},
+		{Agent{BackendID: "codex", SessionID: "9f2e"}, "resume --last"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(resumeArgs(&tt.agent), " "); got != tt.want {
+			t.Errorf("resumeArgs(%s, %q) = %q, want %q", tt.agent.BackendID, tt.agent.SessionID, got, tt.want)
+		}
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	if c := (&ClaudeBackend{}).Capabilities(); !c.SupportsResume || !c.SupportsHooks || !c.SupportsModes || !c.SupportsCostReporting {
+		t.Errorf("claude capabilities = %+v, want all", c)
+	}
+	if c := (&GeminiBackend{}).Capabilities(); c.SupportsResume || !c.SupportsHooks {
+		t.Errorf("gemini capabilities = %+v, want hooks without resume", c)
+	}
+	if got := noResumeNote(&Agent{BackendID: "gemini"}); !strings.Contains(got, "new conversation") {
+		t.Errorf("noResumeNote(gemini) = %q, want a new-conversation warning", got)
+	}
+	if got := noResumeNote(&Agent{BackendID: "codex"}); got != "" {
+		t.Errorf("noResumeNote(codex) = %q, want empty", got)
+	}
+}
+
+func TestBackendLabel(t *testing.T) {
+	tests := map[string]string{
+		"claude": "CC",
+		"codex":  "CX",
+		"gemini": "GM",
+		"ollama": "OL",
+		"x":      "X",
+	}
+	for id, want := range tests {
+		if got := backendLabel(id); got != want {
+			t.Errorf("backendLabel(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestInterpreterDetectStatus(t *testing.T) {
+	o := &InterpreterBackend{}
+	tests := []struct {
+		name    string
+		content string
+		want    AgentStatus
+		conf    bool
+	}{
+		{"prompt", "▌ Model set to ollama/qwen2.5-coder\n\n> ", StatusIdle, true},
+		{"code confirmation", "  ls -la\n\n  Would you like to run this code? (y/n)\n", StatusWaiting, true},
+		{"generating", "> list the files\n\n  Sure, I'll run ls.", StatusRunning, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := o.DetectStatus(tt.content)
+			if got.Status != tt.want || got.Confident != tt.conf {
+				t.Errorf("DetectStatus() = %+v, want {%s %v}", got, tt.want, tt.conf)
+			}
+		})
+	}
+}
+
+func TestIsInterpreterCommand(t *testing.T) {
+	tests := map[string]bool{
+		"/usr/bin/python3 /home/u/.local/bin/interpreter --local": true,
+		"interpreter":                    true,
+		"python3 -m http.server":         false,
+		"/usr/bin/ruby-interpreter-shim": false,
+	}
+	for cmd, want := range tests {
+		if got := isInterpreterCommand(cmd); got != want {
+			t.Errorf("isInterpreterCommand(%q) = %v, want %v", cmd, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Saving keeps the state file's last few versions beside it, at most one
+// per stateBackupEvery, so a bad write or a mistaken clear can be undone
+// with `tickettok restore`.
+const (
+	stateBackups     = 5
+	stateBackupEvery = 10 * time.Minute
+)
+
+// stateBackupPath is where the nth newest backup of the state file at path
+// is kept, from 1.
+func stateBackupPath(path string, n int) string {
+	return filepath.Join(filepath.Dir(path), "backups", fmt.Sprintf("state.%d.json", n))
+}
+
+// backupState copies the state file at path into the newest backup,
+// shifting the others back and dropping the oldest. Unless force is set,
+// it does nothing while the newest backup is under stateBackupEvery old.
+// Callers hold the lock.
+func backupState(path string, force bool) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	newest := stateBackupPath(path, 1)
+	if info, err := os.Stat(newest); !force && err == nil && time.Since(info.ModTime()) < stateBackupEvery {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(newest), 0755); err != nil {
+		return err
+	}
+	for n := stateBackups - 1; n >= 1; n-- {
+		if err := os.Rename(stateBackupPath(path, n), stateBackupPath(path, n+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.WriteFile(newest, data, 0644)
+}
+
+// StateBackup is one of the state file's backups.
+type StateBackup struct {
+	N      int
+	At     time.Time // when it was taken
+	Agents int
+}
+
+// listStateBackups returns the backups of the state file at path, newest
+// first. Unreadable ones are left out.
+func listStateBackups(path string) []StateBackup {
+	var out []StateBackup
+	for n := 1; n <= stateBackups; n++ {
+		sf, at, err := readStateBackup(path, n)
+		if err != nil {
+			continue
+		}
+		out = append(out, StateBackup{N: n, At: at, Agents: len(sf.Agents)})
+	}
+	return out
+}
+
+// readStateBackup reads the nth newest backup of the state file at path.
+func readStateBackup(path string, n int) (StateFile, time.Time, error) {
+	var sf StateFile
+	p := stateBackupPath(path, n)
+	info, err := os.Stat(p)
+	if err != nil {
+		return sf, time.Time{}, err
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return sf, time.Time{}, err
+	}
+	if err := json.Unmarshal(data, &sf); err != nil {
+		return sf, time.Time{}, fmt.Errorf("backup %d: %w", n, err)
+	}
+	return sf, info.ModTime(), nil
+}
+
+// RestoreBackup replaces the board with its nth newest backup, returning how
+// many agents that has. The state it replaces becomes the newest backup,
+// so a restore can itself be undone.
+func (s *Store) RestoreBackup(n int) (int, error) {
+	sf, _, err := readStateBackup(s.path, n)
+	if os.IsNotExist(err) {
+		return 0, fmt.Errorf("no backup %d", n)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var backupErr error
+	err = s.commit(func() {
+		backupErr = backupState(s.path, true)
+		s.apply(sf)
+		s.syncNextID()
+	})
+	if err == nil {
+		err = backupErr
+	}
+	return len(s.agents), err
+}
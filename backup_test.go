@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStateBackups(t *testing.T) {
+	s := newTestStore(t)
+	s.Add("one", "/tmp")
+	if _, err := os.Stat(stateBackupPath(s.path, 1)); !os.IsNotExist(err) {
+		t.Fatal("the first save has nothing to back up")
+	}
+
+	s.Add("two", "/tmp")
+	if got := listStateBackups(s.path); len(got) != 1 || got[0].Agents != 1 {
+		t.Fatalf("backups after the second save = %+v, want one with 1 agent", got)
+	}
+
+	// Within stateBackupEvery of the last backup, saves don't take another
+	s.Add("three", "/tmp")
+	if got := listStateBackups(s.path); len(got) != 1 {
+		t.Fatalf("%d backups, want still 1", len(got))
+	}
+
+	old := time.Now().Add(-2 * stateBackupEvery)
+	if err := os.Chtimes(stateBackupPath(s.path, 1), old, old); err != nil {
+		t.Fatal(err)
+	}
+	s.Add("four", "/tmp")
+	got := listStateBackups(s.path)
+	if len(got) != 2 || got[0].Agents != 3 || got[1].Agents != 1 {
+		t.Fatalf("backups = %+v, want 3 then 1 agents", got)
+	}
+
+	n, err := s.RestoreBackup(2)
+	if err != nil || n != 1 || len(s.List()) != 1 {
+		t.Fatalf("RestoreBackup(2) = %d, %v with %d agents, want 1", n, err, len(s.List()))
+	}
+	reopened := &Store{path: s.path}
+	if err := reopened.load(); err != nil || len(reopened.agents) != 1 {
+		t.Fatalf("state file after restore has %d agents, %v", len(reopened.agents), err)
+	}
+	// The board restore replaced is the newest backup
+	if got := listStateBackups(s.path); got[0].Agents != 4 {
+		t.Errorf("newest backup after restore has %d agents, want 4", got[0].Agents)
+	}
+
+	for i := 0; i < stateBackups+2; i++ {
+		if err := backupState(s.path, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := listStateBackups(s.path); len(got) != stateBackups {
+		t.Errorf("%d backups, want at most %d", len(got), stateBackups)
+	}
+	if _, err := s.RestoreBackup(stateBackups + 1); err == nil {
+		t.Error("restoring a backup that doesn't exist should fail")
+	}
+}
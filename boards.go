@@ -0,0 +1,266 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// currentBoard is the named board this process works on, picked with
+// --board: "" for the default board, whose state lives in stateDir itself,
+// or for a project's.
+var currentBoard string
+
+// projectRoot is the directory holding the project board this process
+// works on, "" when it isn't on one. See findProjectRoot.
+var projectRoot string
+
+// projectStateDir names a project's own state directory.
+const projectStateDir = ".tickettok"
+
+// Board names end up in paths and tmux session names
+var boardNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// checkBoardName rejects board names that aren't safe as a directory and
+// session name.
+func checkBoardName(name string) error {
+	if !boardNameRe.MatchString(name) {
+		return fmt.Errorf("invalid board name %q: use letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// boardsDir holds a directory per named board.
+func boardsDir() string {
+	return filepath.Join(stateDir(), "boards")
+}
+
+// boardDir is where the current board keeps its state, event feed and
+// archive.
+func boardDir() string {
+	switch {
+	case projectRoot != "":
+		return filepath.Join(projectRoot, projectStateDir)
+	case currentBoard != "":
+		return filepath.Join(boardsDir(), currentBoard)
+	}
+	return stateDir()
+}
+
+// boardIDPrefix starts the IDs of the current board's agents, so their
+// tmux sessions and hook status files don't clash with other boards'.
+func boardIDPrefix() string {
+	switch {
+	case projectRoot != "":
+		return projectBoardID(projectRoot) + "-"
+	case currentBoard != "":
+		return currentBoard + "-"
+	}
+	return ""
+}
+
+// Characters a project's directory name can't keep in its agents' IDs
+var unsafeIDRe = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// projectBoardID names a project's board in its agents' IDs: the
+// directory's name and a hash of its path, as two repos can share a name.
+func projectBoardID(root string) string {
+	sum := sha1.Sum([]byte(root))
+	return unsafeIDRe.ReplaceAllString(filepath.Base(root), "_") + "_" + hex.EncodeToString(sum[:2])
+}
+
+// findProjectRoot returns the nearest of dir and its parents holding a
+// .tickettok state directory of its own, or "". The global state
+// directory, and ~/.tickettok when that's moved, don't count.
+func findProjectRoot(dir string) string {
+	global := stateDir()
+	home, _ := os.UserHomeDir()
+	for {
+		if candidate := filepath.Join(dir, projectStateDir); candidate != global && dir != home {
+			if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+				return dir
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// selectBoard picks the board from the command line — --board <name>, or
+// --global for the default board — or else the project the working
+// directory is in. It returns args without those flags.
+func selectBoard(args []string) ([]string, error) {
+	args, board, err := takeBoardFlag(args)
+	if err != nil {
+		return nil, err
+	}
+	var rest []string
+	global := false
+	for _, a := range args {
+		if a == "--global" {
+			global = true
+			continue
+		}
+		rest = append(rest, a)
+	}
+	if global && board != "" {
+		return nil, fmt.Errorf("--global and --board don't go together")
+	}
+	currentBoard, projectRoot = board, ""
+	if board == "" && !global {
+		if wd, err := os.Getwd(); err == nil {
+			projectRoot = findProjectRoot(wd)
+		}
+	}
+	return rest, nil
+}
+
+// boardLabel names the current board for display, "" for the default one.
+func boardLabel() string {
+	switch {
+	case projectRoot != "":
+		return filepath.Base(projectRoot) + " project"
+	case currentBoard != "":
+		return currentBoard + " board"
+	}
+	return ""
+}
+
+// boardArgs picks the current board again on the command line.
+func boardArgs() []string {
+	switch {
+	case projectRoot != "":
+		return nil
+	case currentBoard != "":
+		return []string{"--board", currentBoard}
+	}
+	if wd, err := os.Getwd(); err == nil && findProjectRoot(wd) != "" {
+		return []string{"--global"}
+	}
+	return nil
+}
+
+// ownedID reports whether an agent ID belongs to the current board: a
+// number after the board's prefix.
+func ownedID(id string) bool {
+	prefix := boardIDPrefix()
+	if !strings.HasPrefix(id, prefix) {
+		return false
+	}
+	_, err := strconv.Atoi(strings.TrimPrefix(id, prefix))
+	return err == nil
+}
+
+// takeBoardFlag removes `--board <name>` (or `--board=<name>`) from args,
+// wherever it is, returning what's left and the name.
+func takeBoardFlag(args []string) ([]string, string, error) {
+	rest, name, err := takeFlag(args, "--board")
+	if err != nil {
+		return nil, "", err
+	}
+	if name != "" || len(rest) < len(args) {
+		if err := checkBoardName(name); err != nil {
+			return nil, "", err
+		}
+	}
+	return rest, name, nil
+}
+
+// takeFlag removes a global `<flag> <value>` (or `<flag>=<value>`) from
+// args, wherever it is, returning what's left and the value.
+func takeFlag(args []string, flag string) ([]string, string, error) {
+	var rest []string
+	var value string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == flag:
+			if i+1 >= len(args) {
+				return nil, "", fmt.Errorf("%s needs a value", flag)
+			}
+			value = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], flag+"="):
+			value = strings.TrimPrefix(args[i], flag+"=")
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return rest, value, nil
+}
+
+// listBoards returns the named boards, sorted.
+func listBoards() []string {
+	entries, err := os.ReadDir(boardsDir())
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && checkBoardName(e.Name()) == nil {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// boardChoice is a board the TUI's board switcher offers.
+type boardChoice struct {
+	Label   string
+	Dir     string   // where it keeps its state
+	Args    []string // pick it on the command line
+	Project bool     // the working directory's project board
+	Current bool     // the board this process is on
+}
+
+// boardChoices lists the boards to switch between: the project the
+// working directory is in, if any, the default board, then the named ones.
+func boardChoices() []boardChoice {
+	var out []boardChoice
+	project := projectRoot
+	if wd, err := os.Getwd(); err == nil && project == "" {
+		project = findProjectRoot(wd)
+	}
+	if project != "" {
+		out = append(out, boardChoice{Label: "project " + filepath.Base(project), Dir: filepath.Join(project, projectStateDir), Project: true, Current: projectRoot != ""})
+	}
+	def := boardChoice{Label: "default", Dir: stateDir(), Current: projectRoot == "" && currentBoard == ""}
+	if project != "" {
+		def.Label = "global (default)"
+		def.Args = []string{"--global"}
+	}
+	out = append(out, def)
+	for _, b := range listBoards() {
+		out = append(out, boardChoice{Label: b, Dir: filepath.Join(boardsDir(), b), Args: []string{"--board", b}, Current: projectRoot == "" && currentBoard == b})
+	}
+	return out
+}
+
+// boardAgentCount reads how many agents the board in dir has without
+// loading it into a Store.
+func boardAgentCount(dir string) int {
+	s := &Store{path: filepath.Join(dir, "state.json")}
+	if err := s.load(); err != nil {
+		return 0
+	}
+	return len(s.agents)
+}
+
+// execBoard replaces this process with a TUI on the board args pick.
+func execBoard(args []string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	return syscall.Exec(exe, append([]string{os.Args[0]}, args...), os.Environ())
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTakeBoardFlag(t *testing.T) {
+	for _, tc := range []struct {
+		args  []string
+		rest  []string
+		board string
+	}{
+		{[]string{"list"}, []string{"list"}, ""},
+		{[]string{"--board", "work", "list"}, []string{"list"}, "work"},
+		{[]string{"add", ".", "--board=client_a", "--name", "api"}, []string{"add", ".", "--name", "api"}, "client_a"},
+		{nil, nil, ""},
+	} {
+		rest, board, err := takeBoardFlag(tc.args)
+		if err != nil || board != tc.board || !reflect.DeepEqual(rest, tc.rest) {
+			t.Errorf("takeBoardFlag(%q) = %q, %q, %v, want %q, %q", tc.args, rest, board, err, tc.rest, tc.board)
+		}
+	}
+	for _, bad := range [][]string{{"--board"}, {"--board", "a/b"}, {"--board="}, {"--board", "x.y"}} {
+		if _, _, err := takeBoardFlag(bad); err == nil {
+			t.Errorf("takeBoardFlag(%q) should fail", bad)
+		}
+	}
+}
+
+func TestBoardIDs(t *testing.T) {
+	defer func() { currentBoard = "" }()
+
+	if !ownedID("12") || ownedID("work-3") {
+		t.Error("the default board owns plain numeric IDs only")
+	}
+
+	currentBoard = "work"
+	if !ownedID("work-3") || ownedID("3") || ownedID("work-x") || ownedID("workshop-3") {
+		t.Error("a named board owns its own prefixed IDs only")
+	}
+
+	s := newTestStore(t)
+	s.idPrefix = boardIDPrefix()
+	a := s.Add("api", "/tmp")
+	b := s.Add("web", "/tmp")
+	if a.ID != "work-1" || b.ID != "work-2" {
+		t.Errorf("IDs = %s, %s, want work-1, work-2", a.ID, b.ID)
+	}
+	s.nextID = 1
+	s.syncNextID()
+	if s.nextID != 3 {
+		t.Errorf("nextID = %d after sync, want 3", s.nextID)
+	}
+
+	a.SessionName = SessionName(a.ID)
+	plan := planPrune([]*Agent{a}, []string{"tickettok_work-1", "tickettok_work-9", "tickettok_4", "tickettok_personal-1"}, []string{"work-1", "work-7", "5"})
+	if !reflect.DeepEqual(plan.OrphanSessions, []string{"tickettok_work-9"}) {
+		t.Errorf("OrphanSessions = %v, want only this board's", plan.OrphanSessions)
+	}
+	if !reflect.DeepEqual(plan.StaleStatus, []string{"work-7"}) {
+		t.Errorf("StaleStatus = %v, want only this board's", plan.StaleStatus)
+	}
+}
+
+func TestProjectBoard(t *testing.T) {
+	defer func() { currentBoard, projectRoot = "", "" }()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := filepath.Join(home, "code", "api")
+	sub := filepath.Join(repo, "internal", "db")
+	for _, d := range []string{filepath.Join(home, ".tickettok"), filepath.Join(repo, ".tickettok"), sub} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := findProjectRoot(sub); got != repo {
+		t.Errorf("findProjectRoot(sub) = %q, want %q", got, repo)
+	}
+	if got := findProjectRoot(filepath.Join(home, "code")); got != "" {
+		t.Errorf("findProjectRoot outside a project = %q, want none: ~/.tickettok is the global state", got)
+	}
+
+	t.Chdir(sub)
+	rest, err := selectBoard([]string{"list"})
+	if err != nil || projectRoot != repo || !reflect.DeepEqual(rest, []string{"list"}) {
+		t.Fatalf("selectBoard in a project: %q, %v, projectRoot %q", rest, err, projectRoot)
+	}
+	if boardDir() != filepath.Join(repo, ".tickettok") || boardArgs() != nil {
+		t.Errorf("boardDir = %q, boardArgs = %q on the project board", boardDir(), boardArgs())
+	}
+	prefix := boardIDPrefix()
+	if !strings.HasPrefix(prefix, "api_") || !ownedID(prefix+"2") || ownedID("2") {
+		t.Errorf("project board ID prefix %q", prefix)
+	}
+	if choices := boardChoices(); !choices[0].Project || !choices[0].Current || choices[1].Current {
+		t.Errorf("boardChoices = %+v, want the current project board first", choices)
+	}
+
+	if _, err := selectBoard([]string{"--global"}); err != nil || projectRoot != "" || currentBoard != "" {
+		t.Fatalf("--global: %v, projectRoot %q, board %q", err, projectRoot, currentBoard)
+	}
+	if boardDir() != stateDir() || !reflect.DeepEqual(boardArgs(), []string{"--global"}) {
+		t.Errorf("boardDir = %q, boardArgs = %q on the global board", boardDir(), boardArgs())
+	}
+
+	if _, err := selectBoard([]string{"--board", "work"}); err != nil || projectRoot != "" || currentBoard != "work" {
+		t.Errorf("--board in a project: %v, projectRoot %q, board %q", err, projectRoot, currentBoard)
+	}
+	if _, err := selectBoard([]string{"--global", "--board", "work"}); err == nil {
+		t.Error("--global with --board should fail")
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// branchMode is how an agent's spawn sets up git for it.
+type branchMode int
+
+const (
+	branchNone     branchMode = iota // work in the checkout as it is
+	branchNew                        // check out a new branch in the checkout
+	branchWorktree                   // new branch in a worktree of its own
+)
+
+// Label names the mode in the spawn dialog.
+func (b branchMode) Label() string {
+	switch b {
+	case branchNew:
+		return "new branch"
+	case branchWorktree:
+		return "new branch in its own worktree"
+	}
+	return "current checkout"
+}
+
+// AgentBranch is a branch tickettok created for an agent. It's remembered
+// after the agent is gone so prune can delete it once merged.
+type AgentBranch struct {
+	Repo string `json:"repo"`
+	Name string `json:"name"`
+}
+
+var branchUnsafeRe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// agentBranch names the branch created for an agent, like
+// tickettok/agent-7-fix-auth. The ID keeps agents of the same name apart.
+func agentBranch(a *Agent) string {
+	branch := "tickettok/agent-" + a.ID
+	if name := strings.Trim(branchUnsafeRe.ReplaceAllString(a.Name, "-"), "-."); name != "" {
+		branch += "-" + name
+	}
+	return branch
+}
+
+// repoToplevel returns the top level of the repository dir is in.
+func repoToplevel(dir string) (string, error) {
+	repo, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("%s is not in a git repository", dir)
+	}
+	return repo, nil
+}
+
+// freeBranch returns name, or name with a numeric suffix if repo already
+// has a branch called that. Agent IDs start over once the board is empty,
+// so an old agent's branch can still be around.
+func freeBranch(repo, name string) string {
+	branch := name
+	for n := 2; ; n++ {
+		if _, err := git(repo, "rev-parse", "--verify", "-q", "refs/heads/"+branch); err != nil {
+			return branch
+		}
+		branch = name + "-" + strconv.Itoa(n)
+	}
+}
+
+// createBranch checks out a new branch from HEAD in repo. Uncommitted
+// changes come along.
+func createBranch(repo, branch string) error {
+	_, err := git(repo, "checkout", "-q", "-b", branch)
+	return err
+}
+
+// branchAgent puts a just-added agent on a new branch in its checkout.
+func branchAgent(store *Store, agent *Agent) error {
+	repo, err := repoToplevel(agent.Dir)
+	if err != nil {
+		return err
+	}
+	branch := freeBranch(repo, agentBranch(agent))
+	if err := createBranch(repo, branch); err != nil {
+		return err
+	}
+	store.SetBranch(agent.ID, AgentBranch{Repo: repo, Name: branch})
+	return nil
+}
+
+// prepareGit sets up the branch or worktree a spawn asked for.
+func prepareGit(store *Store, agent *Agent, mode branchMode) error {
+	switch mode {
+	case branchNew:
+		return branchAgent(store, agent)
+	case branchWorktree:
+		return isolateAgent(store, agent)
+	}
+	return nil
+}
+
+// branchMerged reports whether branch can go: it exists, every commit on it
+// is reachable from the repository's HEAD, and it isn't HEAD itself.
+// found is false once the branch has been deleted some other way.
+func branchMerged(b AgentBranch) (merged, found bool) {
+	if _, err := git(b.Repo, "rev-parse", "--verify", "-q", "refs/heads/"+b.Name); err != nil {
+		return false, false
+	}
+	if head, _ := git(b.Repo, "symbolic-ref", "-q", "--short", "HEAD"); head == b.Name {
+		return false, true
+	}
+	err := exec.Command("git", "-C", b.Repo, "merge-base", "--is-ancestor", b.Name, "HEAD").Run()
+	return err == nil, true
+}
+
+// deleteBranch deletes a merged branch. git refuses if it's checked out in
+// a worktree that's still around.
+func deleteBranch(b AgentBranch) error {
+	_, err := git(b.Repo, "branch", "-d", b.Name)
+	return err
+}
+
+// planBranchPrune picks the recorded branches prune can delete: merged ones
+// whose agent is gone, or is one of the dead agents being pruned. gone
+// lists branches that no longer exist, to be forgotten.
+func planBranchPrune(branches []AgentBranch, agents, dead []*Agent, status func(AgentBranch) (merged, found bool)) (del, gone []AgentBranch) {
+	owned := make(map[string]bool)
+	pruned := make(map[string]bool, len(dead))
+	for _, a := range dead {
+		pruned[a.ID] = true
+	}
+	for _, a := range agents {
+		if a.Branch != "" && !pruned[a.ID] {
+			owned[a.Branch] = true
+		}
+	}
+	for _, b := range branches {
+		if owned[b.Name] {
+			continue
+		}
+		switch merged, found := status(b); {
+		case !found:
+			gone = append(gone, b)
+		case merged:
+			del = append(del, b)
+		}
+	}
+	return del, gone
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestAgentBranch(t *testing.T) {
+	cases := map[string]string{
+		"api":            "tickettok/agent-7-api",
+		"fix auth (wip)": "tickettok/agent-7-fix-auth-wip",
+		"../..":          "tickettok/agent-7",
+		"feature/login!": "tickettok/agent-7-feature-login",
+	}
+	for name, want := range cases {
+		if got := agentBranch(&Agent{ID: "7", Name: name}); got != want {
+			t.Errorf("agentBranch(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestPlanBranchPrune(t *testing.T) {
+	branches := []AgentBranch{
+		{Repo: "/r", Name: "tickettok/agent-1-api"},   // agent still running
+		{Repo: "/r", Name: "tickettok/agent-2-web"},   // agent being pruned, merged
+		{Repo: "/r", Name: "tickettok/agent-3-docs"},  // agent gone, merged
+		{Repo: "/r", Name: "tickettok/agent-4-tests"}, // agent gone, not merged
+		{Repo: "/r", Name: "tickettok/agent-5-old"},   // deleted by hand
+	}
+	live := &Agent{ID: "1", Branch: "tickettok/agent-1-api"}
+	dead := &Agent{ID: "2", Branch: "tickettok/agent-2-web"}
+	status := func(b AgentBranch) (bool, bool) {
+		switch b.Name {
+		case "tickettok/agent-4-tests":
+			return false, true
+		case "tickettok/agent-5-old":
+			return false, false
+		}
+		return true, true
+	}
+
+	del, gone := planBranchPrune(branches, []*Agent{live, dead}, []*Agent{dead}, status)
+	if len(del) != 2 || del[0].Name != "tickettok/agent-2-web" || del[1].Name != "tickettok/agent-3-docs" {
+		t.Errorf("deletable = %v", del)
+	}
+	if len(gone) != 1 || gone[0].Name != "tickettok/agent-5-old" {
+		t.Errorf("gone = %v", gone)
+	}
+}
+
+func TestBranchLifecycle(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo, _ := filepath.EvalSymlinks(t.TempDir())
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	run("commit", "-q", "--allow-empty", "-m", "init")
+	run("branch", "base")
+
+	if _, err := repoToplevel(t.TempDir()); err == nil {
+		t.Error("repoToplevel outside a repo succeeded")
+	}
+	if got, _ := repoToplevel(repo); got != repo {
+		t.Errorf("repoToplevel = %q, want %q", got, repo)
+	}
+
+	if got := freeBranch(repo, "base"); got != "base-2" {
+		t.Errorf("freeBranch(taken) = %q, want base-2", got)
+	}
+	b := AgentBranch{Repo: repo, Name: freeBranch(repo, "tickettok/agent-1-x")}
+	if err := createBranch(repo, b.Name); err != nil {
+		t.Fatalf("createBranch: %v", err)
+	}
+	if merged, found := branchMerged(b); merged || !found {
+		t.Errorf("checked-out branch: merged=%v found=%v, want false true", merged, found)
+	}
+
+	run("commit", "-q", "--allow-empty", "-m", "work")
+	run("checkout", "-q", "base")
+	if merged, _ := branchMerged(b); merged {
+		t.Error("branch with unmerged commits counted as merged")
+	}
+	run("merge", "-q", "--ff-only", b.Name)
+	if merged, _ := branchMerged(b); !merged {
+		t.Error("merged branch not counted as merged")
+	}
+	if err := deleteBranch(b); err != nil {
+		t.Fatalf("deleteBranch: %v", err)
+	}
+	if _, found := branchMerged(b); found {
+		t.Error("deleted branch still found")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// broadcastTargets returns the agents a message to everyone reaches: those
+// with a live session that aren't queued or frozen.
+func broadcastTargets(agents []*Agent) []*Agent {
+	var out []*Agent
+	for _, a := range agents {
+		if a.Status == StatusPending || a.Status == StatusPaused || a.SessionName == "" {
+			continue
+		}
+		if IsSessionAlive(a.SessionName) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// sendResult is how sending a message to one agent went.
+type sendResult struct {
+	agent *Agent
+	err   error
+}
+
+// broadcast sends message to each agent in turn, carrying on past
+// failures.
+func broadcast(agents []*Agent, message string, send func(*Agent, string) error) []sendResult {
+	results := make([]sendResult, len(agents))
+	for i, a := range agents {
+		results[i] = sendResult{agent: a, err: send(a, message)}
+	}
+	return results
+}
+
+// broadcastSummary sums up a broadcast for the status bar, naming the
+// agents it failed for.
+func broadcastSummary(results []sendResult) string {
+	var failed []string
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", r.agent.Name, r.err))
+		}
+	}
+	if len(failed) == 0 && len(results) == 1 {
+		return "Sent to 1 agent"
+	}
+	if len(failed) == 0 {
+		return fmt.Sprintf("Sent to %d agents", len(results))
+	}
+	return fmt.Sprintf("Sent to %d of %d agents; failed: %s", len(results)-len(failed), len(results), strings.Join(failed, ", "))
+}
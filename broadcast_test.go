@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBroadcast(t *testing.T) {
+	agents := []*Agent{{Name: "api"}, {Name: "web"}, {Name: "docs"}}
+	var got []string
+	results := broadcast(agents, "rebase on main", func(a *Agent, msg string) error {
+		got = append(got, a.Name+": "+msg)
+		if a.Name == "web" {
+			return errors.New("session gone")
+		}
+		return nil
+	})
+	if len(got) != 3 || got[2] != "docs: rebase on main" {
+		t.Fatalf("sent %q, want all three past the failure", got)
+	}
+	if s, want := broadcastSummary(results), "Sent to 2 of 3 agents; failed: web (session gone)"; s != want {
+		t.Errorf("broadcastSummary() = %q, want %q", s, want)
+	}
+	if s, want := broadcastSummary(results[:1]), "Sent to 1 agent"; s != want {
+		t.Errorf("broadcastSummary() = %q, want %q", s, want)
+	}
+}
+
+func TestBroadcastTargets(t *testing.T) {
+	agents := []*Agent{{Name: "queued", Status: StatusPending, SessionName: "x"}, {Name: "frozen", Status: StatusPaused, SessionName: "x"}, {Name: "none", Status: StatusIdle}}
+	if got := broadcastTargets(agents); len(got) != 0 {
+		t.Errorf("broadcastTargets() = %d agents, want none", len(got))
+	}
+}
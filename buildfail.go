@@ -0,0 +1,50 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// buildScanLines is how much of the end of a pane is read for failed
+// builds and test runs.
+const buildScanLines = 40
+
+// Lines reporting a failed build or test run: Go, Rust, TypeScript, npm,
+// make, and the summaries of pytest, jest and mocha
+var buildFailRe = regexp.MustCompile(`(?i)((?-i:^--- FAIL: |^FAIL\b|^FAILED\b|^npm ERR!|^error(?:\[E\d+\])?: |\berror TS\d+:)|\b[1-9]\d* (?:failed|failing)\b|\bbuild failed\b|\bcompilation failed\b|^make(?:\[\d+\])?: \*\*\* .*Error \d+|\b(?:exited|failed) with (?:exit )?(?:code|status) [1-9]|\bexit (?:code|status):? [1-9])`)
+
+// Lines saying a later run went through, clearing a failure above them
+var buildPassRe = regexp.MustCompile(`(?i)((?-i:^ok\s|^PASS\b|^Finished\b.*\btarget)|\ball tests passed\b|\b\d+ passed\b|\bbuild succeeded\b|\bcompiled successfully\b)`)
+
+// detectBuildFailure looks for a failed build or test run near the end of
+// plain pane content, returning its line. A run that passed after the
+// failure clears it.
+func detectBuildFailure(content string) (string, bool) {
+	lines := strings.Split(strings.TrimRight(content, " \n"), "\n")
+	if len(lines) > buildScanLines {
+		lines = lines[len(lines)-buildScanLines:]
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		// Agent TUIs indent tool output behind their own markers
+		l := strings.TrimSpace(strings.TrimLeft(stripAnsiStr(lines[i]), " \t⎿│●⏺"))
+		switch {
+		case buildFailRe.MatchString(l):
+			if r := []rune(l); len(r) > 80 {
+				l = string(r[:79]) + "…"
+			}
+			return l, true
+		case buildPassRe.MatchString(l):
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// buildFailureNotice is the line a card shows about a broken build: the
+// failing line, unless the agent is ERROR, which says why on its own.
+func buildFailureNotice(a *Agent, line string) string {
+	if a.Status == StatusError {
+		return ""
+	}
+	return line
+}
@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestDetectBuildFailure(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		broken  bool
+	}{
+		{"go test", "--- FAIL: TestParse (0.00s)\n    parse_test.go:12: got 1, want 2\nFAIL\nFAIL\texample.com/pkg\t0.003s\n", "FAIL\texample.com/pkg\t0.003s", true},
+		{"jest summary", "Tests:       2 failed, 14 passed, 16 total\nTime:        3.2s\n", "Tests:       2 failed, 14 passed, 16 total", true},
+		{"behind tool markers", "⏺ Bash(npm run build)\n  ⎿  npm ERR! code ELIFECYCLE\n", "npm ERR! code ELIFECYCLE", true},
+		{"exit banner", "make: *** [Makefile:12: all] Error 2\n", "make: *** [Makefile:12: all] Error 2", true},
+		{"passed after failing", "FAIL\texample.com/pkg\t0.003s\n> fixed the test\nok  \texample.com/pkg\t0.004s\n", "", false},
+		{"nothing failed", "===== 12 passed, 0 failed in 1.02s =====\n", "", false},
+		{"words in prose", "Failing that, I'll retry the request.\nFail-safe defaults are kept.\n", "", false},
+		{"plain output", "Reading files…\nDone.\n", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, broken := detectBuildFailure(tt.content)
+			if got != tt.want || broken != tt.broken {
+				t.Errorf("detectBuildFailure() = %q, %v, want %q, %v", got, broken, tt.want, tt.broken)
+			}
+		})
+	}
+}
+
+func TestDetectBuildFailureOnlyReadsTheEnd(t *testing.T) {
+	content := "FAIL\texample.com/pkg\t0.003s\n"
+	for i := 0; i < buildScanLines; i++ {
+		content += "more output\n"
+	}
+	if line, broken := detectBuildFailure(content); broken {
+		t.Errorf("failure %q out of range was flagged", line)
+	}
+}
+
+func TestBuildFailureNotice(t *testing.T) {
+	a := &Agent{Status: StatusIdle}
+	if got := buildFailureNotice(a, "FAIL"); got != "FAIL" {
+		t.Errorf("IDLE agent notice = %q, want FAIL", got)
+	}
+	a.Status = StatusError
+	if got := buildFailureNotice(a, "FAIL"); got != "" {
+		t.Errorf("ERROR agent notice = %q, want none", got)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultCheckpointCommand commits everything in the agent's directory.
+// Checkpoint commands run through sh with $TICKETTOK_AGENT set to the
+// agent's name.
+const defaultCheckpointCommand = `git add -A && git commit -q -m "tickettok: $TICKETTOK_AGENT checkpoint"`
+
+// checkpointTimeout bounds a checkpoint command, so a commit hook that
+// hangs can't pile up runs.
+const checkpointTimeout = 2 * time.Minute
+
+// checkpointDue reports whether a status change should checkpoint an agent:
+// it opted in and has just stopped working.
+func checkpointDue(a *Agent, oldStatus, newStatus AgentStatus) bool {
+	if !a.Checkpoint || a.Discovered {
+		return false
+	}
+	stopped := newStatus == StatusIdle || newStatus == StatusAsk || newStatus == StatusDone
+	wasStopped := oldStatus == StatusIdle || oldStatus == StatusAsk || oldStatus == StatusDone || oldStatus == StatusPending
+	return stopped && !wasStopped
+}
+
+// checkpointMsg reports a checkpoint that ran.
+type checkpointMsg struct {
+	name string
+	out  string // last line of the command's output
+	err  error
+}
+
+// runCheckpoint runs command in dir when dir is a git repository with
+// uncommitted changes. ran is false when there was nothing to save.
+func runCheckpoint(command, name, dir string) (ran bool, out string, err error) {
+	if status, err := git(dir, "status", "--porcelain"); err != nil || status == "" {
+		return false, "", nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), checkpointTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "TICKETTOK_AGENT="+name)
+	data, err := cmd.CombinedOutput()
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	out = lines[len(lines)-1]
+	if err != nil {
+		if out == "" {
+			return true, "", err
+		}
+		return true, "", fmt.Errorf("%w: %s", err, out)
+	}
+	return true, out, nil
+}
+
+// checkpointCmd checkpoints an agent in the background.
+func checkpointCmd(command string, a *Agent) tea.Cmd {
+	name, dir := a.Name, a.Dir
+	return func() tea.Msg {
+		ran, out, err := runCheckpoint(command, name, dir)
+		if !ran {
+			return nil
+		}
+		return checkpointMsg{name: name, out: out, err: err}
+	}
+}
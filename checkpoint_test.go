@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckpointDue(t *testing.T) {
+	on := &Agent{Checkpoint: true}
+	cases := []struct {
+		agent    *Agent
+		old, new AgentStatus
+		want     bool
+	}{
+		{on, StatusRunning, StatusIdle, true},
+		{on, StatusWaiting, StatusDone, true},
+		{on, StatusStuck, StatusIdle, true},
+		{on, StatusIdle, StatusDone, false},    // already stopped
+		{on, StatusPending, StatusIdle, false}, // never worked
+		{on, StatusIdle, StatusRunning, false},
+		{&Agent{}, StatusRunning, StatusIdle, false},
+		{&Agent{Checkpoint: true, Discovered: true}, StatusRunning, StatusIdle, false},
+	}
+	for _, c := range cases {
+		if got := checkpointDue(c.agent, c.old, c.new); got != c.want {
+			t.Errorf("checkpointDue(%+v, %s → %s) = %v, want %v", *c.agent, c.old, c.new, got, c.want)
+		}
+	}
+}
+
+func TestRunCheckpoint(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	t.Setenv("GIT_AUTHOR_NAME", "t")
+	t.Setenv("GIT_AUTHOR_EMAIL", "t@t")
+	t.Setenv("GIT_COMMITTER_NAME", "t")
+	t.Setenv("GIT_COMMITTER_EMAIL", "t@t")
+	if out, err := exec.Command("git", "-C", dir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+
+	if ran, _, _ := runCheckpoint(defaultCheckpointCommand, "api", dir); ran {
+		t.Error("checkpoint ran in a clean repo")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("work\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ran, _, err := runCheckpoint(defaultCheckpointCommand, "api", dir)
+	if !ran || err != nil {
+		t.Fatalf("runCheckpoint() = %v, %v; want ran, nil", ran, err)
+	}
+	msg, _ := git(dir, "log", "-1", "--format=%s")
+	if msg != "tickettok: api checkpoint" {
+		t.Errorf("commit message = %q", msg)
+	}
+	if status, _ := git(dir, "status", "--porcelain"); status != "" {
+		t.Errorf("tree still dirty after checkpoint: %q", status)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("more\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := runCheckpoint("echo nope >&2; exit 3", "api", dir); err == nil || !strings.Contains(err.Error(), "nope") {
+		t.Errorf("failing command error = %v, want its output", err)
+	}
+
+	if ran, _, _ := runCheckpoint(defaultCheckpointCommand, "api", t.TempDir()); ran {
+		t.Error("checkpoint ran outside a git repo")
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardCommand picks a system clipboard tool, or nil when none is
+// installed. lookPath is exec.LookPath, injectable for tests.
+func clipboardCommand(goos string, lookPath func(string) (string, error)) []string {
+	candidates := [][]string{
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	}
+	if goos == "darwin" {
+		candidates = [][]string{{"pbcopy"}}
+	}
+	for _, c := range candidates {
+		if _, err := lookPath(c[0]); err == nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// osc52 wraps text in an OSC 52 "set clipboard" escape. Inside tmux the
+// sequence is wrapped in a DCS passthrough so it reaches the outer terminal.
+func osc52(text string, inTmux bool) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	if inTmux {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	return seq
+}
+
+// copyToClipboard puts text on the system clipboard, falling back to OSC 52
+// (which works over SSH) when no clipboard tool is installed. It returns the
+// mechanism used.
+func copyToClipboard(text string) (string, error) {
+	if c := clipboardCommand(runtime.GOOS, exec.LookPath); c != nil {
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return c[0], fmt.Errorf("%s: %w", c[0], err)
+		}
+		return c[0], nil
+	}
+	if _, err := os.Stdout.WriteString(osc52(text, os.Getenv("TMUX") != "")); err != nil {
+		return "OSC 52", err
+	}
+	return "OSC 52", nil
+}
+
+// selectLines returns lines from..to (inclusive, either order) of content as
+// plain text, without ANSI escapes or trailing padding.
+func selectLines(content string, from, to int) string {
+	if from > to {
+		from, to = to, from
+	}
+	lines := strings.Split(content, "\n")
+	if from < 0 {
+		from = 0
+	}
+	if to >= len(lines) {
+		to = len(lines) - 1
+	}
+	if from > to {
+		return ""
+	}
+	out := make([]string, 0, to-from+1)
+	for _, l := range lines[from : to+1] {
+		out = append(out, strings.TrimRight(stripAnsiStr(l), " "))
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClipboardCommand(t *testing.T) {
+	only := func(names ...string) func(string) (string, error) {
+		return func(file string) (string, error) {
+			for _, n := range names {
+				if n == file {
+					return "/usr/bin/" + file, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	tests := []struct {
+		name      string
+		goos      string
+		installed []string
+		want      []string
+	}{
+		{"mac", "darwin", []string{"pbcopy", "xclip"}, []string{"pbcopy"}},
+		{"wayland preferred", "linux", []string{"xclip", "wl-copy"}, []string{"wl-copy"}},
+		{"xclip", "linux", []string{"xclip", "xsel"}, []string{"xclip", "-selection", "clipboard"}},
+		{"xsel", "linux", []string{"xsel"}, []string{"xsel", "--clipboard", "--input"}},
+		{"none", "linux", nil, nil},
+	}
+	for _, tt := range tests {
+		if got := clipboardCommand(tt.goos, only(tt.installed...)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOSC52(t *testing.T) {
+	enc := base64.StdEncoding.EncodeToString([]byte("hi"))
+
+	if got, want := osc52("hi", false), "\x1b]52;c;"+enc+"\a"; got != want {
+		t.Errorf("osc52 = %q, want %q", got, want)
+	}
+	got := osc52("hi", true)
+	if !strings.HasPrefix(got, "\x1bPtmux;\x1b\x1b]52;c;") || !strings.HasSuffix(got, "\x1b\\") {
+		t.Errorf("tmux passthrough not applied: %q", got)
+	}
+}
+
+func TestSelectLines(t *testing.T) {
+	content := "zero\n\x1b[32mone\x1b[0m   \ntwo\nthree"
+
+	if got := selectLines(content, 1, 2); got != "one\ntwo" {
+		t.Errorf("selectLines(1,2) = %q", got)
+	}
+	if got := selectLines(content, 2, 1); got != "one\ntwo" {
+		t.Errorf("reversed range should match, got %q", got)
+	}
+	if got := selectLines(content, 3, 10); got != "three" {
+		t.Errorf("out-of-range end should clamp, got %q", got)
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sns45/tickettok/ui"
+)
+
+// Config holds optional user settings from ~/.tickettok/config.json.
+type Config struct {
+	// Columns replaces the default 3-column board when non-empty.
+	Columns []ColumnConfig `json:"columns,omitempty"`
+
+	// Theme names a built-in palette: dark (default), light, or solarized.
+	Theme string `json:"theme,omitempty"`
+	// ThemeColors overrides individual theme colors with hex values,
+	// keyed by role (e.g. "accent", "running", "text").
+	ThemeColors map[string]string `json:"theme_colors,omitempty"`
+
+	// Alerts controls how the TUI gets your attention when agents stall.
+	Alerts AlertConfig `json:"alerts"`
+
+	// StaleMinutes flags agents RUNNING longer than this in the status
+	// strip, since long-silent agents are usually stuck. 0 turns it off.
+	StaleMinutes int `json:"stale_minutes,omitempty"`
+
+	// OnQuit picks what quitting does to managed tmux sessions: detach
+	// (default, leave them running), kill, or ask every time.
+	OnQuit string `json:"on_quit,omitempty"`
+
+	// ClaudeStreamJSON launches Claude Code agents that are given a task
+	// in print mode with a stream-json event log, which tickettok reads
+	// for status, current tool and tokens instead of scraping the pane.
+	ClaudeStreamJSON bool `json:"claude_stream_json,omitempty"`
+
+	// MaxRunning caps how many agents may be RUNNING at once. Spawns past
+	// it wait as PENDING and start as agents finish. 0 means no limit.
+	MaxRunning int `json:"max_running,omitempty"`
+
+	// CheckpointCommand runs through sh in an agent's directory when an
+	// agent with checkpoints on goes IDLE or DONE with uncommitted changes.
+	// $TICKETTOK_AGENT holds the agent's name. Empty means a git commit of
+	// everything (see defaultCheckpointCommand).
+	CheckpointCommand string `json:"checkpoint_command,omitempty"`
+
+	// OnTimeout picks what happens to an agent still working past its
+	// timeout, besides going TIMED-OUT: notify (default, nothing more),
+	// interrupt (send Escape), or kill its session.
+	OnTimeout string `json:"on_timeout,omitempty"`
+
+	// IdleShutdown stops managed agents left IDLE for hours.
+	IdleShutdown IdleShutdownConfig `json:"idle_shutdown"`
+
+	// ApprovalRules answer permission prompts of every managed agent,
+	// after the agent's own rules; see parseRule for the syntax.
+	ApprovalRules []string `json:"approval_rules,omitempty"`
+
+	// ThrottleResume is typed into a THROTTLED agent when its rate limit
+	// resets, e.g. "continue". Empty leaves the agent for you.
+	ThrottleResume string `json:"throttle_resume,omitempty"`
+
+	// Transcripts is the directory killed and cleared agents' scrollback
+	// is saved to, ~/.tickettok/transcripts if empty. "off" keeps nothing.
+	Transcripts string `json:"transcripts,omitempty"`
+
+	// MemoryWarnMB is the memory use, in MiB, past which an agent's card
+	// warns. 0 means defaultMemoryWarnMB; negative turns the warning off.
+	MemoryWarnMB int `json:"memory_warn_mb,omitempty"`
+
+	// Lifecycle runs shell commands of the user's on agent events, like
+	// spawning and going DONE.
+	Lifecycle LifecycleConfig `json:"lifecycle"`
+
+	// Validation maps project directories to a command checking an
+	// agent's work each time it goes IDLE, like "go test ./...".
+	Validation map[string]Validation `json:"validation,omitempty"`
+}
+
+// Quit actions for Config.OnQuit.
+const (
+	QuitDetach = "detach"
+	QuitKill   = "kill"
+	QuitAsk    = "ask"
+)
+
+// QuitAction returns the configured quit action, defaulting to detach.
+func (c Config) QuitAction() string {
+	if c.OnQuit == "" {
+		return QuitDetach
+	}
+	return strings.ToLower(c.OnQuit)
+}
+
+// Checkpoint returns the configured checkpoint command, defaulting to
+// committing everything.
+func (c Config) Checkpoint() string {
+	if strings.TrimSpace(c.CheckpointCommand) == "" {
+		return defaultCheckpointCommand
+	}
+	return c.CheckpointCommand
+}
+
+// TimeoutAction returns the configured timeout action, defaulting to
+// notify.
+func (c Config) TimeoutAction() string {
+	if c.OnTimeout == "" {
+		return TimeoutNotify
+	}
+	return strings.ToLower(c.OnTimeout)
+}
+
+// MemoryWarn returns the memory use in bytes past which a card warns, 0
+// when the warning is off.
+func (c Config) MemoryWarn() int64 {
+	switch {
+	case c.MemoryWarnMB < 0:
+		return 0
+	case c.MemoryWarnMB == 0:
+		return defaultMemoryWarnMB << 20
+	}
+	return int64(c.MemoryWarnMB) << 20
+}
+
+// Rules returns the global approval rules. validate has already
+// rejected any that don't parse.
+func (c Config) Rules() []ApprovalRule {
+	rules, _ := parseRules(c.ApprovalRules)
+	return rules
+}
+
+// TranscriptDir returns where agent output is archived, "" when archiving
+// is off.
+func (c Config) TranscriptDir() string {
+	switch dir := strings.TrimSpace(c.Transcripts); {
+	case dir == "":
+		return defaultTranscriptDir()
+	case strings.EqualFold(dir, "off"):
+		return ""
+	case strings.HasPrefix(dir, "~/"):
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, dir[2:])
+	default:
+		return dir
+	}
+}
+
+// IdleShutdownConfig kills or detaches the sessions of agents that sit
+// IDLE too long, after a warning on their card.
+type IdleShutdownConfig struct {
+	// Hours an agent may stay IDLE; 0 (the default) turns the policy off.
+	Hours float64 `json:"hours,omitempty"`
+	// Action is kill (default: the agent stays on the board as DONE and
+	// resumes on zoom) or detach (the session keeps running in tmux).
+	Action string `json:"action,omitempty"`
+	// WarnMinutes is how long before the shutdown the card warns; 30 if unset.
+	WarnMinutes int `json:"warn_minutes,omitempty"`
+}
+
+// After returns how long an agent may stay IDLE, 0 when the policy is off.
+func (c IdleShutdownConfig) After() time.Duration {
+	return time.Duration(c.Hours * float64(time.Hour))
+}
+
+// Warn returns the warning period, defaulting to 30 minutes.
+func (c IdleShutdownConfig) Warn() time.Duration {
+	if c.WarnMinutes == 0 {
+		return defaultIdleWarn
+	}
+	return time.Duration(c.WarnMinutes) * time.Minute
+}
+
+// IdleAction returns the configured action, defaulting to kill.
+func (c IdleShutdownConfig) IdleAction() string {
+	if c.Action == "" {
+		return IdleKill
+	}
+	return strings.ToLower(c.Action)
+}
+
+// AlertConfig selects the alerts fired when an agent starts waiting for input.
+type AlertConfig struct {
+	// Bell rings the terminal bell; on unless set to false.
+	Bell *bool `json:"bell,omitempty"`
+	// Say speaks the waiting agent's name (say on macOS, spd-say or espeak
+	// on Linux).
+	Say bool `json:"say,omitempty"`
+}
+
+// BellEnabled reports whether the terminal bell should ring.
+func (a AlertConfig) BellEnabled() bool {
+	return a.Bell == nil || *a.Bell
+}
+
+// ColumnConfig defines a custom board column. Agents whose status is listed
+// in Statuses land here; a column with no statuses only holds agents that are
+// explicitly placed in it.
+type ColumnConfig struct {
+	Name     string        `json:"name"`
+	Statuses []AgentStatus `json:"statuses,omitempty"`
+	Color    string        `json:"color,omitempty"` // hex, e.g. "#eab308"
+}
+
+func configPath() string {
+	return filepath.Join(stateDir(), "config.json")
+}
+
+// loadConfig reads the config file. A missing file yields an empty Config.
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+	}
+	return cfg, cfg.validate()
+}
+
+func (c Config) validate() error {
+	if _, err := c.UITheme(); err != nil {
+		return err
+	}
+	if c.StaleMinutes < 0 {
+		return fmt.Errorf("stale_minutes must not be negative")
+	}
+	if c.MaxRunning < 0 {
+		return fmt.Errorf("max_running must not be negative")
+	}
+	switch c.QuitAction() {
+	case QuitDetach, QuitKill, QuitAsk:
+	default:
+		return fmt.Errorf("unknown on_quit %q (want detach, kill, or ask)", c.OnQuit)
+	}
+	switch c.TimeoutAction() {
+	case TimeoutNotify, TimeoutInterrupt, TimeoutKill:
+	default:
+		return fmt.Errorf("unknown on_timeout %q (want notify, interrupt, or kill)", c.OnTimeout)
+	}
+	if c.IdleShutdown.Hours < 0 || c.IdleShutdown.WarnMinutes < 0 {
+		return fmt.Errorf("idle_shutdown hours and warn_minutes must not be negative")
+	}
+	if _, err := parseRules(c.ApprovalRules); err != nil {
+		return fmt.Errorf("approval_rules: %w", err)
+	}
+	switch c.IdleShutdown.IdleAction() {
+	case IdleKill, IdleDetach:
+	default:
+		return fmt.Errorf("unknown idle_shutdown action %q (want kill or detach)", c.IdleShutdown.Action)
+	}
+	seen := make(map[string]bool)
+	for i, col := range c.Columns {
+		name := strings.ToUpper(strings.TrimSpace(col.Name))
+		if name == "" {
+			return fmt.Errorf("column %d has no name", i+1)
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate column %q", col.Name)
+		}
+		seen[name] = true
+		for _, s := range col.Statuses {
+			if _, ok := ParseStatus(string(s)); !ok {
+				return fmt.Errorf("column %q: unknown status %q", col.Name, s)
+			}
+		}
+	}
+	return nil
+}
+
+// UITheme resolves the configured theme name and color overrides.
+func (c Config) UITheme() (ui.Theme, error) {
+	theme := ui.DarkTheme
+	if c.Theme != "" {
+		t, ok := ui.Themes[strings.ToLower(c.Theme)]
+		if !ok {
+			return theme, fmt.Errorf("unknown theme %q (want dark, light, or solarized)", c.Theme)
+		}
+		theme = t
+	}
+	return theme.WithOverrides(c.ThemeColors)
+}
+
+// Layout converts the configured columns into a board layout, or nil when
+// none are configured.
+func (c Config) Layout() []ui.Column {
+	if len(c.Columns) == 0 {
+		return nil
+	}
+	layout := make([]ui.Column, len(c.Columns))
+	for i, col := range c.Columns {
+		color := ui.PaletteColor(i)
+		if col.Color != "" {
+			color = lipgloss.Color(col.Color)
+		}
+		statuses := make([]string, len(col.Statuses))
+		for j, s := range col.Statuses {
+			statuses[j] = strings.ToUpper(string(s))
+		}
+		layout[i] = ui.Column{
+			Title:    strings.ToUpper(strings.TrimSpace(col.Name)),
+			Color:    color,
+			Statuses: statuses,
+		}
+	}
+	return layout
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sns45/tickettok/ui"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("alerts", func(t *testing.T) {
+		path := filepath.Join(dir, "alerts.json")
+		os.WriteFile(path, []byte(`{"alerts": {"bell": false, "say": true}}`), 0644)
+
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatalf("loadConfig() error: %v", err)
+		}
+		if cfg.Alerts.BellEnabled() || !cfg.Alerts.Say {
+			t.Errorf("Alerts = %+v, want bell off and say on", cfg.Alerts)
+		}
+		if !(AlertConfig{}).BellEnabled() {
+			t.Error("bell should default to on")
+		}
+	})
+
+	t.Run("stale threshold", func(t *testing.T) {
+		path := filepath.Join(dir, "stale.json")
+		os.WriteFile(path, []byte(`{"stale_minutes": 45}`), 0644)
+		cfg, err := loadConfig(path)
+		if err != nil || cfg.StaleMinutes != 45 {
+			t.Errorf("loadConfig() = %d, %v; want 45, nil", cfg.StaleMinutes, err)
+		}
+
+		os.WriteFile(path, []byte(`{"stale_minutes": -1}`), 0644)
+		if _, err := loadConfig(path); err == nil {
+			t.Error("negative stale_minutes should be rejected")
+		}
+	})
+
+	t.Run("max running", func(t *testing.T) {
+		path := filepath.Join(dir, "max.json")
+		os.WriteFile(path, []byte(`{"max_running": 3}`), 0644)
+		cfg, err := loadConfig(path)
+		if err != nil || cfg.MaxRunning != 3 {
+			t.Errorf("loadConfig() = %d, %v; want 3, nil", cfg.MaxRunning, err)
+		}
+
+		os.WriteFile(path, []byte(`{"max_running": -2}`), 0644)
+		if _, err := loadConfig(path); err == nil {
+			t.Error("negative max_running should be rejected")
+		}
+	})
+
+	t.Run("checkpoint command", func(t *testing.T) {
+		if got := (Config{}).Checkpoint(); got != defaultCheckpointCommand {
+			t.Errorf("default Checkpoint() = %q", got)
+		}
+		if got := (Config{CheckpointCommand: "make save"}).Checkpoint(); got != "make save" {
+			t.Errorf("Checkpoint() = %q, want make save", got)
+		}
+	})
+
+	t.Run("quit action", func(t *testing.T) {
+		if got := (Config{}).QuitAction(); got != QuitDetach {
+			t.Errorf("default QuitAction() = %q, want detach", got)
+		}
+		path := filepath.Join(dir, "quit.json")
+		os.WriteFile(path, []byte(`{"on_quit": "Kill"}`), 0644)
+		cfg, err := loadConfig(path)
+		if err != nil || cfg.QuitAction() != QuitKill {
+			t.Errorf("loadConfig() = %q, %v; want kill, nil", cfg.QuitAction(), err)
+		}
+
+		os.WriteFile(path, []byte(`{"on_quit": "explode"}`), 0644)
+		if _, err := loadConfig(path); err == nil {
+			t.Error("unknown on_quit should be rejected")
+		}
+	})
+
+	t.Run("timeout action", func(t *testing.T) {
+		if got := (Config{}).TimeoutAction(); got != TimeoutNotify {
+			t.Errorf("default TimeoutAction() = %q, want notify", got)
+		}
+		path := filepath.Join(dir, "timeout.json")
+		os.WriteFile(path, []byte(`{"on_timeout": "Interrupt"}`), 0644)
+		cfg, err := loadConfig(path)
+		if err != nil || cfg.TimeoutAction() != TimeoutInterrupt {
+			t.Errorf("loadConfig() = %q, %v; want interrupt, nil", cfg.TimeoutAction(), err)
+		}
+
+		os.WriteFile(path, []byte(`{"on_timeout": "explode"}`), 0644)
+		if _, err := loadConfig(path); err == nil {
+			t.Error("unknown on_timeout should be rejected")
+		}
+	})
+
+	t.Run("idle shutdown", func(t *testing.T) {
+		var off IdleShutdownConfig
+		if off.After() != 0 || off.Warn() != defaultIdleWarn || off.IdleAction() != IdleKill {
+			t.Errorf("defaults = %v, %v, %q", off.After(), off.Warn(), off.IdleAction())
+		}
+		path := filepath.Join(dir, "idle.json")
+		os.WriteFile(path, []byte(`{"idle_shutdown": {"hours": 1.5, "action": "Detach", "warn_minutes": 10}}`), 0644)
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := cfg.IdleShutdown; got.After() != 90*time.Minute || got.Warn() != 10*time.Minute || got.IdleAction() != IdleDetach {
+			t.Errorf("loaded = %v, %v, %q", got.After(), got.Warn(), got.IdleAction())
+		}
+
+		os.WriteFile(path, []byte(`{"idle_shutdown": {"hours": 8, "action": "explode"}}`), 0644)
+		if _, err := loadConfig(path); err == nil {
+			t.Error("unknown idle_shutdown action should be rejected")
+		}
+	})
+
+	t.Run("approval rules", func(t *testing.T) {
+		path := filepath.Join(dir, "rules.json")
+		os.WriteFile(path, []byte(`{"approval_rules": ["deny Bash rm", "approve Read"]}`), 0644)
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rules := cfg.Rules(); len(rules) != 2 || rules[0].Action != RuleDeny || rules[1].Tool != "Read" {
+			t.Errorf("Rules() = %+v", rules)
+		}
+
+		os.WriteFile(path, []byte(`{"approval_rules": ["allow Read"]}`), 0644)
+		if _, err := loadConfig(path); err == nil {
+			t.Error("a bad approval rule should be rejected")
+		}
+	})
+
+	t.Run("missing file is empty config", func(t *testing.T) {
+		cfg, err := loadConfig(filepath.Join(dir, "nope.json"))
+		if err != nil || len(cfg.Columns) != 0 {
+			t.Errorf("loadConfig(missing) = %+v, %v; want empty, nil", cfg, err)
+		}
+	})
+
+	t.Run("custom columns", func(t *testing.T) {
+		path := filepath.Join(dir, "config.json")
+		os.WriteFile(path, []byte(`{"columns": [
+			{"name": "todo", "statuses": ["IDLE"]},
+			{"name": "Blocked", "statuses": ["WAITING", "STUCK"], "color": "#eab308"},
+			{"name": "doing", "statuses": ["running"]},
+			{"name": "review"}
+		]}`), 0644)
+
+		cfg, err := loadConfig(path)
+		if err != nil {
+			t.Fatalf("loadConfig() error: %v", err)
+		}
+		layout := cfg.Layout()
+		if len(layout) != 4 {
+			t.Fatalf("Layout() has %d columns, want 4", len(layout))
+		}
+		if layout[0].Title != "TODO" || layout[1].Color != "#eab308" {
+			t.Errorf("Layout() = %+v", layout)
+		}
+		if got := ui.ColumnIndex(layout, "RUNNING"); got != 2 {
+			t.Errorf("RUNNING column = %d, want 2", got)
+		}
+		// DONE isn't mapped anywhere, so it falls back to the first column
+		if got := ui.ColumnIndex(layout, "DONE"); got != 0 {
+			t.Errorf("DONE column = %d, want 0", got)
+		}
+	})
+
+	t.Run("rejects bad config", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"unknown status": `{"columns": [{"name": "x", "statuses": ["SLEEPING"]}]}`,
+			"duplicate":      `{"columns": [{"name": "x"}, {"name": "X"}]}`,
+			"unnamed":        `{"columns": [{"statuses": ["IDLE"]}]}`,
+			"not json":       `{`,
+		} {
+			path := filepath.Join(dir, "bad.json")
+			os.WriteFile(path, []byte(body), 0644)
+			if _, err := loadConfig(path); err == nil {
+				t.Errorf("loadConfig(%s) should fail", name)
+			}
+		}
+	})
+}
+
+func TestColumnForStatusCustomLayout(t *testing.T) {
+	custom := []ui.Column{
+		{Title: "TODO", Statuses: []string{"IDLE"}},
+		{Title: "REVIEW", Statuses: []string{"DONE"}},
+		{Title: "DOING", Statuses: []string{"RUNNING", "WAITING"}},
+	}
+	m := &Model{columns: 3, customColumns: custom}
+	if got := m.columnForStatus(StatusDone); got != 1 {
+		t.Errorf("columnForStatus(DONE) = %d, want 1", got)
+	}
+
+	// 2-column mode keeps the built-in IDLE/ACTIVE split
+	m.columns = 2
+	if got := m.columnForStatus(StatusDone); got != 0 {
+		t.Errorf("2-col columnForStatus(DONE) = %d, want 0", got)
+	}
+}
+
+func TestConfigTheme(t *testing.T) {
+	got, err := Config{}.UITheme()
+	if err != nil || got.Name != "dark" {
+		t.Errorf("default UITheme() = %q, %v; want dark", got.Name, err)
+	}
+
+	got, err = Config{Theme: "Light", ThemeColors: map[string]string{"accent": "#ff00ff"}}.UITheme()
+	if err != nil {
+		t.Fatalf("UITheme() error: %v", err)
+	}
+	if got.Name != "light" || got.Accent != "#ff00ff" || got.Running != ui.LightTheme.Running {
+		t.Errorf("UITheme() = %+v, want light with accent override", got)
+	}
+
+	bad := []Config{
+		{Theme: "neon"},
+		{ThemeColors: map[string]string{"sparkle": "#ffffff"}},
+		{ThemeColors: map[string]string{"accent": "magenta"}},
+	}
+	for _, c := range bad {
+		if err := c.validate(); err == nil {
+			t.Errorf("validate(%+v) should fail", c)
+		}
+	}
+}
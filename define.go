@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// An agent definition is an agent saved under a name to be spawned again
+// and again with `tickettok up <name>`, the way a compose file's services
+// are brought up: where it works, on which backend, its task and how it's
+// set up. Definitions are shared by every board, like workspaces.
+
+// AgentDefinition is a named agent to spawn on demand.
+type AgentDefinition struct {
+	Name        string    `json:"name"`
+	Dir         string    `json:"dir"`
+	BackendID   string    `json:"backend,omitempty"`
+	Prompt      string    `json:"prompt,omitempty"` // template: see expandPrompt
+	Tags        []string  `json:"tags,omitempty"`
+	Rules       []string  `json:"rules,omitempty"` // approval rules
+	AutoApprove bool      `json:"auto_approve,omitempty"`
+	KeepAlive   bool      `json:"keep_alive,omitempty"`
+	Checkpoint  bool      `json:"checkpoint,omitempty"`
+	Branch      bool      `json:"branch,omitempty"`
+	Worktree    bool      `json:"worktree,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func definitionDir() string {
+	return filepath.Join(stateDir(), "definitions")
+}
+
+func definitionPath(name string) string {
+	return filepath.Join(definitionDir(), name+".json")
+}
+
+// checkDefinitionName rejects names that aren't safe as a file name, by
+// the same rule as board names.
+func checkDefinitionName(name string) error {
+	if !boardNameRe.MatchString(name) {
+		return fmt.Errorf("invalid definition name %q: use letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// SaveDefinition writes a definition, replacing any of the same name.
+func SaveDefinition(d *AgentDefinition) error {
+	if err := checkDefinitionName(d.Name); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(definitionDir(), 0755); err != nil {
+		return fmt.Errorf("create definitions dir: %w", err)
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal definition: %w", err)
+	}
+	return os.WriteFile(definitionPath(d.Name), data, 0644)
+}
+
+// LoadDefinition reads a saved definition.
+func LoadDefinition(name string) (*AgentDefinition, error) {
+	if err := checkDefinitionName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(definitionPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no agent definition %q (see tickettok define list)", name)
+		}
+		return nil, fmt.Errorf("read definition %q: %w", name, err)
+	}
+	var d AgentDefinition
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("parse definition %q: %w", name, err)
+	}
+	d.Name = name
+	return &d, nil
+}
+
+// ListDefinitions returns the sorted names of the saved definitions.
+func ListDefinitions() ([]string, error) {
+	entries, err := os.ReadDir(definitionDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DeleteDefinition removes a saved definition.
+func DeleteDefinition(name string) error {
+	if err := checkDefinitionName(name); err != nil {
+		return err
+	}
+	return os.Remove(definitionPath(name))
+}
+
+// definitionFromAgent makes a definition of a live agent: its original
+// task becomes the prompt, and a worktree agent's repo its dir, so each
+// spawn gets a fresh worktree.
+func definitionFromAgent(name string, a *Agent) *AgentDefinition {
+	d := &AgentDefinition{
+		Name:        name,
+		Dir:         a.Dir,
+		BackendID:   a.BackendID,
+		Prompt:      a.Prompt,
+		Tags:        append([]string(nil), a.Tags...),
+		Rules:       append([]string(nil), a.Rules...),
+		AutoApprove: a.AutoApprove,
+		KeepAlive:   a.KeepAlive,
+		Checkpoint:  a.Checkpoint,
+		Branch:      a.Branch != "" && a.Worktree == nil,
+	}
+	if a.Worktree != nil {
+		d.Dir = worktreeSourceDir(a)
+		d.Worktree = true
+	}
+	return d
+}
+
+// Placeholders in a definition's prompt: {{key}}, spaces allowed inside
+var promptVarRe = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_-]+)\s*\}\}`)
+
+// expandPrompt fills in a prompt template's {{key}} placeholders from
+// vars, naming every one left without a value.
+func expandPrompt(tmpl string, vars map[string]string) (string, error) {
+	var missing []string
+	out := promptVarRe.ReplaceAllStringFunc(tmpl, func(m string) string {
+		key := promptVarRe.FindStringSubmatch(m)[1]
+		v, ok := vars[key]
+		if !ok {
+			missing = append(missing, key)
+			return m
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("no value for %s (pass --set %s=...)", strings.Join(missing, ", "), missing[0])
+	}
+	return out, nil
+}
+
+// promptVars lists a prompt template's placeholders, once each.
+func promptVars(tmpl string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, m := range promptVarRe.FindAllStringSubmatch(tmpl, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			out = append(out, m[1])
+		}
+	}
+	return out
+}
+
+// template turns the definition into the workspace template to spawn,
+// its prompt filled in from vars on top of {{name}} and {{dir}}.
+func (d *AgentDefinition) template(vars map[string]string) (WorkspaceAgent, error) {
+	all := map[string]string{"name": d.Name, "dir": d.Dir}
+	for k, v := range vars {
+		all[k] = v
+	}
+	prompt, err := expandPrompt(d.Prompt, all)
+	if err != nil {
+		return WorkspaceAgent{}, fmt.Errorf("%s: %w", d.Name, err)
+	}
+	return WorkspaceAgent{
+		Name:        d.Name,
+		Dir:         d.Dir,
+		BackendID:   d.BackendID,
+		AutoApprove: d.AutoApprove,
+		KeepAlive:   d.KeepAlive,
+		Checkpoint:  d.Checkpoint,
+		Branch:      d.Branch && !d.Worktree,
+		Worktree:    d.Worktree,
+		Prompt:      prompt,
+		Tags:        d.Tags,
+		Rules:       d.Rules,
+	}, nil
+}
+
+// parseSetFlag splits a --set key=value.
+func parseSetFlag(s string) (string, string, error) {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || !boardNameRe.MatchString(k) {
+		return "", "", fmt.Errorf("--set wants key=value, got %q", s)
+	}
+	return k, v, nil
+}
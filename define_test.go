@@ -0,0 +1,85 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandPrompt(t *testing.T) {
+	vars := map[string]string{"name": "api-fixer", "issue": "412"}
+	got, err := expandPrompt("{{name}}: fix #{{ issue }}, then #{{issue}} again", vars)
+	if err != nil || got != "api-fixer: fix #412, then #412 again" {
+		t.Errorf("expandPrompt = %q, %v", got, err)
+	}
+	if got, err := expandPrompt("no placeholders {here}", nil); err != nil || got != "no placeholders {here}" {
+		t.Errorf("expandPrompt without placeholders = %q, %v", got, err)
+	}
+	_, err = expandPrompt("fix #{{issue}} in {{area}}", map[string]string{"issue": "1"})
+	if err == nil || !strings.Contains(err.Error(), "area") || strings.Contains(err.Error(), "issue") {
+		t.Errorf("expandPrompt with a missing value: %v", err)
+	}
+	if got := promptVars("{{a}} {{ b }} {{a}}"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("promptVars = %v", got)
+	}
+}
+
+func TestDefinitionRoundTrip(t *testing.T) {
+	t.Setenv(stateDirEnv, t.TempDir())
+
+	d := &AgentDefinition{
+		Name:        "api-fixer",
+		Dir:         "/srv/api",
+		BackendID:   "codex",
+		Prompt:      "Fix #{{issue}} in {{dir}}",
+		Tags:        []string{"api"},
+		Rules:       []string{"approve Read"},
+		AutoApprove: true,
+		Worktree:    true,
+	}
+	if err := SaveDefinition(d); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveDefinition(&AgentDefinition{Name: "../escape"}); err == nil {
+		t.Error("saved a definition named ../escape")
+	}
+	if names, err := ListDefinitions(); err != nil || !reflect.DeepEqual(names, []string{"api-fixer"}) {
+		t.Errorf("ListDefinitions = %v, %v", names, err)
+	}
+
+	loaded, err := LoadDefinition("api-fixer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loaded.template(nil); err == nil {
+		t.Error("template without --set issue succeeded")
+	}
+	got, err := loaded.template(map[string]string{"issue": "412"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := WorkspaceAgent{Name: "api-fixer", Dir: "/srv/api", BackendID: "codex", AutoApprove: true, Worktree: true,
+		Prompt: "Fix #412 in /srv/api", Tags: []string{"api"}, Rules: []string{"approve Read"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("template = %+v, want %+v", got, want)
+	}
+
+	if err := DeleteDefinition("api-fixer"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDefinition("api-fixer"); err == nil {
+		t.Error("loaded a deleted definition")
+	}
+}
+
+func TestDefinitionFromAgent(t *testing.T) {
+	a := &Agent{Name: "app", Dir: "/srv/app", BackendID: "gemini", Prompt: "Add /export", Tags: []string{"web"}, Rules: []string{"deny Bash rm"}, KeepAlive: true, Branch: "tickettok/app-1"}
+	d := definitionFromAgent("exporter", a)
+	if d.Name != "exporter" || d.Dir != "/srv/app" || d.BackendID != "gemini" || d.Prompt != "Add /export" || !d.Branch || d.Worktree || !d.KeepAlive {
+		t.Errorf("definitionFromAgent = %+v", d)
+	}
+	d.Tags[0] = "changed"
+	if a.Tags[0] != "web" {
+		t.Error("definition shares the agent's tags")
+	}
+}
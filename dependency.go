@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// promptStartupDelay is how long SendPromptAfterDelay gives an agent's CLI
+// to start before typing the initial prompt.
+const promptStartupDelay = 4 * time.Second
+
+// dependencyReady reports whether agents waiting on a can start: a has
+// finished — DONE, or back at IDLE after running since its task was sent.
+// An agent that hasn't had its own task yet is never ready.
+func dependencyReady(a *Agent) bool {
+	if a.After != "" {
+		return false
+	}
+	switch a.Status {
+	case StatusDone:
+		return true
+	case StatusIdle:
+		for _, h := range a.History {
+			if h.Status == StatusRunning && h.At.After(a.PromptAt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dueDependents splits the agents waiting on another into those that can
+// have their task sent now and those whose dependency is gone from the
+// board.
+func dueDependents(agents []*Agent) (ready, orphaned []*Agent) {
+	byID := make(map[string]*Agent, len(agents))
+	for _, a := range agents {
+		byID[a.ID] = a
+	}
+	for _, a := range agents {
+		// A queued agent gets its task once it has started
+		if a.After == "" || a.Status == StatusPending {
+			continue
+		}
+		dep, ok := byID[a.After]
+		switch {
+		case !ok:
+			orphaned = append(orphaned, a)
+		case dependencyReady(dep):
+			ready = append(ready, a)
+		}
+	}
+	return ready, orphaned
+}
+
+// dependencyLine describes an agent's place in the chains still pending
+// for its card: "waiting for api; then tests, docs", or "" when it's in
+// none.
+func dependencyLine(a *Agent, agents []*Agent) string {
+	var parts []string
+	var then []string
+	for _, o := range agents {
+		if a.After != "" && o.ID == a.After {
+			parts = append(parts, "waiting for "+o.Name)
+		}
+		if o.After == a.ID {
+			then = append(then, o.Name)
+		}
+	}
+	if len(then) > 0 {
+		parts = append(parts, "then "+strings.Join(then, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// resolveAfter maps each workspace template's After (another template's
+// name) to the index of that template. Unknown names and cycles are
+// reported as errors and left out, so nothing waits forever.
+func resolveAfter(templates []WorkspaceAgent) (map[int]int, []error) {
+	byName := make(map[string]int, len(templates))
+	for i, t := range templates {
+		byName[t.Name] = i
+	}
+	deps := make(map[int]int)
+	var errs []error
+	for i, t := range templates {
+		if t.After == "" {
+			continue
+		}
+		j, ok := byName[t.After]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: after %q: no such agent in the workspace", t.Name, t.After))
+			continue
+		}
+		deps[i] = j
+	}
+	// Each template has at most one dependency, so i is on a cycle when
+	// following the chain from it comes back to it
+	var cyclic []int
+	for i := range templates {
+		j, ok := deps[i]
+		for n := 0; ok && j != i && n < len(templates); n++ {
+			j, ok = deps[j]
+		}
+		if ok && j == i {
+			cyclic = append(cyclic, i)
+		}
+	}
+	for _, i := range cyclic {
+		errs = append(errs, fmt.Errorf("%s: after %q: dependency cycle", templates[i].Name, templates[deps[i]].Name))
+		delete(deps, i)
+	}
+	return deps, errs
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDependencyReady(t *testing.T) {
+	sent := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		agent Agent
+		want  bool
+	}{
+		{"done", Agent{Status: StatusDone}, true},
+		{"running", Agent{Status: StatusRunning, PromptAt: sent}, false},
+		{"idle before its task", Agent{Status: StatusIdle, PromptAt: sent}, false},
+		{"idle after working", Agent{Status: StatusIdle, PromptAt: sent, History: []StatusChange{
+			{StatusIdle, sent.Add(-time.Second)},
+			{StatusRunning, sent.Add(time.Second)},
+			{StatusIdle, sent.Add(time.Minute)},
+		}}, true},
+		{"idle, ran only before its task", Agent{Status: StatusIdle, PromptAt: sent, History: []StatusChange{
+			{StatusRunning, sent.Add(-time.Minute)},
+			{StatusIdle, sent.Add(-time.Second)},
+		}}, false},
+		{"itself waiting", Agent{Status: StatusDone, After: "1"}, false},
+	}
+	for _, tt := range tests {
+		if got := dependencyReady(&tt.agent); got != tt.want {
+			t.Errorf("%s: dependencyReady() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDueDependents(t *testing.T) {
+	agents := []*Agent{
+		{ID: "1", Name: "api", Status: StatusDone},
+		{ID: "2", Name: "tests", After: "1"},
+		{ID: "3", Name: "docs", After: "2"},
+		{ID: "4", Name: "deploy", After: "9"},
+	}
+	ready, orphaned := dueDependents(agents)
+	if len(ready) != 1 || ready[0].Name != "tests" {
+		t.Errorf("ready = %v, want only tests (docs waits on a waiting agent)", names(ready))
+	}
+	if len(orphaned) != 1 || orphaned[0].Name != "deploy" {
+		t.Errorf("orphaned = %v, want deploy", names(orphaned))
+	}
+}
+
+func TestDependencyLine(t *testing.T) {
+	agents := []*Agent{
+		{ID: "1", Name: "api"},
+		{ID: "2", Name: "tests", After: "1"},
+		{ID: "3", Name: "docs", After: "1"},
+		{ID: "4", Name: "lint"},
+	}
+	if got := dependencyLine(agents[0], agents); got != "then tests, docs" {
+		t.Errorf("api: got %q", got)
+	}
+	if got := dependencyLine(agents[1], agents); got != "waiting for api" {
+		t.Errorf("tests: got %q", got)
+	}
+	if got := dependencyLine(agents[3], agents); got != "" {
+		t.Errorf("lint: got %q, want empty", got)
+	}
+}
+
+func TestResolveAfter(t *testing.T) {
+	templates := []WorkspaceAgent{
+		{Name: "api"},
+		{Name: "tests", After: "api"},
+		{Name: "docs", After: "missing"},
+		{Name: "a", After: "b"},
+		{Name: "b", After: "a"},
+		{Name: "self", After: "self"},
+	}
+	deps, errs := resolveAfter(templates)
+	if len(deps) != 1 || deps[1] != 0 {
+		t.Errorf("deps = %v, want only tests → api", deps)
+	}
+	if len(errs) != 4 {
+		t.Errorf("got %d errors, want 4 (missing, self and both sides of the cycle): %v", len(errs), errs)
+	}
+}
+
+func names(agents []*Agent) []string {
+	var out []string
+	for _, a := range agents {
+		out = append(out, a.Name)
+	}
+	return out
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// EventKind classifies entries in the event feed.
+type EventKind string
+
+const (
+	EventSpawn    EventKind = "SPAWN"
+	EventStatus   EventKind = "STATUS"
+	EventKill     EventKind = "KILL"
+	EventDiscover EventKind = "DISCOVER"
+	EventHandoff  EventKind = "HANDOFF"
+	EventChain    EventKind = "CHAIN"
+	EventRestart  EventKind = "RESTART"
+	EventCommit   EventKind = "COMMIT"
+	EventPR       EventKind = "PR"
+	EventTimeout  EventKind = "TIMEOUT"
+	EventIdle     EventKind = "IDLE"
+	EventRule     EventKind = "RULE"
+	EventThrottle EventKind = "THROTTLE"
+	EventError    EventKind = "ERROR"
+	EventNudge    EventKind = "NUDGE"
+	EventHook     EventKind = "HOOK"
+	EventValidate EventKind = "VALIDATE"
+	EventRetry    EventKind = "RETRY"
+)
+
+// Event is one line of the event feed.
+type Event struct {
+	At      time.Time `json:"at"`
+	Kind    EventKind `json:"kind"`
+	Agent   string    `json:"agent"`
+	Message string    `json:"message,omitempty"`
+}
+
+// maxEvents caps the feed kept in memory and on disk.
+const maxEvents = 500
+
+// EventLog is an append-only feed of agent events, persisted as JSON lines
+// so the CLI and TUI share one history. A nil *EventLog discards events.
+type EventLog struct {
+	mu     sync.Mutex
+	path   string
+	events []Event
+}
+
+func eventsPath() string {
+	return filepath.Join(boardDir(), "events.jsonl")
+}
+
+// OpenEventLog loads the most recent events from path. A missing or
+// unreadable file starts an empty feed.
+func OpenEventLog(path string) *EventLog {
+	l := &EventLog{path: path}
+	l.Reload()
+	return l
+}
+
+// Reload re-reads the feed from disk, picking up events written by other
+// processes, and compacts the file once it grows well past maxEvents.
+func (l *EventLog) Reload() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if err != nil {
+		return
+	}
+	var events []Event
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Event
+		if json.Unmarshal(sc.Bytes(), &e) == nil {
+			events = append(events, e)
+		}
+	}
+	f.Close()
+
+	if len(events) > 2*maxEvents {
+		events = events[len(events)-maxEvents:]
+		l.rewrite(events)
+	} else if len(events) > maxEvents {
+		events = events[len(events)-maxEvents:]
+	}
+	l.events = events
+}
+
+// Add records an event and appends it to the log file.
+func (l *EventLog) Add(kind EventKind, agent, message string) {
+	if l == nil {
+		return
+	}
+	e := Event{At: time.Now(), Kind: kind, Agent: agent, Message: message}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, e)
+	if len(l.events) > maxEvents {
+		l.events = l.events[len(l.events)-maxEvents:]
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	_ = os.MkdirAll(filepath.Dir(l.path), 0755)
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	f.Write(append(data, '\n'))
+	f.Close()
+}
+
+// List returns the feed oldest first.
+func (l *EventLog) List() []Event {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Event(nil), l.events...)
+}
+
+// rewrite replaces the log file with events. Caller holds l.mu.
+func (l *EventLog) rewrite(events []Event) {
+	tmp := l.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return
+	}
+	w := bufio.NewWriter(f)
+	for _, e := range events {
+		if data, err := json.Marshal(e); err == nil {
+			w.Write(append(data, '\n'))
+		}
+	}
+	if w.Flush() != nil || f.Close() != nil {
+		os.Remove(tmp)
+		return
+	}
+	_ = os.Rename(tmp, l.path)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEventLogPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	l := OpenEventLog(path)
+	l.Add(EventSpawn, "api", "spawned in ~/dev/api")
+	l.Add(EventStatus, "api", "RUNNING → WAITING")
+
+	reopened := OpenEventLog(path)
+	got := reopened.List()
+	if len(got) != 2 {
+		t.Fatalf("reopened log has %d events, want 2", len(got))
+	}
+	if got[0].Kind != EventSpawn || got[1].Message != "RUNNING → WAITING" {
+		t.Errorf("events out of order or corrupted: %+v", got)
+	}
+}
+
+func TestEventLogSkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	data := `{"at":"2026-01-02T10:00:00Z","kind":"KILL","agent":"web"}` + "\nnot json\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := OpenEventLog(path).List()
+	if len(got) != 1 || got[0].Agent != "web" {
+		t.Errorf("got %+v, want the single valid event", got)
+	}
+}
+
+func TestEventLogCompacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	line := `{"at":"2026-01-02T10:00:00Z","kind":"STATUS","agent":"a"}` + "\n"
+	if err := os.WriteFile(path, []byte(strings.Repeat(line, 2*maxEvents+1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l := OpenEventLog(path)
+	if n := len(l.List()); n != maxEvents {
+		t.Errorf("in-memory feed has %d events, want %d", n, maxEvents)
+	}
+	data, _ := os.ReadFile(path)
+	if n := strings.Count(string(data), "\n"); n != maxEvents {
+		t.Errorf("compacted file has %d lines, want %d", n, maxEvents)
+	}
+}
+
+func TestNilEventLog(t *testing.T) {
+	var l *EventLog
+	l.Add(EventKill, "x", "")
+	l.Reload()
+	if l.List() != nil {
+		t.Error("nil log should list nothing")
+	}
+}
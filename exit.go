@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// crashScanLines is how much of the end of a pane is read for signs of a
+// crash.
+const crashScanLines = 15
+
+// Last words of a process that crashed: runtime panics, stack traces,
+// fatal errors and deaths by signal
+var crashRe = regexp.MustCompile(`(?i)(^panic: |^goroutine \d+ \[|^fatal error: |traceback \(most recent call last\)|^\s+at .+:\d+:\d+\)?$|thread '.*' panicked at|unhandled(?:promiserejection| promise rejection| exception)|segmentation fault|core dumped|\bexited with (?:code|status) [1-9]|^killed$|^aborted)`)
+
+// Exit is how a managed session's process ended, as tmux recorded it.
+type Exit struct {
+	Code   int    // exit status, -1 when it died by a signal
+	Signal int    // signal that killed it, 0 if none
+	Screen string // the pane as it was left, with its scrollback
+}
+
+// watchExit keeps a session's pane open after its process exits, so
+// reapExit can still read how it ended.
+func watchExit(session string) error {
+	if err := exec.Command("tmux", "set-option", "-t", session, "remain-on-exit", "on").Run(); err != nil {
+		return fmt.Errorf("tmux set-option remain-on-exit: %w", err)
+	}
+	return nil
+}
+
+// reapExit returns how a watched session's process ended, if it has, and
+// closes the session as tmux would have without watchExit.
+func reapExit(session string) (Exit, bool) {
+	out, err := exec.Command("tmux", "list-panes", "-t", session, "-F", "#{pane_dead} #{pane_dead_status} #{pane_dead_signal}").Output()
+	line, _, _ := strings.Cut(string(out), "\n")
+	dead, head, _ := strings.Cut(line, " ")
+	if err != nil || dead != "1" {
+		return Exit{}, false
+	}
+	screen, _ := captureScrollback(session)
+	_ = exec.Command("tmux", "kill-session", "-t", session).Run()
+	return parseExit(head + "\n" + screen), true
+}
+
+// parseExit reads "<status> <signal>" on the first line, then the screen
+// of a dead pane.
+func parseExit(s string) Exit {
+	head, screen, _ := strings.Cut(s, "\n")
+	status, signal, _ := strings.Cut(head, " ")
+	e := Exit{Code: -1}
+	if n, err := strconv.Atoi(strings.TrimSpace(status)); err == nil {
+		e.Code = n
+	}
+	e.Signal, _ = strconv.Atoi(strings.TrimSpace(signal))
+	// tmux marks the dead pane itself
+	var lines []string
+	for _, l := range strings.Split(strings.TrimRight(screen, "\n"), "\n") {
+		if !strings.HasPrefix(l, "Pane is dead") {
+			lines = append(lines, l)
+		}
+	}
+	e.Screen = strings.TrimRight(strings.Join(lines, "\n"), " \n")
+	return e
+}
+
+// failure says why a process that ended this way failed: a signal or a
+// non-zero status, with the crash on its screen if there is one. ok is
+// false for a clean exit, including one the user interrupted. The screen
+// decides alone only when tmux couldn't tell the status, which happens
+// when it sees the pane close before the process is reaped.
+func (e Exit) failure() (reason string, ok bool) {
+	crash, crashed := detectCrash(e.Screen)
+	switch {
+	case e.Signal == int(syscall.SIGINT) || e.Code == 0 || e.Code == 130:
+		return "", false
+	case e.Signal != 0:
+		reason = fmt.Sprintf("%s (signal %d)", syscall.Signal(e.Signal), e.Signal)
+	case e.Code > 0:
+		reason = fmt.Sprintf("exit status %d", e.Code)
+	case !crashed:
+		return "", false
+	default:
+		return crash, true
+	}
+	if crashed {
+		reason += ": " + crash
+	}
+	return reason, true
+}
+
+// ended says how a process that exited cleanly — see failure — ended.
+func (e Exit) ended() string {
+	if e.Signal == int(syscall.SIGINT) || e.Code == 130 {
+		return "interrupted (Ctrl+C)"
+	}
+	if e.Code == 0 {
+		return "process exited (status 0)"
+	}
+	return "process exited"
+}
+
+// exitNotice is the line a DONE agent's card shows: why it ended, so one
+// that finished can be told from one that was killed or lost. "" for other
+// agents, and those that don't say.
+func exitNotice(a *Agent) string {
+	if a.Status != StatusDone {
+		return ""
+	}
+	return a.ExitReason
+}
+
+// failureNotice is the line an ERROR agent's card shows: why it failed.
+// "" for other agents.
+func failureNotice(a *Agent) string {
+	if a.Status != StatusError {
+		return ""
+	}
+	return a.Failure
+}
+
+// detectCrash looks for the last words of a crashed process near the end
+// of plain pane content, returning the first line of them.
+func detectCrash(content string) (string, bool) {
+	lines := strings.Split(strings.TrimRight(content, " \n"), "\n")
+	if len(lines) > crashScanLines {
+		lines = lines[len(lines)-crashScanLines:]
+	}
+	for _, l := range lines {
+		if crashRe.MatchString(l) {
+			l = strings.TrimSpace(l)
+			if r := []rune(l); len(r) > 80 {
+				l = string(r[:79]) + "…"
+			}
+			return l, true
+		}
+	}
+	return "", false
+}
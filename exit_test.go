@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestExitFailure(t *testing.T) {
+	tests := []struct {
+		name   string
+		record string
+		want   string // "" for a clean exit
+	}{
+		{"clean", "0 \n> bye\n", ""},
+		{"clean despite a trace on screen", "0 \nTraceback (most recent call last):\n", ""},
+		{"interrupted", "130 \n^C\n", ""},
+		{"sigint", " 2\n", ""},
+		{"status", "1 \nsomething went wrong\n", "exit status 1"},
+		{"status with panic", "2 \nworking\npanic: runtime error: index out of range\n\ngoroutine 1 [running]:\n\n\nPane is dead (status 2, Thu Oct 15 12:00:00 2026)\n", "exit status 2: panic: runtime error: index out of range"},
+		{"signal", " 9\n", "killed (signal 9)"},
+		{"node stack", "1 \nTypeError: x is undefined\n    at run (/app/cli.js:10:5)\n", "exit status 1:"},
+		{"unknown status, crash on screen", "\nSegmentation fault (core dumped)\n", "Segmentation fault (core dumped)"},
+		{"unknown status, nothing on screen", "\n$ \n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, failed := parseExit(tt.record).failure()
+			if failed != (tt.want != "") {
+				t.Fatalf("failure() = %q, %v; want failed %v", reason, failed, tt.want != "")
+			}
+			if len(reason) < len(tt.want) || reason[:len(tt.want)] != tt.want {
+				t.Errorf("failure() = %q, want it to start with %q", reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitEnded(t *testing.T) {
+	for record, want := range map[string]string{
+		"0 0\nbye":  "process exited (status 0)",
+		"130 0\n^C": "interrupted (Ctrl+C)",
+		"-1 2\n":    "interrupted (Ctrl+C)",
+		"garbled\n": "process exited",
+	} {
+		if got := parseExit(record).ended(); got != want {
+			t.Errorf("ended() of %q = %q, want %q", record, got, want)
+		}
+	}
+}
+
+func TestExitNotice(t *testing.T) {
+	s := newTestStore(t)
+	a := s.Add("agent", "/tmp/a")
+	s.SetExitReason(a.ID, "killed after its 30m timeout")
+	if got := exitNotice(a); got != "" {
+		t.Errorf("exitNotice() of a RUNNING agent = %q, want none", got)
+	}
+	s.Update(a.ID, StatusDone)
+	if got := exitNotice(a); got != "killed after its 30m timeout" {
+		t.Errorf("exitNotice() = %q, want the reason set before it went DONE", got)
+	}
+	// Resumed, it has no reason to show the next time it ends
+	s.Update(a.ID, StatusRunning)
+	if a.ExitReason != "" {
+		t.Errorf("ExitReason = %q after leaving DONE, want it dropped", a.ExitReason)
+	}
+}
+
+func TestDetectCrash(t *testing.T) {
+	if line, ok := detectCrash("ok\nthread 'main' panicked at src/main.rs:2:5:\nboom\n"); !ok || line != "thread 'main' panicked at src/main.rs:2:5:" {
+		t.Errorf("rust panic: %q, %v", line, ok)
+	}
+	if _, ok := detectCrash("All tests passed\n> "); ok {
+		t.Error("clean output counts as a crash")
+	}
+}
+
+func TestReapExit(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	session := fmt.Sprintf("tickettok-test-%d", os.Getpid())
+	if err := exec.Command("tmux", "new-session", "-d", "-s", session, "sleep 0.5; echo 'panic: boom'; exit 3").Run(); err != nil {
+		t.Skipf("tmux new-session: %v", err)
+	}
+	defer exec.Command("tmux", "kill-session", "-t", session).Run()
+	if err := watchExit(session); err != nil {
+		t.Fatal(err)
+	}
+
+	if !IsSessionAlive(session) {
+		t.Fatal("session not alive while its process runs")
+	}
+
+	var ex Exit
+	var ok bool
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline) && !ok; {
+		time.Sleep(100 * time.Millisecond)
+		ex, ok = reapExit(session)
+	}
+	if !ok {
+		t.Fatal("exit not seen")
+	}
+	// tmux doesn't always get the status in time
+	if reason, _ := ex.failure(); reason != "exit status 3: panic: boom" && reason != "panic: boom" {
+		t.Errorf("failure() = %q", reason)
+	}
+	if exec.Command("tmux", "has-session", "-t", session).Run() == nil {
+		t.Error("session left open after it was reaped")
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import "strings"
+
+// fuzzyMatch reports whether every rune of pattern appears in s in order,
+// ignoring case. An empty pattern matches everything.
+func fuzzyMatch(pattern, s string) bool {
+	pattern = strings.ToLower(pattern)
+	s = strings.ToLower(s)
+	for _, r := range pattern {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
+
+// agentMatchesFilter checks each whitespace-separated term of filter against
+// the agent's name, dir, backend, tags, and last seen preview text. Every term
+// must fuzzy-match at least one of those fields. A term starting with '#'
+// must instead exactly match one of the agent's tags.
+func agentMatchesFilter(a *Agent, filter, preview string) bool {
+	fields := []string{a.Name, a.Dir, a.Backend().ID(), strings.Join(a.Tags, " "), preview}
+	for _, term := range strings.Fields(filter) {
+		if strings.HasPrefix(term, "#") {
+			if !a.HasTag(term) {
+				return false
+			}
+			continue
+		}
+		matched := false
+		for _, f := range fields {
+			if fuzzyMatch(term, f) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// filterAgents returns the agents matching filter. previews maps agent ID to
+// the preview text last captured for it.
+func filterAgents(agents []*Agent, filter string, previews map[string]string) []*Agent {
+	if strings.TrimSpace(filter) == "" {
+		return agents
+	}
+	var out []*Agent
+	for _, a := range agents {
+		if agentMatchesFilter(a, filter, previews[a.ID]) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
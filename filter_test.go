@@ -0,0 +1,68 @@
+package main
+
+import "testing"
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"", "anything", true},
+		{"api", "my-api-server", true},
+		{"mas", "my-api-server", true},
+		{"MAS", "my-api-server", true},
+		{"sam", "my-api-server", false},
+		{"xyz", "my-api-server", false},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestFilterAgents(t *testing.T) {
+	agents := []*Agent{
+		{ID: "1", Name: "api", Dir: "/home/me/api", BackendID: "claude", Tags: []string{"backend", "urgent"}},
+		{ID: "2", Name: "web", Dir: "/home/me/frontend", BackendID: "codex"},
+		{ID: "3", Name: "docs", Dir: "/home/me/docs", BackendID: "gemini"},
+	}
+	previews := map[string]string{"3": "Running migrations"}
+
+	names := func(as []*Agent) []string {
+		var out []string
+		for _, a := range as {
+			out = append(out, a.Name)
+		}
+		return out
+	}
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"", []string{"api", "web", "docs"}},
+		{"front", []string{"web"}},
+		{"codex", []string{"web"}},
+		{"migrat", []string{"docs"}},
+		{"me api", []string{"api"}},
+		{"nomatch", nil},
+		{"#urgent", []string{"api"}},
+		{"#URGENT", []string{"api"}},
+		{"#urg", nil},
+		{"backend", []string{"api"}},
+	}
+	for _, tt := range tests {
+		got := names(filterAgents(agents, tt.filter, previews))
+		if len(got) != len(tt.want) {
+			t.Errorf("filterAgents(%q) = %v, want %v", tt.filter, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("filterAgents(%q) = %v, want %v", tt.filter, got, tt.want)
+				break
+			}
+		}
+	}
+}
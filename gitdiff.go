@@ -0,0 +1,136 @@
+package main
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sns45/tickettok/ui"
+)
+
+// DiffStat summarizes uncommitted changes in an agent's working tree.
+type DiffStat struct {
+	Files      int
+	Insertions int
+	Deletions  int
+	Stat       string // full `git diff --stat` output
+}
+
+// Info converts the stat for card and detail rendering.
+func (d DiffStat) Info() ui.DiffInfo {
+	return ui.DiffInfo{Files: d.Files, Added: d.Insertions, Removed: d.Deletions, Stat: d.Stat}
+}
+
+var (
+	diffFilesRe = regexp.MustCompile(`(\d+) files? changed`)
+	diffInsRe   = regexp.MustCompile(`(\d+) insertions?\(\+\)`)
+	diffDelRe   = regexp.MustCompile(`(\d+) deletions?\(-\)`)
+)
+
+// parseDiffStat reads the totals from the summary line of `git diff --stat`.
+func parseDiffStat(out string) DiffStat {
+	out = strings.TrimRight(out, "\n")
+	d := DiffStat{Stat: out}
+	lines := strings.Split(out, "\n")
+	last := lines[len(lines)-1]
+	num := func(re *regexp.Regexp) int {
+		if m := re.FindStringSubmatch(last); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			return n
+		}
+		return 0
+	}
+	d.Files = num(diffFilesRe)
+	d.Insertions = num(diffInsRe)
+	d.Deletions = num(diffDelRe)
+	return d
+}
+
+// gitDiffStat runs `git diff --stat` in dir. Directories that aren't git
+// repos return an error.
+func gitDiffStat(dir string) (DiffStat, error) {
+	out, err := exec.Command("git", "-C", dir, "diff", "--stat").Output()
+	if err != nil {
+		return DiffStat{}, err
+	}
+	return parseDiffStat(string(out)), nil
+}
+
+// RepoState is the working tree and upstream state of an agent's repo.
+type RepoState struct {
+	Changed  int    // uncommitted entries, including untracked files
+	Ahead    int    // commits not yet pushed
+	Behind   int    // upstream commits not yet pulled
+	Upstream bool   // branch tracks a remote
+	Root     string // repository toplevel, used to group agents by project
+}
+
+// Info converts the state for card rendering.
+func (r RepoState) Info() ui.RepoInfo {
+	return ui.RepoInfo{Changed: r.Changed, Ahead: r.Ahead, Behind: r.Behind, Upstream: r.Upstream}
+}
+
+// parseGitStatus reads `git status --porcelain=v2 --branch` output.
+func parseGitStatus(out string) RepoState {
+	var r RepoState
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.upstream "):
+			r.Upstream = true
+		case strings.HasPrefix(line, "# branch.ab "):
+			// "# branch.ab +2 -1"
+			fields := strings.Fields(line)
+			if len(fields) == 4 {
+				r.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+				r.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+			}
+		case line == "" || strings.HasPrefix(line, "#"):
+		default:
+			r.Changed++
+		}
+	}
+	return r
+}
+
+// gitStatus runs a lightweight `git status` in dir and resolves its repo root.
+func gitStatus(dir string) (RepoState, error) {
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain=v2", "--branch").Output()
+	if err != nil {
+		return RepoState{}, err
+	}
+	r := parseGitStatus(string(out))
+	if top, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output(); err == nil {
+		r.Root = strings.TrimSpace(string(top))
+	}
+	return r, nil
+}
+
+// gitInfoMsg carries diff stats and repo state keyed by agent directory.
+type gitInfoMsg struct {
+	stats map[string]DiffStat
+	repos map[string]RepoState
+}
+
+// gitInfoCmd collects diff stats and repo state for dirs in the background.
+// Directories outside a git repo are left out.
+func gitInfoCmd(dirs []string) tea.Cmd {
+	return func() tea.Msg {
+		msg := gitInfoMsg{
+			stats: make(map[string]DiffStat),
+			repos: make(map[string]RepoState),
+		}
+		for _, dir := range dirs {
+			r, err := gitStatus(dir)
+			if err != nil {
+				continue
+			}
+			msg.repos[dir] = r
+			if d, err := gitDiffStat(dir); err == nil {
+				msg.stats[dir] = d
+			}
+		}
+		return msg
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseDiffStat(t *testing.T) {
+	out := ` main.go   | 20 ++++++++++++++------
+ model.go  | 141 ++++++++++++++++++++++++++++++++++++++++++-----
+ 2 files changed, 124 insertions(+), 37 deletions(-)
+`
+	d := parseDiffStat(out)
+	if d.Files != 2 || d.Insertions != 124 || d.Deletions != 37 {
+		t.Errorf("parseDiffStat() = %+v", d)
+	}
+
+	one := parseDiffStat(" a.go | 1 +\n 1 file changed, 1 insertion(+)\n")
+	if one.Files != 1 || one.Insertions != 1 || one.Deletions != 0 {
+		t.Errorf("single-file stat = %+v", one)
+	}
+	if clean := parseDiffStat(""); clean.Files != 0 {
+		t.Errorf("clean tree stat = %+v, want zero files", clean)
+	}
+}
+
+func TestGitDiffStat(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	file := filepath.Join(dir, "a.txt")
+	os.WriteFile(file, []byte("one\n"), 0644)
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-qm", "init")
+	os.WriteFile(file, []byte("two\nthree\n"), 0644)
+
+	d, err := gitDiffStat(dir)
+	if err != nil {
+		t.Fatalf("gitDiffStat() error: %v", err)
+	}
+	if d.Files != 1 || d.Insertions != 2 || d.Deletions != 1 {
+		t.Errorf("gitDiffStat() = %+v", d)
+	}
+	if _, err := gitDiffStat(t.TempDir()); err == nil {
+		t.Error("non-repo dir should return an error")
+	}
+}
+
+func TestParseGitStatus(t *testing.T) {
+	out := `# branch.oid 1234
+# branch.head main
+# branch.upstream origin/main
+# branch.ab +2 -1
+1 .M N... 100644 100644 100644 abc abc model.go
+? notes.txt
+`
+	r := parseGitStatus(out)
+	want := RepoState{Changed: 2, Ahead: 2, Behind: 1, Upstream: true}
+	if r != want {
+		t.Errorf("parseGitStatus() = %+v, want %+v", r, want)
+	}
+
+	local := parseGitStatus("# branch.oid 1234\n# branch.head main\n")
+	if local != (RepoState{}) {
+		t.Errorf("clean local branch = %+v, want zero state", local)
+	}
+}
@@ -7,6 +7,8 @@ require (
 	github.com/charmbracelet/bubbletea v1.3.10
 	github.com/charmbracelet/lipgloss v1.1.0
 	github.com/creack/pty/v2 v2.0.1
+	github.com/pelletier/go-toml/v2 v2.3.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
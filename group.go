@@ -0,0 +1,50 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/sns45/tickettok/ui"
+)
+
+// groupAgents orders agents by project for the grouped view. Projects appear
+// in the order their first agent does, and agents keep their relative order
+// within a project. keyOf maps an agent to its project key: the git
+// toplevel of its dir, or the dir itself outside a repo, or for a swarm
+// member, swarmGroupPrefix and the swarm's name. Agents in collapsed
+// projects are left out of the returned slice but still counted in their
+// group.
+func groupAgents(agents []*Agent, keyOf func(a *Agent) string, collapsed map[string]bool) ([]*Agent, []ui.Group) {
+	var order []string
+	members := make(map[string][]*Agent)
+	for _, a := range agents {
+		root := keyOf(a)
+		if _, ok := members[root]; !ok {
+			order = append(order, root)
+		}
+		members[root] = append(members[root], a)
+	}
+
+	var visible []*Agent
+	groups := make([]ui.Group, 0, len(order))
+	for _, root := range order {
+		g := ui.Group{
+			Name:      filepath.Base(root),
+			Path:      root,
+			Start:     len(visible),
+			Collapsed: collapsed[root],
+		}
+		if swarm, ok := strings.CutPrefix(root, swarmGroupPrefix); ok {
+			g.Name, g.Path = "⬡ "+swarm, ""
+		}
+		for _, a := range members[root] {
+			g.Statuses = append(g.Statuses, string(a.Status))
+		}
+		if !g.Collapsed {
+			g.Count = len(members[root])
+			visible = append(visible, members[root]...)
+		}
+		groups = append(groups, g)
+	}
+	return visible, groups
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupAgents(t *testing.T) {
+	agents := []*Agent{
+		{Name: "api", Dir: "/srv/mono/api", Status: StatusRunning},
+		{Name: "docs", Dir: "/srv/docs", Status: StatusIdle},
+		{Name: "web", Dir: "/srv/mono/web", Status: StatusWaiting},
+		{Name: "tool", Dir: "/srv/tools", Status: StatusIdle},
+	}
+	keyOf := func(a *Agent) string {
+		if strings.HasPrefix(a.Dir, "/srv/mono/") {
+			return "/srv/mono"
+		}
+		return a.Dir
+	}
+
+	visible, groups := groupAgents(agents, keyOf, map[string]bool{"/srv/docs": true})
+
+	var names []string
+	for _, a := range visible {
+		names = append(names, a.Name)
+	}
+	if got := strings.Join(names, ","); got != "api,web,tool" {
+		t.Errorf("visible = %s, want api,web,tool (mono together, docs collapsed)", got)
+	}
+
+	if len(groups) != 3 {
+		t.Fatalf("got %d groups, want 3", len(groups))
+	}
+	mono, docs, tools := groups[0], groups[1], groups[2]
+	if mono.Name != "mono" || mono.Start != 0 || mono.Count != 2 || len(mono.Statuses) != 2 {
+		t.Errorf("mono group = %+v", mono)
+	}
+	if !docs.Collapsed || docs.Count != 0 || len(docs.Statuses) != 1 {
+		t.Errorf("docs group = %+v, want collapsed with one status", docs)
+	}
+	if tools.Start != 2 || tools.Count != 1 {
+		t.Errorf("tools group = %+v, want Start 2 Count 1", tools)
+	}
+}
+
+func TestGroupAgentsSwarm(t *testing.T) {
+	agents := []*Agent{
+		{Name: "api", Dir: "/srv/shop", Swarm: "checkout"},
+		{Name: "solo", Dir: "/srv/shop"},
+		{Name: "ui", Dir: "/srv/web", Swarm: "checkout"},
+	}
+	keyOf := func(a *Agent) string {
+		if a.Swarm != "" {
+			return swarmGroupPrefix + a.Swarm
+		}
+		return a.Dir
+	}
+	visible, groups := groupAgents(agents, keyOf, map[string]bool{})
+	if len(groups) != 2 || groups[0].Name != "⬡ checkout" || groups[0].Path != "" || groups[0].Count != 2 {
+		t.Fatalf("groups = %+v, want the swarm first under its own header", groups)
+	}
+	if visible[1].Name != "ui" {
+		t.Errorf("visible[1] = %s, want ui next to api", visible[1].Name)
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import "strings"
+
+// handoffPrompt is the first message sent to an agent handed to another
+// backend: the original task, then the note (usually where the previous
+// attempt got stuck). Empty when there is neither.
+func handoffPrompt(a *Agent) string {
+	var parts []string
+	if a.Prompt != "" {
+		parts = append(parts, a.Prompt)
+	}
+	if a.Note != "" {
+		parts = append(parts, "Notes from a previous attempt:\n"+a.Note)
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestHandoffPrompt(t *testing.T) {
+	tests := []struct {
+		agent Agent
+		want  string
+	}{
+		{Agent{}, ""},
+		{Agent{Prompt: "fix the flaky test"}, "fix the flaky test"},
+		{Agent{Prompt: "fix the flaky test", Note: "stuck on the mock clock"},
+			"fix the flaky test\n\nNotes from a previous attempt:\nstuck on the mock clock"},
+		{Agent{Note: "retry the migration"}, "Notes from a previous attempt:\nretry the migration"},
+	}
+	for _, tt := range tests {
+		if got := handoffPrompt(&tt.agent); got != tt.want {
+			t.Errorf("handoffPrompt(%+v) = %q, want %q", tt.agent, got, tt.want)
+		}
+	}
+}
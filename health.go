@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// versionProbeTimeout bounds `<cli> --version`, which some CLIs slow down
+// with an update check.
+const versionProbeTimeout = 3 * time.Second
+
+// probeVersion runs `bin --version` and returns the first line it prints,
+// or "" if bin is missing or fails.
+func probeVersion(bin string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), versionProbeTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, bin, "--version").Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// BackendHealth is one backend's row in `tickettok backends` and the TUI
+// backends overlay.
+type BackendHealth struct {
+	ID        string
+	Name      string
+	Installed bool
+	Hint      string // install hint from CheckDeps when not installed
+	Version   string // "" when not installed or not reported
+	Hooks     string // installed, missing, or unsupported
+}
+
+// hookState describes a backend's hook registration.
+func hookState(b Backend) string {
+	switch {
+	case !b.Capabilities().SupportsHooks:
+		return "unsupported"
+	case b.HooksInstalled():
+		return "installed"
+	default:
+		return "missing"
+	}
+}
+
+// backendHealth checks every registered backend, sorted by ID. Versions
+// are probed concurrently, and only for installed CLIs.
+func backendHealth() []BackendHealth {
+	all := AllBackends()
+	sort.Slice(all, func(i, j int) bool { return all[i].ID() < all[j].ID() })
+
+	rows := make([]BackendHealth, len(all))
+	var wg sync.WaitGroup
+	for i, b := range all {
+		rows[i] = BackendHealth{ID: b.ID(), Name: b.Name(), Hooks: hookState(b)}
+		if err := b.CheckDeps(); err != nil {
+			rows[i].Hint = err.Error()
+			continue
+		}
+		rows[i].Installed = true
+		wg.Add(1)
+		go func(i int, b Backend) {
+			defer wg.Done()
+			rows[i].Version = b.Version()
+		}(i, b)
+	}
+	wg.Wait()
+	return rows
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProbeVersion(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "fakecli")
+	os.WriteFile(bin, []byte("#!/bin/sh\necho\necho '1.0.42 (Fake CLI)'\necho 'extra line'\n"), 0755)
+
+	if got := probeVersion(bin); got != "1.0.42 (Fake CLI)" {
+		t.Errorf("probeVersion() = %q, want the first non-empty line", got)
+	}
+	if got := probeVersion(filepath.Join(t.TempDir(), "missing")); got != "" {
+		t.Errorf("probeVersion(missing) = %q, want empty", got)
+	}
+}
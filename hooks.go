@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hooksDisabledPath is a marker file listing backend IDs whose hooks were removed
+// via `tickettok hooks uninstall`. The implicit install on startup skips them.
+func hooksDisabledPath() string {
+	return filepath.Join(stateDir(), "hooks_disabled")
+}
+
+// hooksDisabled returns the set of backend IDs opted out of implicit hook install.
+func hooksDisabled() map[string]bool {
+	disabled := make(map[string]bool)
+	data, err := os.ReadFile(hooksDisabledPath())
+	if err != nil {
+		return disabled
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if id := strings.TrimSpace(line); id != "" {
+			disabled[id] = true
+		}
+	}
+	return disabled
+}
+
+// setHooksDisabled adds or removes a backend ID from the opt-out marker file.
+func setHooksDisabled(id string, disabled bool) error {
+	set := hooksDisabled()
+	if set[id] == disabled {
+		return nil
+	}
+	if disabled {
+		set[id] = true
+	} else {
+		delete(set, id)
+	}
+
+	var ids []string
+	for k := range set {
+		ids = append(ids, k)
+	}
+	if len(ids) == 0 {
+		err := os.Remove(hooksDisabledPath())
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := os.MkdirAll(stateDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(hooksDisabledPath(), []byte(strings.Join(ids, "\n")+"\n"), 0644)
+}
+
+// readSettingsJSON reads a JSON settings file, returning an empty map if it doesn't exist.
+func readSettingsJSON(path string) (map[string]interface{}, error) {
+	settings := make(map[string]interface{})
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("parse settings: %w", err)
+	}
+	return settings, nil
+}
+
+// writeSettingsJSON writes settings back as indented JSON.
+func writeSettingsJSON(path string, settings map[string]interface{}) error {
+	out, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0644)
+}
+
+// removeHookEntries drops every hook that invokes a copy of scriptPath from the
+// "hooks" map of a Claude/Gemini settings.json, undoing InstallHooks. Other
+// hooks in the same entry are kept; entries, events and the "hooks" map
+// itself are dropped only when removing ours leaves them empty. Returns
+// true if anything changed.
+func removeHookEntries(settings map[string]interface{}, scriptPath string) bool {
+	hooks, ok := settings["hooks"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	changed := false
+	for event, entries := range hooks {
+		arr, ok := entries.([]interface{})
+		if !ok {
+			continue
+		}
+		var kept []interface{}
+		eventChanged := false
+		for _, entry := range arr {
+			if entryRunsCommand(entry, scriptPath) {
+				eventChanged = true
+				if entry = withoutCommand(entry, scriptPath); entry == nil {
+					continue
+				}
+			}
+			kept = append(kept, entry)
+		}
+		if !eventChanged {
+			continue
+		}
+		changed = true
+		if len(kept) == 0 {
+			delete(hooks, event)
+		} else {
+			hooks[event] = kept
+		}
+	}
+	if changed && len(hooks) == 0 {
+		delete(settings, "hooks")
+	}
+	return changed
+}
+
+// withoutCommand returns a copy of a hook entry minus the hooks invoking
+// a copy of scriptPath, or nil if none are left.
+func withoutCommand(entry interface{}, scriptPath string) interface{} {
+	em := entry.(map[string]interface{})
+	var rest []interface{}
+	for _, h := range em["hooks"].([]interface{}) {
+		if hm, ok := h.(map[string]interface{}); ok && sameHookScript(fmt.Sprint(hm["command"]), scriptPath) {
+			continue
+		}
+		rest = append(rest, h)
+	}
+	if len(rest) == 0 {
+		return nil
+	}
+	out := make(map[string]interface{}, len(em))
+	for k, v := range em {
+		out[k] = v
+	}
+	out["hooks"] = rest
+	return out
+}
+
+// settingsHasHook reports whether any hook entry in a settings.json invokes
+// scriptPath.
+func settingsHasHook(settings map[string]interface{}, scriptPath string) bool {
+	for _, cmd := range hookCommands(settings) {
+		if cmd == scriptPath {
+			return true
+		}
+	}
+	return false
+}
+
+// hooksCurrent reports whether a settings.json runs scriptPath and no
+// other copy of it, so installing has nothing to do.
+func hooksCurrent(settings map[string]interface{}, scriptPath string) bool {
+	found := false
+	for _, cmd := range hookCommands(settings) {
+		if sameHookScript(cmd, scriptPath) {
+			if cmd != scriptPath {
+				return false
+			}
+			found = true
+		}
+	}
+	return found
+}
+
+// hookCommands lists the commands of every hook in a settings.json.
+func hookCommands(settings map[string]interface{}) []string {
+	hooks, _ := settings["hooks"].(map[string]interface{})
+	var cmds []string
+	for _, entries := range hooks {
+		arr, _ := entries.([]interface{})
+		for _, entry := range arr {
+			cmds = append(cmds, entryCommands(entry)...)
+		}
+	}
+	return cmds
+}
+
+// entryCommands lists the commands of a settings.json hook entry's hooks.
+func entryCommands(entry interface{}) []string {
+	em, _ := entry.(map[string]interface{})
+	hookList, _ := em["hooks"].([]interface{})
+	var cmds []string
+	for _, h := range hookList {
+		if hm, ok := h.(map[string]interface{}); ok {
+			if cmd, ok := hm["command"].(string); ok {
+				cmds = append(cmds, cmd)
+			}
+		}
+	}
+	return cmds
+}
+
+// entryRunsCommand reports whether a settings.json hook entry invokes a
+// copy of scriptPath.
+func entryRunsCommand(entry interface{}, scriptPath string) bool {
+	for _, cmd := range entryCommands(entry) {
+		if sameHookScript(cmd, scriptPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectBackends returns the backend with the given ID, or all backends when id is empty.
+func selectBackends(id string) ([]Backend, error) {
+	if id == "" {
+		return AllBackends(), nil
+	}
+	b := GetBackend(id)
+	if b == nil {
+		return nil, fmt.Errorf("unknown backend: %s", id)
+	}
+	return []Backend{b}, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoveHookEntries(t *testing.T) {
+	script := "/home/u/.tickettok/tickettok-hook.sh"
+	ours := map[string]interface{}{
+		"hooks": []interface{}{map[string]interface{}{"type": "command", "command": script}},
+	}
+	user := map[string]interface{}{
+		"hooks": []interface{}{map[string]interface{}{"type": "command", "command": "/usr/local/bin/notify"}},
+	}
+	shared := map[string]interface{}{
+		"matcher": "Bash",
+		"hooks": []interface{}{
+			map[string]interface{}{"type": "command", "command": script},
+			map[string]interface{}{"type": "command", "command": "/usr/local/bin/audit"},
+		},
+	}
+	settings := map[string]interface{}{
+		"model": "opus",
+		"hooks": map[string]interface{}{
+			"Stop":        []interface{}{ours, user},
+			"PreToolUse":  []interface{}{ours},
+			"PostToolUse": []interface{}{shared},
+			"SessionEnd":  []interface{}{},
+		},
+	}
+
+	if !removeHookEntries(settings, script) {
+		t.Fatal("removeHookEntries() = false, want true")
+	}
+
+	hooks := settings["hooks"].(map[string]interface{})
+	stop := hooks["Stop"].([]interface{})
+	if len(stop) != 1 || entryRunsCommand(stop[0], script) {
+		t.Errorf("Stop entries = %v, want only the user hook", stop)
+	}
+	if pre, ok := hooks["PreToolUse"]; ok {
+		t.Errorf("PreToolUse = %v, want the event removed with our only entry", pre)
+	}
+	post := hooks["PostToolUse"].([]interface{})
+	if len(post) != 1 || entryRunsCommand(post[0], script) || !entryRunsCommand(post[0], "/usr/local/bin/audit") {
+		t.Errorf("PostToolUse entries = %v, want the shared entry minus our hook", post)
+	}
+	if _, ok := hooks["SessionEnd"]; !ok {
+		t.Error("an event the user left empty should be preserved")
+	}
+	if settings["model"] != "opus" {
+		t.Error("unrelated settings keys should be preserved")
+	}
+
+	if removeHookEntries(settings, script) {
+		t.Error("second removeHookEntries() = true, want false")
+	}
+}
+
+func TestHooksDisabled(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if hooksDisabled()["claude"] {
+		t.Fatal("claude should not be disabled by default")
+	}
+	if err := setHooksDisabled("claude", true); err != nil {
+		t.Fatalf("setHooksDisabled(true) error: %v", err)
+	}
+	if !hooksDisabled()["claude"] {
+		t.Error("claude should be disabled after setHooksDisabled(true)")
+	}
+	if err := setHooksDisabled("claude", false); err != nil {
+		t.Fatalf("setHooksDisabled(false) error: %v", err)
+	}
+	if hooksDisabled()["claude"] {
+		t.Error("claude should be enabled after setHooksDisabled(false)")
+	}
+}
+
+func TestRemoveHookEntriesDropsEmptyHooks(t *testing.T) {
+	script := "/home/u/.tickettok/tickettok-hook.sh"
+	settings := map[string]interface{}{
+		"hooks": map[string]interface{}{
+			"Stop": []interface{}{map[string]interface{}{
+				"hooks": []interface{}{map[string]interface{}{"type": "command", "command": script}},
+			}},
+		},
+	}
+	if !removeHookEntries(settings, script) {
+		t.Fatal("removeHookEntries() = false, want true")
+	}
+	if _, ok := settings["hooks"]; ok {
+		t.Errorf("settings = %v, want hooks removed once empty", settings)
+	}
+}
+
+func TestHooksAcrossStateDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	codexConfig := filepath.Join(home, ".codex", "config.toml")
+	os.MkdirAll(filepath.Dir(codexConfig), 0755)
+	// Left by a TICKETTOK_HOME that's since been deleted, nested in front
+	// of the user's own command
+	os.WriteFile(codexConfig, []byte(`notify = ["/tmp/b/tickettok-codex-notify.sh", "/tmp/a/tickettok-codex-notify.sh", "python3", "n.py"]`+"\n"), 0644)
+	claudeSettings := filepath.Join(home, ".claude", "settings.json")
+	os.MkdirAll(filepath.Dir(claudeSettings), 0755)
+	os.WriteFile(claudeSettings, []byte(`{"hooks": {"Stop": [{"hooks": [{"type": "command", "command": "/tmp/a/tickettok-hook.sh"}]}]}}`), 0644)
+
+	claude, codex := &ClaudeBackend{}, &CodexBackend{}
+	for _, dir := range []string{"a", "b", "a", "", "b"} {
+		t.Setenv(stateDirEnv, "")
+		if dir != "" {
+			t.Setenv(stateDirEnv, filepath.Join(home, dir))
+		}
+		if err := claude.InstallHooks(); err != nil {
+			t.Fatalf("claude InstallHooks() with home %q: %v", dir, err)
+		}
+		if err := codex.InstallHooks(); err != nil {
+			t.Fatalf("codex InstallHooks() with home %q: %v", dir, err)
+		}
+	}
+
+	script := filepath.Join(home, ".tickettok", "tickettok-hook.sh")
+	settings, _ := readSettingsJSON(claudeSettings)
+	var stops []string
+	for _, e := range settings["hooks"].(map[string]interface{})["Stop"].([]interface{}) {
+		stops = append(stops, entryCommands(e)...)
+	}
+	if len(stops) != 1 || stops[0] != script {
+		t.Errorf("Claude Stop hooks = %q, want just %s", stops, script)
+	}
+	data, _ := os.ReadFile(codexConfig)
+	notify := filepath.Join(home, ".tickettok", "tickettok-codex-notify.sh")
+	if want := `notify = ["` + notify + `", "python3", "n.py"]` + "\n"; string(data) != want {
+		t.Errorf("config.toml = %q, want %q", data, want)
+	}
+	if !claude.HooksInstalled() || !codex.HooksInstalled() {
+		t.Error("HooksInstalled() = false after switching state dirs")
+	}
+
+	if err := codex.UninstallHooks(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(codexConfig); string(data) != `notify = ["python3", "n.py"]`+"\n" {
+		t.Errorf("config.toml after uninstall = %q, want the user's command back", data)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Idle shutdown actions for IdleShutdownConfig.Action.
+const (
+	IdleKill   = "kill"
+	IdleDetach = "detach"
+)
+
+// defaultIdleWarn is how long before an idle shutdown the card warns.
+const defaultIdleWarn = 30 * time.Minute
+
+// idleStage is where an agent stands with the idle shutdown policy.
+type idleStage int
+
+const (
+	idleActive   idleStage = iota // not idle long enough to matter
+	idleWarning                   // shutdown due within the warning period
+	idleShutdown                  // idle past the limit
+)
+
+// stage places an agent against the policy. Only managed agents sitting
+// IDLE with a live, attached session count.
+func (c IdleShutdownConfig) stage(a *Agent, now time.Time) idleStage {
+	after := c.After()
+	if after <= 0 || a.Discovered || a.Detached || a.Status != StatusIdle {
+		return idleActive
+	}
+	switch idle := now.Sub(a.StatusSince); {
+	case idle >= after:
+		return idleShutdown
+	case idle >= after-c.Warn():
+		return idleWarning
+	}
+	return idleActive
+}
+
+// notice is the line a card shows about the policy: the coming shutdown
+// while warning, or that the agent was detached. "" otherwise.
+func (c IdleShutdownConfig) notice(a *Agent, now time.Time) string {
+	if a.Detached {
+		return "detached while idle; zoom in to reattach"
+	}
+	if c.stage(a, now) != idleWarning {
+		return ""
+	}
+	left := c.After() - now.Sub(a.StatusSince)
+	verb := "killed"
+	if c.IdleAction() == IdleDetach {
+		verb = "detached"
+	}
+	return fmt.Sprintf("idle: %s in %s unless it gets work", verb, formatTimeout((left + time.Minute - 1).Truncate(time.Minute)))
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdleStage(t *testing.T) {
+	now := time.Now()
+	policy := IdleShutdownConfig{Hours: 8}
+	idleFor := func(d time.Duration) *Agent {
+		return &Agent{Status: StatusIdle, StatusSince: now.Add(-d)}
+	}
+	tests := []struct {
+		name   string
+		policy IdleShutdownConfig
+		agent  *Agent
+		want   idleStage
+	}{
+		{"policy off", IdleShutdownConfig{}, idleFor(24 * time.Hour), idleActive},
+		{"recently idle", policy, idleFor(time.Hour), idleActive},
+		{"warning period", policy, idleFor(7*time.Hour + 45*time.Minute), idleWarning},
+		{"past the limit", policy, idleFor(9 * time.Hour), idleShutdown},
+		{"running", policy, &Agent{Status: StatusRunning, StatusSince: now.Add(-9 * time.Hour)}, idleActive},
+		{"discovered", policy, &Agent{Status: StatusIdle, StatusSince: now.Add(-9 * time.Hour), Discovered: true}, idleActive},
+		{"already detached", policy, &Agent{Status: StatusIdle, StatusSince: now.Add(-9 * time.Hour), Detached: true}, idleActive},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.stage(tt.agent, now); got != tt.want {
+			t.Errorf("%s: stage() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIdleNotice(t *testing.T) {
+	now := time.Now()
+	a := &Agent{Status: StatusIdle, StatusSince: now.Add(-7*time.Hour - 45*time.Minute)}
+
+	if got := (IdleShutdownConfig{Hours: 8}).notice(a, now); !strings.Contains(got, "killed in 15m") {
+		t.Errorf("kill notice = %q", got)
+	}
+	if got := (IdleShutdownConfig{Hours: 8, Action: IdleDetach}).notice(a, now); !strings.Contains(got, "detached in 15m") {
+		t.Errorf("detach notice = %q", got)
+	}
+	if got := (IdleShutdownConfig{Hours: 10}).notice(a, now); got != "" {
+		t.Errorf("notice before the warning period = %q", got)
+	}
+	a.Detached = true
+	if got := (IdleShutdownConfig{}).notice(a, now); !strings.Contains(got, "detached") {
+		t.Errorf("detached notice = %q", got)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import "time"
+
+// Keep-alive restart limits. Restarts back off from restartBackoff,
+// doubling each time; an agent that stays up for restartResetAfter starts
+// its count over.
+const (
+	maxRestarts       = 5
+	restartBackoff    = 5 * time.Second
+	restartResetAfter = 10 * time.Minute
+)
+
+// restartAction is what keep-alive does about an agent whose session died.
+type restartAction int
+
+const (
+	restartWait   restartAction = iota // backing off from the last restart
+	restartNow                         // respawn it
+	restartGiveUp                      // out of restarts
+)
+
+// recentRestarts returns how many restarts count against an agent's limit:
+// those since it last stayed up for restartResetAfter.
+func recentRestarts(a *Agent, now time.Time) int {
+	if now.Sub(a.RestartAt) > restartResetAfter {
+		return 0
+	}
+	return a.Restarts
+}
+
+// restartDelay is how long after the n-th restart the next may happen.
+func restartDelay(n int) time.Duration {
+	if n == 0 {
+		return 0
+	}
+	return restartBackoff << (n - 1)
+}
+
+// nextRestart decides what to do about a keep-alive agent found dead at
+// now.
+func nextRestart(a *Agent, now time.Time) restartAction {
+	n := recentRestarts(a, now)
+	switch {
+	case n >= maxRestarts:
+		return restartGiveUp
+	case now.Sub(a.RestartAt) < restartDelay(n):
+		return restartWait
+	}
+	return restartNow
+}
+
+// crashed reports whether a keep-alive agent's session died under it: it's
+// gone although the agent never reported finishing. A clean exit fires the
+// backend's SessionEnd hook, or was already seen in the pane, as DONE.
+func (m *AgentManager) crashed(agent *Agent) bool {
+	if !agent.KeepAlive || agent.Discovered || agent.Status == StatusDone || agent.Status == StatusPending {
+		return false
+	}
+	if status, ok := agent.Backend().ReadHookStatus(agent.ID); ok && status == StatusDone {
+		return false
+	}
+	if agent.Stream {
+		if st, ok := streamStatus(agent.ID); ok && st.done {
+			return false
+		}
+	}
+	sess := m.GetSession(agent)
+	return sess == nil || !sess.IsAlive()
+}
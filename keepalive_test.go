@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextRestart(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		agent Agent
+		want  restartAction
+	}{
+		{"first crash", Agent{}, restartNow},
+		{"backing off", Agent{Restarts: 2, RestartAt: now.Add(-5 * time.Second)}, restartWait},
+		{"backoff over", Agent{Restarts: 2, RestartAt: now.Add(-10 * time.Second)}, restartNow},
+		{"out of restarts", Agent{Restarts: maxRestarts, RestartAt: now.Add(-time.Minute)}, restartGiveUp},
+		{"stayed up long enough", Agent{Restarts: maxRestarts, RestartAt: now.Add(-restartResetAfter - time.Second)}, restartNow},
+	}
+	for _, tt := range tests {
+		if got := nextRestart(&tt.agent, now); got != tt.want {
+			t.Errorf("%s: nextRestart() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRestartDelay(t *testing.T) {
+	want := []time.Duration{0, 5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second}
+	for n, w := range want {
+		if got := restartDelay(n); got != w {
+			t.Errorf("restartDelay(%d) = %v, want %v", n, got, w)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Lifecycle events a user command can run on
+const (
+	LifecyclePreSpawn  = "pre_spawn"
+	LifecyclePostSpawn = "post_spawn"
+	LifecycleWaiting   = "on_waiting"
+	LifecycleDone      = "on_done"
+	LifecycleKill      = "on_kill"
+)
+
+// lifecycleTimeout bounds a lifecycle command, so one that hangs doesn't
+// hold up a spawn or pile up processes.
+const lifecycleTimeout = time.Minute
+
+// tuiPreSpawnTimeout bounds pre_spawn in the TUI, which spawns from its
+// update loop: the board is frozen while the command runs.
+const tuiPreSpawnTimeout = 5 * time.Second
+
+// LifecycleConfig holds the shell commands run on agent lifecycle events,
+// through sh in the agent's directory with the agent described in
+// TICKETTOK_* variables (see lifecycleEnv).
+type LifecycleConfig struct {
+	// PreSpawn runs before an agent's session starts; failing stops the
+	// spawn.
+	PreSpawn string `json:"pre_spawn,omitempty"`
+	// PostSpawn runs once the session has started.
+	PostSpawn string `json:"post_spawn,omitempty"`
+	// OnWaiting runs when an agent starts waiting on you: WAITING or ASK.
+	OnWaiting string `json:"on_waiting,omitempty"`
+	// OnDone runs when an agent goes DONE.
+	OnDone string `json:"on_done,omitempty"`
+	// OnKill runs when an agent is killed.
+	OnKill string `json:"on_kill,omitempty"`
+}
+
+// command returns the command configured for event, "" for none.
+func (c LifecycleConfig) command(event string) string {
+	switch event {
+	case LifecyclePreSpawn:
+		return c.PreSpawn
+	case LifecyclePostSpawn:
+		return c.PostSpawn
+	case LifecycleWaiting:
+		return c.OnWaiting
+	case LifecycleDone:
+		return c.OnDone
+	case LifecycleKill:
+		return c.OnKill
+	}
+	return ""
+}
+
+// lifecycleEnv is the environment of a lifecycle command: tickettok's own,
+// plus the event and the agent it's about.
+func lifecycleEnv(event string, a *Agent) []string {
+	return append(os.Environ(),
+		"TICKETTOK_EVENT="+event,
+		"TICKETTOK_AGENT="+a.Name,
+		"TICKETTOK_AGENT_ID="+a.ID,
+		"TICKETTOK_AGENT_DIR="+a.Dir,
+		"TICKETTOK_BACKEND="+a.Backend().ID(),
+		"TICKETTOK_STATUS="+string(a.Status),
+		"TICKETTOK_BRANCH="+a.Branch,
+		"TICKETTOK_SESSION="+a.SessionName,
+		"TICKETTOK_PROMPT="+a.Prompt,
+	)
+}
+
+// lifecycleCommand builds the command for event, nil when none is
+// configured. It runs in the agent's directory, or the current one once
+// that's gone, like a killed agent's worktree.
+func (c LifecycleConfig) lifecycleCommand(ctx context.Context, event string, a *Agent) *exec.Cmd {
+	command := strings.TrimSpace(c.command(event))
+	if command == "" {
+		return nil
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	if info, err := os.Stat(a.Dir); err == nil && info.IsDir() {
+		cmd.Dir = a.Dir
+	}
+	cmd.Env = lifecycleEnv(event, a)
+	return cmd
+}
+
+// run runs the command for event and waits for it, up to timeout,
+// returning why it failed with the last line of its output.
+func (c LifecycleConfig) run(event string, a *Agent, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := c.lifecycleCommand(ctx, event, a)
+	if cmd == nil {
+		return nil
+	}
+	// Don't wait on children of sh still holding its output
+	cmd.WaitDelay = time.Second
+	data, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%s: still running after %v", event, timeout)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if out := lines[len(lines)-1]; out != "" {
+		return fmt.Errorf("%s: %w: %s", event, err, out)
+	}
+	return fmt.Errorf("%s: %w", event, err)
+}
+
+// fire starts the command for event without waiting for it; its output is
+// discarded and a failure goes to events. The command outlives a CLI that
+// exits before it's done.
+func (c LifecycleConfig) fire(event string, a *Agent, events *EventLog) {
+	ctx, cancel := context.WithTimeout(context.Background(), lifecycleTimeout)
+	cmd := c.lifecycleCommand(ctx, event, a)
+	if cmd == nil {
+		cancel()
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		events.Add(EventHook, a.Name, fmt.Sprintf("%s: %v", event, err))
+		return
+	}
+	name := a.Name
+	go func() {
+		defer cancel()
+		if err := cmd.Wait(); err != nil {
+			events.Add(EventHook, name, fmt.Sprintf("%s: %v", event, err))
+		}
+	}()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLifecycleRun(t *testing.T) {
+	dir := t.TempDir()
+	a := &Agent{ID: "agent-7", Name: "api", Dir: dir, Prompt: "fix the tests"}
+
+	t.Run("none configured", func(t *testing.T) {
+		if err := (LifecycleConfig{}).run(LifecyclePreSpawn, a, lifecycleTimeout); err != nil {
+			t.Errorf("run() = %v, want nil", err)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		c := LifecycleConfig{PreSpawn: `echo "$TICKETTOK_EVENT $TICKETTOK_AGENT $TICKETTOK_AGENT_ID $TICKETTOK_PROMPT" > out.txt`}
+		if err := c.run(LifecyclePreSpawn, a, lifecycleTimeout); err != nil {
+			t.Fatalf("run() = %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := strings.TrimSpace(string(data)), "pre_spawn api agent-7 fix the tests"; got != want {
+			t.Errorf("command saw %q, want %q", got, want)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		c := LifecycleConfig{PreSpawn: "echo checking; echo branch exists >&2; exit 3"}
+		err := c.run(LifecyclePreSpawn, a, lifecycleTimeout)
+		if err == nil || !strings.Contains(err.Error(), "pre_spawn") || !strings.HasSuffix(err.Error(), "branch exists") {
+			t.Errorf("run() = %v, want the event and the last line of output", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		start := time.Now()
+		err := (LifecycleConfig{PreSpawn: "sleep 30"}).run(LifecyclePreSpawn, a, 100*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "still running after 100ms") {
+			t.Errorf("run() = %v, want it cut off", err)
+		}
+		if d := time.Since(start); d > 5*time.Second {
+			t.Errorf("run() took %v past its timeout", d)
+		}
+	})
+
+	t.Run("missing dir", func(t *testing.T) {
+		gone := &Agent{Name: "gone", Dir: filepath.Join(dir, "removed-worktree")}
+		if err := (LifecycleConfig{OnKill: "true"}).run(LifecycleKill, gone, lifecycleTimeout); err != nil {
+			t.Errorf("run() = %v, want it to run outside the missing dir", err)
+		}
+	})
+}
+
+func TestLifecycleFire(t *testing.T) {
+	events := OpenEventLog(filepath.Join(t.TempDir(), "events.jsonl"))
+	a := &Agent{Name: "api", Dir: t.TempDir()}
+	LifecycleConfig{OnDone: "exit 1"}.fire(LifecycleDone, a, events)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(events.List()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	got := events.List()
+	if len(got) != 1 || got[0].Kind != EventHook || !strings.HasPrefix(got[0].Message, "on_done") {
+		t.Errorf("events = %+v, want one HOOK event for on_done", got)
+	}
+}
+
+func TestFireTransitionsByID(t *testing.T) {
+	s := newTestStore(t)
+	out := filepath.Join(t.TempDir(), "done.txt")
+	// Agents in one repo are all named after it
+	first := s.Add("shop", t.TempDir())
+	second := s.Add("shop", t.TempDir())
+	m := Model{store: s, manager: &AgentManager{lifecycle: LifecycleConfig{OnDone: `echo "$TICKETTOK_AGENT_ID" > ` + out}}}
+
+	m.fireTransitions([]statusTransition{{second.ID, second.Name, StatusRunning, StatusDone}})
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(out)
+		if got := strings.TrimSpace(string(data)); got != "" {
+			if got != second.ID {
+				t.Errorf("on_done ran for agent %s, want %s (not %s, of the same name)", got, second.ID, first.ID)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("on_done never ran")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -1,22 +1,41 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sns45/tickettok/ui"
 )
 
 var version = "0.13.1"
 
 func main() {
+	args, err := takeStateDirFlag(os.Args[1:])
+	if err == nil {
+		args, err = selectBoard(args)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Args = append(os.Args[:1], args...)
+
+	registerPlugins()
 	checkDeps()
-	installBackendHooks()
+	// `hooks` manages installation explicitly; don't reinstall behind its back
+	if len(os.Args) < 2 || os.Args[1] != "hooks" {
+		installBackendHooks()
+	}
 
 	if len(os.Args) < 2 {
 		runTUI()
@@ -30,18 +49,58 @@ func main() {
 		cmdAdd()
 	case "list":
 		cmdList()
+	case "stats":
+		cmdStats()
 	case "kill":
 		cmdKill()
 	case "send":
 		cmdSend()
+	case "race":
+		cmdRace()
+	case "swarm":
+		cmdSwarm()
+	case "priority":
+		cmdPriority()
 	case "status":
 		cmdStatus()
 	case "discover":
 		cmdDiscover()
 	case "clear":
 		cmdClear()
+	case "rename":
+		cmdRename()
+	case "mark":
+		cmdMark()
+	case "adopt":
+		cmdAdopt()
+	case "watch":
+		cmdWatch()
+	case "wait":
+		cmdWait()
+	case "prune":
+		cmdPrune()
+	case "pr":
+		cmdPR()
+	case "transcript":
+		cmdTranscript()
+	case "export":
+		cmdExport()
+	case "import":
+		cmdImport()
+	case "restore":
+		cmdRestore()
+	case "archive":
+		cmdArchive()
+	case "hooks":
+		cmdHooks()
+	case "backends":
+		cmdBackends()
 	case "workspace", "ws":
 		cmdWorkspace()
+	case "define":
+		cmdDefine()
+	case "up":
+		cmdUp()
 	case "version", "--version", "-v":
 		fmt.Println("tickettok " + version)
 	case "help", "--help", "-h":
@@ -75,7 +134,7 @@ func checkDeps() {
 		}
 	}
 	if available == 0 {
-		fmt.Fprintln(os.Stderr, "At least one agent CLI is required (claude, codex, or gemini)")
+		fmt.Fprintln(os.Stderr, "At least one agent CLI is required (claude, codex, gemini, qwen, or interpreter)")
 		os.Exit(1)
 	}
 }
@@ -86,22 +145,53 @@ func runTUI() {
 		fmt.Fprintf(os.Stderr, "Error initializing state: %v\n", err)
 		os.Exit(1)
 	}
+	// Status changes every tick would otherwise rewrite the file each time
+	store.DeferSaves()
+
+	cfg, err := loadConfig(configPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid config: %v\n", err)
+		os.Exit(1)
+	}
 
 	manager := NewAgentManager()
 
+	// Theme must be in place before any styles or layouts are used
+	theme, _ := cfg.UITheme()
+	ui.ApplyTheme(theme)
+
 	m := initialModel(store, manager)
+	m.customColumns = cfg.Layout()
+	m.events = OpenEventLog(eventsPath())
+	manager.SetLifecycle(cfg.Lifecycle, m.events)
+	manager.LimitPreSpawn(tuiPreSpawnTimeout)
+	m.alerts = cfg.Alerts
+	m.staleAfter = time.Duration(cfg.StaleMinutes) * time.Minute
+	m.quitAction = cfg.QuitAction()
+	m.streamJSON = cfg.ClaudeStreamJSON
+	m.maxRunning = cfg.MaxRunning
+	m.checkpointCommand = cfg.Checkpoint()
+	m.validations = cfg.Validation
+	m.timeoutAction = cfg.TimeoutAction()
+	m.memoryWarn = cfg.MemoryWarn()
+	m.idleShutdown = cfg.IdleShutdown
+	m.approvalRules = cfg.Rules()
+	m.throttleResume = strings.TrimSpace(cfg.ThrottleResume)
+	m.transcriptDir = cfg.TranscriptDir()
 	p := tea.NewProgram(m,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(),
 	)
 
 	finalModel, err := p.Run()
+	manager.CloseAll()
+	if ferr := store.Flush(); ferr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: state not saved: %v\n", ferr)
+	}
 	if err != nil {
-		manager.CloseAll()
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-	manager.CloseAll()
 
 	if fm, ok := finalModel.(Model); ok && fm.shouldReExec {
 		if err := reExec(); err != nil {
@@ -109,12 +199,18 @@ func runTUI() {
 			os.Exit(1)
 		}
 	}
+	if fm, ok := finalModel.(Model); ok && fm.switchBoard != nil {
+		if err := execBoard(fm.switchBoard); err != nil {
+			fmt.Fprintf(os.Stderr, "Switching boards failed: %v (run tickettok %s)\n", err, strings.Join(fm.switchBoard, " "))
+			os.Exit(1)
+		}
+	}
 }
 
 // cmdAdd spawns an agent headlessly from CLI.
 func cmdAdd() {
 	if len(os.Args) < 3 {
-		fmt.Fprintln(os.Stderr, "Usage: tickettok add <dir> [--name <name>] [--backend <claude|codex|gemini>] [--prompt <text>] [--auto-approve]")
+		fmt.Fprintln(os.Stderr, "Usage: tickettok add <dir> [--name <name>] [--backend <claude|codex|gemini|qwen|interpreter>] [--prompt <text> | --prompt-file <file|->] [--after <id|name>] [--tag <tag>]... [--auto-approve] [--keep-alive] [--checkpoint] [--timeout <duration>] [--rule <rule>]... [--nudge <rule>] [--priority high|normal|low] [--branch | --worktree]")
 		os.Exit(1)
 	}
 
@@ -122,7 +218,16 @@ func cmdAdd() {
 	name := ""
 	backendID := ""
 	prompt := ""
+	after := ""
+	var tags []string
 	autoApprove := false
+	keepAlive := false
+	checkpoint := false
+	var timeout time.Duration
+	var rules []string
+	var nudge *Nudge
+	priority := PriorityNormal
+	gitMode := branchNone
 
 	for i := 3; i < len(os.Args); i++ {
 		switch os.Args[i] {
@@ -141,8 +246,75 @@ func cmdAdd() {
 				prompt = os.Args[i+1]
 				i++
 			}
+		case "--prompt-file":
+			if i+1 < len(os.Args) {
+				text, err := readPromptFile(os.Args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Cannot read prompt: %v\n", err)
+					os.Exit(1)
+				}
+				prompt = text
+				i++
+			}
+		case "--after":
+			if i+1 < len(os.Args) {
+				after = os.Args[i+1]
+				i++
+			}
+		case "--tag":
+			if i+1 < len(os.Args) {
+				tags = append(tags, os.Args[i+1])
+				i++
+			}
 		case "--auto-approve":
 			autoApprove = true
+		case "--keep-alive":
+			keepAlive = true
+		case "--checkpoint":
+			checkpoint = true
+		case "--timeout":
+			if i+1 < len(os.Args) {
+				d, err := parseAge(os.Args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: --timeout: %v\n", err)
+					os.Exit(1)
+				}
+				timeout = d
+				i++
+			}
+		case "--rule":
+			if i+1 < len(os.Args) {
+				if _, err := parseRule(os.Args[i+1]); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: --rule: %v\n", err)
+					os.Exit(1)
+				}
+				rules = append(rules, os.Args[i+1])
+				i++
+			}
+		case "--nudge":
+			if i+1 < len(os.Args) {
+				n, err := parseNudge(os.Args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: --nudge: %v\n", err)
+					os.Exit(1)
+				}
+				nudge = n
+				i++
+			}
+		case "--priority":
+			if i+1 < len(os.Args) {
+				p, err := parsePriority(os.Args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: --priority: %v\n", err)
+					os.Exit(1)
+				}
+				priority = p
+				i++
+			}
+		case "--branch":
+			gitMode = branchNew
+		case "--worktree":
+			gitMode = branchWorktree
 		}
 	}
 
@@ -159,6 +331,19 @@ func cmdAdd() {
 		}
 	}
 
+	// Validate backend selection before anything is recorded in state
+	if backendID != "" {
+		b := GetBackend(backendID)
+		if b == nil {
+			fmt.Fprintf(os.Stderr, "Unknown backend: %s\n", backendID)
+			os.Exit(1)
+		}
+		if err := b.CheckDeps(); err != nil {
+			fmt.Fprintf(os.Stderr, "Backend %s not installed: %v\n", backendID, err)
+			os.Exit(1)
+		}
+	}
+
 	store, err := NewStore()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -167,25 +352,69 @@ func cmdAdd() {
 
 	manager := NewAgentManager()
 
+	var dep *Agent
+	if after != "" {
+		if prompt == "" {
+			fmt.Fprintln(os.Stderr, "--after needs a --prompt to hold back")
+			os.Exit(1)
+		}
+		if dep = store.Get(after); dep == nil {
+			dep = store.GetByName(after)
+		}
+		if dep == nil {
+			fmt.Fprintf(os.Stderr, "Agent not found: %s\n", after)
+			os.Exit(1)
+		}
+	}
+
 	if name == "" {
 		name = deriveNameFromDir(dir)
 	}
 
-	agent := store.Add(name, dir)
-
-	// Apply backend selection
-	if backendID != "" {
-		if GetBackend(backendID) == nil {
-			fmt.Fprintf(os.Stderr, "Unknown backend: %s\n", backendID)
-			os.Exit(1)
-		}
-		agent.BackendID = backendID
+	cfg, _ := loadConfig(configPath())
+	manager.SetLifecycle(cfg.Lifecycle, OpenEventLog(eventsPath()))
+	queued := !hasCapacity(store.List(), cfg.MaxRunning)
+	agent := store.AddWithBackend(name, dir, backendID)
+	if err := prepareGit(store, agent, gitMode); err != nil {
+		store.Remove(agent.ID)
+		fmt.Fprintf(os.Stderr, "Cannot create branch: %v\n", err)
+		os.Exit(1)
+	}
+	if agent.Worktree != nil {
+		fmt.Printf("Created worktree %s on branch %s\n", agent.Worktree.Path, agent.Branch)
+	} else if agent.Branch != "" {
+		fmt.Printf("Checked out new branch %s\n", agent.Branch)
 	}
 
 	// Apply auto-approve
 	if autoApprove {
 		agent.AutoApprove = true
 	}
+	agent.KeepAlive = keepAlive
+	agent.Checkpoint = checkpoint
+	agent.Timeout = timeout
+	agent.Rules = rules
+	agent.Nudge = nudge
+	agent.Priority = priority
+
+	// A task can run headless with an event stream when configured
+	agent.Prompt = prompt
+	if dep != nil {
+		agent.After = dep.ID
+	} else if cfg.ClaudeStreamJSON {
+		agent.Stream = prompt != "" && canStream(agent.Backend())
+	}
+	agent.Tags = normalizeTags(tags)
+
+	if queued {
+		store.Update(agent.ID, StatusPending)
+		if abs, err := filepath.Abs(dir); err == nil {
+			store.AddRecentDir(abs)
+		}
+		fmt.Printf("Queued %s agent %q (ID: %s): %d agents are already running (max_running). The TUI starts it when a slot frees up.\n", agent.Backend().Name(), name, agent.ID, cfg.MaxRunning)
+		OpenEventLog(eventsPath()).Add(EventSpawn, name, fmt.Sprintf("%s in %s (cli), queued", agent.Backend().Name(), dir))
+		return
+	}
 
 	// Build extra args from auto-approve
 	var extraArgs []string
@@ -194,193 +423,1241 @@ func cmdAdd() {
 	}
 
 	if err := manager.SpawnAgent(agent, extraArgs); err != nil {
+		store.Remove(agent.ID)
 		fmt.Fprintf(os.Stderr, "Failed to spawn agent: %v\n", err)
 		os.Exit(1)
 	}
 
 	store.UpdateSessionName(agent.ID, agent.SessionName)
-	// Persist backend and auto-approve to state
+	// Persist auto-approve, keep-alive, checkpoint, timeout, prompt and tags to state
 	store.Save()
+	if abs, err := filepath.Abs(dir); err == nil {
+		store.AddRecentDir(abs)
+	}
+
+	fmt.Printf("Spawned %s agent %q (ID: %s, session: %s) in %s\n", agent.Backend().Name(), name, agent.ID, agent.SessionName, dir)
+	OpenEventLog(eventsPath()).Add(EventSpawn, name, fmt.Sprintf("%s in %s (cli)", agent.Backend().Name(), dir))
 
-	fmt.Printf("Spawned agent %q (ID: %s, session: %s) in %s\n", name, agent.ID, agent.SessionName, dir)
+	if dep != nil {
+		OpenEventLog(eventsPath()).Add(EventChain, name, "task waits for "+dep.Name)
+		fmt.Printf("Its task waits for %q to finish; the TUI sends it while it's running.\n", dep.Name)
+		return
+	}
 
-	// Send initial prompt after startup delay
-	if prompt != "" {
-		go SendPromptAfterDelay(agent.SessionName, prompt)
+	// Send initial prompt after startup delay. This blocks: a goroutine
+	// would be killed when the CLI process exits.
+	if prompt != "" && !agent.Stream {
+		store.MarkPromptSent(agent.ID, time.Now().Add(promptStartupDelay))
+		fmt.Println("Sending initial prompt...")
+		SendPromptAfterDelay(agent.SessionName, prompt)
+	} else if agent.Stream {
+		store.MarkPromptSent(agent.ID, time.Now())
 	}
 }
 
-func cmdList() {
+// sendWorkspacePrompts delivers the prompts of freshly loaded workspace
+// agents, blocking like cmdAdd, and points out any left waiting on another
+// agent.
+func sendWorkspacePrompts(store *Store, prompts []*Agent) {
+	if len(prompts) > 0 {
+		fmt.Printf("Sending %d initial prompt(s)...\n", len(prompts))
+		sendInitialPrompts(prompts)
+	}
+	held, queued := 0, 0
+	for _, a := range store.List() {
+		if a.After != "" {
+			held++
+		}
+		if a.Status == StatusPending {
+			queued++
+		}
+	}
+	if held > 0 {
+		fmt.Printf("%d task(s) wait for other agents to finish; the TUI sends them while it's running.\n", held)
+	}
+	if queued > 0 {
+		fmt.Printf("%d agent(s) queued by max_running; the TUI starts them as slots free up.\n", queued)
+	}
+}
+
+// cmdSwarm starts a team of agents from a manifest, and shows or kills
+// the teams on the board.
+func cmdSwarm() {
+	usage := "Usage: tickettok swarm <start <manifest.json>|status [name]|kill <name>>"
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
 	store, err := NewStore()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	agents := store.List()
-	if len(agents) == 0 {
-		fmt.Println("No agents.")
-		return
-	}
+	switch os.Args[2] {
+	case "start":
+		if len(os.Args) < 4 {
+			fmt.Fprintln(os.Stderr, "Usage: tickettok swarm start <manifest.json>")
+			os.Exit(1)
+		}
+		s, err := LoadSwarmManifest(os.Args[3])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, a := range swarmMembers(store.List(), s.Name) {
+			if !a.Ended() {
+				fmt.Fprintf(os.Stderr, "Swarm %q is already running; stop it first with: tickettok swarm kill %s\n", s.Name, s.Name)
+				os.Exit(1)
+			}
+		}
+		cfg, _ := loadConfig(configPath())
+		manager := NewAgentManager()
+		manager.SetLifecycle(cfg.Lifecycle, OpenEventLog(eventsPath()))
+		count, prompts := spawnWorkspaceAgents(s.workspace(), store, manager, cfg.MaxRunning)
+		fmt.Printf("Started swarm %q: spawned %d of %d agent(s).\n", s.Name, count, len(s.Agents))
+		sendWorkspacePrompts(store, prompts)
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tDIR\tSESSION")
-	for _, a := range agents {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.ID, a.Name, a.Status, shortenPath(a.Dir), a.SessionName)
+	case "status":
+		names := swarmNames(store.List())
+		if len(os.Args) > 3 {
+			names = []string{os.Args[3]}
+		}
+		if len(names) == 0 {
+			fmt.Println("No swarms.")
+			return
+		}
+		for i, name := range names {
+			members := swarmMembers(store.List(), name)
+			if len(members) == 0 {
+				fmt.Fprintf(os.Stderr, "No swarm named %q\n", name)
+				os.Exit(1)
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s: %s\n", name, swarmStatus(members))
+			w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			for _, a := range members {
+				fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", a.Name, a.Status, a.Backend().ID(), shortenPath(a.Dir))
+			}
+			w.Flush()
+		}
+
+	case "kill":
+		if len(os.Args) < 4 {
+			fmt.Fprintln(os.Stderr, "Usage: tickettok swarm kill <name>")
+			os.Exit(1)
+		}
+		members := swarmMembers(store.List(), os.Args[3])
+		if len(members) == 0 {
+			fmt.Fprintf(os.Stderr, "No swarm named %q\n", os.Args[3])
+			os.Exit(1)
+		}
+		killAgentsCLI(store, members)
+
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
 	}
-	w.Flush()
 }
 
-func cmdKill() {
-	if len(os.Args) < 3 {
-		fmt.Fprintln(os.Stderr, "Usage: tickettok kill <name-or-id>")
+// cmdRace spawns the same prompt on several backends, each in a worktree of
+// its own, to compare the results side by side in the TUI.
+func cmdRace() {
+	usage := "Usage: tickettok race <dir> <prompt> --backends <id,id,...> [--name <name>] [--auto-approve]"
+	if len(os.Args) < 4 {
+		fmt.Fprintln(os.Stderr, usage)
 		os.Exit(1)
 	}
 
-	target := os.Args[2]
+	dir := os.Args[2]
+	prompt := os.Args[3]
+	name := ""
+	var backends []string
+	autoApprove := false
+	for i := 4; i < len(os.Args); i++ {
+		switch os.Args[i] {
+		case "--backends":
+			if i+1 < len(os.Args) {
+				ids, err := parseRaceBackends(os.Args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: --backends: %v\n", err)
+					os.Exit(1)
+				}
+				backends = ids
+				i++
+			}
+		case "--name":
+			if i+1 < len(os.Args) {
+				name = os.Args[i+1]
+				i++
+			}
+		case "--auto-approve":
+			autoApprove = true
+		}
+	}
+	if backends == nil || strings.TrimSpace(prompt) == "" {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	if strings.HasPrefix(dir, "~/") {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, dir[2:])
+	}
+	if _, err := repoToplevel(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "Racers each get a worktree, so %s must be in a git repository\n", dir)
+		os.Exit(1)
+	}
+	if name == "" {
+		name = deriveNameFromDir(dir)
+	}
 
 	store, err := NewStore()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	cfg, _ := loadConfig(configPath())
+	events := OpenEventLog(eventsPath())
+	manager := NewAgentManager()
+	manager.SetLifecycle(cfg.Lifecycle, events)
 
-	agent := store.Get(target)
-	if agent == nil {
-		agent = store.GetByName(target)
+	racers, send, err := spawnRace(store, manager, dir, name, prompt, backends, autoApprove, cfg.MaxRunning)
+	for _, a := range racers {
+		fmt.Printf("Spawned %s agent %q (ID: %s) in %s on %s\n", a.Backend().Name(), a.Name, a.ID, a.Dir, a.Branch)
+		events.Add(EventSpawn, a.Name, fmt.Sprintf("%s in %s (cli race)", a.Backend().Name(), a.Dir))
 	}
-	if agent == nil {
-		fmt.Fprintf(os.Stderr, "Agent not found: %s\n", target)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Race cut short: %v\n", err)
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		store.AddRecentDir(abs)
+	}
+	sendWorkspacePrompts(store, send)
+	if len(racers) > 1 {
+		fmt.Println("Press | on any of them in the TUI to compare them side by side, and p there to pick the winner.")
+	}
+	if err != nil {
 		os.Exit(1)
 	}
+}
 
-	if agent.SessionName != "" {
-		_ = KillBySession(agent.SessionName)
+// readPromptFile reads an initial prompt from path, or from stdin when path
+// is "-".
+func readPromptFile(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
 	}
-
-	store.Update(agent.ID, StatusDone)
-	fmt.Printf("Killed agent %q (ID: %s)\n", agent.Name, agent.ID)
+	if err != nil {
+		return "", err
+	}
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return text, nil
 }
 
-func cmdSend() {
-	if len(os.Args) < 4 {
-		fmt.Fprintln(os.Stderr, "Usage: tickettok send <name-or-id> <message>")
+func cmdList() {
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	target := os.Args[2]
-	message := strings.Join(os.Args[3:], " ")
+	var tag string
+	verbose := false
+	for i := 2; i < len(os.Args); i++ {
+		switch {
+		case os.Args[i] == "--tag" && i+1 < len(os.Args):
+			tag = os.Args[i+1]
+			i++
+		case os.Args[i] == "--verbose" || os.Args[i] == "-v":
+			verbose = true
+		}
+	}
+
+	var agents []*Agent
+	for _, a := range store.List() {
+		if tag == "" || a.HasTag(tag) {
+			agents = append(agents, a)
+		}
+	}
+	if len(agents) == 0 {
+		fmt.Println("No agents.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tBACKEND\tTAGS\tDIR\tSESSION")
+	for _, a := range agents {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", a.ID, a.Name, a.Status, "["+a.BackendLabel()+"] "+a.Backend().ID(), strings.Join(a.Tags, ","), shortenPath(a.Dir), a.SessionName)
+	}
+	w.Flush()
+
+	if !verbose {
+		return
+	}
+	for _, a := range agents {
+		if a.Summary == "" {
+			continue
+		}
+		fmt.Printf("\n%s (%s, %s):\n", a.Name, a.ID, a.Status)
+		for _, l := range strings.Split(a.Summary, "\n") {
+			fmt.Println("  " + l)
+		}
+	}
+}
 
+// cmdStats prints each matching agent's running time, time to its first
+// permission prompt, prompt and turn counts, then their totals.
+func cmdStats() {
 	store, err := NewStore()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	agent := store.Get(target)
-	if agent == nil {
-		agent = store.GetByName(target)
+	var pattern, tag string
+	for i := 2; i < len(os.Args); i++ {
+		switch {
+		case os.Args[i] == "--tag" && i+1 < len(os.Args):
+			tag = os.Args[i+1]
+			i++
+		case !strings.HasPrefix(os.Args[i], "-"):
+			pattern = os.Args[i]
+		}
 	}
-	if agent == nil {
-		fmt.Fprintf(os.Stderr, "Agent not found: %s\n", target)
+	matched, err := MatchAgents(store.List(), pattern, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	var agents []*Agent
+	for _, a := range matched {
+		if tag == "" || a.HasTag(tag) {
+			agents = append(agents, a)
+		}
+	}
+	if len(agents) == 0 {
+		fmt.Println("No agents.")
+		return
+	}
 
-	if agent.SessionName == "" || !IsSessionAlive(agent.SessionName) {
-		fmt.Fprintf(os.Stderr, "Agent %q is not running\n", agent.Name)
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tRUNNING\tFIRST WAIT\tPROMPTS\tTURNS")
+	for _, a := range agents {
+		st := a.Stats(now)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\n", a.ID, a.Name, a.Status, formatStatDuration(st.Running, true), formatStatDuration(st.FirstWait, st.Waited), st.Prompts, st.Turns)
+	}
+	w.Flush()
+	fmt.Printf("\n%s\n", summarizeStats(agents, now))
+}
+
+func cmdKill() {
+	usage := "Usage: tickettok kill <name-or-id|pattern> | --all [--status <STATUS>]"
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, usage)
 		os.Exit(1)
 	}
 
-	cmd := exec.Command("tmux", "send-keys", "-t", agent.SessionName, message, "Enter")
-	if err := cmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to send message: %v\n", err)
+	target := ""
+	all := false
+	var status AgentStatus
+
+	for i := 2; i < len(os.Args); i++ {
+		switch os.Args[i] {
+		case "--all":
+			all = true
+		case "--status":
+			if i+1 < len(os.Args) {
+				st, ok := ParseStatus(os.Args[i+1])
+				if !ok {
+					fmt.Fprintf(os.Stderr, "Unknown status: %s\n", os.Args[i+1])
+					os.Exit(1)
+				}
+				status = st
+				i++
+			}
+		default:
+			target = os.Args[i]
+		}
+	}
+
+	if target == "" && !all && status == "" {
+		fmt.Fprintln(os.Stderr, usage)
 		os.Exit(1)
 	}
 
-	fmt.Printf("Sent to %q: %s\n", agent.Name, message)
-}
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-func cmdStatus() {
-	if len(os.Args) < 3 {
-		fmt.Fprintln(os.Stderr, "Usage: tickettok status <name-or-id>")
+	agents, err := MatchAgents(store.List(), target, status)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(agents) == 0 {
+		fmt.Fprintf(os.Stderr, "No agents matched: %s\n", strings.TrimSpace(target+" "+string(status)))
 		os.Exit(1)
 	}
 
-	target := os.Args[2]
+	killAgentsCLI(store, agents)
+}
+
+// killAgentsCLI kills agents' sessions, leaving them DONE on the board.
+func killAgentsCLI(store *Store, agents []*Agent) {
+	cfg, _ := loadConfig(configPath())
+	events := OpenEventLog(eventsPath())
+	for _, agent := range agents {
+		if err := archiveSession(cfg.TranscriptDir(), agent, "killed (cli)"); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: transcript of %s not saved: %v\n", agent.Name, err)
+		}
+		if agent.SessionName != "" {
+			_ = KillBySession(agent.SessionName)
+		}
+		store.SetExitReason(agent.ID, "killed (cli)")
+		store.Update(agent.ID, StatusDone)
+		events.Add(EventKill, agent.Name, "(cli)")
+		cfg.Lifecycle.fire(LifecycleKill, agent, events)
+		fmt.Printf("Killed agent %q (ID: %s)\n", agent.Name, agent.ID)
+	}
+	for _, err := range removeWorktrees(agents) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+func cmdSend() {
+	if len(os.Args) < 4 {
+		fmt.Fprintln(os.Stderr, "Usage: tickettok send <name-or-id> | --all-running <message>")
+		os.Exit(1)
+	}
+
+	target := os.Args[2]
+	message := strings.Join(os.Args[3:], " ")
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if target == "--all-running" {
+		agents := broadcastTargets(store.List())
+		if len(agents) == 0 {
+			fmt.Fprintln(os.Stderr, "No agents are running")
+			os.Exit(1)
+		}
+		failed := 0
+		for _, r := range broadcast(agents, message, func(a *Agent, msg string) error { return SendPrompt(a.SessionName, msg) }) {
+			if r.err != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "Failed to send to %q: %v\n", r.agent.Name, r.err)
+			} else {
+				store.LogPrompt(r.agent.ID, "broadcast (cli)", message)
+				fmt.Printf("Sent to %q\n", r.agent.Name)
+			}
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	agent := store.Get(target)
+	if agent == nil {
+		agent = store.GetByName(target)
+	}
+	if agent == nil {
+		fmt.Fprintf(os.Stderr, "Agent not found: %s\n", target)
+		os.Exit(1)
+	}
+
+	if agent.SessionName == "" || !IsSessionAlive(agent.SessionName) {
+		fmt.Fprintf(os.Stderr, "Agent %q is not running\n", agent.Name)
+		os.Exit(1)
+	}
+
+	cmd := exec.Command("tmux", "send-keys", "-t", agent.SessionName, message, "Enter")
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to send message: %v\n", err)
+		os.Exit(1)
+	}
+	store.LogPrompt(agent.ID, "send (cli)", message)
+
+	fmt.Printf("Sent to %q: %s\n", agent.Name, message)
+}
+
+func cmdRename() {
+	if len(os.Args) < 4 {
+		fmt.Fprintln(os.Stderr, "Usage: tickettok rename <name-or-id> <new-name>")
+		os.Exit(1)
+	}
+
+	target := os.Args[2]
+	newName := strings.TrimSpace(strings.Join(os.Args[3:], " "))
+	if newName == "" {
+		fmt.Fprintln(os.Stderr, "New name cannot be empty")
+		os.Exit(1)
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	agent := store.Get(target)
+	if agent == nil {
+		agent = store.GetByName(target)
+	}
+	if agent == nil {
+		fmt.Fprintf(os.Stderr, "Agent not found: %s\n", target)
+		os.Exit(1)
+	}
+
+	oldName := agent.Name
+	store.Rename(agent.ID, newName)
+	fmt.Printf("Renamed %q to %q (ID: %s)\n", oldName, newName, agent.ID)
+}
+
+// cmdPriority shows an agent's priority, or sets it.
+func cmdPriority() {
+	const usage = "Usage: tickettok priority <name-or-id> [high|normal|low]"
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	target := os.Args[2]
+	agent := store.Get(target)
+	if agent == nil {
+		agent = store.GetByName(target)
+	}
+	if agent == nil {
+		fmt.Fprintf(os.Stderr, "Agent not found: %s\n", target)
+		os.Exit(1)
+	}
+
+	if len(os.Args) > 3 {
+		p, err := parsePriority(os.Args[3])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		store.SetPriority(agent.ID, p)
+	}
+	fmt.Printf("%s: %s priority\n", agent.Name, priorityName(agent.Priority))
+}
+
+func cmdMark() {
+	const usage = "Usage: tickettok mark <name-or-id> review|none"
+	if len(os.Args) < 4 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	target := os.Args[2]
+	agent := store.Get(target)
+	if agent == nil {
+		agent = store.GetByName(target)
+	}
+	if agent == nil {
+		fmt.Fprintf(os.Stderr, "Agent not found: %s\n", target)
+		os.Exit(1)
+	}
+
+	events := OpenEventLog(eventsPath())
+	switch os.Args[3] {
+	case "review":
+		if agent.Status == StatusPending || agent.Status == StatusPaused {
+			fmt.Fprintf(os.Stderr, "%s is %s; nothing to review yet\n", agent.Name, agent.Status)
+			os.Exit(1)
+		}
+		store.Update(agent.ID, StatusReview)
+		events.Add(EventStatus, agent.Name, "marked for review (cli)")
+	case "none":
+		if agent.Status != StatusReview {
+			fmt.Printf("%s isn't marked (%s)\n", agent.Name, agent.Status)
+			return
+		}
+		store.Update(agent.ID, statusBefore(agent, StatusReview, StatusIdle))
+		events.Add(EventStatus, agent.Name, "reviewed (cli)")
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+	fmt.Printf("%s is %s\n", agent.Name, agent.Status)
+}
+
+func cmdStatus() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "Usage: tickettok status <name-or-id>")
+		os.Exit(1)
+	}
+
+	target := os.Args[2]
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	agent := store.Get(target)
+	if agent == nil {
+		agent = store.GetByName(target)
+	}
+	if agent == nil {
+		fmt.Fprintf(os.Stderr, "Agent not found: %s\n", target)
+		os.Exit(1)
+	}
+
+	status := PassiveStatus(agent)
+	if status == StatusDone && agent.ExitReason != "" {
+		fmt.Printf("%s: %s (%s)\n", agent.Name, status, agent.ExitReason)
+		return
+	}
+	fmt.Printf("%s: %s\n", agent.Name, status)
+}
+
+func cmdPR() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "Usage: tickettok pr <name-or-id>")
+		os.Exit(1)
+	}
+
+	target := os.Args[2]
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	agent := store.Get(target)
+	if agent == nil {
+		agent = store.GetByName(target)
+	}
+	if agent == nil {
+		fmt.Fprintf(os.Stderr, "Agent not found: %s\n", target)
+		os.Exit(1)
+	}
+
+	url, err := openPR(agent)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(url)
+}
+
+// watchEvent is a single status change emitted by `tickettok watch`.
+type watchEvent struct {
+	Time time.Time   `json:"ts"`
+	ID   string      `json:"id"`
+	Name string      `json:"name"`
+	From AgentStatus `json:"from"`
+	To   AgentStatus `json:"to"`
+}
+
+// cmdWatch polls agent statuses and prints one line per status change until interrupted.
+func cmdWatch() {
+	asJSON := false
+	interval := 2 * time.Second
+
+	for i := 2; i < len(os.Args); i++ {
+		switch os.Args[i] {
+		case "--json":
+			asJSON = true
+		case "--interval":
+			if i+1 < len(os.Args) {
+				d, err := time.ParseDuration(os.Args[i+1])
+				if err != nil || d <= 0 {
+					fmt.Fprintf(os.Stderr, "Invalid interval: %s\n", os.Args[i+1])
+					os.Exit(1)
+				}
+				interval = d
+				i++
+			}
+		}
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	last := make(map[string]AgentStatus)
+	for {
+		_ = store.Reload()
+		for _, a := range store.List() {
+			status := PassiveStatus(a)
+			prev, seen := last[a.ID]
+			last[a.ID] = status
+			if !seen || prev == status {
+				continue
+			}
+
+			ev := watchEvent{Time: time.Now(), ID: a.ID, Name: a.Name, From: prev, To: status}
+			if asJSON {
+				_ = enc.Encode(ev)
+			} else {
+				fmt.Printf("%s %s %s\u2192%s\n", ev.Time.Format("15:04:05"), ev.Name, ev.From, ev.To)
+			}
+		}
+		time.Sleep(interval)
+	}
+}
+
+func cmdDiscover() {
+	found := discoverAll()
+
+	if len(found) == 0 {
+		fmt.Println("No running agent instances found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tBACKEND\tNAME\tDIR\tSESSION/PID")
+	for _, d := range found {
+		source := "tmux"
+		id := d.SessionName
+		if d.PID > 0 {
+			source = "process"
+			id = fmt.Sprintf("%d", d.PID)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", source, d.BackendID, d.Name, d.Dir, id)
+	}
+	w.Flush()
+}
+
+// cmdAdopt converts a discovered external tmux session into a managed agent.
+func cmdAdopt() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "Usage: tickettok adopt <tmux-session>")
+		os.Exit(1)
+	}
+
+	sessName := os.Args[2]
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	agent := store.GetBySession(sessName)
+	added := false
+	if agent == nil {
+		// Not tracked yet — find it via discovery so we know its dir and backend
+		var foundBy []string
+		var match *DiscoveredAgent
+		for _, d := range discoverAll() {
+			if d.SessionName == sessName {
+				foundBy = append(foundBy, d.BackendID)
+				match = &d
+			}
+		}
+		if match != nil {
+			content, _ := CapturePanePlain(sessName)
+			agent = store.AddWithBackend(match.Name, match.Dir, detectBackendID(content, foundBy))
+			agent.SessionName = match.SessionName
+			agent.Discovered = true
+			added = true
+		}
+	}
+	if agent == nil {
+		fmt.Fprintf(os.Stderr, "No agent found in tmux session: %s\n", sessName)
+		os.Exit(1)
+	}
+
+	manager := NewAgentManager()
+	if err := manager.AdoptAgent(agent); err != nil {
+		// Don't leave an agent behind for a session it never took over
+		if added {
+			store.Remove(agent.ID)
+		}
+		fmt.Fprintf(os.Stderr, "Adopt failed: %v\n", err)
+		os.Exit(1)
+	}
+	store.UpdateSessionName(agent.ID, agent.SessionName)
+	store.UpdateDiscovered(agent.ID, false)
+
+	fmt.Printf("Adopted %q as agent %s (session: %s)\n", agent.Name, agent.ID, agent.SessionName)
+}
+
+func cmdClear() {
+	var olderThan time.Duration
+	for i := 2; i < len(os.Args); i++ {
+		switch os.Args[i] {
+		case "--older-than":
+			if i+1 < len(os.Args) {
+				d, err := parseAge(os.Args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				olderThan = d
+				i++
+			}
+		case "--archive":
+			// Clearing always archives now
+		}
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	var cutoff time.Time
+	if olderThan > 0 {
+		cutoff = now.Add(-olderThan)
+	}
+
+	cfg, _ := loadConfig(configPath())
+	// Transcripts first, so the archive can point at them, and the archive
+	// before removing, so a failed write never loses records
+	var toArchive []*Agent
+	for _, a := range store.List() {
+		if isClearable(a, cutoff) {
+			toArchive = append(toArchive, a)
+			if err := archiveSession(cfg.TranscriptDir(), a, "cleared (cli)"); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: transcript of %s not saved: %v\n", a.Name, err)
+			}
+		}
+	}
+	if err := appendArchive(archivePath(), toArchive, "cleared", cfg.TranscriptDir(), now); err != nil {
+		fmt.Fprintf(os.Stderr, "Archive failed, nothing cleared: %v\n", err)
+		os.Exit(1)
+	}
+
+	cleared := store.ClearDoneBefore(cutoff)
+	for _, err := range removeWorktrees(cleared) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	fmt.Printf("Cleared %d completed agents (archived to %s).\n", len(cleared), shortenPath(archivePath()))
+}
+
+// cmdTranscript prints an agent's archived output, or lists the
+// transcripts there are.
+func cmdTranscript() {
+	cfg, _ := loadConfig(configPath())
+	dir := cfg.TranscriptDir()
+	if dir == "" {
+		fmt.Fprintln(os.Stderr, `Transcripts are off ("transcripts": "off" in config.json)`)
+		os.Exit(1)
+	}
+
+	if len(os.Args) < 3 {
+		entries, _ := os.ReadDir(dir)
+		if len(entries) == 0 {
+			fmt.Println("No transcripts.")
+			return
+		}
+		for _, p := range sortedByModTime(dir, entries) {
+			if info, err := os.Stat(p); err == nil {
+				fmt.Printf("%s  %6.1f KB  %s\n", info.ModTime().Format("2006-01-02 15:04"), float64(info.Size())/1024, filepath.Base(p))
+			}
+		}
+		return
+	}
+
+	target := os.Args[2]
+	paths := findTranscripts(dir, target)
+	if len(paths) == 0 {
+		fmt.Fprintf(os.Stderr, "No transcript for %s in %s\n", target, shortenPath(dir))
+		os.Exit(1)
+	}
+	path := paths[len(paths)-1]
+	if len(paths) > 1 {
+		fmt.Fprintf(os.Stderr, "%d transcripts match %s; showing the newest, %s\n", len(paths), target, filepath.Base(path))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(data)
+}
+
+// cmdWait blocks until an agent reaches one of the requested statuses.
+// Exit codes: 0 reached, 1 error or agent exited first, 2 timed out.
+func cmdWait() {
+	usage := "Usage: tickettok wait <name-or-id> [--for IDLE|DONE|WAITING[,...]] [--timeout 30m]"
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	target := os.Args[2]
+	want := map[AgentStatus]bool{StatusIdle: true, StatusAsk: true}
+	var timeout time.Duration
+
+	for i := 3; i < len(os.Args); i++ {
+		switch os.Args[i] {
+		case "--for":
+			if i+1 < len(os.Args) {
+				var err error
+				if want, err = parseWaitStatuses(os.Args[i+1]); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				i++
+			}
+		case "--timeout":
+			if i+1 < len(os.Args) {
+				d, err := time.ParseDuration(os.Args[i+1])
+				if err != nil || d <= 0 {
+					fmt.Fprintf(os.Stderr, "Invalid timeout: %s\n", os.Args[i+1])
+					os.Exit(1)
+				}
+				timeout = d
+				i++
+			}
+		}
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	poll := func() (*Agent, AgentStatus) {
+		_ = store.Reload()
+		agent := store.Get(target)
+		if agent == nil {
+			agent = store.GetByName(target)
+		}
+		if agent == nil {
+			return nil, ""
+		}
+		return agent, PassiveStatus(agent)
+	}
+	msg, code := waitForStatus(target, poll, want, timeout, 2*time.Second)
+	if code != waitReached {
+		fmt.Fprintln(os.Stderr, msg)
+		os.Exit(code)
+	}
+	fmt.Println(msg)
+}
+
+// cmdPrune reconciles state.json, hook status files, and live tmux sessions
+// after they drift apart (e.g. following a crash).
+func cmdPrune() {
+	dryRun := false
+	for _, arg := range os.Args[2:] {
+		if arg == "--dry-run" || arg == "-n" {
+			dryRun = true
+		}
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	sessions, err := listTmuxSessions()
+	if err != nil {
+		// Every agent would look dead
+		fmt.Fprintf(os.Stderr, "Error: %v; nothing pruned\n", err)
+		os.Exit(1)
+	}
+	plan := planPrune(store.List(), sessions, listHookStatusIDs())
+	merged, gone := planBranchPrune(store.Branches(), store.List(), plan.DeadAgents, branchMerged)
+	plan.MergedBranches = merged
+	for _, b := range gone {
+		// Deleted by hand; nothing left to clean up
+		if !dryRun {
+			store.ForgetBranch(b)
+		}
+	}
+	if plan.Empty() {
+		fmt.Println("Nothing to prune.")
+		return
+	}
+
+	verb := "Pruned"
+	if dryRun {
+		verb = "Would prune"
+	}
+
+	for _, s := range plan.OrphanSessions {
+		fmt.Printf("  orphan session  %s\n", s)
+		if !dryRun {
+			_ = KillBySession(s)
+		}
+	}
+	for _, a := range plan.DeadAgents {
+		fmt.Printf("  dead agent      %s (ID: %s)\n", a.Name, a.ID)
+		if !dryRun {
+			a.Backend().CleanHookStatus(a.ID)
+			store.Remove(a.ID)
+			for _, err := range removeWorktrees([]*Agent{a}) {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+	}
+	for _, id := range plan.StaleStatus {
+		fmt.Printf("  stale status    %s.json\n", id)
+		if !dryRun {
+			cleanHookStatusFile(id)
+		}
+	}
+	for _, b := range plan.MergedBranches {
+		fmt.Printf("  merged branch   %s (%s)\n", b.Name, shortenPath(b.Repo))
+		if !dryRun {
+			if err := deleteBranch(b); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				continue
+			}
+			store.ForgetBranch(b)
+		}
+	}
+
+	fmt.Printf("%s %d session(s), %d agent(s), %d status file(s), %d branch(es).\n",
+		verb, len(plan.OrphanSessions), len(plan.DeadAgents), len(plan.StaleStatus), len(plan.MergedBranches))
+}
+
+// cmdExport writes the full agent state as JSON to stdout.
+func cmdExport() {
+	format := "json"
+	for i := 2; i < len(os.Args); i++ {
+		switch arg := os.Args[i]; {
+		case arg == "--format" && i+1 < len(os.Args):
+			format = os.Args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+		default:
+			fmt.Fprintln(os.Stderr, "Usage: tickettok export [--format json|markdown|csv]")
+			os.Exit(1)
+		}
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	switch format {
+	case "json":
+	case "markdown", "md":
+		title := "Agents, " + now.Format("2006-01-02 15:04")
+		if board := boardLabel(); board != "" {
+			title = "Agents on the " + board + ", " + now.Format("2006-01-02 15:04")
+		}
+		agents := store.List()
+		if err := writeMarkdownReport(os.Stdout, title, reportRows(agents, now, agentRepo)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(agents) > 0 {
+			fmt.Printf("\n%s\n", summarizeStats(agents, now))
+		}
+		return
+	case "csv":
+		if err := writeCSVReport(os.Stdout, reportRows(store.List(), now, agentRepo)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown export format %q: use json, markdown or csv\n", format)
+		os.Exit(1)
+	}
 
-	store, err := NewStore()
+	data, err := store.Export()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	fmt.Println(string(data))
+}
 
-	agent := store.Get(target)
-	if agent == nil {
-		agent = store.GetByName(target)
-	}
-	if agent == nil {
-		fmt.Fprintf(os.Stderr, "Agent not found: %s\n", target)
+// cmdImport loads agents from a file produced by `tickettok export`.
+func cmdImport() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "Usage: tickettok import <file|-> [--replace]")
 		os.Exit(1)
 	}
 
-	// Try hook-based status first
-	backend := agent.Backend()
-	if status, ok := backend.ReadHookStatus(agent.ID); ok {
-		fmt.Printf("%s: %s\n", agent.Name, status)
-		return
+	path := os.Args[2]
+	replace := false
+	for _, arg := range os.Args[3:] {
+		if arg == "--replace" {
+			replace = true
+		}
 	}
 
-	// Check if session is alive
-	if agent.SessionName == "" || !IsSessionAlive(agent.SessionName) {
-		fmt.Printf("%s: %s\n", agent.Name, StatusDone)
-		return
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+		os.Exit(1)
 	}
 
-	// Fall back to capture-pane detection
-	content, err := CapturePane(agent.SessionName)
+	store, err := NewStore()
 	if err != nil {
-		fmt.Printf("%s: %s\n", agent.Name, StatusRunning)
-		return
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
 
-	result := backend.DetectStatus(content)
-	fmt.Printf("%s: %s\n", agent.Name, result.Status)
+	n, err := store.Import(data, replace)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Import failed: %v\n", err)
+		os.Exit(1)
+	}
+	if replace {
+		fmt.Printf("Replaced board with %d imported agent(s).\n", n)
+	} else {
+		fmt.Printf("Imported %d agent(s). They will resume when zoomed.\n", n)
+	}
 }
 
-func cmdDiscover() {
-	var found []DiscoveredAgent
-	for _, b := range AllBackends() {
-		found = append(found, b.Discover()...)
+// cmdArchive lists, searches or shows the agents archived off the board.
+func cmdArchive() {
+	usage := "Usage: tickettok archive [list [--limit <n>] | search <query> | show <#>]"
+	entries, err := readArchive(archivePath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
 
-	if len(found) == 0 {
-		fmt.Println("No running agent instances found.")
+	sub := "list"
+	if len(os.Args) > 2 {
+		sub = os.Args[2]
+	}
+	limit := 20
+	var matches []int
+	switch sub {
+	case "list":
+		for i := 3; i < len(os.Args); i++ {
+			if os.Args[i] == "--limit" && i+1 < len(os.Args) {
+				if limit, err = strconv.Atoi(os.Args[i+1]); err != nil || limit < 1 {
+					fmt.Fprintln(os.Stderr, usage)
+					os.Exit(1)
+				}
+				i++
+			}
+		}
+		matches = searchArchive(entries, "")
+	case "search":
+		if len(os.Args) < 4 {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(1)
+		}
+		limit = 0
+		matches = searchArchive(entries, strings.Join(os.Args[3:], " "))
+	case "show":
+		n := 0
+		if len(os.Args) > 3 {
+			n, _ = strconv.Atoi(strings.TrimPrefix(os.Args[3], "#"))
+		}
+		if n < 1 || n > len(entries) {
+			fmt.Fprintf(os.Stderr, "No archived agent #%s; see tickettok archive list\n", strings.Join(os.Args[3:], " "))
+			os.Exit(1)
+		}
+		fmt.Println(strings.Join(archiveDetail(entries[n-1]), "\n"))
 		return
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
 	}
 
+	if len(matches) == 0 {
+		fmt.Println("No archived agents.")
+		return
+	}
 	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
-	fmt.Fprintln(w, "SOURCE\tNAME\tDIR\tSESSION/PID")
-	for _, d := range found {
-		source := "tmux"
-		id := d.SessionName
-		if d.PID > 0 {
-			source = "process"
-			id = fmt.Sprintf("%d", d.PID)
-		}
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", source, d.Name, d.Dir, id)
+	fmt.Fprintln(w, "#\tARCHIVED\tNAME\tSTATUS\tRAN\tDIR\tTASK")
+	shown := matches
+	if limit > 0 && len(shown) > limit {
+		shown = shown[:limit]
+	}
+	for _, i := range shown {
+		e := entries[i]
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", i+1, e.ArchivedAt.Local().Format("2006-01-02 15:04"), e.Name, e.Status, formatStatDuration(e.Ran(), true), shortenPath(e.Dir), archiveTask(e, 50))
 	}
 	w.Flush()
+	if len(shown) < len(matches) {
+		fmt.Printf("\n%d more; pass --limit to see them.\n", len(matches)-len(shown))
+	}
+	fmt.Println("\nSee one in full with: tickettok archive show <#>")
 }
 
-func cmdClear() {
+// cmdRestore lists the board's state backups, or puts one back.
+func cmdRestore() {
 	store, err := NewStore()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	n := store.ClearDone()
-	fmt.Printf("Cleared %d completed agents.\n", n)
+	if len(os.Args) < 3 {
+		backups := listStateBackups(store.path)
+		if len(backups) == 0 {
+			fmt.Println("No backups yet.")
+			return
+		}
+		now := time.Now()
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "BACKUP\tTAKEN\tAGENTS")
+		for _, b := range backups {
+			fmt.Fprintf(w, "%d\t%s (%s ago)\t%d\n", b.N, b.At.Format("2006-01-02 15:04"), formatStatDuration(now.Sub(b.At), true), b.Agents)
+		}
+		w.Flush()
+		fmt.Println("\nRestore one with: tickettok restore <backup>")
+		return
+	}
+
+	n, err := strconv.Atoi(os.Args[2])
+	if err != nil || n < 1 || n > stateBackups {
+		fmt.Fprintf(os.Stderr, "Usage: tickettok restore [1-%d]\n", stateBackups)
+		os.Exit(1)
+	}
+	count, err := store.RestoreBackup(n)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Restore failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Restored backup %d: %d agent(s). The board it replaced is now backup 1.\n", n, count)
 }
 
 func printUsage() {
@@ -389,49 +1666,182 @@ func printUsage() {
 Usage:
   tickettok              Launch the TUI dashboard
   tickettok start        Launch the TUI dashboard
+  tickettok --board <name> [command]
+                         Work on a named board: its own agents, state and event
+                         feed, apart from the default board's (any command)
+  tickettok --state-dir <dir> [command]
+                         Keep state, config and hook files in dir instead of
+                         ~/.tickettok (also $TICKETTOK_HOME)
+  tickettok --global [command]
+                         Work on the default board from inside a project with a
+                         .tickettok directory, whose own board is used otherwise
   tickettok add <dir> [flags]
                          Spawn an agent headlessly
     --name <name>        Agent display name (default: dir basename)
-    --backend <id>       Backend to use: claude, codex, gemini
+    --backend <id>       Backend to use: claude, codex, gemini, qwen, interpreter, or a plugin ID
     --prompt <text>      Initial prompt sent after agent starts
+    --prompt-file <f>    Read initial prompt from file (- for stdin); may be multi-line
+    --after <agent>      Hold the prompt until that agent is DONE or back at IDLE
+    --tag <tag>          Label the agent (repeatable, or comma-separated)
     --auto-approve       Enable auto-approve mode for the backend
+    --keep-alive         Restart the agent (resuming) if its session dies
+    --checkpoint         Commit the agent's changes each time it goes IDLE or DONE
+    --timeout <duration> Mark it TIMED-OUT if still working after this long (e.g. 2h)
+    --rule <rule>        Answer matching permission prompts, e.g. "approve Read" (repeatable)
+    --nudge <rule>       Send "continue" when it stops with work left, e.g. "3x continue until DONE"
+    --priority <p>       high, normal or low: order on the board and in alerts
+    --branch             Check out a new branch for the agent first
+    --worktree           Run in a new git worktree on its own branch
+  tickettok race <dir> <prompt> --backends <id,id,...>
+                         Spawn the prompt on each backend, each in its own worktree,
+                         to compare the results side by side (| in the TUI)
+    --name <name>        Base name; each agent is <name>-<backend>
+    --auto-approve       Enable auto-approve mode for each backend
+  tickettok swarm start <manifest.json>
+                         Spawn a team of agents on one feature, each given its task
+                         plus the manifest's shared context
+  tickettok swarm status [name]
+                         Show each swarm's agents and their statuses
+  tickettok swarm kill <name>
+                         Kill every agent in a swarm
+  tickettok priority <name-or-id> [high|normal|low]
+                         Show or set an agent's priority
   tickettok send <name-or-id> <message>
                          Send a message to a running agent
+    --all-running        Send it to every agent with a live session instead
   tickettok status <name-or-id>
                          Check an agent's current status
-  tickettok list         List all agents
-  tickettok kill <name>  Kill an agent by name or ID
+  tickettok watch [--json] [--interval 2s]
+                         Stream agent status changes until interrupted
+  tickettok wait <name-or-id> [--for IDLE|DONE|WAITING] [--timeout 30m]
+                         Block until an agent reaches a status (exit 2 on timeout)
+  tickettok list [--tag <tag>] [--verbose]
+                         List all agents, optionally only those with a tag;
+                         --verbose adds what each one said when it last finished
+  tickettok stats [name-or-id|pattern] [--tag <tag>]
+                         Running time, time to the first permission prompt, prompts
+                         and finished turns per agent, with totals
+  tickettok kill <name>  Kill an agent by name, ID, or glob (e.g. 'api-*')
+    --all                Kill every agent
+    --status <STATUS>    Only kill agents in this status (e.g. DONE, IDLE)
+  tickettok rename <name-or-id> <new-name>
+                         Rename an agent
+  tickettok mark <name-or-id> review|none
+                         Mark an agent NEEDS-REVIEW, or take the mark off
   tickettok discover     Scan for running agent instances
-  tickettok clear        Remove completed agents
+  tickettok adopt <tmux-session>
+                         Take over a discovered session as a managed agent
+  tickettok clear        Remove completed agents, keeping them in the archive
+    --older-than <age>   Only clear agents done for longer than age (e.g. 24h, 7d)
+  tickettok archive [list [--limit <n>] | search <query> | show <#>]
+                         Look back on agents that were killed or cleared: their
+                         task, summary, run time and saved output
+  tickettok transcript [<id-or-name>]
+                         Print an agent's output saved when it was killed or cleared,
+                         or list the saved transcripts
+  tickettok prune [--dry-run]
+                         Kill orphaned sessions, drop dead agents and stale status files,
+                         and delete merged agent branches
+  tickettok pr <name-or-id>
+                         Push the agent's branch and open a pull request with gh
+  tickettok export [--format json|markdown|csv]
+                         Write the full board state as JSON to stdout, or a
+                         report to share: a standup-style markdown summary, or
+                         CSV for spreadsheets (agent, repo, status, duration,
+                         task, summary)
+  tickettok import <file|-> [--replace]
+                         Add agents from an export (--replace restores it exactly)
+  tickettok restore [n]  List the board's state backups, or put backup n back
+  tickettok hooks <install|uninstall|status> [--backend <id>]
+                         Manage status hooks in each backend's settings
+  tickettok backends     Show each backend's CLI, version, and hook status
   tickettok workspace save <name>          Save current agents as workspace
   tickettok workspace load <name>          Clear current + spawn workspace agents
   tickettok workspace add <name>           Spawn workspace agents alongside current
   tickettok workspace list                 List saved workspaces
   tickettok workspace create <name>        Create empty workspace
+  tickettok workspace import <project.yml> [--name <name>]
+                                           Make a workspace of a tmuxinator or
+                                           teamocil project's agent panes
   tickettok workspace delete <name>        Delete saved workspace
   tickettok workspace agent <ws> <dir> [flags]
                                            Add agent template to workspace
+  tickettok define save <name> <dir> [flags]
+                                           Save an agent under a name to spawn again:
+                                           --backend, --prompt, --prompt-file, --tag,
+                                           --rule, --auto-approve, --keep-alive,
+                                           --checkpoint, --branch and --worktree as
+                                           for add; {{key}} in the prompt is filled in
+                                           by up
+  tickettok define save <name> --from <agent>
+                                           Save a definition of an agent on the board
+  tickettok define list                    List agent definitions
+  tickettok define show <name>             Print an agent definition
+  tickettok define delete <name>           Delete an agent definition
+  tickettok up <name>... [--set <key>=<value>]...
+                                           Spawn agents from their definitions
   tickettok help         Show this help
 
 TUI Keybindings:
   ↑/↓ or j/k    Navigate agents (board mode)
   ←/→ or h/l    Cycle agents (carousel mode)
-  1/2/3          Switch column mode
+  1/2/3/4        Switch column mode (4 = compact list)
   N              Spawn new agent
-  W              Workspace manager
+  Shift+D        Clone selected agent: spawn dialog with its dir, backend, and prompt
+  W              Jump to the next WAITING or ASK agent (wraps)
+  Shift+W        Workspace manager
   Enter          Zoom into agent (Ctrl+Q to return)
+                 In zoom: Ctrl+F searches scrollback, n/N jump between matches
+                 In zoom: Ctrl+Y selects lines to copy to the clipboard
+                 In zoom: Ctrl+O toggles observe-only (keys aren't sent to the agent)
   S              Send message to agent
+  R              Rename selected agent
+  T              Edit tags on selected agent (filter with /#tag)
+  E              Edit note on selected agent (shown on card and detail panel)
+  /              Filter agents by name, dir, backend, or output (Esc clears)
+  O              Cycle column sort: created, last change, name, attention
+  P              Pin selected agent to a column regardless of status
+  I              Toggle detail panel for the selected agent
+  G              Group agents by project (git repo) or swarm; z folds a group, Z unfolds all
+  Z              Board: collapse the selected column (Shift+Z expands all)
+  + / -          Board: widen / narrow the selected column (saved across restarts)
+  |              Split view: selected agent beside the next, independent scroll
+  L              Event feed: spawns, status changes, kills, discoveries
+  %              Stats: running time, permission prompts and turns per agent
+  Shift+I        Archive: search agents that were killed or cleared
+  V              Boards: switch to another board or start a new one
+  Shift+B        Backends: installed CLIs, versions, hook registration
+  Y              Approve a waiting agent's prompt without zooming (first option)
+  Shift+Y        Pick any of the prompt's options (allow always, deny, ...)
+  Shift+H        Hand the task to another backend: kills the session, respawns
+                 there with the original prompt and note
+  Shift+O        Start over: retry the agent's task in a fresh session from its
+                 original prompt (not a resume); the card shows TRY 2, 3, ...
   K              Kill selected agent
+  Shift+M        Keep alive: restart the agent, resuming, if its session dies
   D              Discover running instances
+  A              Adopt selected discovered agent
   C              Clear completed agents
-  Q              Quit
-
-Requires: tmux + at least one agent CLI (claude, codex, or gemini)`)
+  Shift+C        Checkpoint: commit the agent's changes each time it goes IDLE or DONE
+  Shift+P        Push the agent's branch and open a pull request (needs gh)
+  Shift+F        Pause the agent (SIGSTOP its processes) / resume it
+  M              Mark the agent NEEDS-REVIEW / unmark it once its output is read
+  Shift+X        Set a max runtime; past it the agent goes TIMED-OUT (see on_timeout)
+  Shift+V        Set approval rules that answer the agent's permission prompts
+  Shift+N        Nudge: send a follow-up like "continue" when it stops with work left
+  !              Cycle the agent's priority: normal, high, low
+  U              Undo the last kill or clear (within 30 seconds)
+  Shift+U        Install available update
+  ?              Show all keybindings
+  Q              Quit; asks first while agents are RUNNING (detach or kill sessions)
+  Ctrl+C         Quit at once, leaving sessions running
+
+Requires: tmux + at least one agent CLI (claude, codex, gemini, qwen, or interpreter)`)
 }
 
 func cmdWorkspace() {
 	if len(os.Args) < 3 {
-		fmt.Fprintln(os.Stderr, "Usage: tickettok workspace <save|load|add|list|create|delete|agent> ...")
+		fmt.Fprintln(os.Stderr, "Usage: tickettok workspace <save|load|add|list|create|import|delete|agent> ...")
 		os.Exit(1)
 	}
 
@@ -472,17 +1882,30 @@ func cmdWorkspace() {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+		cfg, _ := loadConfig(configPath())
 		// Kill all current agents
-		for _, a := range store.List() {
+		replaced := store.List()
+		for _, a := range replaced {
+			_ = archiveSession(cfg.TranscriptDir(), a, "replaced by a workspace")
+		}
+		if err := archiveAgents(replaced, "replaced by a workspace", cfg.TranscriptDir()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: archive not written: %v\n", err)
+		}
+		for _, a := range replaced {
 			if a.SessionName != "" {
 				_ = KillBySession(a.SessionName)
 			}
 			a.Backend().CleanHookStatus(a.ID)
 			store.Remove(a.ID)
+			for _, err := range removeWorktrees([]*Agent{a}) {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
 		}
 		manager := NewAgentManager()
-		count := spawnWorkspaceAgents(wf, store, manager)
+		manager.SetLifecycle(cfg.Lifecycle, OpenEventLog(eventsPath()))
+		count, prompts := spawnWorkspaceAgents(wf, store, manager, cfg.MaxRunning)
 		fmt.Printf("Loaded workspace %q: spawned %d agent(s).\n", name, count)
+		sendWorkspacePrompts(store, prompts)
 
 	case "add":
 		if len(os.Args) < 4 {
@@ -500,9 +1923,12 @@ func cmdWorkspace() {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+		cfg, _ := loadConfig(configPath())
 		manager := NewAgentManager()
-		count := spawnWorkspaceAgents(wf, store, manager)
+		manager.SetLifecycle(cfg.Lifecycle, OpenEventLog(eventsPath()))
+		count, prompts := spawnWorkspaceAgents(wf, store, manager, cfg.MaxRunning)
 		fmt.Printf("Added workspace %q: spawned %d agent(s).\n", name, count)
+		sendWorkspacePrompts(store, prompts)
 
 	case "list":
 		names, err := ListWorkspaces()
@@ -541,6 +1967,50 @@ func cmdWorkspace() {
 		}
 		fmt.Printf("Created empty workspace %q.\n", name)
 
+	case "import":
+		if len(os.Args) < 4 {
+			fmt.Fprintln(os.Stderr, "Usage: tickettok workspace import <project.yml> [--name <name>]")
+			os.Exit(1)
+		}
+		name := ""
+		for i := 4; i < len(os.Args); i++ {
+			if os.Args[i] == "--name" && i+1 < len(os.Args) {
+				name = os.Args[i+1]
+				i++
+			}
+		}
+		wf, imp, err := ImportTmuxProject(os.Args[3], name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if WorkspaceExists(wf.Name) {
+			fmt.Fprintf(os.Stderr, "Workspace %q already exists; pick another name with --name.\n", wf.Name)
+			os.Exit(1)
+		}
+		if len(wf.Agents) == 0 {
+			fmt.Fprintln(os.Stderr, "No pane runs an agent CLI; nothing to import.")
+			os.Exit(1)
+		}
+		wf.CreatedAt = time.Now()
+		if err := writeWorkspace(wf); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Imported workspace %q with %d agent(s):\n", wf.Name, len(wf.Agents))
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, a := range wf.Agents {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", a.Name, a.BackendID, a.Dir)
+		}
+		w.Flush()
+		if len(imp.Skipped) > 0 {
+			fmt.Printf("Skipped %d pane(s) not running an agent CLI:\n", len(imp.Skipped))
+			for _, s := range imp.Skipped {
+				fmt.Printf("  %s\n", s)
+			}
+		}
+		fmt.Printf("Start them with: tickettok workspace add %s\n", wf.Name)
+
 	case "delete":
 		if len(os.Args) < 4 {
 			fmt.Fprintln(os.Stderr, "Usage: tickettok workspace delete <name>")
@@ -555,7 +2025,7 @@ func cmdWorkspace() {
 
 	case "agent":
 		if len(os.Args) < 5 {
-			fmt.Fprintln(os.Stderr, "Usage: tickettok workspace agent <workspace> <dir> [--name <name>] [--backend <id>] [--auto-approve]")
+			fmt.Fprintln(os.Stderr, "Usage: tickettok workspace agent <workspace> <dir> [--name <name>] [--backend <id>] [--prompt <text>] [--after <name>] [--auto-approve] [--branch | --worktree]")
 			os.Exit(1)
 		}
 		wsName := os.Args[3]
@@ -563,7 +2033,10 @@ func cmdWorkspace() {
 
 		agentName := ""
 		backendID := ""
+		prompt := ""
+		after := ""
 		autoApprove := false
+		branch, worktree := false, false
 
 		for i := 5; i < len(os.Args); i++ {
 			switch os.Args[i] {
@@ -577,8 +2050,22 @@ func cmdWorkspace() {
 					backendID = os.Args[i+1]
 					i++
 				}
+			case "--prompt":
+				if i+1 < len(os.Args) {
+					prompt = os.Args[i+1]
+					i++
+				}
+			case "--after":
+				if i+1 < len(os.Args) {
+					after = os.Args[i+1]
+					i++
+				}
 			case "--auto-approve":
 				autoApprove = true
+			case "--branch":
+				branch = true
+			case "--worktree":
+				worktree = true
 			}
 		}
 
@@ -601,6 +2088,10 @@ func cmdWorkspace() {
 			Dir:         dir,
 			BackendID:   backendID,
 			AutoApprove: autoApprove,
+			Branch:      branch && !worktree,
+			Worktree:    worktree,
+			Prompt:      prompt,
+			After:       after,
 		}
 		if err := AddAgentToWorkspace(wsName, wa); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -610,13 +2101,346 @@ func cmdWorkspace() {
 
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown workspace command: %s\n", sub)
-		fmt.Fprintln(os.Stderr, "Usage: tickettok workspace <save|load|add|list|create|delete|agent> ...")
+		fmt.Fprintln(os.Stderr, "Usage: tickettok workspace <save|load|add|list|create|import|delete|agent> ...")
+		os.Exit(1)
+	}
+}
+
+// cmdDefine saves, lists, shows and deletes named agent definitions.
+func cmdDefine() {
+	const usage = "Usage: tickettok define <save|list|show|delete> ..."
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+
+	switch sub := os.Args[2]; sub {
+	case "save":
+		if len(os.Args) < 5 {
+			fmt.Fprintln(os.Stderr, "Usage: tickettok define save <name> <dir> [--backend <id>] [--prompt <template> | --prompt-file <file|->] [--tag <tag>]... [--rule <rule>]... [--auto-approve] [--keep-alive] [--checkpoint] [--branch | --worktree]")
+			fmt.Fprintln(os.Stderr, "       tickettok define save <name> --from <agent>")
+			os.Exit(1)
+		}
+		name := os.Args[3]
+		if err := checkDefinitionName(name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var d *AgentDefinition
+		args := os.Args[4:]
+		if args[0] == "--from" {
+			if len(args) < 2 {
+				fmt.Fprintln(os.Stderr, "--from needs an agent name or ID")
+				os.Exit(1)
+			}
+			store, err := NewStore()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			a := store.Get(args[1])
+			if a == nil {
+				a = store.GetByName(args[1])
+			}
+			if a == nil {
+				fmt.Fprintf(os.Stderr, "Agent not found: %s\n", args[1])
+				os.Exit(1)
+			}
+			d = definitionFromAgent(name, a)
+		} else {
+			dir := args[0]
+			if !strings.HasPrefix(dir, "~/") {
+				if abs, err := filepath.Abs(dir); err == nil {
+					dir = abs
+				}
+			}
+			d = &AgentDefinition{Name: name, Dir: dir}
+			for i := 1; i < len(args); i++ {
+				switch args[i] {
+				case "--backend":
+					if i+1 < len(args) {
+						d.BackendID = args[i+1]
+						i++
+					}
+				case "--prompt":
+					if i+1 < len(args) {
+						d.Prompt = args[i+1]
+						i++
+					}
+				case "--prompt-file":
+					if i+1 < len(args) {
+						text, err := readPromptFile(args[i+1])
+						if err != nil {
+							fmt.Fprintf(os.Stderr, "Cannot read prompt: %v\n", err)
+							os.Exit(1)
+						}
+						d.Prompt = text
+						i++
+					}
+				case "--tag":
+					if i+1 < len(args) {
+						d.Tags = append(d.Tags, args[i+1])
+						i++
+					}
+				case "--rule":
+					if i+1 < len(args) {
+						if _, err := parseRule(args[i+1]); err != nil {
+							fmt.Fprintf(os.Stderr, "Error: --rule: %v\n", err)
+							os.Exit(1)
+						}
+						d.Rules = append(d.Rules, args[i+1])
+						i++
+					}
+				case "--auto-approve":
+					d.AutoApprove = true
+				case "--keep-alive":
+					d.KeepAlive = true
+				case "--checkpoint":
+					d.Checkpoint = true
+				case "--branch":
+					d.Branch = true
+				case "--worktree":
+					d.Worktree = true
+				}
+			}
+			d.Tags = normalizeTags(d.Tags)
+			if d.BackendID != "" && GetBackend(d.BackendID) == nil {
+				fmt.Fprintf(os.Stderr, "Unknown backend: %s\n", d.BackendID)
+				os.Exit(1)
+			}
+		}
+		d.CreatedAt = time.Now()
+		if err := SaveDefinition(d); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Saved agent definition %q. Spawn it with: tickettok up %s\n", name, name)
+		if vars := promptVars(d.Prompt); len(vars) > 0 {
+			fmt.Printf("Its prompt fills in %s; pass values with --set <key>=<value>.\n", strings.Join(vars, ", "))
+		}
+
+	case "list":
+		names, err := ListDefinitions()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(names) == 0 {
+			fmt.Println("No agent definitions. Save one with: tickettok define save <name> <dir>")
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tBACKEND\tDIR\tTAGS")
+		for _, n := range names {
+			d, err := LoadDefinition(n)
+			if err != nil {
+				continue
+			}
+			backend := d.BackendID
+			if backend == "" {
+				backend = DefaultBackend().ID()
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", n, backend, d.Dir, strings.Join(d.Tags, ","))
+		}
+		w.Flush()
+
+	case "show":
+		if len(os.Args) < 4 {
+			fmt.Fprintln(os.Stderr, "Usage: tickettok define show <name>")
+			os.Exit(1)
+		}
+		d, err := LoadDefinition(os.Args[3])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		data, _ := json.MarshalIndent(d, "", "  ")
+		fmt.Println(string(data))
+
+	case "delete":
+		if len(os.Args) < 4 {
+			fmt.Fprintln(os.Stderr, "Usage: tickettok define delete <name>")
+			os.Exit(1)
+		}
+		if err := DeleteDefinition(os.Args[3]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Deleted agent definition %q.\n", os.Args[3])
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown define command: %s\n", sub)
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+}
+
+// cmdUp spawns agents from their saved definitions, alongside the board's
+// current agents.
+func cmdUp() {
+	var names []string
+	vars := map[string]string{}
+	for i := 2; i < len(os.Args); i++ {
+		switch os.Args[i] {
+		case "--set":
+			if i+1 < len(os.Args) {
+				k, v, err := parseSetFlag(os.Args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				vars[k] = v
+				i++
+			}
+		default:
+			names = append(names, os.Args[i])
+		}
+	}
+	if len(names) == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: tickettok up <definition>... [--set <key>=<value>]...")
+		os.Exit(1)
+	}
+
+	// Every definition is checked before any agent is spawned
+	wf := &WorkspaceFile{Name: strings.Join(names, " ")}
+	for _, n := range names {
+		d, err := LoadDefinition(n)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		t, err := d.template(vars)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		wf.Agents = append(wf.Agents, t)
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	cfg, _ := loadConfig(configPath())
+	manager := NewAgentManager()
+	manager.SetLifecycle(cfg.Lifecycle, OpenEventLog(eventsPath()))
+	count, prompts := spawnWorkspaceAgents(wf, store, manager, cfg.MaxRunning)
+	fmt.Printf("Spawned %d of %d agent(s).\n", count, len(wf.Agents))
+	sendWorkspacePrompts(store, prompts)
+}
+
+// cmdHooks explicitly installs, removes, or reports backend hook registration.
+func cmdHooks() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "Usage: tickettok hooks <install|uninstall|status> [--backend <claude|codex|gemini|qwen>]")
+		os.Exit(1)
+	}
+
+	sub := os.Args[2]
+	backendID := ""
+	for i := 3; i < len(os.Args); i++ {
+		if os.Args[i] == "--backend" && i+1 < len(os.Args) {
+			backendID = os.Args[i+1]
+			i++
+		}
+	}
+
+	targets, err := selectBackends(backendID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch sub {
+	case "install":
+		failed := false
+		for _, b := range targets {
+			if !b.Capabilities().SupportsHooks {
+				if backendID != "" {
+					fmt.Fprintf(os.Stderr, "%s: does not support hooks\n", b.Name())
+					failed = true
+				}
+				continue
+			}
+			_ = setHooksDisabled(b.ID(), false)
+			if err := b.InstallHooks(); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: install failed: %v\n", b.Name(), err)
+				failed = true
+				continue
+			}
+			fmt.Printf("%s: hooks installed\n", b.Name())
+		}
+		if failed {
+			os.Exit(1)
+		}
+
+	case "uninstall":
+		failed := false
+		for _, b := range targets {
+			if !b.Capabilities().SupportsHooks {
+				continue
+			}
+			if err := b.UninstallHooks(); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: uninstall failed: %v\n", b.Name(), err)
+				failed = true
+				continue
+			}
+			_ = setHooksDisabled(b.ID(), true)
+			fmt.Printf("%s: hooks removed\n", b.Name())
+		}
+		if failed {
+			os.Exit(1)
+		}
+
+	case "status":
+		disabled := hooksDisabled()
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "BACKEND\tHOOKS\tAUTO-INSTALL")
+		for _, b := range targets {
+			state := hookState(b)
+			auto := "on"
+			if disabled[b.ID()] {
+				auto = "off"
+			}
+			if state == "unsupported" {
+				auto = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", b.ID(), state, auto)
+		}
+		w.Flush()
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown hooks command: %s\n", sub)
+		fmt.Fprintln(os.Stderr, "Usage: tickettok hooks <install|uninstall|status> [--backend <claude|codex|gemini|qwen>]")
 		os.Exit(1)
 	}
 }
 
+func cmdBackends() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "BACKEND\tNAME\tCLI\tVERSION\tHOOKS")
+	for _, h := range backendHealth() {
+		cli := "installed"
+		if !h.Installed {
+			cli = "missing: " + h.Hint
+		}
+		version := h.Version
+		if version == "" {
+			version = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", h.ID, h.Name, cli, version, h.Hooks)
+	}
+	w.Flush()
+}
+
 func installBackendHooks() {
+	disabled := hooksDisabled()
 	for _, b := range AllBackends() {
+		if disabled[b.ID()] || !b.Capabilities().SupportsHooks {
+			continue
+		}
 		if err := b.InstallHooks(); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: could not install %s hooks: %v\n", b.Name(), err)
 		}
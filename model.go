@@ -12,6 +12,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -41,6 +43,24 @@ const (
 	viewConfirmAutoApprove
 	viewWorkspace
 	viewBatch
+	viewRename
+	viewFilter
+	viewTags
+	viewHelp
+	viewPin
+	viewSplit
+	viewEvents
+	viewNote
+	viewApprove
+	viewQuit
+	viewHandoff
+	viewBackends
+	viewTimeout
+	viewRules
+	viewNudge
+	viewStats
+	viewBoards
+	viewArchive
 )
 
 // spawnFocus tracks which section of the spawn dialog has focus.
@@ -49,7 +69,10 @@ type spawnFocus int
 const (
 	focusBackend spawnFocus = iota // arrow keys change backend selection
 	focusDir                       // typing goes to textinput, arrows navigate suggestions
+	focusPrompt                    // typing goes to the initial prompt textarea
 	focusApprove                   // auto-approve toggle
+	focusBranch                    // branch/worktree picker
+	focusAfter                     // agent whose finish releases the prompt
 )
 
 // tickMsg is sent periodically to refresh status.
@@ -58,12 +81,25 @@ type tickMsg time.Time
 // zoomTickMsg carries captured tmux pane content for zoom view.
 type zoomTickMsg struct{ content string }
 
+// splitTickMsg carries captured content for each pane of split view.
+type splitTickMsg struct{ content []string }
+
 // discoverMsg carries newly discovered external Claude agents.
 type discoverMsg struct{ found []DiscoveredAgent }
 
+// backendHealthMsg carries the result of probing every backend for the
+// backends overlay.
+type backendHealthMsg struct{ rows []BackendHealth }
+
 // reconcileMsg signals that stale discovered agents have been reconciled.
 type reconcileMsg struct{}
 
+// stateChangedMsg signals that another process wrote the board's state.
+type stateChangedMsg struct{}
+
+// stateFlushMsg has the state's held changes written out.
+type stateFlushMsg struct{}
+
 // Model is the Bubble Tea application model.
 type Model struct {
 	store    *Store
@@ -77,15 +113,35 @@ type Model struct {
 
 	// Spawn dialog fields
 	spawnDir         textinput.Model
-	spawnSuggestions []string  // filtered directory matches
-	spawnSelIdx      int       // selected suggestion index (-1 = none)
-	spawnBackends    []Backend // available backends (populated on dialog open)
-	spawnBackendIdx  int       // currently selected backend index
-	spawnFocus       spawnFocus // focusBackend, focusDir, or focusApprove
-	spawnAutoApprove bool       // toggle: bypass permission checks
-
-	// Send dialog
-	sendInput textinput.Model
+	spawnSuggestions []string       // filtered directory matches
+	spawnSelIdx      int            // selected suggestion index (-1 = none)
+	spawnQuick       int            // leading suggestions that are favorite/recent dirs
+	spawnBackends    []Backend      // available backends (populated on dialog open)
+	spawnBackendIdx  int            // currently selected backend index
+	lastSpawnBackend string         // backend ID of the previous spawn
+	spawnFocus       spawnFocus     // focusBackend, focusDir, focusPrompt, focusApprove, focusBranch, or focusAfter
+	spawnAutoApprove bool           // toggle: bypass permission checks
+	spawnBranch      branchMode     // new branch or worktree for the agent
+	spawnPrompt      textarea.Model // optional initial task sent after startup
+	spawnAfter       []*Agent       // agents the prompt can wait for
+	spawnAfterIdx    int            // index into spawnAfter (-1 = send right away)
+	spawnCloneOf     string         // name of the agent being cloned, "" for a fresh spawn
+
+	// Send dialog, and the agents it goes to when sending to several
+	sendInput   textinput.Model
+	broadcastTo []*Agent
+
+	// Rename dialog
+	renameInput textinput.Model
+
+	// Timeout dialog
+	timeoutInput textinput.Model
+
+	// Approval rules dialog
+	rulesInput textinput.Model
+
+	// Nudge rule dialog
+	nudgeInput textinput.Model
 
 	// Zoom mode
 	zoomAgentID    string
@@ -95,16 +151,165 @@ type Model struct {
 	zoomTotalLines int      // total lines in captured content
 	zoomAltBracket bool     // true after receiving alt+[ (potential SGR mouse prefix)
 
+	// Observe-only zoom: keys scroll locally instead of reaching the agent
+	zoomObserve bool
+
+	// Zoom scrollback search
+	zoomSearching   bool            // typing a query into zoomSearchInput
+	zoomSearchInput textinput.Model // query prompt shown in the zoom footer
+	zoomQuery       string          // active query; "" when not searching
+	zoomMatches     []int           // line indices in zoomContent containing zoomQuery
+	zoomMatchIdx    int             // current match within zoomMatches
+
+	// Zoom copy mode: keyboard line selection copied to the clipboard
+	zoomCopying bool
+	zoomCursor  int // line index in zoomContent
+	zoomAnchor  int // start of the selection, or -1 for just the cursor line
+
 	// Status message
 	statusMsg     string
 	statusExpires time.Time
 
+	// Failed reads of the state file in a row since another process
+	// wrote it; the watcher backs off while it stays unreadable
+	stateSyncFails int
+
+	// Agents removed by the last kill or clear, restorable with u
+	undo *undoEntry
+
 	// Scroll offset for board/carousel views
 	scrollOffset int
 
+	// Compact list mode (key 4): single column, one row per agent
+	compact bool
+
+	// Per-column collapse and relative width (z, +/-), mirrored to the store
+	columnPrefs map[string]ColumnPref
+
+	// Group-by-project mode (key g): single column under repo headers
+	grouped   bool
+	groups    []ui.Group
+	collapsed map[string]bool   // project roots folded to their header
+	repoRoots map[string]string // agent dir -> git toplevel, kept across refreshes
+
 	// Cached card data (refreshed on tick, not every render)
 	cachedCards []ui.CardData
 
+	// Order of cards within each board column
+	sortMode ui.SortMode
+
+	// User-defined columns from config; replace the 3-column layout when set
+	customColumns []ui.Column
+
+	// Attention alerts from config (bell, spoken)
+	alerts AlertConfig
+
+	// RUNNING longer than this is flagged in the status strip (0 = off)
+	staleAfter time.Duration
+
+	// What q does to managed sessions: QuitDetach, QuitKill or QuitAsk
+	quitAction string
+
+	// Launch Claude agents that have a task in stream-json mode
+	streamJSON bool
+
+	// Most agents RUNNING at once; further spawns queue as PENDING (0 = no limit)
+	maxRunning int
+
+	// Shell command committing an agent's work when it stops, and the
+	// agents due one since the last tick
+	checkpointCommand string
+	checkpoints       []*Agent
+
+	// Validation commands by project dir, the agents due one since the
+	// last tick, and each agent's latest result by ID
+	validations map[string]Validation
+	validating  []*Agent
+	checks      map[string]validationResult
+
+	// What happens to an agent past its timeout besides going TIMED-OUT:
+	// TimeoutNotify, TimeoutInterrupt or TimeoutKill
+	timeoutAction string
+
+	// Idle shutdown policy, and the IDLE spell (by StatusSince) each agent
+	// was last warned about
+	idleShutdown IdleShutdownConfig
+	idleWarned   map[string]time.Time
+
+	// Global approval rules, and the prompt (by WAITING spell and text)
+	// each agent last had answered by a rule
+	approvalRules []ApprovalRule
+	ruled         map[string]string
+
+	// Message typed into a THROTTLED agent when its limit resets ("" to
+	// leave it), and the pane tail each agent was last released with, so
+	// the same rate-limit message doesn't throttle it twice; and whether
+	// stopped agents' panes are being scanned for one in the background
+	throttleResume   string
+	throttleSeen     map[string]string
+	throttleScanning bool
+
+	// Where scrollback is saved before a session is killed or cleared
+	// away, "" when archiving is off
+	transcriptDir string
+
+	// Git state per agent dir, refreshed periodically in the background
+	diffStats  map[string]DiffStat  // `git diff --stat` totals
+	repoStates map[string]RepoState // dirty count, ahead/behind upstream
+
+	// Highest token/cost reading seen per agent ID; pane output scrolls away
+	usage map[string]Usage
+
+	// CPU and memory per tmux session, refreshed periodically in the
+	// background, and the memory use past which a card warns (0 = never)
+	resources  map[string]Resources
+	memoryWarn int64
+
+	// Backend health shown by B; nil while the probe runs
+	backendRows []BackendHealth
+
+	// Backends offered by H for handing agent handoffID to
+	handoffID       string
+	handoffBackends []Backend
+
+	// Permission prompt options offered by Y, for agent approveID
+	approveID      string
+	approveOptions []PromptOption
+
+	// Event feed (spawns, status changes, kills, discoveries)
+	events       *EventLog
+	eventsScroll int // lines scrolled up from the newest event
+	statsScroll  int // lines scrolled down the stats screen
+
+	// Archive browser: the agents that left the board, searched by
+	// archiveInput
+	archiveEntries  []ArchivedAgent
+	archiveMatches  []int // indexes into archiveEntries, newest first
+	archiveSelected int   // position in archiveMatches
+	archiveInput    textinput.Model
+	archiveTyping   bool // the search is being edited
+	archiveOpen     bool // the selected agent is shown in full
+	archiveScroll   int  // lines scrolled down its details
+
+	// Split view: agents side by side (two, or the agents of a race), each
+	// with its own scroll
+	splitIDs     []string
+	splitContent []string
+	splitScroll  []int
+	splitFocus   int // pane receiving scroll and cycle keys
+
+	// Detail side panel (board mode)
+	showDetail    bool
+	detailID      string   // agent whose preview is in detailPreview
+	detailPreview []string // longer live preview, refreshed on tick
+
+	// Board filter (persists across ticks until cleared)
+	filter      string
+	filterInput textinput.Model
+	tagInput    textinput.Model
+	noteInput   textarea.Model
+	previews    map[string]string // agent ID → last captured preview text
+
 	// Batch dialog
 	batchOptions []batchOption // computed when opening dialog
 
@@ -125,6 +330,13 @@ type Model struct {
 	wsNameInput     textinput.Model // text input for save-as name
 	activeWorkspace string          // name of last loaded/saved workspace
 
+	// Board switcher
+	boardChoices  []boardChoice
+	boardSelected int
+	boardNewMode  bool            // true = typing a new board's name
+	boardInput    textinput.Model // the new board's name
+	switchBoard   []string        // args to reopen on another board after quitting, nil to stay
+
 	// Remote control web server (nil when not active)
 	webServer *WebServer
 }
@@ -140,22 +352,104 @@ func initialModel(store *Store, manager *AgentManager) Model {
 	sendInput.CharLimit = 500
 	sendInput.Width = 60
 
+	promptInput := textarea.New()
+	promptInput.Placeholder = "initial task (optional)"
+	promptInput.ShowLineNumbers = false
+	promptInput.CharLimit = 4000
+	promptInput.SetWidth(60)
+	promptInput.SetHeight(4)
+	// Enter spawns; newlines need an explicit chord
+	promptInput.KeyMap.InsertNewline = key.NewBinding(key.WithKeys("ctrl+j", "alt+enter"))
+
+	renameInput := textinput.New()
+	renameInput.Placeholder = "new agent name"
+	renameInput.CharLimit = 50
+	renameInput.Width = 40
+
+	timeoutInput := textinput.New()
+	timeoutInput.Placeholder = "e.g. 90m, 2h, 1d (empty for none)"
+	timeoutInput.CharLimit = 20
+	timeoutInput.Width = 40
+
+	nudgeInput := textinput.New()
+	nudgeInput.Placeholder = "e.g. 3x continue until ALL DONE (empty for none)"
+	nudgeInput.CharLimit = 200
+	nudgeInput.Width = 60
+
+	rulesInput := textinput.New()
+	rulesInput.Placeholder = "e.g. approve Read; approve Grep; deny Bash rm"
+	rulesInput.CharLimit = 500
+	rulesInput.Width = 60
+
+	filterInput := textinput.New()
+	filterInput.Placeholder = "name, dir, backend, or output"
+	filterInput.Prompt = "/"
+	filterInput.CharLimit = 100
+	filterInput.Width = 40
+
+	tagInput := textinput.New()
+	tagInput.Placeholder = "space or comma separated, e.g. backend urgent"
+	tagInput.CharLimit = 200
+	tagInput.Width = 50
+
+	searchInput := textinput.New()
+	searchInput.Placeholder = "search scrollback"
+	searchInput.Prompt = "/"
+	searchInput.CharLimit = 100
+	searchInput.Width = 40
+
+	noteInput := textarea.New()
+	noteInput.Placeholder = "what you asked for, what you're waiting on"
+	noteInput.ShowLineNumbers = false
+	noteInput.CharLimit = 2000
+	noteInput.SetWidth(60)
+	noteInput.SetHeight(5)
+	noteInput.KeyMap.InsertNewline = key.NewBinding(key.WithKeys("ctrl+j", "alt+enter"))
+
 	wsInput := textinput.New()
 	wsInput.Placeholder = "workspace name"
 	wsInput.CharLimit = 50
 	wsInput.Width = 40
 
+	boardInput := textinput.New()
+	boardInput.Placeholder = "board name"
+	boardInput.CharLimit = 40
+	boardInput.Width = 40
+
+	archiveInput := textinput.New()
+	archiveInput.Placeholder = "name, dir, task, summary, tag"
+	archiveInput.Prompt = "/"
+	archiveInput.CharLimit = 100
+	archiveInput.Width = 40
+
 	return Model{
-		store:       store,
-		manager:     manager,
-		agents:      store.List(),
-		columns:     3,
-		view:        viewBoard,
-		width:       120,
-		height:      40,
-		spawnDir:    dirInput,
-		sendInput:   sendInput,
-		wsNameInput: wsInput,
+		store:           store,
+		manager:         manager,
+		agents:          store.List(),
+		columns:         3,
+		view:            viewBoard,
+		width:           120,
+		height:          40,
+		spawnDir:        dirInput,
+		spawnPrompt:     promptInput,
+		sendInput:       sendInput,
+		renameInput:     renameInput,
+		timeoutInput:    timeoutInput,
+		rulesInput:      rulesInput,
+		nudgeInput:      nudgeInput,
+		filterInput:     filterInput,
+		tagInput:        tagInput,
+		noteInput:       noteInput,
+		previews:        make(map[string]string),
+		usage:           make(map[string]Usage),
+		checks:          make(map[string]validationResult),
+		collapsed:       make(map[string]bool),
+		columnPrefs:     store.ColumnPrefs(),
+		repoRoots:       make(map[string]string),
+		wsNameInput:     wsInput,
+		boardInput:      boardInput,
+		archiveInput:    archiveInput,
+		zoomSearchInput: searchInput,
 	}
 }
 
@@ -165,6 +459,9 @@ func (m Model) Init() tea.Cmd {
 		tea.ClearScreen,
 		discoverCmd(),
 		reconcileCmd(m.store),
+		watchStateCmd(m.store, stateWatchInterval),
+		flushStateCmd(),
+		statusGCCmd(m.store.List()),
 		tea.SetWindowTitle("TicketTok"),
 		checkUpdateCmd(),
 	)
@@ -193,29 +490,143 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tickMsg:
 		m.refreshStatuses()
-		m.agents = m.store.List()
+		m.startPending()
+		m.releaseDependents()
+		m.refreshAgents()
 		m.cachedCards = m.buildCardData()
+		m.rememberPreviews()
+		m.rememberUsage()
+		m.refreshDetail()
 		m.tickCount++
 		if m.webServer != nil {
 			m.webServer.BroadcastState()
 		}
 		var cmds []tea.Cmd
 		cmds = append(cmds, tickCmd())
+		for _, a := range m.checkpoints {
+			cmds = append(cmds, checkpointCmd(m.checkpointCommand, a))
+		}
+		m.checkpoints = nil
+		for _, a := range m.validating {
+			v, _ := validationFor(m.validations, a)
+			m.checks[a.ID] = validationResult{Command: v.Command, Running: true, Fed: m.checks[a.ID].Fed}
+			cmds = append(cmds, validationCmd(v, a))
+		}
+		m.validating = nil
 		// Re-discover every 5th tick (~10s)
 		if m.tickCount%5 == 0 {
 			cmds = append(cmds, discoverCmd())
 		}
+		// Refresh git state on a staggered ~10s cycle
+		if m.tickCount%5 == 2 {
+			cmds = append(cmds, gitInfoCmd(m.gitDirs()))
+		}
+		// And process resources, in between
+		if m.tickCount%5 == 4 {
+			cmds = append(cmds, resourcesCmd())
+		}
+		// And sweep leftover hook status files every minute
+		if m.tickCount%statusGCTicks == 0 {
+			cmds = append(cmds, statusGCCmd(m.store.List()))
+		}
+		if !m.throttleScanning {
+			if c := m.throttleCandidates(); len(c) > 0 {
+				m.throttleScanning = true
+				cmds = append(cmds, throttleScanCmd(c))
+			}
+		}
 		return m, tea.Batch(cmds...)
 
+	case throttleScanMsg:
+		m.throttleScanning = false
+		if m.applyThrottleScan(msg.found, time.Now()) {
+			m.refreshAgents()
+			m.cachedCards = m.buildCardData()
+		}
+		return m, nil
+
+	case gitInfoMsg:
+		m.diffStats = msg.stats
+		m.repoStates = msg.repos
+		for dir, r := range msg.repos {
+			if r.Root != "" {
+				m.repoRoots[dir] = r.Root
+			}
+		}
+		if m.grouped {
+			m.refreshAgents()
+		}
+		m.cachedCards = m.buildCardData()
+		return m, nil
+
+	case resourcesMsg:
+		m.resources = msg.res
+		m.cachedCards = m.buildCardData()
+		return m, nil
+
+	case backendHealthMsg:
+		m.backendRows = msg.rows
+		return m, nil
+
+	case checkpointMsg:
+		if msg.err != nil {
+			m.events.Add(EventCommit, msg.name, fmt.Sprintf("checkpoint failed: %v", msg.err))
+			m.setStatus(fmt.Sprintf("Checkpoint failed for %s: %v", msg.name, msg.err))
+		} else {
+			m.events.Add(EventCommit, msg.name, "checkpoint "+msg.out)
+			m.setStatus(fmt.Sprintf("Checkpoint saved for %s", msg.name))
+		}
+		return m, nil
+
+	case validationMsg:
+		m.finishValidation(msg)
+		m.cachedCards = m.buildCardData()
+		return m, nil
+
+	case prMsg:
+		if msg.err != nil {
+			m.setStatus(fmt.Sprintf("PR failed for %s: %v", msg.name, msg.err))
+		} else {
+			m.events.Add(EventPR, msg.name, "opened "+msg.url)
+			m.setStatus(fmt.Sprintf("Opened PR for %s: %s", msg.name, msg.url))
+		}
+		return m, nil
+
 	case discoverMsg:
 		m.mergeDiscovered(msg.found)
-		m.agents = m.store.List()
+		m.refreshAgents()
 		return m, nil
 
 	case reconcileMsg:
-		m.agents = m.store.List()
+		m.refreshAgents()
 		return m, nil
 
+	case stateChangedMsg:
+		// Another TUI, a CLI command or a script changed the board
+		changed, err := m.store.Sync()
+		if err != nil {
+			if m.stateSyncFails == 0 {
+				m.setStatus(fmt.Sprintf("Can't read the board's state: %v", err))
+			}
+			m.stateSyncFails++
+			return m, watchStateCmd(m.store, stateWatchBackoff(m.stateSyncFails))
+		}
+		m.stateSyncFails = 0
+		if changed {
+			m.refreshAgents()
+			m.cachedCards = m.buildCardData()
+			if m.webServer != nil {
+				m.webServer.BroadcastState()
+			}
+		}
+		return m, watchStateCmd(m.store, stateWatchInterval)
+
+	case stateFlushMsg:
+		if err := m.store.Flush(); err != nil {
+			m.setStatus(fmt.Sprintf("State not saved: %v", err))
+		}
+		return m, flushStateCmd()
+
 	case updateCheckMsg:
 		if msg.available {
 			m.updateAvailable = true
@@ -246,10 +657,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.webServer.BroadcastZoom(m.zoomAgentID, msg.content)
 			}
 			m.zoomTotalLines = strings.Count(msg.content, "\n") + 1
+			if m.zoomQuery != "" {
+				m.zoomMatches = findMatches(msg.content, m.zoomQuery)
+				if m.zoomMatchIdx >= len(m.zoomMatches) {
+					m.zoomMatchIdx = len(m.zoomMatches) - 1
+				}
+			}
 			return m, zoomCaptureCmd(m.zoomSession)
 		}
 		return m, nil
 
+	case splitTickMsg:
+		if m.view == viewSplit {
+			// A capture started before the panes changed is dropped
+			if len(msg.content) == len(m.splitIDs) {
+				m.splitContent = msg.content
+			}
+			return m, splitCaptureCmd(m.splitSessions())
+		}
+		return m, nil
+
 	case tea.MouseMsg:
 		return m.handleMouse(msg)
 
@@ -264,10 +691,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmd = m.updateSpawnInputs(msg)
 		case viewSend:
 			m.sendInput, cmd = m.sendInput.Update(msg)
+		case viewRename:
+			m.renameInput, cmd = m.renameInput.Update(msg)
+		case viewTimeout:
+			m.timeoutInput, cmd = m.timeoutInput.Update(msg)
+		case viewRules:
+			m.rulesInput, cmd = m.rulesInput.Update(msg)
+		case viewNudge:
+			m.nudgeInput, cmd = m.nudgeInput.Update(msg)
+		case viewFilter:
+			m.filterInput, cmd = m.filterInput.Update(msg)
+		case viewTags:
+			m.tagInput, cmd = m.tagInput.Update(msg)
+		case viewNote:
+			m.noteInput, cmd = m.noteInput.Update(msg)
 		case viewWorkspace:
 			if m.wsSaveMode {
 				m.wsNameInput, cmd = m.wsNameInput.Update(msg)
 			}
+		case viewBoards:
+			if m.boardNewMode {
+				m.boardInput, cmd = m.boardInput.Update(msg)
+			}
+		case viewArchive:
+			if m.archiveTyping {
+				m.archiveInput, cmd = m.archiveInput.Update(msg)
+			}
 		}
 		return m, cmd
 	}
@@ -277,6 +726,20 @@ func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	if m.view == viewZoom {
 		return m.handleZoomMouse(msg)
 	}
+	if m.view == viewSplit {
+		// Wheel scrolls whichever pane the pointer is over
+		pane := msg.X * len(m.splitIDs) / max(m.width, 1)
+		if pane >= len(m.splitIDs) {
+			pane = len(m.splitIDs) - 1
+		}
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.scrollSplit(pane, 3)
+		case tea.MouseButtonWheelDown:
+			m.scrollSplit(pane, -3)
+		}
+		return m, nil
+	}
 
 	// Mouse wheel scrolls the viewport without changing selection
 	n := len(m.agents)
@@ -343,47 +806,135 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleSpawnKey(msg)
 	case m.view == viewWorkspace:
 		return m.handleWorkspaceKey(msg)
+	case m.view == viewBoards:
+		return m.handleBoardsKey(msg)
 	case m.view == viewSend:
 		return m.handleSendKey(msg)
+	case m.view == viewRename:
+		return m.handleRenameKey(msg)
+	case m.view == viewTimeout:
+		return m.handleTimeoutKey(msg)
+	case m.view == viewRules:
+		return m.handleRulesKey(msg)
+	case m.view == viewNudge:
+		return m.handleNudgeKey(msg)
+	case m.view == viewFilter:
+		return m.handleFilterKey(msg)
+	case m.view == viewTags:
+		return m.handleTagsKey(msg)
+	case m.view == viewNote:
+		return m.handleNoteKey(msg)
+	case m.view == viewPin:
+		return m.handlePinKey(key)
+	case m.view == viewApprove:
+		return m.handleApproveKey(key)
+	case m.view == viewQuit:
+		return m.handleQuitKey(key)
+	case m.view == viewHandoff:
+		return m.handleHandoffKey(key)
+	case m.view == viewBackends:
+		return m.handleBackendsKey(key)
+	case m.view == viewSplit:
+		return m.handleSplitKey(key)
+	case m.view == viewEvents:
+		return m.handleEventsKey(key)
+	case m.view == viewStats:
+		return m.handleStatsKey(key)
+	case m.view == viewArchive:
+		return m.handleArchiveKey(msg)
+	case m.view == viewHelp:
+		// Any key closes the overlay
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
 	}
 
 	// Board/carousel keys
 	switch key {
 	case "ctrl+r":
 		return m.toggleRemote()
-	case "q", "ctrl+q", "ctrl+c":
-		if m.webServer != nil {
-			m.webServer.Stop()
-		}
-		return m, tea.Quit
+	case "q", "ctrl+q":
+		return m.requestQuit()
+	case "ctrl+c":
+		// Escape hatch: never prompts and never kills anything
+		return m.quit(false)
 	case "n":
 		m.openSpawnDialog()
 		return m, nil
-	case "w":
+	case "W":
 		m.openWorkspaceDialog()
 		return m, nil
-	case "1":
+	case "w":
+		if next := nextWaiting(m.agents, m.selected); next >= 0 {
+			m.selected = next
+			m.ensureSelectedVisible()
+		} else {
+			m.setStatus("No agents waiting")
+		}
+		return m, nil
+	case "1", "4":
+		// 4 is the compact list: a single-column mode with one row per agent
 		m.columns = 1
+		m.compact = key == "4"
 		m.view = viewCarousel
 		if len(m.agents) > 0 && m.selected >= len(m.agents) {
 			m.selected = 0
 		}
+		m.scrollOffset = 0
+		m.ensureSelectedVisible()
 		return m, nil
 	case "2":
 		m.columns = 2
+		m.compact = false
+		m.setGrouped(false)
 		m.view = viewBoard
 		return m, nil
 	case "3":
 		m.columns = 3
+		m.compact = false
+		m.setGrouped(false)
 		m.view = viewBoard
 		return m, nil
+	case "g":
+		m.setGrouped(!m.grouped)
+		if m.grouped {
+			m.columns = 1
+			m.view = viewCarousel
+		}
+		return m, nil
+	case "z":
+		if m.grouped && m.selected < len(m.agents) {
+			root := m.groupKey(m.agents[m.selected])
+			m.collapsed[root] = !m.collapsed[root]
+			m.refreshAgents()
+			m.ensureSelectedVisible()
+		} else if m.columns > 1 {
+			m.toggleColumnCollapse()
+			m.ensureSelectedVisible()
+		}
+		return m, nil
+	case "Z":
+		if m.grouped {
+			m.collapsed = make(map[string]bool)
+			m.refreshAgents()
+			m.ensureSelectedVisible()
+		} else if m.columns > 1 {
+			m.expandColumns()
+		}
+		return m, nil
 	case "d":
 		m.discoverAgents()
 		return m, nil
 	case "c":
-		n := m.store.ClearDone()
-		m.agents = m.store.List()
-		m.setStatus(fmt.Sprintf("Cleared %d completed agents", n))
+		cleared := m.store.ClearDoneBefore(time.Time{})
+		m.archiveAll(cleared, "cleared")
+		m.archiveRemoved(cleared, "cleared")
+		kept := removeWorktrees(cleared)
+		m.rememberUndo(cleared, false)
+		m.refreshAgents()
+		m.setStatus(fmt.Sprintf("Cleared %d completed agents%s%s", len(cleared), worktreeNote(kept), undoHint(len(cleared))))
 		if m.selected >= len(m.agents) && len(m.agents) > 0 {
 			m.selected = len(m.agents) - 1
 		}
@@ -391,7 +942,51 @@ func (m *Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "b":
 		m.openBatchDialog()
 		return m, nil
+	case "/":
+		m.openFilter()
+		return m, nil
+	case "?":
+		m.view = viewHelp
+		return m, nil
+	case "|":
+		return m.openSplit()
+	case "B":
+		m.backendRows = nil
+		m.view = viewBackends
+		return m, backendHealthCmd()
+	case "L":
+		m.events.Reload()
+		m.eventsScroll = 0
+		m.view = viewEvents
+		return m, nil
+	case "%":
+		m.statsScroll = 0
+		m.view = viewStats
+		return m, nil
+	case "I":
+		m.openArchive()
+		return m, nil
+	case "v":
+		m.openBoardsDialog()
+		return m, nil
+	case "i":
+		m.showDetail = !m.showDetail
+		m.refreshDetail()
+		return m, nil
+	case "o":
+		m.sortMode = m.sortMode.Next()
+		m.setStatus("Sort: " + m.sortMode.String())
+		m.ensureSelectedVisible()
+		return m, nil
+	case "esc":
+		if m.filter != "" {
+			m.setFilter("")
+		}
+		return m, nil
 	case "u":
+		m.undoLast()
+		return m, nil
+	case "U":
 		if m.updateAvailable && !m.updating {
 			m.updating = true
 			m.setStatus(fmt.Sprintf("Downloading v%s...", m.latestVersion))
@@ -415,6 +1010,9 @@ func (m *Model) toggleRemote() (tea.Model, tea.Cmd) {
 	}
 
 	ws := NewWebServer(m.store, m.manager, 8422)
+	ws.events = m.events
+	ws.maxRunning = m.maxRunning
+	ws.transcriptDir = m.transcriptDir
 	if err := ws.Start(); err != nil {
 		m.setStatus(fmt.Sprintf("Remote failed: %v", err))
 		return m, nil
@@ -447,6 +1045,9 @@ func (m *Model) handleBoardNav(key string) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Status changes can move the selection into a collapsed column
+	m.selectVisibleColumn()
+
 	switch key {
 	case "j", "down":
 		m.selected = m.nextInSameColumn(+1)
@@ -464,8 +1065,50 @@ func (m *Model) handleBoardNav(key string) (tea.Model, tea.Cmd) {
 		m.openSendDialog()
 	case "a":
 		m.toggleAutoApprove()
-	case "r", "R":
+	case "M":
+		m.toggleKeepAlive()
+	case "C":
+		m.toggleCheckpoint()
+	case "P":
+		return m, m.openPRSelected()
+	case "D":
+		return m, m.openCloneDialog()
+	case "X":
+		m.openTimeoutDialog()
+	case "V":
+		m.openRulesDialog()
+	case "N":
+		m.openNudgeDialog()
+	case "!":
+		m.cyclePriority()
+	case "F":
+		m.togglePause()
+	case "m":
+		m.toggleReview()
+	case "A":
+		m.adoptSelected()
+	case "r":
 		return m.restartStuckAgent()
+	case "R":
+		m.openRenameDialog()
+	case "t", "T":
+		m.openTagDialog()
+	case "e":
+		return m, m.openNoteDialog()
+	case "y":
+		m.approvePrompt()
+	case "Y":
+		m.openApproveDialog()
+	case "H":
+		m.openHandoffDialog()
+	case "O":
+		m.retrySelected()
+	case "p":
+		m.openPinDialog()
+	case "+", "=":
+		m.resizeColumn(+1)
+	case "-":
+		m.resizeColumn(-1)
 	}
 	m.ensureSelectedVisible()
 	return m, nil
@@ -479,62 +1122,27 @@ func (m *Model) nextInColumn(delta int) int {
 		return m.selected
 	}
 
-	// Build column assignment for each agent
-	cols := make([]int, n)
-	for i, a := range m.agents {
-		cols[i] = m.columnForStatus(a.Status)
-	}
-
-	curCol := cols[m.selected]
-
-	// Compute current row within current column
-	curRow := 0
-	for i := 0; i < m.selected; i++ {
-		if cols[i] == curCol {
-			curRow++
-		}
-	}
+	order := m.columnOrder()
+	curCol := m.columnFor(m.agents[m.selected])
+	curRow := indexOf(order[curCol], m.selected)
 
-	// Target column, skipping empty columns in the delta direction
-	maxCol := m.columns - 1
+	// Target column, skipping empty and collapsed columns in the delta direction
+	layout := m.layout()
+	maxCol := len(layout) - 1
 	targetCol := curCol + delta
-	for targetCol >= 0 && targetCol <= maxCol {
-		// Check if any agent lives in this column
-		hasAgent := false
-		for i := 0; i < n; i++ {
-			if cols[i] == targetCol {
-				hasAgent = true
-				break
-			}
-		}
-		if hasAgent {
-			break
-		}
+	for targetCol >= 0 && targetCol <= maxCol && (len(order[targetCol]) == 0 || layout[targetCol].Collapsed) {
 		targetCol += delta
 	}
 	if targetCol < 0 || targetCol > maxCol || targetCol == curCol {
 		return m.selected
 	}
 
-	// Find agents in target column, pick closest row
-	bestIdx := m.selected
-	bestDist := n + 1
-	row := 0
-	for i := 0; i < n; i++ {
-		if cols[i] == targetCol {
-			dist := row - curRow
-			if dist < 0 {
-				dist = -dist
-			}
-			if dist < bestDist {
-				bestDist = dist
-				bestIdx = i
-			}
-			row++
-		}
+	// Pick the closest row in the target column
+	target := order[targetCol]
+	if curRow >= len(target) {
+		curRow = len(target) - 1
 	}
-
-	return bestIdx
+	return target[curRow]
 }
 
 // nextInSameColumn returns the flat index of the next (delta=+1) or previous (delta=-1)
@@ -545,50 +1153,199 @@ func (m *Model) nextInSameColumn(delta int) int {
 		return m.selected
 	}
 
-	curCol := m.columnForStatus(m.agents[m.selected].Status)
+	sameCol := m.columnOrder()[m.columnFor(m.agents[m.selected])]
+	pos := indexOf(sameCol, m.selected)
 
-	// Collect flat indices of agents in the same column
-	var sameCol []int
+	// Move within column, wrapping around
+	k := len(sameCol)
+	newPos := (pos + delta%k + k) % k
+	return sameCol[newPos]
+}
+
+// columnOrder returns the flat agent indices in each board column, in the
+// order they are displayed under the current sort mode.
+func (m *Model) columnOrder() map[int][]int {
+	now := time.Now()
+	keys := make([]ui.CardData, len(m.agents))
+	order := make(map[int][]int)
 	for i, a := range m.agents {
-		if m.columnForStatus(a.Status) == curCol {
-			sameCol = append(sameCol, i)
+		keys[i] = ui.CardData{
+			Pin:    a.Pin,
+			Name:   a.Name,
+			Status: string(a.Status),
+			Uptime: now.Sub(a.CreatedAt),
+			Since:  now.Sub(a.StatusSince),
+		}
+		col := m.columnFor(a)
+		order[col] = append(order[col], i)
+	}
+	for _, idx := range order {
+		ui.SortColumn(keys, idx, m.sortMode)
+	}
+	return order
+}
+
+// nextWaiting returns the index of the first WAITING or ASK agent after
+// from, wrapping around (so from itself comes last), or -1 if none is
+// waiting.
+// High-priority agents are found before the rest, and low-priority ones
+// last.
+func nextWaiting(agents []*Agent, from int) int {
+	n := len(agents)
+	best := -1
+	for i := 1; i <= n; i++ {
+		idx := (from + i) % n
+		if agents[idx].Status != StatusWaiting && agents[idx].Status != StatusAsk {
+			continue
+		}
+		if best < 0 || priorityRank(agents[idx].Priority) < priorityRank(agents[best].Priority) {
+			best = idx
 		}
 	}
+	return best
+}
 
-	// Find current position within column
-	pos := 0
-	for i, idx := range sameCol {
-		if idx == m.selected {
-			pos = i
-			break
+// indexOf returns the position of v in s, or 0 if absent.
+func indexOf(s []int, v int) int {
+	for i, x := range s {
+		if x == v {
+			return i
 		}
 	}
+	return 0
+}
 
-	// Move within column, wrapping around
-	k := len(sameCol)
-	newPos := (pos + delta%k + k) % k
-	return sameCol[newPos]
+// columnFor returns the column index for an agent, honoring its pin.
+func (m *Model) columnFor(a *Agent) int {
+	return ui.PlaceCard(m.layout(), string(a.Status), a.Pin)
 }
 
 // columnForStatus returns the column index for a given agent status.
 func (m *Model) columnForStatus(status AgentStatus) int {
+	return ui.ColumnIndex(m.layout(), string(status))
+}
+
+// layout returns the board columns for the current mode: IDLE/ACTIVE in
+// 2-column mode, otherwise the configured columns or IDLE/WAITING/RUNNING.
+func (m *Model) layout() []ui.Column {
+	base := ui.ThreeColumnLayout
 	if m.columns == 2 {
-		// 2-col: IDLE/DONE=0, ACTIVE(RUNNING+WAITING+STUCK)=1
-		switch status {
-		case StatusRunning, StatusWaiting, StatusError:
-			return 1
-		default:
-			return 0
+		base = ui.TwoColumnLayout
+	} else if len(m.customColumns) > 0 {
+		base = m.customColumns
+	}
+	if m.anyInStatus(StatusPaused) && !ui.HasStatus(base, string(StatusPaused)) {
+		base = append(base[:len(base):len(base)], ui.PausedColumn)
+	}
+	if m.anyInStatus(StatusReview) && !ui.HasStatus(base, string(StatusReview)) {
+		base = append(base[:len(base):len(base)], ui.ReviewColumn)
+	}
+	if len(m.columnPrefs) == 0 {
+		return base
+	}
+	layout := make([]ui.Column, len(base))
+	for i, c := range base {
+		p := m.columnPrefs[c.Title]
+		c.Collapsed, c.Weight = p.Collapsed, p.Weight
+		layout[i] = c
+	}
+	return layout
+}
+
+// anyInStatus reports whether an agent in status is on the board.
+func (m *Model) anyInStatus(status AgentStatus) bool {
+	for _, a := range m.agents {
+		if a.Status == status {
+			return true
 		}
 	}
-	// 3-col: IDLE/DONE=0, WAITING/STUCK=1, RUNNING=2
-	switch status {
-	case StatusWaiting, StatusError:
-		return 1
-	case StatusRunning:
-		return 2
-	default:
-		return 0
+	return false
+}
+
+// maxColumnWeight caps how wide a column can grow relative to the others.
+const maxColumnWeight = 4
+
+// setColumnPref updates a column's view setting and saves it.
+func (m *Model) setColumnPref(title string, p ColumnPref) {
+	if m.columnPrefs == nil {
+		m.columnPrefs = make(map[string]ColumnPref)
+	}
+	if p == (ColumnPref{}) {
+		delete(m.columnPrefs, title)
+	} else {
+		m.columnPrefs[title] = p
+	}
+	m.store.SetColumnPref(title, p)
+}
+
+// toggleColumnCollapse folds the selected agent's column into a narrow
+// strip and moves the selection out of it.
+func (m *Model) toggleColumnCollapse() {
+	if m.selected >= len(m.agents) {
+		return
+	}
+	c := m.layout()[m.columnFor(m.agents[m.selected])]
+	p := m.columnPrefs[c.Title]
+	p.Collapsed = !p.Collapsed
+	m.setColumnPref(c.Title, p)
+	if p.Collapsed {
+		m.setStatus(fmt.Sprintf("Collapsed %s (Z expands all)", c.Title))
+	} else {
+		m.setStatus(fmt.Sprintf("Expanded %s", c.Title))
+	}
+	m.selectVisibleColumn()
+}
+
+// expandColumns unfolds every collapsed column, keeping widths.
+func (m *Model) expandColumns() {
+	for title, p := range m.columnPrefs {
+		if p.Collapsed {
+			p.Collapsed = false
+			m.setColumnPref(title, p)
+		}
+	}
+}
+
+// resizeColumn widens (+1) or narrows (-1) the selected agent's column
+// relative to the others.
+func (m *Model) resizeColumn(delta int) {
+	if m.selected >= len(m.agents) {
+		return
+	}
+	c := m.layout()[m.columnFor(m.agents[m.selected])]
+	p := m.columnPrefs[c.Title]
+	w := max(p.Weight, 1) + delta
+	if w < 1 || w > maxColumnWeight {
+		return
+	}
+	p.Weight = w
+	if w == 1 {
+		p.Weight = 0
+	}
+	m.setColumnPref(c.Title, p)
+	m.setStatus(fmt.Sprintf("%s width ×%d", c.Title, w))
+}
+
+// selectVisibleColumn moves the selection to the nearest agent in an
+// expanded column when the selected agent's column is collapsed.
+func (m *Model) selectVisibleColumn() {
+	if m.selected >= len(m.agents) {
+		return
+	}
+	layout := m.layout()
+	cur := m.columnFor(m.agents[m.selected])
+	if !layout[cur].Collapsed {
+		return
+	}
+	order := m.columnOrder()
+	for d := 1; d < len(layout); d++ {
+		for _, col := range []int{cur + d, cur - d} {
+			if col >= 0 && col < len(layout) && !layout[col].Collapsed && len(order[col]) > 0 {
+				m.selected = order[col][0]
+				m.scrollOffset = 0
+				return
+			}
+		}
 	}
 }
 
@@ -599,9 +1356,14 @@ func (m *Model) ensureSelectedVisible() {
 	// Use visual row (position within column) instead of flat index
 	// so multi-column layouts scroll correctly.
 	row := m.visualRow(m.selected)
+	top := row
+	if m.grouped && row > 0 && m.visualRow(m.selected-1) != row-1 {
+		// First agent of its group: bring the header into view too
+		top = row - 1
+	}
 
-	if row < m.scrollOffset {
-		m.scrollOffset = row
+	if top < m.scrollOffset {
+		m.scrollOffset = top
 	} else if row >= m.scrollOffset+maxVisible {
 		m.scrollOffset = row - maxVisible + 1
 	}
@@ -610,6 +1372,15 @@ func (m *Model) ensureSelectedVisible() {
 // maxVisibleCards returns how many card rows fit in the viewport.
 // Conservative estimate ensures we scroll before cards get cut off.
 func (m *Model) maxVisibleCards() int {
+	if m.compact && m.columns == 1 {
+		// One line per row: title, strip, footer, and a status line
+		footer := ui.RenderFooter(m.width, 1, m.updateAvailable && !m.updating, m.webServer != nil)
+		rows := m.height - 3 - lipgloss.Height(footer)
+		if rows < 1 {
+			rows = 1
+		}
+		return rows
+	}
 	// 7 lines of chrome: title bar, blank line, column headers, footer, status, gaps
 	viewportLines := m.height - 7
 	if viewportLines < estimatedCardHeight {
@@ -626,25 +1397,22 @@ func (m *Model) maxVisibleCards() int {
 // In carousel mode (1 col), this is the flat index.
 // In board mode (2/3 col), this is the agent's position within its column.
 func (m *Model) visualRow(idx int) int {
+	if m.grouped {
+		return ui.GroupRowOf(m.groups, idx)
+	}
 	if m.columns == 1 || idx >= len(m.agents) {
 		return idx
 	}
-	col := m.columnForStatus(m.agents[idx].Status)
-	row := 0
-	for i, a := range m.agents {
-		if i == idx {
-			return row
-		}
-		if m.columnForStatus(a.Status) == col {
-			row++
-		}
-	}
-	return row
+	col := m.columnFor(m.agents[idx])
+	return indexOf(m.columnOrder()[col], idx)
 }
 
 // maxScrollRows returns the number of card rows in the tallest column.
 // In carousel mode, this is the total agent count.
 func (m *Model) maxScrollRows() int {
+	if m.grouped {
+		return ui.GroupRowCount(m.groups)
+	}
 	if len(m.agents) == 0 {
 		return 0
 	}
@@ -653,7 +1421,7 @@ func (m *Model) maxScrollRows() int {
 	}
 	colCounts := make(map[int]int)
 	for _, a := range m.agents {
-		colCounts[m.columnForStatus(a.Status)]++
+		colCounts[m.columnFor(a)]++
 	}
 	maxCol := 0
 	for _, c := range colCounts {
@@ -683,8 +1451,44 @@ func (m *Model) handleCarouselNav(key string) (tea.Model, tea.Cmd) {
 		m.openSendDialog()
 	case "a":
 		m.toggleAutoApprove()
-	case "r", "R":
+	case "M":
+		m.toggleKeepAlive()
+	case "C":
+		m.toggleCheckpoint()
+	case "P":
+		return m, m.openPRSelected()
+	case "D":
+		return m, m.openCloneDialog()
+	case "X":
+		m.openTimeoutDialog()
+	case "V":
+		m.openRulesDialog()
+	case "N":
+		m.openNudgeDialog()
+	case "!":
+		m.cyclePriority()
+	case "F":
+		m.togglePause()
+	case "m":
+		m.toggleReview()
+	case "A":
+		m.adoptSelected()
+	case "r":
 		return m.restartStuckAgent()
+	case "R":
+		m.openRenameDialog()
+	case "t", "T":
+		m.openTagDialog()
+	case "e":
+		return m, m.openNoteDialog()
+	case "y":
+		m.approvePrompt()
+	case "Y":
+		m.openApproveDialog()
+	case "H":
+		m.openHandoffDialog()
+	case "O":
+		m.retrySelected()
 	}
 	m.ensureSelectedVisible()
 	return m, nil
@@ -693,8 +1497,8 @@ func (m *Model) handleCarouselNav(key string) (tea.Model, tea.Cmd) {
 func (m *Model) handleZoomKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 
-	// Ctrl+Q exits zoom
-	if key == "ctrl+q" {
+	// Ctrl+Q exits zoom, as does q while observing
+	if key == "ctrl+q" || (m.zoomObserve && key == "q") {
 		zoomedID := m.zoomAgentID
 
 		m.view = viewBoard
@@ -708,20 +1512,74 @@ func (m *Model) handleZoomKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.zoomSession = ""
 		m.zoomContent = ""
 		m.zoomScrollOff = 0
+		m.endZoomSearch()
+		m.zoomCopying = false
 
 		// Immediate status refresh for the agent we just exited
 		if agent := m.store.Get(zoomedID); agent != nil {
-			newStatus := m.manager.DetectStatus(agent)
+			newStatus, why := m.manager.DetectStatus(agent)
 			if newStatus != agent.Status {
+				if newStatus == StatusDone {
+					m.noteExit(agent, why)
+				}
 				m.store.Update(agent.ID, newStatus)
 			}
 		}
-		m.agents = m.store.List()
+		m.refreshAgents()
 		m.cachedCards = m.buildCardData()
 
 		return m, tea.SetWindowTitle("TicketTok")
 	}
 
+	// Ctrl+O toggles observe-only mode: keys stay local, nothing is sent
+	if key == "ctrl+o" {
+		m.zoomObserve = !m.zoomObserve
+		return m, nil
+	}
+
+	// Copy mode captures every key until the selection is copied or dropped
+	if m.zoomCopying {
+		return m.handleZoomCopyKey(key)
+	}
+	if key == "ctrl+y" {
+		m.endZoomSearch()
+		m.zoomCopying = true
+		m.zoomAnchor = -1
+		m.zoomCursor = m.zoomWindowStart(m.height-4) + m.height - 5
+		if m.zoomCursor >= m.zoomTotalLines {
+			m.zoomCursor = m.zoomTotalLines - 1
+		}
+		return m, nil
+	}
+
+	// Scrollback search. Ctrl+F always opens the prompt; "/" only while
+	// scrolled back or observing, since otherwise it belongs to the agent.
+	if m.zoomSearching {
+		return m.handleZoomSearchKey(msg)
+	}
+	if key == "ctrl+f" || (key == "/" && (m.zoomScrollOff > 0 || m.zoomObserve)) {
+		m.zoomSearching = true
+		m.zoomSearchInput.SetValue(m.zoomQuery)
+		m.zoomSearchInput.CursorEnd()
+		return m, m.zoomSearchInput.Focus()
+	}
+	if m.zoomQuery != "" {
+		switch key {
+		case "n":
+			m.jumpZoomMatch(-1)
+			return m, nil
+		case "N":
+			m.jumpZoomMatch(+1)
+			return m, nil
+		case "esc":
+			m.endZoomSearch()
+			m.zoomScrollOff = 0
+			return m, nil
+		}
+		// Any other key ends the search and goes to the agent
+		m.endZoomSearch()
+	}
+
 	// PgUp/PgDown scroll the zoom view by half a page
 	if msg.Type == tea.KeyPgUp || msg.Type == tea.KeyPgDown {
 		halfPage := (m.height - 2) / 2
@@ -778,8 +1636,15 @@ func (m *Model) handleZoomKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 		// Not a mouse sequence — flush the buffered alt+[ then fall through
-		exec.Command("tmux", "send-keys", "-t", m.zoomSession, "Escape").Run()
-		exec.Command("tmux", "send-keys", "-t", m.zoomSession, "-l", "[").Run()
+		if !m.zoomObserve {
+			exec.Command("tmux", "send-keys", "-t", m.zoomSession, "Escape").Run()
+			exec.Command("tmux", "send-keys", "-t", m.zoomSession, "-l", "[").Run()
+		}
+	}
+
+	if m.zoomObserve {
+		m.handleZoomObserveKey(key)
+		return m, nil
 	}
 
 	// Any keypress resets scroll to follow latest output
@@ -792,21 +1657,172 @@ func (m *Model) handleZoomKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// forwardKeyToTmux sends a keystroke to the tmux session via send-keys.
-func (m *Model) forwardKeyToTmux(msg tea.KeyMsg) {
-	if m.zoomSession == "" {
-		return
+// handleZoomObserveKey scrolls locally in observe-only zoom. Keys without a
+// meaning here are dropped rather than sent to the agent.
+func (m *Model) handleZoomObserveKey(key string) {
+	maxScroll := m.zoomTotalLines - (m.height - 2)
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	switch key {
+	case "k", "up":
+		m.zoomScrollOff++
+	case "j", "down":
+		m.zoomScrollOff--
+	case "g", "home":
+		m.zoomScrollOff = maxScroll
+	case "G", "end":
+		m.zoomScrollOff = 0
+	}
+	if m.zoomScrollOff > maxScroll {
+		m.zoomScrollOff = maxScroll
+	}
+	if m.zoomScrollOff < 0 {
+		m.zoomScrollOff = 0
 	}
+}
 
-	// Map Bubble Tea key names to tmux key names
-	var tmuxKey string
-	switch msg.Type {
-	case tea.KeyRunes:
-		// Regular character input
-		exec.Command("tmux", "send-keys", "-t", m.zoomSession, "-l", string(msg.Runes)).Run()
+// handleZoomSearchKey edits the zoom search query. Enter runs the search
+// and jumps to the most recent match.
+func (m *Model) handleZoomSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+q":
+		m.zoomSearching = false
+		m.zoomSearchInput.Blur()
+		return m, nil
+	case "enter":
+		m.zoomSearching = false
+		m.zoomSearchInput.Blur()
+		m.zoomQuery = strings.TrimSpace(m.zoomSearchInput.Value())
+		m.zoomMatches = findMatches(m.zoomContent, m.zoomQuery)
+		if m.zoomQuery == "" {
+			return m, nil
+		}
+		if len(m.zoomMatches) == 0 {
+			m.setStatus(fmt.Sprintf("No matches for %q", m.zoomQuery))
+			m.zoomQuery = ""
+			return m, nil
+		}
+		m.zoomMatchIdx = len(m.zoomMatches)
+		m.jumpZoomMatch(-1)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.zoomSearchInput, cmd = m.zoomSearchInput.Update(msg)
+	return m, cmd
+}
+
+// jumpZoomMatch moves to the previous (-1, older) or next (+1, newer) match,
+// wrapping around, and scrolls it into the middle of the view.
+func (m *Model) jumpZoomMatch(delta int) {
+	n := len(m.zoomMatches)
+	if n == 0 {
 		return
-	case tea.KeySpace:
-		tmuxKey = "Space"
+	}
+	m.zoomMatchIdx = (m.zoomMatchIdx + delta + n) % n
+	maxScroll := m.zoomTotalLines - (m.height - 2)
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	m.zoomScrollOff = matchScrollOffset(m.zoomTotalLines, m.zoomMatches[m.zoomMatchIdx], m.height-4, maxScroll)
+}
+
+// handleZoomCopyKey moves the copy-mode cursor and copies the selection.
+func (m *Model) handleZoomCopyKey(key string) (tea.Model, tea.Cmd) {
+	half := (m.height - 4) / 2
+	if half < 1 {
+		half = 1
+	}
+	switch key {
+	case "esc", "ctrl+y":
+		m.zoomCopying = false
+	case "k", "up":
+		m.moveZoomCursor(-1)
+	case "j", "down":
+		m.moveZoomCursor(1)
+	case "pgup":
+		m.moveZoomCursor(-half)
+	case "pgdown":
+		m.moveZoomCursor(half)
+	case "g", "home":
+		m.moveZoomCursor(-m.zoomTotalLines)
+	case "G", "end":
+		m.moveZoomCursor(m.zoomTotalLines)
+	case "v", " ":
+		if m.zoomAnchor < 0 {
+			m.zoomAnchor = m.zoomCursor
+		} else {
+			m.zoomAnchor = -1
+		}
+	case "y", "enter":
+		from := m.zoomAnchor
+		if from < 0 {
+			from = m.zoomCursor
+		}
+		text := selectLines(m.zoomContent, from, m.zoomCursor)
+		m.zoomCopying = false
+		via, err := copyToClipboard(text)
+		if err != nil {
+			m.setStatus(fmt.Sprintf("Copy failed: %v", err))
+			return m, nil
+		}
+		m.setStatus(fmt.Sprintf("Copied %d lines (%s)", strings.Count(text, "\n")+1, via))
+	}
+	return m, nil
+}
+
+// moveZoomCursor moves the copy cursor by delta lines and scrolls the zoom
+// view just enough to keep it on screen.
+func (m *Model) moveZoomCursor(delta int) {
+	m.zoomCursor += delta
+	if m.zoomCursor >= m.zoomTotalLines {
+		m.zoomCursor = m.zoomTotalLines - 1
+	}
+	if m.zoomCursor < 0 {
+		m.zoomCursor = 0
+	}
+
+	maxLines := m.height - 4
+	start := m.zoomWindowStart(maxLines)
+	switch {
+	case m.zoomCursor < start:
+		m.zoomScrollOff = m.zoomTotalLines - m.zoomCursor - maxLines
+	case m.zoomCursor >= start+maxLines:
+		m.zoomScrollOff = m.zoomTotalLines - m.zoomCursor - 1
+	}
+	maxScroll := m.zoomTotalLines - (m.height - 2)
+	if m.zoomScrollOff > maxScroll {
+		m.zoomScrollOff = maxScroll
+	}
+	if m.zoomScrollOff < 0 {
+		m.zoomScrollOff = 0
+	}
+}
+
+// endZoomSearch clears the active zoom search and its highlights.
+func (m *Model) endZoomSearch() {
+	m.zoomSearching = false
+	m.zoomSearchInput.Blur()
+	m.zoomQuery = ""
+	m.zoomMatches = nil
+	m.zoomMatchIdx = 0
+}
+
+// forwardKeyToTmux sends a keystroke to the tmux session via send-keys.
+func (m *Model) forwardKeyToTmux(msg tea.KeyMsg) {
+	if m.zoomSession == "" {
+		return
+	}
+
+	// Map Bubble Tea key names to tmux key names
+	var tmuxKey string
+	switch msg.Type {
+	case tea.KeyRunes:
+		// Regular character input
+		exec.Command("tmux", "send-keys", "-t", m.zoomSession, "-l", string(msg.Runes)).Run()
+		return
+	case tea.KeySpace:
+		tmuxKey = "Space"
 	case tea.KeyEnter:
 		tmuxKey = "Enter"
 	case tea.KeyCtrlJ:
@@ -891,9 +1907,60 @@ func (m *Model) handleSpawnKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.spawnFocus == focusApprove {
 		return m.handleSpawnApproveKey(msg)
 	}
+	if m.spawnFocus == focusBranch {
+		return m.handleSpawnBranchKey(msg)
+	}
+	if m.spawnFocus == focusAfter {
+		return m.handleSpawnAfterKey(msg)
+	}
+	if m.spawnFocus == focusPrompt {
+		return m.handleSpawnPromptKey(msg)
+	}
 	return m.handleSpawnDirKey(msg)
 }
 
+// focusSpawnPrompt moves spawn dialog focus to the prompt textarea.
+func (m *Model) focusSpawnPrompt() tea.Cmd {
+	m.spawnFocus = focusPrompt
+	m.spawnSelIdx = -1
+	m.spawnDir.Blur()
+	return m.spawnPrompt.Focus()
+}
+
+func (m *Model) handleSpawnPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		return m.doSpawn()
+	case "shift+tab":
+		m.spawnPrompt.Blur()
+		m.spawnFocus = focusDir
+		m.spawnDir.Focus()
+		return m, nil
+	case "up":
+		if m.spawnPrompt.Line() == 0 {
+			m.spawnPrompt.Blur()
+			m.spawnFocus = focusDir
+			m.spawnDir.Focus()
+			return m, nil
+		}
+	case "tab", "down":
+		last := m.spawnPrompt.Line() >= m.spawnPrompt.LineCount()-1
+		if (msg.String() == "tab" || last) && m.spawnSelectedBackendSupportsAutoApprove() {
+			m.spawnPrompt.Blur()
+			m.spawnFocus = focusApprove
+			return m, nil
+		}
+		if msg.String() == "tab" || last {
+			m.spawnPrompt.Blur()
+			m.spawnFocus = focusBranch
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.spawnPrompt, cmd = m.spawnPrompt.Update(msg)
+	return m, cmd
+}
+
 func (m *Model) handleSpawnBackendKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 	switch key {
@@ -944,11 +2011,9 @@ func (m *Model) handleSpawnDirKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "down", "tab":
 			if len(m.spawnSuggestions) > 0 {
 				m.spawnSelIdx = 0
-			} else if m.spawnSelectedBackendSupportsAutoApprove() {
-				m.spawnFocus = focusApprove
-				m.spawnDir.Blur()
+				return m, nil
 			}
-			return m, nil
+			return m, m.focusSpawnPrompt()
 		case "enter":
 			return m.doSpawn()
 		}
@@ -962,14 +2027,16 @@ func (m *Model) handleSpawnDirKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case "down":
 			if m.spawnSelIdx < len(m.spawnSuggestions)-1 {
 				m.spawnSelIdx++
-			} else if m.spawnSelectedBackendSupportsAutoApprove() {
-				// Past last suggestion → move to approve toggle
-				m.spawnFocus = focusApprove
-				m.spawnDir.Blur()
-				m.spawnSelIdx = -1
+				return m, nil
 			}
-			return m, nil
+			// Past last suggestion → move to prompt
+			return m, m.focusSpawnPrompt()
 		case "enter":
+			if m.spawnSelIdx >= 0 && m.spawnSelIdx < m.spawnQuick {
+				// Favorites and recents are spawn targets, not places to browse
+				m.spawnDir.SetValue(m.spawnSuggestions[m.spawnSelIdx])
+				return m.doSpawn()
+			}
 			if m.spawnSelIdx >= 0 && m.spawnSelIdx < len(m.spawnSuggestions) {
 				sel := m.spawnSuggestions[m.spawnSelIdx]
 				m.spawnDir.SetValue(sel + "/")
@@ -979,6 +2046,10 @@ func (m *Model) handleSpawnDirKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		}
+		if key == "*" && m.spawnSelIdx >= 0 && m.spawnSelIdx < len(m.spawnSuggestions) {
+			m.toggleFavoriteSuggestion()
+			return m, nil
+		}
 		// Any rune key → reset selection, forward to textinput
 		if msg.Type == tea.KeyRunes {
 			m.spawnSelIdx = -1
@@ -997,22 +2068,70 @@ func (m *Model) handleSpawnDirKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// toggleFavoriteSuggestion stars or unstars the highlighted spawn suggestion,
+// keeping it highlighted in the refreshed list.
+func (m *Model) toggleFavoriteSuggestion() {
+	dir := m.spawnSuggestions[m.spawnSelIdx]
+	if m.store.ToggleFavoriteDir(expandTilde(dir)) {
+		m.setStatus(fmt.Sprintf("Starred %s", dir))
+	} else {
+		m.setStatus(fmt.Sprintf("Unstarred %s", dir))
+	}
+	m.refreshSpawnSuggestions()
+	for i, s := range m.spawnSuggestions {
+		if s == dir {
+			m.spawnSelIdx = i
+		}
+	}
+}
+
 func (m *Model) handleSpawnApproveKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 	switch key {
-	case "up":
+	case "up", "shift+tab":
+		return m, m.focusSpawnPrompt()
+	case "down", "tab":
+		m.spawnFocus = focusBranch
+		return m, nil
+	case " ":
+		m.spawnAutoApprove = !m.spawnAutoApprove
+		return m, nil
+	case "enter":
+		return m.doSpawn()
+	}
+	// Any rune key → switch to dir input and forward
+	if msg.Type == tea.KeyRunes {
 		m.spawnFocus = focusDir
 		m.spawnDir.Focus()
-		// If there are suggestions, select the last one
-		if len(m.spawnSuggestions) > 0 {
-			m.spawnSelIdx = len(m.spawnSuggestions) - 1
-			if m.spawnSelIdx > 7 {
-				m.spawnSelIdx = 7 // max visible
-			}
+		var cmd tea.Cmd
+		m.spawnDir, cmd = m.spawnDir.Update(msg)
+		m.refreshSpawnSuggestions()
+		return m, cmd
+	}
+	return m, nil
+}
+
+// handleSpawnBranchKey picks whether the agent gets a new branch, a new
+// branch in its own worktree, or neither.
+func (m *Model) handleSpawnBranchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch key {
+	case "up", "shift+tab":
+		if m.spawnSelectedBackendSupportsAutoApprove() {
+			m.spawnFocus = focusApprove
+			return m, nil
+		}
+		return m, m.focusSpawnPrompt()
+	case "down", "tab":
+		if len(m.spawnAfter) > 0 {
+			m.spawnFocus = focusAfter
 		}
 		return m, nil
-	case " ":
-		m.spawnAutoApprove = !m.spawnAutoApprove
+	case "left":
+		m.spawnBranch = (m.spawnBranch + 2) % 3
+		return m, nil
+	case "right", " ":
+		m.spawnBranch = (m.spawnBranch + 1) % 3
 		return m, nil
 	case "enter":
 		return m.doSpawn()
@@ -1029,9 +2148,45 @@ func (m *Model) handleSpawnApproveKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleSpawnAfterKey picks the agent the new agent's prompt waits for.
+// Left/right cycle through the agents on the board, with "none" first.
+func (m *Model) handleSpawnAfterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch key {
+	case "up", "shift+tab":
+		m.spawnFocus = focusBranch
+		return m, nil
+	case "left":
+		m.spawnAfterIdx--
+		if m.spawnAfterIdx < -1 {
+			m.spawnAfterIdx = len(m.spawnAfter) - 1
+		}
+		return m, nil
+	case "right", " ":
+		m.spawnAfterIdx++
+		if m.spawnAfterIdx >= len(m.spawnAfter) {
+			m.spawnAfterIdx = -1
+		}
+		return m, nil
+	case "enter":
+		return m.doSpawn()
+	}
+	// Any other rune key → switch to dir input and forward
+	if msg.Type == tea.KeyRunes {
+		m.spawnFocus = focusDir
+		m.spawnDir.Focus()
+		var cmd tea.Cmd
+		m.spawnDir, cmd = m.spawnDir.Update(msg)
+		m.refreshSpawnSuggestions()
+		return m, cmd
+	}
+	return m, nil
+}
+
 func (m *Model) handleSendKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc":
+		m.broadcastTo = nil
 		m.view = viewBoard
 		if m.columns == 1 {
 			m.view = viewCarousel
@@ -1045,6 +2200,103 @@ func (m *Model) handleSendKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m *Model) handleRenameKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
+	case "enter":
+		return m.doRename()
+	}
+	var cmd tea.Cmd
+	m.renameInput, cmd = m.renameInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) handleTimeoutKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
+	case "enter":
+		return m.doSetTimeout()
+	}
+	var cmd tea.Cmd
+	m.timeoutInput, cmd = m.timeoutInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) handleNudgeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
+	case "enter":
+		return m.doSetNudge()
+	}
+	var cmd tea.Cmd
+	m.nudgeInput, cmd = m.nudgeInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) handleRulesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
+	case "enter":
+		return m.doSetRules()
+	}
+	var cmd tea.Cmd
+	m.rulesInput, cmd = m.rulesInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) handleTagsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
+	case "enter":
+		return m.doSetTags()
+	}
+	var cmd tea.Cmd
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) handleNoteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.noteInput.Blur()
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
+	case "enter":
+		return m.doSetNote()
+	}
+	var cmd tea.Cmd
+	m.noteInput, cmd = m.noteInput.Update(msg)
+	return m, cmd
+}
+
 func (m *Model) handleConfirmKill(key string) (tea.Model, tea.Cmd) {
 	switch key {
 	case "y", "Y", "enter":
@@ -1069,63 +2321,245 @@ func (m *Model) openSpawnDialog() {
 	m.spawnDir.CursorEnd()
 	m.spawnDir.Focus()
 	m.spawnBackends = AvailableBackends()
+	// Preselect the backend used for the previous spawn
 	m.spawnBackendIdx = 0
+	for i, b := range m.spawnBackends {
+		if b.ID() == m.lastSpawnBackend {
+			m.spawnBackendIdx = i
+		}
+	}
 	m.spawnFocus = focusDir
 	m.spawnSelIdx = -1
 	m.spawnAutoApprove = false
+	m.spawnBranch = branchNone
+	m.spawnPrompt.Reset()
+	m.spawnPrompt.Blur()
+	// Any agent still working can gate the new one's prompt
+	m.spawnAfter = nil
+	for _, a := range m.store.List() {
+		if !a.Ended() {
+			m.spawnAfter = append(m.spawnAfter, a)
+		}
+	}
+	m.spawnAfterIdx = -1
+	m.spawnCloneOf = ""
+	m.refreshSpawnSuggestions()
+}
+
+// openCloneDialog opens the spawn dialog filled in from the selected agent:
+// same directory, backend, auto-approve and git setup, with its task in the
+// prompt box to keep, edit or clear.
+func (m *Model) openCloneDialog() tea.Cmd {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return nil
+	}
+	agent := m.agents[m.selected]
+	m.openSpawnDialog()
+	m.spawnCloneOf = agent.Name
+
+	dir := agent.Dir
+	switch {
+	case agent.Worktree != nil:
+		// A fresh worktree off the original checkout, not one nested in it
+		dir = worktreeSourceDir(agent)
+		m.spawnBranch = branchWorktree
+	case agent.Branch != "":
+		m.spawnBranch = branchNew
+	}
+	m.spawnDir.SetValue(collapseTilde(dir))
+	m.spawnDir.CursorEnd()
+	for i, b := range m.spawnBackends {
+		if b.ID() == agent.Backend().ID() {
+			m.spawnBackendIdx = i
+		}
+	}
+	m.spawnAutoApprove = agent.AutoApprove && m.spawnSelectedBackendSupportsAutoApprove()
+	m.spawnPrompt.SetValue(agent.Prompt)
 	m.refreshSpawnSuggestions()
+	return m.focusSpawnPrompt()
 }
 
 func (m *Model) openSendDialog() {
 	if len(m.agents) == 0 || m.selected >= len(m.agents) {
 		return
 	}
+	m.broadcastTo = nil
 	m.view = viewSend
 	m.sendInput.SetValue("")
 	m.sendInput.Focus()
 }
 
-func (m *Model) doSpawn() (tea.Model, tea.Cmd) {
-	dir := strings.TrimSpace(m.spawnDir.Value())
+// openBroadcastDialog opens the send dialog for a message to every live
+// agent on the board (only the matching ones while a filter is active).
+func (m *Model) openBroadcastDialog(agents []*Agent) {
+	m.broadcastTo = agents
+	m.view = viewSend
+	m.sendInput.SetValue("")
+	m.sendInput.Focus()
+}
 
-	if dir == "" {
-		home, _ := os.UserHomeDir()
-		dir = filepath.Join(home, "dev")
-	}
-	if strings.HasPrefix(dir, "~/") {
-		home, _ := os.UserHomeDir()
-		dir = filepath.Join(home, dir[2:])
+func (m *Model) openTagDialog() {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return
 	}
+	m.view = viewTags
+	m.tagInput.SetValue(strings.Join(m.agents[m.selected].Tags, " "))
+	m.tagInput.CursorEnd()
+	m.tagInput.Focus()
+}
 
-	// Create directory if it doesn't exist
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			m.setStatus(fmt.Sprintf("Cannot create dir: %v", err))
-			m.view = viewBoard
-			return m, nil
-		}
+func (m *Model) openNoteDialog() tea.Cmd {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return nil
+	}
+	m.view = viewNote
+	m.noteInput.SetValue(m.agents[m.selected].Note)
+	m.noteInput.CursorEnd()
+	return m.noteInput.Focus()
+}
+
+func (m *Model) openTimeoutDialog() {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return
+	}
+	agent := m.agents[m.selected]
+	if agent.Discovered {
+		m.setStatus("Adopt the agent first (A) to give it a timeout")
+		return
+	}
+	m.view = viewTimeout
+	m.timeoutInput.SetValue("")
+	if agent.Timeout > 0 {
+		m.timeoutInput.SetValue(formatTimeout(agent.Timeout))
+	}
+	m.timeoutInput.CursorEnd()
+	m.timeoutInput.Focus()
+}
+
+func (m *Model) openRulesDialog() {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return
+	}
+	agent := m.agents[m.selected]
+	if agent.Discovered {
+		m.setStatus("Adopt the agent first (A) to give it approval rules")
+		return
+	}
+	m.view = viewRules
+	m.rulesInput.SetValue(strings.Join(agent.Rules, "; "))
+	m.rulesInput.CursorEnd()
+	m.rulesInput.Focus()
+}
+
+func (m *Model) openNudgeDialog() {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return
+	}
+	agent := m.agents[m.selected]
+	if agent.Discovered {
+		m.setStatus("Adopt the agent first (A) to give it a nudge rule")
+		return
+	}
+	m.view = viewNudge
+	m.nudgeInput.SetValue("")
+	if agent.Nudge != nil {
+		m.nudgeInput.SetValue(agent.Nudge.String())
+	}
+	m.nudgeInput.CursorEnd()
+	m.nudgeInput.Focus()
+}
+
+func (m *Model) openRenameDialog() {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return
+	}
+	m.view = viewRename
+	m.renameInput.SetValue(m.agents[m.selected].Name)
+	m.renameInput.CursorEnd()
+	m.renameInput.Focus()
+}
+
+func (m *Model) doSpawn() (tea.Model, tea.Cmd) {
+	dir := strings.TrimSpace(m.spawnDir.Value())
+
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, "dev")
+	}
+	if strings.HasPrefix(dir, "~/") {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, dir[2:])
+	}
+
+	// Create directory if it doesn't exist
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			m.setStatus(fmt.Sprintf("Cannot create dir: %v", err))
+			m.view = viewBoard
+			return m, nil
+		}
 	}
 
 	name := deriveNameFromDir(dir)
 
-	agent := m.store.Add(name, dir)
-	// Set backend from spawn dialog selection
+	// Record backend from spawn dialog selection
+	backendID := ""
 	if len(m.spawnBackends) > 0 && m.spawnBackendIdx < len(m.spawnBackends) {
-		agent.BackendID = m.spawnBackends[m.spawnBackendIdx].ID()
+		backendID = m.spawnBackends[m.spawnBackendIdx].ID()
+	}
+	queued := !hasCapacity(m.store.List(), m.maxRunning)
+	agent := m.store.AddWithBackend(name, dir, backendID)
+	if err := prepareGit(m.store, agent, m.spawnBranch); err != nil {
+		m.store.Remove(agent.ID)
+		m.setStatus(fmt.Sprintf("Git error: %v", err))
+		m.view = viewBoard
+		return m, nil
 	}
+	m.lastSpawnBackend = backendID
 	agent.AutoApprove = m.spawnAutoApprove
+	agent.Prompt = strings.TrimSpace(m.spawnPrompt.Value())
+	var after *Agent
+	if agent.Prompt != "" && m.spawnAfterIdx >= 0 && m.spawnAfterIdx < len(m.spawnAfter) {
+		after = m.spawnAfter[m.spawnAfterIdx]
+		agent.After = after.ID
+	}
+	// A stream-mode prompt goes in with the command, so it can't be held
+	agent.Stream = m.streamJSON && agent.Prompt != "" && agent.After == "" && canStream(agent.Backend())
 	var spawnArgs []string
 	if agent.AutoApprove {
 		spawnArgs = agent.Backend().AutoApproveArgs()
 	}
-	if err := m.manager.SpawnAgent(agent, spawnArgs); err != nil {
+	if queued {
+		m.store.Update(agent.ID, StatusPending)
+		m.store.AddRecentDir(dir)
+		m.setStatus(fmt.Sprintf("Queued: %s (%d agents already running)", name, m.maxRunning))
+		m.events.Add(EventSpawn, name, fmt.Sprintf("%s in %s, queued", agent.Backend().Name(), dir))
+		if after != nil {
+			m.events.Add(EventChain, name, "task waits for "+after.Name)
+		}
+	} else if err := m.manager.SpawnAgent(agent, spawnArgs); err != nil {
 		m.setStatus(fmt.Sprintf("Spawn error: %v", err))
 	} else {
 		m.store.UpdateSessionName(agent.ID, agent.SessionName)
-		m.setStatus(fmt.Sprintf("Spawned: %s", name))
+		m.store.AddRecentDir(dir)
+		if agent.Branch != "" {
+			m.setStatus(fmt.Sprintf("Spawned: %s on branch %s", name, agent.Branch))
+		} else {
+			m.setStatus(fmt.Sprintf("Spawned: %s", name))
+		}
+		m.events.Add(EventSpawn, name, fmt.Sprintf("%s in %s", agent.Backend().Name(), dir))
+		switch {
+		case after != nil:
+			m.events.Add(EventChain, name, "task waits for "+after.Name)
+		case agent.Prompt != "" && !agent.Stream:
+			m.store.MarkPromptSent(agent.ID, time.Now().Add(promptStartupDelay))
+			go SendPromptAfterDelay(agent.SessionName, agent.Prompt)
+		case agent.Stream:
+			m.store.MarkPromptSent(agent.ID, time.Now())
+		}
 	}
 
-	m.agents = m.store.List()
+	m.refreshAgents()
 	m.view = viewBoard
 	if m.columns == 1 {
 		m.view = viewCarousel
@@ -1135,6 +2569,9 @@ func (m *Model) doSpawn() (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) doSend() (tea.Model, tea.Cmd) {
+	if m.broadcastTo != nil {
+		return m.doBroadcast()
+	}
 	if m.selected >= len(m.agents) {
 		return m, nil
 	}
@@ -1143,10 +2580,16 @@ func (m *Model) doSend() (tea.Model, tea.Cmd) {
 	if msg == "" {
 		return m, nil
 	}
+	if agent.Detached {
+		m.store.SetDetached(agent.ID, false)
+	}
+	// New work gets nudges of its own
+	m.store.SetNudges(agent.ID, 0)
 
 	if err := m.manager.SendKeys(agent, msg); err != nil {
 		m.setStatus(fmt.Sprintf("Send error: %v", err))
 	} else {
+		m.store.LogPrompt(agent.ID, "send", msg)
 		m.setStatus(fmt.Sprintf("Sent to %s", agent.Name))
 	}
 
@@ -1160,12 +2603,193 @@ func (m *Model) doSend() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// doBroadcast types the send dialog's message into each agent it was
+// opened for, reporting which ones it failed for.
+func (m *Model) doBroadcast() (tea.Model, tea.Cmd) {
+	msg := m.sendInput.Value()
+	if msg == "" {
+		return m, nil
+	}
+	results := broadcast(m.broadcastTo, msg, func(a *Agent, msg string) error {
+		return SendPrompt(a.SessionName, msg)
+	})
+	for _, r := range results {
+		if r.err != nil {
+			m.events.Add(EventStatus, r.agent.Name, fmt.Sprintf("broadcast not sent: %v", r.err))
+		} else {
+			m.store.SetNudges(r.agent.ID, 0)
+			m.store.LogPrompt(r.agent.ID, "broadcast", msg)
+		}
+	}
+	m.setStatus(broadcastSummary(results))
+	m.broadcastTo = nil
+
+	m.view = viewBoard
+	if m.columns == 1 {
+		m.view = viewCarousel
+	}
+	return m, nil
+}
+
+func (m *Model) doRename() (tea.Model, tea.Cmd) {
+	if m.selected >= len(m.agents) {
+		return m, nil
+	}
+	agent := m.agents[m.selected]
+	name := strings.TrimSpace(m.renameInput.Value())
+	if name == "" {
+		return m, nil
+	}
+
+	oldName := agent.Name
+	m.store.Rename(agent.ID, name)
+	m.refreshAgents()
+	m.cachedCards = m.buildCardData()
+	m.setStatus(fmt.Sprintf("Renamed %s \u2192 %s", oldName, name))
+
+	m.view = viewBoard
+	if m.columns == 1 {
+		m.view = viewCarousel
+	}
+	return m, nil
+}
+
+func (m *Model) doSetTimeout() (tea.Model, tea.Cmd) {
+	if m.selected >= len(m.agents) {
+		return m, nil
+	}
+	agent := m.agents[m.selected]
+	d, err := parseTimeout(m.timeoutInput.Value())
+	if err != nil {
+		m.setStatus(fmt.Sprintf("Timeout: %v", err))
+		return m, nil
+	}
+
+	m.store.SetTimeout(agent.ID, d)
+	if d == 0 {
+		m.setStatus(fmt.Sprintf("Timeout removed from %s", agent.Name))
+	} else {
+		m.setStatus(fmt.Sprintf("Timeout for %s: %s", agent.Name, formatTimeout(d)))
+	}
+
+	m.view = viewBoard
+	if m.columns == 1 {
+		m.view = viewCarousel
+	}
+	return m, nil
+}
+
+func (m *Model) doSetRules() (tea.Model, tea.Cmd) {
+	if m.selected >= len(m.agents) {
+		return m, nil
+	}
+	agent := m.agents[m.selected]
+	rules := splitRules(m.rulesInput.Value())
+	if _, err := parseRules(rules); err != nil {
+		m.setStatus(err.Error())
+		return m, nil
+	}
+
+	m.store.SetRules(agent.ID, rules)
+	if len(rules) == 0 {
+		m.setStatus(fmt.Sprintf("Cleared approval rules on %s", agent.Name))
+	} else {
+		m.setStatus(fmt.Sprintf("%d approval rule(s) for %s", len(rules), agent.Name))
+	}
+
+	m.view = viewBoard
+	if m.columns == 1 {
+		m.view = viewCarousel
+	}
+	return m, nil
+}
+
+func (m *Model) doSetNudge() (tea.Model, tea.Cmd) {
+	if m.selected >= len(m.agents) {
+		return m, nil
+	}
+	agent := m.agents[m.selected]
+	n, err := parseNudge(m.nudgeInput.Value())
+	if err != nil {
+		m.setStatus(fmt.Sprintf("Nudge: %v", err))
+		return m, nil
+	}
+
+	m.store.SetNudge(agent.ID, n)
+	if n == nil {
+		m.setStatus(fmt.Sprintf("Nudging off for %s", agent.Name))
+	} else {
+		m.setStatus(fmt.Sprintf("Nudge for %s: %s", agent.Name, n))
+	}
+
+	m.view = viewBoard
+	if m.columns == 1 {
+		m.view = viewCarousel
+	}
+	return m, nil
+}
+
+func (m *Model) doSetNote() (tea.Model, tea.Cmd) {
+	if m.selected >= len(m.agents) {
+		return m, nil
+	}
+	agent := m.agents[m.selected]
+
+	m.store.SetNote(agent.ID, m.noteInput.Value())
+	m.noteInput.Blur()
+	m.refreshAgents()
+	m.cachedCards = m.buildCardData()
+	if agent.Note == "" {
+		m.setStatus(fmt.Sprintf("Cleared note on %s", agent.Name))
+	} else {
+		m.setStatus(fmt.Sprintf("Saved note on %s", agent.Name))
+	}
+
+	m.view = viewBoard
+	if m.columns == 1 {
+		m.view = viewCarousel
+	}
+	return m, nil
+}
+
+func (m *Model) doSetTags() (tea.Model, tea.Cmd) {
+	if m.selected >= len(m.agents) {
+		return m, nil
+	}
+	agent := m.agents[m.selected]
+	tags := strings.Fields(strings.ReplaceAll(m.tagInput.Value(), ",", " "))
+
+	m.store.SetTags(agent.ID, tags)
+	m.refreshAgents()
+	m.cachedCards = m.buildCardData()
+	if len(agent.Tags) == 0 {
+		m.setStatus(fmt.Sprintf("Cleared tags on %s", agent.Name))
+	} else {
+		m.setStatus(fmt.Sprintf("Tagged %s: %s", agent.Name, strings.Join(agent.Tags, ", ")))
+	}
+
+	m.view = viewBoard
+	if m.columns == 1 {
+		m.view = viewCarousel
+	}
+	return m, nil
+}
+
 func (m *Model) enterZoom() (tea.Model, tea.Cmd) {
 	if len(m.agents) == 0 || m.selected >= len(m.agents) {
 		return m, nil
 	}
 	agent := m.agents[m.selected]
 
+	if agent.Status == StatusPending {
+		m.setStatus(fmt.Sprintf("%s is queued: it starts when fewer than %d agents are running", agent.Name, m.maxRunning))
+		return m, nil
+	}
+
+	if agent.Detached {
+		m.store.SetDetached(agent.ID, false)
+	}
+
 	if agent.Discovered {
 		// PTY-free path: no GetSession/SetSize, just capture directly
 		if !IsSessionAlive(agent.SessionName) {
@@ -1184,15 +2808,15 @@ func (m *Model) enterZoom() (tea.Model, tea.Cmd) {
 
 	sess := m.manager.GetSession(agent)
 	if sess == nil || !sess.IsAlive() {
-		// Dead session — respawn with --continue to resume the conversation
+		// Dead session — respawn, resuming the conversation if the backend can
 		if err := m.manager.RespawnAgent(agent); err != nil {
 			m.setStatus(fmt.Sprintf("Resume error: %v", err))
 			return m, nil
 		}
 		m.store.UpdateSessionName(agent.ID, agent.SessionName)
 		m.store.Update(agent.ID, StatusRunning)
-		m.agents = m.store.List()
-		m.setStatus(fmt.Sprintf("Resumed: %s", agent.Name))
+		m.refreshAgents()
+		m.setStatus(fmt.Sprintf("Resumed: %s%s", agent.Name, noResumeNote(agent)))
 		sess = m.manager.GetSession(agent)
 	}
 
@@ -1228,6 +2852,8 @@ func (m *Model) killSelected() {
 		return
 	}
 	agent := m.agents[m.selected]
+	m.archiveAll([]*Agent{agent}, "killed")
+	m.archiveRemoved([]*Agent{agent}, "killed")
 
 	// Try manager first (has session in memory)
 	sess := m.manager.GetSession(agent)
@@ -1243,8 +2869,12 @@ func (m *Model) killSelected() {
 
 	// Remove from store entirely (not just mark DONE)
 	m.store.Remove(agent.ID)
-	m.agents = m.store.List()
-	m.setStatus(fmt.Sprintf("Killed: %s", agent.Name))
+	kept := removeWorktrees([]*Agent{agent})
+	m.rememberUndo([]*Agent{agent}, true)
+	m.refreshAgents()
+	m.setStatus(fmt.Sprintf("Killed: %s%s%s", agent.Name, worktreeNote(kept), undoHint(1)))
+	m.events.Add(EventKill, agent.Name, "")
+	m.manager.fireLifecycle(LifecycleKill, agent)
 	if m.selected >= len(m.agents) && len(m.agents) > 0 {
 		m.selected = len(m.agents) - 1
 	}
@@ -1253,6 +2883,136 @@ func (m *Model) killSelected() {
 	}
 }
 
+// pickRacer keeps agent id as the winner of its race: the other agents in
+// it are killed, undoably like any kill, and the winner leaves the race.
+func (m *Model) pickRacer(id string) {
+	winner := m.store.Get(id)
+	if winner == nil || winner.Race == "" {
+		m.setStatus("Only an agent in a race can be picked (tickettok race)")
+		return
+	}
+	var losers []*Agent
+	for _, a := range raceMembers(m.store.List(), winner.Race) {
+		if a.ID != winner.ID {
+			losers = append(losers, a)
+		}
+	}
+	m.archiveAll(losers, "lost the race")
+	m.archiveRemoved(losers, "lost the race")
+	for _, a := range losers {
+		if m.manager.GetSession(a) != nil {
+			_ = m.manager.Kill(a.ID)
+		} else if a.SessionName != "" {
+			_ = KillBySession(a.SessionName)
+		}
+		a.Backend().CleanHookStatus(a.ID)
+		m.store.Remove(a.ID)
+		m.events.Add(EventKill, a.Name, "lost the race to "+winner.Name)
+		m.manager.fireLifecycle(LifecycleKill, a)
+	}
+	m.store.SetRace(winner.ID, "")
+	kept := removeWorktrees(losers)
+	m.rememberUndo(losers, true)
+	m.refreshAgents()
+	for i, a := range m.agents {
+		if a.ID == winner.ID {
+			m.selected = i
+		}
+	}
+	m.view = viewBoard
+	if m.columns == 1 {
+		m.view = viewCarousel
+	}
+	m.setStatus(fmt.Sprintf("Picked %s, killed %d other racer(s)%s%s", winner.Name, len(losers), worktreeNote(kept), undoHint(len(losers))))
+}
+
+// rememberUndo replaces the undo buffer with agents just removed from the
+// store. respawn marks them as killed, so undoing re-creates their sessions.
+func (m *Model) rememberUndo(agents []*Agent, respawn bool) {
+	if len(agents) == 0 {
+		return
+	}
+	m.undo = &undoEntry{agents: agents, respawn: respawn, at: time.Now()}
+}
+
+// undoHint is appended to kill and clear status messages.
+func undoHint(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return " — [u] to undo"
+}
+
+// undoLast restores the agents removed by the last kill or clear if it
+// happened within undoWindow. Killed agents are respawned through their
+// backend's resume args; one that fails to start comes back DONE and
+// resumes on the next zoom instead.
+func (m *Model) undoLast() {
+	u := m.undo
+	m.undo = nil
+	if !u.live(time.Now()) {
+		m.setStatus("Nothing to undo")
+		return
+	}
+
+	n := m.store.Restore(u.agents)
+	failed := 0
+	if u.respawn {
+		for _, a := range u.agents {
+			if err := m.manager.RespawnAgent(a); err != nil {
+				failed++
+				a.SessionName = ""
+				m.store.SetExitReason(a.ID, fmt.Sprintf("couldn't restart: %v", err))
+				m.store.Update(a.ID, StatusDone)
+				continue
+			}
+			m.store.UpdateSessionName(a.ID, a.SessionName)
+			m.store.UpdateDiscovered(a.ID, false)
+			m.store.Update(a.ID, StatusRunning)
+			m.events.Add(EventSpawn, a.Name, "restored (undo)")
+		}
+	}
+	m.refreshAgents()
+	m.cachedCards = m.buildCardData()
+
+	for i, a := range m.agents {
+		if a.ID == u.agents[0].ID {
+			m.selected = i
+		}
+	}
+	m.ensureSelectedVisible()
+	msg := fmt.Sprintf("Restored %d agent", n)
+	if n != 1 {
+		msg += "s"
+	}
+	if failed > 0 {
+		msg += fmt.Sprintf(" (%d could not respawn; zoom to resume)", failed)
+	}
+	m.setStatus(msg)
+}
+
+// adoptSelected converts the selected discovered agent into a managed one.
+func (m *Model) adoptSelected() {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return
+	}
+	agent := m.agents[m.selected]
+	if !agent.Discovered {
+		m.setStatus(fmt.Sprintf("%s is already managed", agent.Name))
+		return
+	}
+
+	if err := m.manager.AdoptAgent(agent); err != nil {
+		m.setStatus(fmt.Sprintf("Adopt failed: %v", err))
+		return
+	}
+	m.store.UpdateSessionName(agent.ID, agent.SessionName)
+	m.store.UpdateDiscovered(agent.ID, false)
+	m.refreshAgents()
+	m.cachedCards = m.buildCardData()
+	m.setStatus(fmt.Sprintf("Adopted: %s", agent.Name))
+}
+
 func (m *Model) toggleAutoApprove() {
 	if len(m.agents) == 0 || m.selected >= len(m.agents) {
 		return
@@ -1266,7 +3026,7 @@ func (m *Model) toggleAutoApprove() {
 	}
 
 	// For DONE agents, just flip the flag without confirmation (no respawn needed)
-	if agent.Status == StatusDone {
+	if agent.Ended() {
 		agent.AutoApprove = !agent.AutoApprove
 		m.store.Save()
 		label := "ON"
@@ -1313,6 +3073,7 @@ func (m *Model) doToggleAutoApprove() {
 	}
 
 	// Kill and respawn with new setting
+	m.archiveAll([]*Agent{agent}, "restarted")
 	_ = m.manager.Kill(agent.ID)
 	if agent.SessionName != "" {
 		_ = KillBySession(agent.SessionName)
@@ -1331,37 +3092,361 @@ func (m *Model) doToggleAutoApprove() {
 	m.setStatus(fmt.Sprintf("Auto-approve %s for %s", label, agent.Name))
 }
 
-func (m *Model) refreshStatuses() {
-	// Track transitions for notifications
-	var transitions []statusTransition
-
-	for _, agent := range m.agents {
-		oldStatus := agent.Status
-		newStatus := m.manager.DetectStatus(agent)
-		if newStatus != oldStatus {
-			m.store.Update(agent.ID, newStatus)
-			transitions = append(transitions, statusTransition{agent.Name, oldStatus, newStatus})
-		}
+// keepAlive restarts a keep-alive agent whose session died, resuming its
+// conversation. It returns false once the agent is out of restarts, leaving
+// status detection to mark it DONE.
+func (m *Model) keepAlive(agent *Agent) bool {
+	now := time.Now()
+	switch nextRestart(agent, now) {
+	case restartWait:
+		return true
+	case restartGiveUp:
+		// The dead session's last hook status would keep it looking alive
+		agent.Backend().CleanHookStatus(agent.ID)
+		m.events.Add(EventRestart, agent.Name, fmt.Sprintf("session died; gave up after %d restarts", agent.Restarts))
+		m.setStatus(fmt.Sprintf("%s keeps crashing; not restarting it", agent.Name))
+		return false
+	}
+
+	n := m.store.RecordRestart(agent.ID, now)
+	agent.Backend().CleanHookStatus(agent.ID)
+	_ = m.manager.Kill(agent.ID)
+	if err := m.manager.RespawnAgent(agent); err != nil {
+		m.events.Add(EventRestart, agent.Name, fmt.Sprintf("session died; restart %d/%d failed: %v", n, maxRestarts, err))
+		return true
 	}
-
-	// Stuck detection: RUNNING >10min with no recent hook activity
+	m.store.UpdateSessionName(agent.ID, agent.SessionName)
+	m.store.Update(agent.ID, StatusRunning)
+	m.events.Add(EventRestart, agent.Name, fmt.Sprintf("session died; restarted (%d/%d)%s", n, maxRestarts, noResumeNote(agent)))
+	m.setStatus(fmt.Sprintf("Restarted %s after its session died", agent.Name))
+	return true
+}
+
+// toggleKeepAlive turns automatic restarts on or off for the selected agent.
+func (m *Model) toggleKeepAlive() {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return
+	}
+	agent := m.agents[m.selected]
+	if agent.Discovered {
+		m.setStatus("Adopt the agent first (A) to keep it alive")
+		return
+	}
+	m.store.SetKeepAlive(agent.ID, !agent.KeepAlive)
+	m.cachedCards = m.buildCardData()
+	if agent.KeepAlive {
+		m.setStatus(fmt.Sprintf("Keep-alive ON for %s: restarts if its session dies", agent.Name))
+	} else {
+		m.setStatus(fmt.Sprintf("Keep-alive OFF for %s", agent.Name))
+	}
+}
+
+// togglePause freezes the selected agent's processes, or lets a paused
+// one carry on.
+func (m *Model) togglePause() {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return
+	}
+	agent := m.agents[m.selected]
+	switch {
+	case agent.Discovered:
+		m.setStatus("Adopt the agent first (A) to pause it")
+		return
+	case agent.Status == StatusPaused:
+		if err := resumeSession(agent.SessionName); err != nil {
+			m.setStatus(fmt.Sprintf("Resume error: %v", err))
+			return
+		}
+		m.store.Update(agent.ID, statusBeforePause(agent))
+		m.events.Add(EventStatus, agent.Name, "resumed")
+		m.setStatus(fmt.Sprintf("Resumed: %s", agent.Name))
+	case agent.Status == StatusPending || agent.Ended():
+		m.setStatus(fmt.Sprintf("%s isn't running", agent.Name))
+		return
+	default:
+		if err := pauseSession(agent.SessionName); err != nil {
+			m.setStatus(fmt.Sprintf("Pause error: %v", err))
+			return
+		}
+		m.store.Update(agent.ID, StatusPaused)
+		m.events.Add(EventStatus, agent.Name, "paused")
+		m.setStatus(fmt.Sprintf("Paused: %s (F to resume)", agent.Name))
+	}
+	m.refreshAgents()
+	m.cachedCards = m.buildCardData()
+}
+
+// archiveAll saves the scrollback of agents whose sessions are about to
+// go away to their transcripts.
+func (m *Model) archiveAll(agents []*Agent, why string) {
+	for _, a := range agents {
+		if err := archiveSession(m.transcriptDir, a, why); err != nil {
+			m.setStatus(fmt.Sprintf("Transcript of %s not saved: %v", a.Name, err))
+		}
+	}
+}
+
+// noteExit records why an agent is going DONE, unless whatever ended it —
+// a kill, a timeout, the process's exit status — already has.
+func (m *Model) noteExit(agent *Agent, why string) {
+	if agent.ExitReason == "" && why != "" {
+		m.store.SetExitReason(agent.ID, why)
+	}
+}
+
+// archiveRemoved keeps agents leaving the board in the archive, after
+// archiveAll has saved their output.
+func (m *Model) archiveRemoved(agents []*Agent, why string) {
+	if err := archiveAgents(agents, why, m.transcriptDir); err != nil {
+		m.setStatus(fmt.Sprintf("Archive not written: %v", err))
+	}
+}
+
+// cyclePriority moves the selected agent to the next priority: normal,
+// high, low.
+func (m *Model) cyclePriority() {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return
+	}
+	agent := m.agents[m.selected]
+	p := nextPriority(agent.Priority)
+	m.store.SetPriority(agent.ID, p)
+	m.cachedCards = m.buildCardData()
+	m.setStatus(fmt.Sprintf("Priority of %s: %s", agent.Name, priorityName(p)))
+}
+
+// toggleReview marks the selected agent NEEDS-REVIEW, or takes the mark
+// off once its output has been read.
+func (m *Model) toggleReview() {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return
+	}
+	agent := m.agents[m.selected]
+	switch {
+	case agent.Discovered:
+		m.setStatus("Adopt the agent first (A) to mark it")
+		return
+	case agent.Status == StatusReview:
+		m.store.Update(agent.ID, statusBefore(agent, StatusReview, StatusIdle))
+		m.events.Add(EventStatus, agent.Name, "reviewed")
+		m.setStatus(fmt.Sprintf("Reviewed: %s", agent.Name))
+	case agent.Status == StatusPending || agent.Status == StatusPaused:
+		m.setStatus(fmt.Sprintf("%s is %s; nothing to review yet", agent.Name, agent.Status))
+		return
+	default:
+		m.store.Update(agent.ID, StatusReview)
+		m.events.Add(EventStatus, agent.Name, "marked for review")
+		m.setStatus(fmt.Sprintf("Marked %s NEEDS-REVIEW (M again once reviewed)", agent.Name))
+	}
+	m.refreshAgents()
+	m.cachedCards = m.buildCardData()
+}
+
+// toggleCheckpoint turns checkpoint commits on or off for the selected agent.
+func (m *Model) toggleCheckpoint() {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return
+	}
+	agent := m.agents[m.selected]
+	if agent.Discovered {
+		m.setStatus("Adopt the agent first (A) to checkpoint it")
+		return
+	}
+	m.store.SetCheckpoint(agent.ID, !agent.Checkpoint)
+	if agent.Checkpoint {
+		m.setStatus(fmt.Sprintf("Checkpoints ON for %s: its changes are committed when it stops", agent.Name))
+	} else {
+		m.setStatus(fmt.Sprintf("Checkpoints OFF for %s", agent.Name))
+	}
+}
+
+// openPRSelected pushes the selected agent's branch and opens a pull
+// request for it.
+func (m *Model) openPRSelected() tea.Cmd {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) {
+		return nil
+	}
+	agent := m.agents[m.selected]
+	m.setStatus(fmt.Sprintf("Opening PR for %s...", agent.Name))
+	return prCmd(agent)
+}
+
+// startPending starts queued agents, oldest first, while fewer than
+// maxRunning agents are RUNNING, and sends their task.
+func (m *Model) startPending() {
+	for _, agent := range startablePending(m.store.List(), m.maxRunning) {
+		if err := startQueued(m.store, m.manager, agent); err != nil {
+			m.store.SetExitReason(agent.ID, fmt.Sprintf("couldn't start from the queue: %v", err))
+			m.store.Update(agent.ID, StatusDone)
+			m.events.Add(EventSpawn, agent.Name, fmt.Sprintf("queued start failed: %v", err))
+			continue
+		}
+		m.events.Add(EventSpawn, agent.Name, "started from the queue")
+		switch {
+		case agent.Stream:
+			m.store.MarkPromptSent(agent.ID, time.Now())
+		case agent.Prompt != "" && agent.After == "" && agent.PromptAt.IsZero():
+			m.store.MarkPromptSent(agent.ID, time.Now().Add(promptStartupDelay))
+			go SendPromptAfterDelay(agent.SessionName, agent.Prompt)
+		}
+	}
+}
+
+// releaseDependents sends the held prompt of every agent whose dependency
+// has finished. Agents whose dependency was killed are released without
+// their prompt: the work they were waiting on never happened.
+func (m *Model) releaseDependents() {
+	ready, orphaned := dueDependents(m.store.List())
+	for _, a := range ready {
+		dep := m.store.Get(a.After)
+		m.store.SetAfter(a.ID, "")
+		if a.Ended() || a.SessionName == "" {
+			m.events.Add(EventChain, a.Name, "task not sent: agent has exited")
+			continue
+		}
+		m.store.MarkPromptSent(a.ID, time.Now())
+		go SendPrompt(a.SessionName, a.Prompt)
+		m.events.Add(EventChain, a.Name, "task sent: "+dep.Name+" finished")
+	}
+	for _, a := range orphaned {
+		m.store.SetAfter(a.ID, "")
+		m.events.Add(EventChain, a.Name, "task not sent: the agent it waited for is gone")
+		m.setStatus(fmt.Sprintf("%s: dependency gone, task not sent", a.Name))
+	}
+}
+
+func (m *Model) refreshStatuses() {
+	// Track transitions for notifications
+	var transitions []statusTransition
+
+	now := time.Now()
+	transitions = append(transitions, m.applyThrottles(now)...)
+	var idled []*Agent
+	for _, agent := range m.agents {
+		if agent.Status == StatusPending || agent.Status == StatusPaused || agent.Status == StatusThrottled || agent.Status == StatusReview {
+			continue
+		}
+		// Detached by idle shutdown: left alone until zoomed into
+		if agent.Detached {
+			continue
+		}
+		// TIMED-OUT holds until the timeout is raised or removed
+		if agent.Status == StatusTimeout && overTime(agent, now) {
+			continue
+		}
+		// A crash is an ERROR whatever hooks last said
+		if !agent.Discovered && !agent.Ended() {
+			if ex, ok := reapExit(agent.SessionName); ok {
+				if err := saveTranscript(m.transcriptDir, agent, ex.Screen, "exited", now); err != nil {
+					m.setStatus(fmt.Sprintf("Transcript of %s not saved: %v", agent.Name, err))
+				}
+				if reason, failed := ex.failure(); failed {
+					if agent.KeepAlive && m.keepAlive(agent) {
+						continue
+					}
+					m.store.SetFailure(agent.ID, reason)
+					transitions = append(transitions, statusTransition{agent.ID, agent.Name, agent.Status, StatusError})
+					m.store.Update(agent.ID, StatusError)
+					m.events.Add(EventError, agent.Name, reason)
+					continue
+				}
+				m.noteExit(agent, ex.ended())
+			}
+		}
+		if m.manager.crashed(agent) && m.keepAlive(agent) {
+			continue
+		}
+		// ERROR holds until the agent is resumed in a new session
+		if agent.Status == StatusError && !IsSessionAlive(agent.SessionName) {
+			continue
+		}
+		oldStatus := agent.Status
+		newStatus, why := m.manager.DetectStatus(agent)
+		// The backend only sees an ASK agent as IDLE
+		if newStatus == StatusIdle && oldStatus == StatusAsk {
+			newStatus = StatusAsk
+		}
+		if newStatus != oldStatus {
+			if newStatus == StatusError {
+				// Only the event stream reports errors this way
+				m.store.SetFailure(agent.ID, "the run ended in an error")
+			}
+			if newStatus == StatusIdle || newStatus == StatusDone {
+				if s := agentSummary(agent, m.previews[agent.ID]); s != "" {
+					m.store.SetSummary(agent.ID, s)
+				}
+			}
+			if newStatus == StatusDone {
+				m.noteExit(agent, why)
+			}
+			// A turn that ends on a question waits for an answer
+			if newStatus == StatusIdle && askQuestion(agent.Summary) != "" {
+				newStatus = StatusAsk
+			}
+			m.store.Update(agent.ID, newStatus)
+			transitions = append(transitions, statusTransition{agent.ID, agent.Name, oldStatus, newStatus})
+			if checkpointDue(agent, oldStatus, newStatus) {
+				m.checkpoints = append(m.checkpoints, agent)
+			}
+			if newStatus == StatusIdle && agent.Nudge != nil {
+				idled = append(idled, agent)
+			}
+			if newStatus == StatusIdle && !m.checks[agent.ID].Running {
+				if _, ok := validationFor(m.validations, agent); ok {
+					m.validating = append(m.validating, agent)
+				}
+			}
+		}
+		// Remember the conversation for an exact resume after the session dies
+		sid := readHookSessionID(agent.ID)
+		if agent.Stream {
+			if st, ok := streamStatus(agent.ID); ok && st.SessionID != "" {
+				sid = st.SessionID
+			}
+		}
+		if sid != "" && sid != agent.SessionID {
+			m.store.SetSessionID(agent.ID, sid)
+		}
+	}
+
+	// Stuck detection: RUNNING >10min with no recent hook activity
 	for _, agent := range m.agents {
 		if agent.Status == StatusRunning && !agent.Discovered &&
 			time.Since(agent.StatusSince) > 10*time.Minute {
 			// Check if hook file is stale or missing
 			hookPath := filepath.Join(hookStatusDir(), agent.ID+".json")
+			if agent.Stream {
+				hookPath = streamLogPath(agent.ID)
+			}
 			info, err := os.Stat(hookPath)
 			if err != nil || time.Since(info.ModTime()) > 5*time.Minute {
-				m.store.Update(agent.ID, StatusError)
-				transitions = append(transitions, statusTransition{agent.Name, StatusRunning, StatusError})
+				m.store.Update(agent.ID, StatusStuck)
+				transitions = append(transitions, statusTransition{agent.ID, agent.Name, StatusRunning, StatusStuck})
 			}
 		}
 	}
 
+	for _, agent := range m.agents {
+		if timeoutDue(agent, now) {
+			transitions = append(transitions, statusTransition{agent.ID, agent.Name, agent.Status, StatusTimeout})
+			m.store.Update(agent.ID, StatusTimeout)
+			m.enforceTimeout(agent)
+		}
+	}
+	m.applyIdleShutdown(now)
+	answered := m.applyRules()
+	nudged := m.applyNudges(idled)
+
+	for _, t := range transitions {
+		m.events.Add(EventStatus, t.name, fmt.Sprintf("%s → %s", t.oldSt, t.newSt))
+	}
+	transitions = needingAttention(transitions, answered, nudged)
+
 	// Notify on transitions
 	if len(transitions) > 0 {
 		m.notifyTransitions(transitions)
 	}
+	m.fireTransitions(transitions)
 
 	// Auto-remove discovered agents that have been DONE for >30s
 	for _, agent := range m.agents {
@@ -1372,21 +3457,114 @@ func (m *Model) refreshStatuses() {
 	}
 }
 
+// finishValidation records a validation that finished, sending a failure
+// back to the agent when its project asks for that.
+func (m *Model) finishValidation(msg validationMsg) {
+	r := m.checks[msg.id]
+	r.Running, r.Passed, r.Output = false, msg.passed, msg.out
+	if msg.passed {
+		r.Fed = 0
+		m.events.Add(EventValidate, msg.name, r.Command+" passed")
+	} else {
+		m.events.Add(EventValidate, msg.name, r.Command+" failed: "+lastLine(msg.out))
+		if agent := m.store.Get(msg.id); agent != nil && m.sendValidationFailure(agent, r) {
+			r.Fed++
+		}
+	}
+	m.checks[msg.id] = r
+}
+
+// sendValidationFailure types a failed validation's output into an agent
+// of a project with feedback on, while it's still IDLE and at most
+// maxValidationFeedback times in a row. It reports whether it sent it.
+func (m *Model) sendValidationFailure(agent *Agent, r validationResult) bool {
+	v, _ := validationFor(m.validations, agent)
+	if !v.Feedback || agent.Discovered || agent.Status != StatusIdle || agent.SessionName == "" {
+		return false
+	}
+	if r.Fed >= maxValidationFeedback {
+		m.setStatus(fmt.Sprintf("%s: %s still failing after %d tries", agent.Name, r.Command, r.Fed))
+		return false
+	}
+	feedback := validationFeedback(r.Command, r.Output)
+	if err := SendPrompt(agent.SessionName, feedback); err != nil {
+		m.events.Add(EventValidate, agent.Name, fmt.Sprintf("feedback not sent: %v", err))
+		return false
+	}
+	m.store.LogPrompt(agent.ID, "validation", feedback)
+	m.events.Add(EventValidate, agent.Name, fmt.Sprintf("sent the failure back (%d of %d)", r.Fed+1, maxValidationFeedback))
+	return true
+}
+
+// fireTransitions runs the user's lifecycle commands for agents that
+// started waiting on them or finished.
+func (m *Model) fireTransitions(transitions []statusTransition) {
+	for _, t := range transitions {
+		var event string
+		switch t.newSt {
+		case StatusWaiting, StatusAsk:
+			event = LifecycleWaiting
+		case StatusDone:
+			event = LifecycleDone
+		default:
+			continue
+		}
+		if a := m.store.Get(t.id); a != nil {
+			m.manager.fireLifecycle(event, a)
+		}
+	}
+}
+
+// applyNudges sends each agent that just went IDLE with work left its
+// nudge, returning the IDs of those it nudged.
+func (m *Model) applyNudges(idled []*Agent) map[string]bool {
+	nudged := make(map[string]bool)
+	for _, agent := range idled {
+		todos := m.manager.GetPaneInfo(agent, 0).Todos
+		if !nudgeDue(agent, todos, agent.Summary) {
+			continue
+		}
+		if err := SendPrompt(agent.SessionName, agent.Nudge.Message); err != nil {
+			m.events.Add(EventNudge, agent.Name, fmt.Sprintf("not sent: %v", err))
+			continue
+		}
+		m.store.SetNudges(agent.ID, agent.Nudges+1)
+		m.store.LogPrompt(agent.ID, "nudge", agent.Nudge.Message)
+		m.events.Add(EventNudge, agent.Name, fmt.Sprintf("sent %q (%d of %d)", agent.Nudge.Message, agent.Nudges, agent.Nudge.Max))
+		nudged[agent.ID] = true
+	}
+	return nudged
+}
+
+// needingAttention drops the transitions no one needs to hear about:
+// prompts a rule answered and stops a nudge carried on from, given the
+// IDs of the agents answered and nudged.
+func needingAttention(transitions []statusTransition, answered, nudged map[string]bool) []statusTransition {
+	kept := transitions[:0]
+	for _, t := range transitions {
+		if (t.newSt != StatusWaiting || !answered[t.id]) && (t.newSt != StatusIdle || !nudged[t.id]) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
 // statusTransition records a single agent status change.
 type statusTransition struct {
-	name  string
+	id    string
+	name  string // names can repeat; id tells the agents apart
 	oldSt AgentStatus
 	newSt AgentStatus
 }
 
 // notifyTransitions shows a status bar message and rings the bell for WAITING transitions.
 func (m *Model) notifyTransitions(transitions []statusTransition) {
-	// Priority: WAITING > STUCK > DONE > IDLE > RUNNING
+	// Priority: WAITING, ASK > ERROR, STUCK, TIMED-OUT > DONE > IDLE > RUNNING
 	priority := func(s AgentStatus) int {
 		switch s {
-		case StatusWaiting:
+		case StatusWaiting, StatusAsk:
 			return 5
-		case StatusError:
+		case StatusError, StatusStuck, StatusTimeout:
 			return 4
 		case StatusDone:
 			return 3
@@ -1397,10 +3575,19 @@ func (m *Model) notifyTransitions(transitions []statusTransition) {
 		}
 	}
 
+	// The agents' own priorities break ties
+	agentPriority := func(id string) string {
+		if a := m.store.Get(id); a != nil {
+			return a.Priority
+		}
+		return PriorityNormal
+	}
+
 	// Find highest priority transition
 	best := 0
 	for i, t := range transitions {
-		if priority(t.newSt) > priority(transitions[best].newSt) {
+		p, bp := priority(t.newSt), priority(transitions[best].newSt)
+		if p > bp || (p == bp && priorityRank(agentPriority(t.id)) < priorityRank(agentPriority(transitions[best].id))) {
 			best = i
 		}
 	}
@@ -1412,20 +3599,238 @@ func (m *Model) notifyTransitions(transitions []statusTransition) {
 	}
 	m.setStatus(msg)
 
-	// Ring terminal bell for transitions that need attention
-	if t.newSt == StatusWaiting || t.newSt == StatusError {
+	// Ring terminal bell for transitions that need attention, and when a
+	// high-priority agent finishes; low-priority agents never ring it
+	bell := t.newSt == StatusWaiting || t.newSt == StatusAsk || t.newSt == StatusError || t.newSt == StatusStuck || t.newSt == StatusTimeout
+	switch agentPriority(t.id) {
+	case PriorityHigh:
+		bell = bell || t.newSt == StatusIdle || t.newSt == StatusDone
+	case PriorityLow:
+		bell = false
+	}
+	if m.alerts.BellEnabled() && bell {
 		fmt.Print("\a")
 	}
+
+	if m.alerts.Say {
+		var waiting []string
+		for _, t := range transitions {
+			if t.oldSt == StatusRunning && (t.newSt == StatusWaiting || t.newSt == StatusAsk) && agentPriority(t.id) != PriorityLow {
+				waiting = append(waiting, t.name)
+			}
+		}
+		if len(waiting) > 0 {
+			speak(waitingAlertText(waiting))
+		}
+	}
 }
 
-func (m *Model) discoverAgents() {
-	var found []DiscoveredAgent
-	for _, b := range AllBackends() {
-		found = append(found, b.Discover()...)
+// enforceTimeout applies on_timeout to an agent that just went TIMED-OUT.
+func (m *Model) enforceTimeout(agent *Agent) {
+	limit := formatTimeout(agent.Timeout)
+	switch m.timeoutAction {
+	case TimeoutInterrupt:
+		if agent.SessionName == "" {
+			return
+		}
+		if err := SendKeyNames(agent.SessionName, "Escape"); err != nil {
+			m.events.Add(EventTimeout, agent.Name, fmt.Sprintf("interrupt failed: %v", err))
+			return
+		}
+		m.events.Add(EventTimeout, agent.Name, "interrupted after "+limit)
+	case TimeoutKill:
+		m.archiveAll([]*Agent{agent}, "killed after its timeout")
+		if m.manager.GetSession(agent) != nil {
+			_ = m.manager.Kill(agent.ID)
+		} else if agent.SessionName != "" {
+			_ = KillBySession(agent.SessionName)
+		}
+		// Shown once the timeout is lifted and it goes DONE
+		m.store.SetExitReason(agent.ID, "killed after its "+limit+" timeout")
+		m.events.Add(EventTimeout, agent.Name, "session killed after "+limit)
+	}
+}
+
+// applyThrottles releases THROTTLED agents once their limit resets:
+// typing throttle_resume into those that can take it, or leaving them
+// IDLE. Agents go THROTTLED by the background scan (applyThrottleScan).
+func (m *Model) applyThrottles(now time.Time) []statusTransition {
+	var transitions []statusTransition
+	for _, agent := range m.agents {
+		if agent.SessionName == "" || agent.Status != StatusThrottled {
+			continue
+		}
+		alive := IsSessionAlive(agent.SessionName)
+		if alive && now.Before(agent.ResetAt) {
+			continue
+		}
+		if m.throttleSeen == nil {
+			m.throttleSeen = make(map[string]string)
+		}
+		if content, err := CapturePanePlain(agent.SessionName); err == nil {
+			m.throttleSeen[agent.ID] = paneTail(content)
+		}
+		next := StatusIdle
+		if alive && m.throttleResume != "" && !agent.Discovered {
+			if err := SendPrompt(agent.SessionName, m.throttleResume); err != nil {
+				m.events.Add(EventThrottle, agent.Name, fmt.Sprintf("resume failed: %v", err))
+			} else {
+				next = StatusRunning
+				m.store.LogPrompt(agent.ID, "resume", m.throttleResume)
+				m.events.Add(EventThrottle, agent.Name, fmt.Sprintf("limit reset; sent %q", m.throttleResume))
+			}
+		} else {
+			m.events.Add(EventThrottle, agent.Name, "limit reset")
+		}
+		m.store.Update(agent.ID, next)
+		transitions = append(transitions, statusTransition{agent.ID, agent.Name, StatusThrottled, next})
 	}
+	return transitions
+}
+
+// throttleCandidates lists the agents whose panes the background scan
+// looks at for a rate-limit message: a CLI that hit its limit stops, and
+// RUNNING output that merely mentions rate limits doesn't count.
+func (m *Model) throttleCandidates() []throttleCandidate {
+	var out []throttleCandidate
+	for _, agent := range m.agents {
+		if agent.SessionName == "" {
+			continue
+		}
+		switch agent.Status {
+		case StatusIdle, StatusWaiting, StatusStuck:
+			out = append(out, throttleCandidate{agent.ID, agent.SessionName, agent.Status, agent.StatusSince, m.throttleSeen[agent.ID]})
+		}
+	}
+	return out
+}
+
+// applyThrottleScan puts the agents the background scan found stopped on
+// a rate-limit message into THROTTLED, unless they've moved on since,
+// reporting whether any went.
+func (m *Model) applyThrottleScan(found []throttleFound, now time.Time) bool {
+	var transitions []statusTransition
+	for _, f := range found {
+		agent := m.store.Get(f.id)
+		if agent == nil || agent.Status != f.status || !agent.StatusSince.Equal(f.since) {
+			continue
+		}
+		until := f.th.Until
+		if until.IsZero() {
+			until = now.Add(defaultThrottleBackoff)
+		} else if !until.After(now) {
+			// An old message whose limit has already reset
+			continue
+		}
+		m.store.SetResetAt(agent.ID, until)
+		transitions = append(transitions, statusTransition{agent.ID, agent.Name, agent.Status, StatusThrottled})
+		m.store.Update(agent.ID, StatusThrottled)
+		m.events.Add(EventThrottle, agent.Name, fmt.Sprintf("%s (until %s)", f.th.Message, until.Local().Format("15:04")))
+	}
+	if len(transitions) == 0 {
+		return false
+	}
+	for _, t := range transitions {
+		m.events.Add(EventStatus, t.name, fmt.Sprintf("%s → %s", t.oldSt, t.newSt))
+	}
+	m.notifyTransitions(transitions)
+	return true
+}
+
+// applyIdleShutdown warns about agents nearing the idle_shutdown limit and
+// kills or detaches the sessions of those past it.
+func (m *Model) applyIdleShutdown(now time.Time) {
+	for _, agent := range m.agents {
+		switch m.idleShutdown.stage(agent, now) {
+		case idleWarning:
+			if m.idleWarned[agent.ID].Equal(agent.StatusSince) {
+				continue
+			}
+			if m.idleWarned == nil {
+				m.idleWarned = make(map[string]time.Time)
+			}
+			m.idleWarned[agent.ID] = agent.StatusSince
+			notice := m.idleShutdown.notice(agent, now)
+			m.events.Add(EventIdle, agent.Name, notice)
+			m.setStatus(fmt.Sprintf("%s: %s", agent.Name, notice))
+		case idleShutdown:
+			idle := formatTimeout(now.Sub(agent.StatusSince).Truncate(time.Minute))
+			if m.idleShutdown.IdleAction() == IdleDetach {
+				m.manager.Detach(agent.ID)
+				m.store.SetDetached(agent.ID, true)
+				m.events.Add(EventIdle, agent.Name, "detached after "+idle+" idle")
+				m.setStatus(fmt.Sprintf("Detached %s after %s idle", agent.Name, idle))
+			} else {
+				m.archiveAll([]*Agent{agent}, "killed after "+idle+" idle")
+				if m.manager.GetSession(agent) != nil {
+					_ = m.manager.Kill(agent.ID)
+				} else if agent.SessionName != "" {
+					_ = KillBySession(agent.SessionName)
+				}
+				agent.Backend().CleanHookStatus(agent.ID)
+				m.store.SetExitReason(agent.ID, "killed after "+idle+" idle")
+				m.store.Update(agent.ID, StatusDone)
+				m.events.Add(EventIdle, agent.Name, "session killed after "+idle+" idle")
+				m.setStatus(fmt.Sprintf("Killed %s's session after %s idle", agent.Name, idle))
+			}
+			delete(m.idleWarned, agent.ID)
+		}
+	}
+}
+
+// applyRules answers the permission prompts of WAITING agents that an
+// approval rule covers, the agent's own rules first. Each prompt is acted
+// on once. Returns the IDs of agents whose prompt was answered.
+func (m *Model) applyRules() map[string]bool {
+	answered := make(map[string]bool)
+	for _, agent := range m.agents {
+		if agent.Status != StatusWaiting || agent.Discovered || agent.SessionName == "" {
+			continue
+		}
+		if len(agent.Rules) == 0 && len(m.approvalRules) == 0 {
+			continue
+		}
+		content, err := CapturePanePlain(agent.SessionName)
+		if err != nil {
+			continue
+		}
+		req, opts, ok := parsePermissionRequest(content)
+		if !ok {
+			continue
+		}
+		sig := agent.StatusSince.String() + "\x00" + req.Text
+		if m.ruled[agent.ID] == sig {
+			continue
+		}
+		own, _ := parseRules(agent.Rules)
+		rule, ok := matchRule(append(own, m.approvalRules...), req)
+		if !ok {
+			continue
+		}
+		if m.ruled == nil {
+			m.ruled = make(map[string]string)
+		}
+		m.ruled[agent.ID] = sig
+		key, did, ok := ruleAnswer(rule, opts)
+		if !ok {
+			m.events.Add(EventRule, agent.Name, fmt.Sprintf("left %s for you (%s)", req.Summary(), rule))
+			continue
+		}
+		if err := SendKeyNames(agent.SessionName, key); err != nil {
+			m.events.Add(EventRule, agent.Name, fmt.Sprintf("answer failed: %v", err))
+			continue
+		}
+		m.events.Add(EventRule, agent.Name, fmt.Sprintf("%s %s (%s)", did, req.Summary(), rule))
+		answered[agent.ID] = true
+	}
+	return answered
+}
+
+func (m *Model) discoverAgents() {
+	found := discoverAll()
 	before := len(m.agents)
 	m.mergeDiscovered(found)
-	m.agents = m.store.List()
+	m.refreshAgents()
 	added := len(m.agents) - before
 
 	// Count total external agents for a more informative message
@@ -1444,15 +3849,254 @@ func (m *Model) discoverAgents() {
 	}
 }
 
-func (m *Model) setStatus(msg string) {
-	m.statusMsg = msg
-	m.statusExpires = time.Now().Add(5 * time.Second)
-}
-
-func (m *Model) updateSpawnInputs(msg tea.Msg) tea.Cmd {
-	var cmd tea.Cmd
-	m.spawnDir, cmd = m.spawnDir.Update(msg)
-	return cmd
+// refreshAgents reloads the visible agent list from the store, applying the
+// board filter and keeping the selection in range.
+func (m *Model) refreshAgents() {
+	m.agents = filterAgents(m.store.List(), m.filter, m.previews)
+	m.groups = nil
+	if m.grouped {
+		m.agents, m.groups = groupAgents(m.agents, m.groupKey, m.collapsed)
+	}
+	if m.selected >= len(m.agents) && len(m.agents) > 0 {
+		m.selected = len(m.agents) - 1
+	}
+}
+
+// setGrouped switches group-by-project mode, keeping the selected agent
+// selected across the reorder.
+func (m *Model) setGrouped(on bool) {
+	if m.grouped == on {
+		return
+	}
+	var selID string
+	if m.selected < len(m.agents) {
+		selID = m.agents[m.selected].ID
+	}
+	m.grouped = on
+	m.refreshAgents()
+	for i, a := range m.agents {
+		if a.ID == selID {
+			m.selected = i
+		}
+	}
+	m.cachedCards = m.buildCardData()
+	m.scrollOffset = 0
+	m.ensureSelectedVisible()
+}
+
+// groupKey returns the group an agent is shown under in the grouped view:
+// its swarm, if it's in one, else its project.
+func (m Model) groupKey(a *Agent) string {
+	if a.Swarm != "" {
+		return swarmGroupPrefix + a.Swarm
+	}
+	return m.projectRoot(a.Dir)
+}
+
+// projectRoot returns the git toplevel of dir once known, else dir itself.
+func (m Model) projectRoot(dir string) string {
+	if root := m.repoRoots[dir]; root != "" {
+		return root
+	}
+	return dir
+}
+
+// refreshDetail captures a longer preview for the selected agent while the
+// detail panel is open.
+func (m *Model) refreshDetail() {
+	if !m.showDetail || m.selected >= len(m.agents) || m.manager == nil {
+		return
+	}
+	agent := m.agents[m.selected]
+	m.detailID = agent.ID
+	m.detailPreview = m.manager.GetPaneInfo(agent, 60).Preview
+}
+
+// detailData assembles the side panel for the selected agent. Until the next
+// tick captures a longer preview, the card's preview is used.
+func (m Model) detailData(cards []ui.CardData) ui.DetailData {
+	agent := m.agents[m.selected]
+	d := ui.DetailData{
+		Backend: agent.Backend().Name(),
+		Session: agent.SessionName,
+	}
+	d.Branch = agent.Branch
+	if agent.Checkpoint {
+		d.Checkpoint = "commit changes when it stops"
+	}
+	if agent.Timeout > 0 {
+		switch left := agent.Timeout - time.Since(runStart(agent)); {
+		case left <= 0:
+			d.Timeout = formatTimeout(agent.Timeout) + ", exceeded"
+		case left < time.Minute:
+			d.Timeout = formatTimeout(agent.Timeout) + ", under a minute left"
+		default:
+			d.Timeout = formatTimeout(agent.Timeout) + ", " + formatTimeout(left.Truncate(time.Minute)) + " left"
+		}
+	}
+	d.Rules = strings.Join(agent.Rules, "; ")
+	d.Nudge = nudgeNotice(agent)
+	if agent.KeepAlive {
+		d.Restarts = fmt.Sprintf("keep alive, %d of %d restarts used", recentRestarts(agent, time.Now()), maxRestarts)
+	}
+	if m.selected < len(cards) {
+		d.CardData = cards[m.selected]
+	}
+	if m.detailID == agent.ID && len(m.detailPreview) > 0 {
+		d.Preview = m.detailPreview
+	}
+	for _, h := range agent.History {
+		d.History = append(d.History, ui.HistoryEntry{Status: string(h.Status), At: h.At})
+	}
+	for _, s := range agent.Sent {
+		// A task typed in after a startup delay isn't sent yet
+		if s.At.Before(time.Now()) {
+			d.Sent = append(d.Sent, ui.SentEntry{At: s.At, Via: s.Via, Text: s.Text})
+		}
+	}
+	return d
+}
+
+// gitDirs lists the distinct working directories of live agents.
+func (m Model) gitDirs() []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, a := range m.store.List() {
+		if a.Ended() || a.Dir == "" || seen[a.Dir] {
+			continue
+		}
+		seen[a.Dir] = true
+		dirs = append(dirs, a.Dir)
+	}
+	return dirs
+}
+
+// rememberPreviews records each visible card's preview text so the filter
+// can match on agent output.
+func (m *Model) rememberPreviews() {
+	if m.previews == nil {
+		m.previews = make(map[string]string)
+	}
+	for i, c := range m.cachedCards {
+		if i < len(m.agents) {
+			m.previews[m.agents[i].ID] = strings.Join(c.Preview, "\n")
+		}
+	}
+}
+
+// rememberUsage keeps each card's usage reading so totals survive the
+// /cost or status output scrolling out of the pane.
+func (m *Model) rememberUsage() {
+	if m.usage == nil {
+		m.usage = make(map[string]Usage)
+	}
+	for i, c := range m.cachedCards {
+		if i < len(m.agents) {
+			m.usage[m.agents[i].ID] = Usage{Tokens: c.Usage.Tokens, Cost: c.Usage.Cost}
+		}
+	}
+}
+
+// totalUsage sums token and cost readings across all agents for the title
+// bar. Agents on backends that don't report cost make the cost partial.
+func (m Model) totalUsage() ui.UsageInfo {
+	var total ui.UsageInfo
+	for _, a := range m.store.List() {
+		u := m.usage[a.ID]
+		total.Tokens += u.Tokens
+		total.Cost += u.Cost
+		if u.Tokens > 0 && !a.Backend().Capabilities().SupportsCostReporting {
+			total.Partial = true
+		}
+	}
+	return total
+}
+
+// totalResources sums the CPU and memory of every agent's processes for
+// the title bar, warning if any agent is past the memory limit.
+func (m Model) totalResources() ui.ResourceInfo {
+	var total Resources
+	warn := false
+	for _, a := range m.store.List() {
+		if a.SessionName == "" {
+			continue
+		}
+		r := m.resources[a.SessionName]
+		total = total.Add(r)
+		warn = warn || r.Info(m.memoryWarn).Warn
+	}
+	info := total.Info(0)
+	info.Warn = warn
+	return info
+}
+
+func (m *Model) openFilter() {
+	m.view = viewFilter
+	m.filterInput.SetValue(m.filter)
+	m.filterInput.CursorEnd()
+	m.filterInput.Focus()
+}
+
+// setFilter applies a new board filter and rebuilds the visible cards.
+func (m *Model) setFilter(filter string) {
+	m.filter = strings.TrimSpace(filter)
+	m.selected = 0
+	m.scrollOffset = 0
+	m.refreshAgents()
+	m.cachedCards = m.buildCardData()
+}
+
+func (m *Model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.setFilter("")
+		m.filterInput.Blur()
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
+	case "enter":
+		m.filterInput.Blur()
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	// Narrow the board live as the user types
+	if strings.TrimSpace(m.filterInput.Value()) != m.filter {
+		m.setFilter(m.filterInput.Value())
+	}
+	return m, cmd
+}
+
+// filterBar renders the filter input while editing, or the active filter.
+func (m Model) filterBar() string {
+	if m.view == viewFilter {
+		return "  " + m.filterInput.View()
+	}
+	if m.filter != "" {
+		return ui.DimText.Render(fmt.Sprintf("  Filter: %s  (%d shown, / edit, Esc clear)", m.filter, len(m.agents)))
+	}
+	return ""
+}
+
+func (m *Model) setStatus(msg string) {
+	m.statusMsg = msg
+	m.statusExpires = time.Now().Add(5 * time.Second)
+}
+
+func (m *Model) updateSpawnInputs(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	if m.spawnFocus == focusPrompt {
+		m.spawnPrompt, cmd = m.spawnPrompt.Update(msg)
+		return cmd
+	}
+	m.spawnDir, cmd = m.spawnDir.Update(msg)
+	return cmd
 }
 
 // expandTilde replaces a leading ~/ with the user's home directory.
@@ -1495,8 +4139,36 @@ func listSubdirs(dir string) []string {
 	return dirs
 }
 
-// refreshSpawnSuggestions updates the suggestion list based on current input.
+// refreshSpawnSuggestions updates the suggestion list based on current input:
+// matching favorite and recent directories first, then subdirectories.
 func (m *Model) refreshSpawnSuggestions() {
+	m.refreshDirSuggestions()
+
+	val := m.spawnDir.Value()
+	partial := ""
+	if val != "" && !strings.HasSuffix(val, "/") {
+		partial = strings.ToLower(filepath.Base(val))
+	}
+	var quick []string
+	inQuick := make(map[string]bool)
+	for _, d := range m.store.QuickDirs() {
+		d = collapseTilde(d)
+		if strings.Contains(strings.ToLower(filepath.Base(d)), partial) {
+			quick = append(quick, d)
+			inQuick[d] = true
+		}
+	}
+	for _, s := range m.spawnSuggestions {
+		if !inQuick[s] {
+			quick = append(quick, s)
+		}
+	}
+	m.spawnQuick = len(inQuick)
+	m.spawnSuggestions = quick
+}
+
+// refreshDirSuggestions lists subdirectories matching the current input.
+func (m *Model) refreshDirSuggestions() {
 	val := m.spawnDir.Value()
 	if val == "" {
 		m.spawnSuggestions = nil
@@ -1557,6 +4229,40 @@ func (m Model) View() string {
 		return m.viewWorkspace()
 	case viewSend:
 		return m.viewSend()
+	case viewRename:
+		return m.viewRename()
+	case viewTimeout:
+		return m.viewTimeoutDialog()
+	case viewRules:
+		return m.viewRulesDialog()
+	case viewNudge:
+		return m.viewNudgeDialog()
+	case viewTags:
+		return m.viewTags()
+	case viewNote:
+		return m.viewNoteDialog()
+	case viewHelp:
+		return ui.RenderHelp(m.width, m.height)
+	case viewPin:
+		return m.viewPinDialog()
+	case viewApprove:
+		return m.viewApproveDialog()
+	case viewQuit:
+		return m.viewQuitDialog()
+	case viewHandoff:
+		return m.viewHandoffDialog()
+	case viewBackends:
+		return m.viewBackendsDialog()
+	case viewSplit:
+		return m.viewSplit()
+	case viewEvents:
+		return m.viewEventFeed()
+	case viewStats:
+		return m.viewStatsScreen()
+	case viewArchive:
+		return m.viewArchiveScreen()
+	case viewBoards:
+		return m.viewBoardsDialog()
 	case viewConfirmKill:
 		return m.viewConfirmKill()
 	case viewConfirmAutoApprove:
@@ -1565,6 +4271,11 @@ func (m Model) View() string {
 		return m.viewBatchDialog()
 	case viewCarousel:
 		return m.viewCarousel()
+	case viewFilter:
+		if m.columns == 1 {
+			return m.viewCarousel()
+		}
+		return m.viewBoard()
 	default:
 		return m.viewBoard()
 	}
@@ -1591,15 +4302,39 @@ func (m Model) viewZoom() string {
 	if dir != "" {
 		header += lipgloss.NewStyle().Foreground(ui.ColorDim).Render("  " + dir)
 	}
+	if m.zoomObserve {
+		header += " " + ui.BadgeWaiting.Render("OBSERVE")
+	}
 	if m.zoomScrollOff > 0 {
 		header += ui.HelpStyle.Render(fmt.Sprintf("  [scrolled +%d lines]", m.zoomScrollOff))
 	}
+	header = withStrip(header, m.statusStrip, m.width)
 
 	// Horizontal rules
 	rule := lipgloss.NewStyle().Foreground(ui.ColorBorder).Render(strings.Repeat("─", m.width))
 
 	// Footer (pinned to bottom, matching dashboard style)
-	footerKeys := ui.HelpStyle.Render("[Ctrl+Q] dashboard  [Ctrl+J] newline  [PgUp/PgDn] scroll")
+	footerKeys := ui.HelpStyle.Render(ui.FooterKeys(ui.ZoomKeys, false))
+	switch {
+	case m.zoomSearching:
+		footerKeys = m.zoomSearchInput.View() + ui.HelpStyle.Render("  [Enter] search  [Esc] cancel")
+	case m.zoomCopying:
+		n := 1
+		if m.zoomAnchor >= 0 {
+			n = m.zoomCursor - m.zoomAnchor
+			if n < 0 {
+				n = -n
+			}
+			n++
+		}
+		footerKeys = ui.SearchCurrent.Render(" COPY ") + ui.HelpStyle.Render(fmt.Sprintf(
+			" %d lines  [↑/↓] move  [v] mark  [y/Enter] copy  [Esc] cancel", n))
+	case m.zoomQuery != "":
+		footerKeys = ui.HelpStyle.Render(fmt.Sprintf("%q match %d/%d  [n] older  [N] newer  [Esc] end search",
+			m.zoomQuery, m.zoomMatchIdx+1, len(m.zoomMatches)))
+	case m.zoomObserve:
+		footerKeys = ui.HelpStyle.Render("Observing — keys aren't sent  [↑/↓ j/k] scroll  [g/G] top/bottom  [/] search  [Ctrl+O] interact  [q] dashboard")
+	}
 	footer := rule + "\n" + " " + footerKeys
 
 	// Calculate content area: total height minus header(1) + top rule(1) + bottom rule(1) + footer text(1)
@@ -1611,10 +4346,118 @@ func (m Model) viewZoom() string {
 	}
 
 	// Pane content — show a window into the full scrollback.
-	content := m.zoomContent
+	visible := scrollWindow(m.zoomContent, m.zoomScrollOff, maxLines)
+	switch {
+	case m.zoomCopying:
+		m.markZoomSelection(visible, maxLines)
+	case m.zoomQuery != "":
+		m.highlightZoomWindow(visible, maxLines)
+	}
+	body := strings.Join(visible, "\n")
+
+	return header + "\n" + rule + "\n" + body + "\n" + footer
+}
+
+// highlightZoomWindow marks search matches in the visible zoom lines, with
+// the current match drawn in a stronger style.
+func (m Model) highlightZoomWindow(visible []string, maxLines int) {
+	start := m.zoomWindowStart(maxLines)
+	current := -1
+	if m.zoomMatchIdx >= 0 && m.zoomMatchIdx < len(m.zoomMatches) {
+		current = m.zoomMatches[m.zoomMatchIdx]
+	}
+	for i := range visible {
+		style := ui.SearchMatch
+		if start+i == current {
+			style = ui.SearchCurrent
+		}
+		visible[i] = highlightMatches(visible[i], m.zoomQuery, style)
+	}
+}
+
+// markZoomSelection draws the copy-mode selection over the visible lines.
+func (m Model) markZoomSelection(visible []string, maxLines int) {
+	from, to := m.zoomCursor, m.zoomCursor
+	if m.zoomAnchor >= 0 {
+		from = m.zoomAnchor
+		if from > to {
+			from, to = to, from
+		}
+	}
+	start := m.zoomWindowStart(maxLines)
+	for i := range visible {
+		switch line := start + i; {
+		case line == m.zoomCursor:
+			visible[i] = ui.SearchCurrent.Render(stripAnsiStr(visible[i]) + " ")
+		case line >= from && line <= to:
+			visible[i] = ui.SearchMatch.Render(stripAnsiStr(visible[i]) + " ")
+		}
+	}
+}
+
+// zoomWindowStart returns the content line index shown at the top of the
+// zoom body, matching scrollWindow.
+func (m Model) zoomWindowStart(maxLines int) int {
+	start := m.zoomTotalLines - m.zoomScrollOff - maxLines
+	if m.zoomTotalLines-m.zoomScrollOff < maxLines {
+		start = 0
+	}
+	if start < 0 {
+		start = 0
+	}
+	return start
+}
+
+// statusStrip renders the fleet-wide status strip from the store, so counts
+// ignore the board filter.
+func (m Model) statusStrip(width int) string {
+	return ui.RenderStatusStrip(statusSummary(m.store.List(), time.Now(), m.staleAfter), width)
+}
+
+// withStrip right-aligns the status strip after line in whatever width is
+// left, for views without a spare row.
+func withStrip(line string, strip func(int) string, width int) string {
+	room := width - lipgloss.Width(line) - 2
+	if room < 20 {
+		return line
+	}
+	s := strip(room)
+	return line + strings.Repeat(" ", width-lipgloss.Width(line)-lipgloss.Width(s)) + s
+}
+
+// activityMinutes is the span of the status strip sparkline.
+const activityMinutes = 10
+
+// statusSummary counts agents per status and buckets their recent status
+// changes per minute, oldest first. Agents RUNNING for longer than
+// staleAfter are named as stale; 0 disables that.
+func statusSummary(agents []*Agent, now time.Time, staleAfter time.Duration) ui.StatusSummary {
+	s := ui.StatusSummary{
+		Counts:     make(map[string]int),
+		Activity:   make([]int, activityMinutes),
+		StaleAfter: staleAfter,
+	}
+	for _, a := range agents {
+		s.Counts[string(a.Status)]++
+		if staleAfter > 0 && a.Status == StatusRunning && now.Sub(a.StatusSince) > staleAfter {
+			s.Stale = append(s.Stale, a.Name)
+		}
+		for _, h := range a.History {
+			ago := int(now.Sub(h.At) / time.Minute)
+			if ago >= 0 && ago < activityMinutes {
+				s.Activity[activityMinutes-1-ago]++
+			}
+		}
+	}
+	return s
+}
+
+// scrollWindow returns maxLines lines of content ending scrollOff lines above
+// the bottom, padded with blank lines so a footer stays pinned below.
+func scrollWindow(content string, scrollOff, maxLines int) []string {
 	lines := strings.Split(content, "\n")
 
-	end := len(lines) - m.zoomScrollOff
+	end := len(lines) - scrollOff
 	if end < maxLines {
 		end = maxLines
 	}
@@ -1625,192 +4468,909 @@ func (m Model) viewZoom() string {
 	if start < 0 {
 		start = 0
 	}
-	visible := lines[start:end]
+	visible := append([]string(nil), lines[start:end]...)
 
-	// Pad body to push footer to bottom
 	for len(visible) < maxLines {
 		visible = append(visible, "")
 	}
-
-	body := strings.Join(visible, "\n")
-
-	return header + "\n" + rule + "\n" + body + "\n" + footer
+	return visible
 }
 
-func (m Model) viewBoard() string {
-	updateVer := ""
-	if m.updateAvailable && !m.updating {
-		updateVer = m.latestVersion
+func (m *Model) handleEventsKey(key string) (tea.Model, tea.Cmd) {
+	half := (m.height - 4) / 2
+	if half < 1 {
+		half = 1
 	}
-	title := ui.RenderTitle(m.width, len(m.agents), m.columns, updateVer, m.activeWorkspace)
-	footer := ui.RenderFooter(m.width, m.columns, m.updateAvailable && !m.updating, m.webServer != nil)
+	maxScroll := len(m.events.List()) - (m.height - 4)
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	switch key {
+	case "esc", "q", "L":
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
+	case "k", "up":
+		m.eventsScroll++
+	case "j", "down":
+		m.eventsScroll--
+	case "pgup":
+		m.eventsScroll += half
+	case "pgdown":
+		m.eventsScroll -= half
+	case "g", "home":
+		m.eventsScroll = maxScroll
+	case "G", "end":
+		m.eventsScroll = 0
+	}
+	if m.eventsScroll > maxScroll {
+		m.eventsScroll = maxScroll
+	}
+	if m.eventsScroll < 0 {
+		m.eventsScroll = 0
+	}
+	return m, nil
+}
 
-	var status string
-	if m.statusMsg != "" && time.Now().Before(m.statusExpires) {
-		status = ui.DimText.Render("  " + m.statusMsg)
+// viewEventFeed shows the event log oldest first, following the newest
+// entries unless scrolled back.
+func (m Model) viewEventFeed() string {
+	events := m.events.List()
+	header := lipgloss.NewStyle().Bold(true).Foreground(ui.ColorAccent).Render(" EVENT FEED ") +
+		ui.HelpStyle.Render(fmt.Sprintf("  %d events", len(events)))
+	if m.eventsScroll > 0 {
+		header += ui.HelpStyle.Render(fmt.Sprintf("  [scrolled +%d]", m.eventsScroll))
 	}
+	header = withStrip(header, m.statusStrip, m.width)
+	rule := lipgloss.NewStyle().Foreground(ui.ColorBorder).Render(strings.Repeat("─", m.width))
 
-	titleHeight := lipgloss.Height(title) + 1 // +1 for blank line
-	footerHeight := lipgloss.Height(footer)
-	if status != "" {
-		footerHeight += lipgloss.Height(status)
+	maxLines := m.height - 4
+	if maxLines < 1 {
+		maxLines = 1
 	}
-	boardHeight := m.height - titleHeight - footerHeight - 1
-	if boardHeight < 5 {
-		boardHeight = 5
+	entries := make([]ui.EventEntry, len(events))
+	for i, e := range events {
+		entries[i] = ui.EventEntry{At: e.At, Kind: string(e.Kind), Agent: e.Agent, Message: e.Message}
+	}
+	var body string
+	if len(entries) == 0 {
+		body = strings.Join(scrollWindow(ui.DimText.Render("  No events yet"), 0, maxLines), "\n")
+	} else {
+		lines := ui.EventLines(entries, m.width-2, time.Now())
+		body = strings.Join(scrollWindow(" "+strings.Join(lines, "\n "), m.eventsScroll, maxLines), "\n")
 	}
 
-	cards := m.getCards()
-	maxVisible := m.maxVisibleCards()
-	board := ui.RenderBoard(cards, m.selected, m.columns, m.width, boardHeight, m.scrollOffset, maxVisible)
+	footer := ui.HelpStyle.Render("[↑/↓/PgUp/PgDn] scroll  [g/G] oldest/newest  [Esc] dashboard")
+	return header + "\n" + rule + "\n" + body + "\n" + rule + "\n " + footer
+}
 
-	// Safety clip: trim any overflow without scroll math
-	board = clipHeight(board, boardHeight)
+func (m *Model) handleStatsKey(key string) (tea.Model, tea.Cmd) {
+	// Rows below the table's header line, which stays put
+	maxScroll := len(m.agents) - (m.height - 5)
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	switch key {
+	case "esc", "q", "%":
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
+	case "k", "up":
+		m.statsScroll--
+	case "j", "down":
+		m.statsScroll++
+	case "pgup":
+		m.statsScroll -= (m.height - 6) / 2
+	case "pgdown":
+		m.statsScroll += (m.height - 6) / 2
+	case "g", "home":
+		m.statsScroll = 0
+	case "G", "end":
+		m.statsScroll = maxScroll
+	}
+	if m.statsScroll > maxScroll {
+		m.statsScroll = maxScroll
+	}
+	if m.statsScroll < 0 {
+		m.statsScroll = 0
+	}
+	return m, nil
+}
 
-	content := lipgloss.JoinVertical(lipgloss.Left, title, "", board)
+// viewStatsScreen shows the running time, permission prompts and turns of
+// each agent on the board (as filtered), with their totals on top.
+func (m Model) viewStatsScreen() string {
+	now := time.Now()
+	header := lipgloss.NewStyle().Bold(true).Foreground(ui.ColorAccent).Render(" STATS ") +
+		ui.HelpStyle.Render("  "+summarizeStats(m.agents, now).String())
+	header = withStrip(header, m.statusStrip, m.width)
+	rule := lipgloss.NewStyle().Foreground(ui.ColorBorder).Render(strings.Repeat("─", m.width))
 
-	contentHeight := lipgloss.Height(content)
-	gap := m.height - contentHeight - footerHeight - 1
-	if gap > 0 {
-		content += strings.Repeat("\n", gap)
+	maxLines := m.height - 4
+	if maxLines < 2 {
+		maxLines = 2
+	}
+	rows := make([]ui.StatsRow, len(m.agents))
+	for i, a := range m.agents {
+		st := a.Stats(now)
+		rows[i] = ui.StatsRow{Name: a.Name, Status: string(a.Status), Running: st.Running, FirstWait: st.FirstWait, Waited: st.Waited, Prompts: st.Prompts, Turns: st.Turns}
+	}
+	var body []string
+	if len(rows) == 0 {
+		body = append(body, ui.DimText.Render("  No agents"))
+	} else {
+		// The table's header stays put while its rows scroll
+		lines := ui.StatsLines(rows, m.width-2)
+		body = append(body, " "+lines[0])
+		lines = lines[1:]
+		end := m.statsScroll + maxLines - 1
+		if end > len(lines) {
+			end = len(lines)
+		}
+		for _, l := range lines[min(m.statsScroll, end):end] {
+			body = append(body, " "+l)
+		}
+	}
+	for len(body) < maxLines {
+		body = append(body, "")
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, content, status, footer)
+	footer := ui.HelpStyle.Render("[↑/↓/PgUp/PgDn] scroll  [Esc] dashboard  ·  tickettok stats for the same in a terminal")
+	return header + "\n" + rule + "\n" + strings.Join(body, "\n") + "\n" + rule + "\n " + footer
 }
 
-func (m Model) viewCarousel() string {
-	updateVer := ""
-	if m.updateAvailable && !m.updating {
-		updateVer = m.latestVersion
+// openArchive shows the agents that left the board, newest first.
+func (m *Model) openArchive() {
+	entries, err := readArchive(archivePath())
+	if err != nil {
+		m.setStatus(fmt.Sprintf("Archive not read: %v", err))
+		return
 	}
-	title := ui.RenderTitle(m.width, len(m.agents), 1, updateVer, m.activeWorkspace)
-	footer := ui.RenderFooter(m.width, 1, m.updateAvailable && !m.updating, m.webServer != nil)
+	m.archiveEntries = entries
+	m.archiveInput.SetValue("")
+	m.archiveInput.Blur()
+	m.archiveMatches = searchArchive(entries, "")
+	m.archiveSelected, m.archiveScroll = 0, 0
+	m.archiveTyping, m.archiveOpen = false, false
+	m.view = viewArchive
+}
 
-	var status string
-	if m.statusMsg != "" && time.Now().Before(m.statusExpires) {
-		status = ui.DimText.Render("  " + m.statusMsg)
+func (m *Model) handleArchiveKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if m.archiveTyping {
+		switch key {
+		case "esc", "enter":
+			if key == "esc" {
+				m.archiveInput.SetValue("")
+			}
+			m.archiveTyping = false
+			m.archiveInput.Blur()
+		default:
+			var cmd tea.Cmd
+			m.archiveInput, cmd = m.archiveInput.Update(msg)
+			// Narrow the list live as the user types
+			m.archiveMatches = searchArchive(m.archiveEntries, m.archiveInput.Value())
+			m.archiveSelected = 0
+			return m, cmd
+		}
+		m.archiveMatches = searchArchive(m.archiveEntries, m.archiveInput.Value())
+		m.archiveSelected = 0
+		return m, nil
 	}
 
-	titleHeight := lipgloss.Height(title) + 1
-	footerHeight := lipgloss.Height(footer)
-	if status != "" {
-		footerHeight += lipgloss.Height(status)
-	}
-	carouselHeight := m.height - titleHeight - footerHeight - 1
-	if carouselHeight < 5 {
-		carouselHeight = 5
+	page := max((m.height-6)/2, 1)
+	if m.archiveOpen {
+		switch key {
+		case "esc", "q", "enter":
+			m.archiveOpen = false
+		case "k", "up":
+			m.archiveScroll--
+		case "j", "down":
+			m.archiveScroll++
+		case "pgup":
+			m.archiveScroll -= page
+		case "pgdown":
+			m.archiveScroll += page
+		case "I":
+			m.view = viewBoard
+			if m.columns == 1 {
+				m.view = viewCarousel
+			}
+		}
+		// viewArchiveScreen keeps the scroll within the details
+		m.archiveScroll = max(m.archiveScroll, 0)
+		return m, nil
 	}
 
-	cards := m.getCards()
-	maxVisible := m.maxVisibleCards()
-	carousel := ui.RenderCarousel(cards, m.selected, m.width, m.height, m.scrollOffset, maxVisible)
+	switch key {
+	case "esc", "q", "I":
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
+	case "/":
+		m.archiveTyping = true
+		m.archiveInput.CursorEnd()
+		m.archiveInput.Focus()
+		return m, nil
+	case "enter":
+		if len(m.archiveMatches) > 0 {
+			m.archiveOpen = true
+			m.archiveScroll = 0
+		}
+		return m, nil
+	case "k", "up":
+		m.archiveSelected--
+	case "j", "down":
+		m.archiveSelected++
+	case "pgup":
+		m.archiveSelected -= page
+	case "pgdown":
+		m.archiveSelected += page
+	case "g", "home":
+		m.archiveSelected = 0
+	case "G", "end":
+		m.archiveSelected = len(m.archiveMatches) - 1
+	}
+	m.archiveSelected = max(min(m.archiveSelected, len(m.archiveMatches)-1), 0)
+	return m, nil
+}
+
+// viewArchiveScreen lists the archived agents matching the search, or the
+// selected one in full.
+func (m Model) viewArchiveScreen() string {
+	summary := fmt.Sprintf("%d archived agents", len(m.archiveEntries))
+	if q := strings.TrimSpace(m.archiveInput.Value()); q != "" {
+		summary = fmt.Sprintf("%d of %d match %q", len(m.archiveMatches), len(m.archiveEntries), q)
+	}
+	header := lipgloss.NewStyle().Bold(true).Foreground(ui.ColorAccent).Render(" ARCHIVE ") +
+		ui.HelpStyle.Render("  "+summary)
+	header = withStrip(header, m.statusStrip, m.width)
+	rule := lipgloss.NewStyle().Foreground(ui.ColorBorder).Render(strings.Repeat("─", m.width))
+
+	maxLines := m.height - 4
+	if maxLines < 2 {
+		maxLines = 2
+	}
+	var body []string
+	var footer string
+	switch {
+	case m.archiveOpen && m.archiveSelected < len(m.archiveMatches):
+		lines := archiveDetail(m.archiveEntries[m.archiveMatches[m.archiveSelected]])
+		scroll := min(m.archiveScroll, max(len(lines)-maxLines, 0))
+		for _, l := range lines[scroll:min(scroll+maxLines, len(lines))] {
+			body = append(body, " "+l)
+		}
+		footer = "[↑/↓/PgUp/PgDn] scroll  [Esc] back to the list  [I] dashboard"
+	default:
+		if m.archiveTyping {
+			body = append(body, " "+m.archiveInput.View())
+		}
+		rows := maxLines - len(body)
+		if len(m.archiveMatches) == 0 {
+			body = append(body, ui.DimText.Render("  No archived agents"))
+		}
+		// Keep the selection in view
+		start := max(m.archiveSelected-rows+1, 0)
+		for i := start; i < len(m.archiveMatches) && i < start+rows; i++ {
+			e := m.archiveEntries[m.archiveMatches[i]]
+			line := fmt.Sprintf("%s  %-20s %-8s %8s  ", e.ArchivedAt.Local().Format("2006-01-02 15:04"), e.Name, e.Status, formatStatDuration(e.Ran(), true))
+			line += archiveTask(e, m.width-4-len([]rune(line)))
+			if i == m.archiveSelected {
+				body = append(body, lipgloss.NewStyle().Foreground(ui.ColorAccent).Bold(true).Render("> "+line))
+			} else {
+				body = append(body, "  "+line)
+			}
+		}
+		footer = "[↑/↓] select  [Enter] details  [/] search  [Esc] dashboard  ·  tickettok archive for the same in a terminal"
+	}
+	for len(body) < maxLines {
+		body = append(body, "")
+	}
+	return header + "\n" + rule + "\n" + strings.Join(body, "\n") + "\n" + rule + "\n " + ui.HelpStyle.Render(footer)
+}
+
+// openSplit shows the selected agent beside the next one on the board, or
+// beside the other agents of its race to compare them.
+func (m *Model) openSplit() (tea.Model, tea.Cmd) {
+	n := len(m.agents)
+	if n == 0 || m.selected >= n {
+		return m, nil
+	}
+	agent := m.agents[m.selected]
+	m.splitIDs = nil
+	m.splitFocus = 0
+	if racers := raceMembers(m.store.List(), agent.Race); len(racers) > 1 {
+		if len(racers) > maxSplitPanes {
+			racers = racers[:maxSplitPanes]
+		}
+		for i, a := range racers {
+			m.splitIDs = append(m.splitIDs, a.ID)
+			if a.ID == agent.ID {
+				m.splitFocus = i
+			}
+		}
+	} else if n < 2 {
+		m.setStatus("Split view needs at least two agents")
+		return m, nil
+	} else {
+		m.splitIDs = []string{agent.ID, m.agents[(m.selected+1)%n].ID}
+	}
+	m.splitContent = make([]string, len(m.splitIDs))
+	m.splitScroll = make([]int, len(m.splitIDs))
+	m.view = viewSplit
+	return m, splitCaptureCmd(m.splitSessions())
+}
+
+// splitSessions returns the tmux session for each split pane's agent.
+func (m *Model) splitSessions() []string {
+	sessions := make([]string, len(m.splitIDs))
+	for i, id := range m.splitIDs {
+		if a := m.store.Get(id); a != nil {
+			sessions[i] = a.SessionName
+		}
+	}
+	return sessions
+}
+
+// splitCaptureCmd captures both split panes with scrollback, like
+// zoomCaptureCmd but at a gentler rate since nothing is being typed.
+func splitCaptureCmd(sessions []string) tea.Cmd {
+	return func() tea.Msg {
+		time.Sleep(250 * time.Millisecond)
+		msg := splitTickMsg{content: make([]string, len(sessions))}
+		for i, s := range sessions {
+			if s == "" {
+				continue
+			}
+			out, err := exec.Command("tmux", "capture-pane", "-p", "-e", "-J", "-S", "-10000", "-t", s).Output()
+			if err != nil {
+				msg.content[i] = fmt.Sprintf("capture error: %v", err)
+				continue
+			}
+			msg.content[i] = string(out)
+		}
+		return msg
+	}
+}
+
+// scrollSplit moves a pane's scroll offset by delta lines (positive = up).
+func (m *Model) scrollSplit(pane, delta int) {
+	off := m.splitScroll[pane] + delta
+	maxScroll := strings.Count(m.splitContent[pane], "\n") + 1 - (m.height - 4)
+	if off > maxScroll {
+		off = maxScroll
+	}
+	if off < 0 {
+		off = 0
+	}
+	m.splitScroll[pane] = off
+}
+
+// cycleSplit swaps the focused pane to the previous/next agent on the board,
+// skipping the agents shown in the other panes.
+func (m *Model) cycleSplit(delta int) tea.Cmd {
+	n := len(m.agents)
+	if n <= len(m.splitIDs) {
+		return nil
+	}
+	cur := 0
+	for i, a := range m.agents {
+		if a.ID == m.splitIDs[m.splitFocus] {
+			cur = i
+		}
+	}
+	shown := make(map[string]bool)
+	for _, id := range m.splitIDs {
+		shown[id] = true
+	}
+	next := (cur + delta + n) % n
+	for shown[m.agents[next].ID] {
+		next = (next + delta + n) % n
+	}
+	m.splitIDs[m.splitFocus] = m.agents[next].ID
+	m.splitContent[m.splitFocus] = ""
+	m.splitScroll[m.splitFocus] = 0
+	return nil
+}
+
+func (m *Model) handleSplitKey(key string) (tea.Model, tea.Cmd) {
+	half := (m.height - 4) / 2
+	if half < 1 {
+		half = 1
+	}
+	switch key {
+	case "esc", "ctrl+q", "|", "q":
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		m.splitContent = make([]string, len(m.splitIDs))
+	case "tab":
+		m.splitFocus = (m.splitFocus + 1) % len(m.splitIDs)
+	case "k", "up":
+		m.scrollSplit(m.splitFocus, 1)
+	case "j", "down":
+		m.scrollSplit(m.splitFocus, -1)
+	case "pgup":
+		m.scrollSplit(m.splitFocus, half)
+	case "pgdown":
+		m.scrollSplit(m.splitFocus, -half)
+	case "G", "end":
+		m.splitScroll[m.splitFocus] = 0
+	case "h", "left":
+		m.cycleSplit(-1)
+	case "l", "right":
+		m.cycleSplit(+1)
+	case "enter":
+		for i, a := range m.agents {
+			if a.ID == m.splitIDs[m.splitFocus] {
+				m.selected = i
+				m.splitContent = make([]string, len(m.splitIDs))
+				return m.enterZoom()
+			}
+		}
+	case "p":
+		m.pickRacer(m.splitIDs[m.splitFocus])
+	}
+	return m, nil
+}
+
+func (m Model) viewSplit() string {
+	paneWidth := (m.width - len(m.splitIDs) + 1) / len(m.splitIDs)
+	bodyLines := m.height - 4 // pane header + rule, footer rule + keys
+	if bodyLines < 1 {
+		bodyLines = 1
+	}
+
+	panes := make([]string, 0, 2*len(m.splitIDs)-1)
+	divider := lipgloss.NewStyle().Foreground(ui.ColorBorder).
+		Render(strings.TrimSuffix(strings.Repeat("│\n", m.height-2), "\n"))
+	for i, id := range m.splitIDs {
+		name, status := id, ""
+		if a := m.store.Get(id); a != nil {
+			name, status = a.Name, string(a.Status)
+		}
+		if i > 0 {
+			panes = append(panes, divider)
+		}
+
+		titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ui.ColorDim)
+		ruleColor := ui.ColorBorder
+		if i == m.splitFocus {
+			titleStyle = titleStyle.Foreground(ui.ColorAccent)
+			ruleColor = ui.ColorAccent
+		}
+		header := titleStyle.Render(" "+name+" ") + " " + ui.StatusDot(status)
+		if m.splitScroll[i] > 0 {
+			header += ui.HelpStyle.Render(fmt.Sprintf("  [+%d]", m.splitScroll[i]))
+		}
+		rule := lipgloss.NewStyle().Foreground(ruleColor).Render(strings.Repeat("─", paneWidth))
+
+		body := strings.Join(scrollWindow(m.splitContent[i], m.splitScroll[i], bodyLines), "\n")
+		body = lipgloss.NewStyle().MaxWidth(paneWidth).Render(body)
+
+		panes = append(panes, lipgloss.NewStyle().Width(paneWidth).Render(header+"\n"+rule+"\n"+body))
+	}
+	content := lipgloss.JoinHorizontal(lipgloss.Top, panes...)
+
+	rule := lipgloss.NewStyle().Foreground(ui.ColorBorder).Render(strings.Repeat("─", m.width))
+	footer := rule + "\n" + withStrip(" "+ui.HelpStyle.Render(ui.FooterKeys(ui.SplitKeys, false)), m.statusStrip, m.width)
+	return clipHeight(content, m.height-2) + "\n" + footer
+}
+
+func (m Model) viewBoard() string {
+	updateVer := ""
+	if m.updateAvailable && !m.updating {
+		updateVer = m.latestVersion
+	}
+	title := ui.RenderTitle(m.width, len(m.agents), m.columns, updateVer, m.titleLabel(), m.totalUsage(), m.totalResources())
+	footer := ui.RenderFooter(m.width, m.columns, m.updateAvailable && !m.updating, m.webServer != nil)
+
+	var status string
+	if m.statusMsg != "" && time.Now().Before(m.statusExpires) {
+		status = ui.DimText.Render("  " + m.statusMsg)
+	}
+	if bar := m.filterBar(); bar != "" {
+		status = strings.TrimPrefix(status+"\n"+bar, "\n")
+	}
+
+	titleHeight := lipgloss.Height(title) + 1 // +1 for blank line
+	footerHeight := lipgloss.Height(footer)
+	if status != "" {
+		footerHeight += lipgloss.Height(status)
+	}
+	boardHeight := m.height - titleHeight - footerHeight - 1
+	if boardHeight < 5 {
+		boardHeight = 5
+	}
+
+	cards := m.getCards()
+	maxVisible := m.maxVisibleCards()
+	boardWidth := m.width
+	showDetail := m.showDetail && m.selected < len(m.agents)
+	if showDetail {
+		boardWidth = m.width - ui.DetailWidth(m.width) - 1
+	}
+	board := ui.RenderBoard(cards, m.layout(), m.selected, boardWidth, boardHeight, m.scrollOffset, maxVisible, m.sortMode)
+
+	// Safety clip: trim any overflow without scroll math
+	board = clipHeight(board, boardHeight)
+	if showDetail {
+		panel := ui.RenderDetail(m.detailData(cards), ui.DetailWidth(m.width), boardHeight)
+		board = lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(boardWidth).Render(board), " ", clipHeight(panel, boardHeight))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, title, m.statusStrip(m.width), board)
+
+	contentHeight := lipgloss.Height(content)
+	gap := m.height - contentHeight - footerHeight - 1
+	if gap > 0 {
+		content += strings.Repeat("\n", gap)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, content, status, footer)
+}
+
+func (m Model) viewCarousel() string {
+	updateVer := ""
+	if m.updateAvailable && !m.updating {
+		updateVer = m.latestVersion
+	}
+	mode := 1
+	if m.compact {
+		mode = 4
+	}
+	title := ui.RenderTitle(m.width, len(m.agents), mode, updateVer, m.titleLabel(), m.totalUsage(), m.totalResources())
+	footer := ui.RenderFooter(m.width, 1, m.updateAvailable && !m.updating, m.webServer != nil)
+
+	var status string
+	if m.statusMsg != "" && time.Now().Before(m.statusExpires) {
+		status = ui.DimText.Render("  " + m.statusMsg)
+	}
+	if bar := m.filterBar(); bar != "" {
+		status = strings.TrimPrefix(status+"\n"+bar, "\n")
+	}
+
+	titleHeight := lipgloss.Height(title) + 1
+	footerHeight := lipgloss.Height(footer)
+	if status != "" {
+		footerHeight += lipgloss.Height(status)
+	}
+	carouselHeight := m.height - titleHeight - footerHeight - 1
+	if carouselHeight < 5 {
+		carouselHeight = 5
+	}
+
+	cards := m.getCards()
+	maxVisible := m.maxVisibleCards()
+	var carousel string
+	if m.grouped {
+		carousel = ui.RenderGrouped(cards, m.groups, m.selected, m.width, m.scrollOffset, maxVisible, m.compact)
+	} else if m.compact {
+		carousel = ui.RenderList(cards, m.selected, m.width, m.scrollOffset, maxVisible)
+	} else {
+		carousel = ui.RenderCarousel(cards, m.selected, m.width, m.height, m.scrollOffset, maxVisible)
+	}
+
+	// Safety clip: trim any overflow without scroll math
+	carousel = clipHeight(carousel, carouselHeight)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, title, m.statusStrip(m.width), carousel)
+
+	contentHeight := lipgloss.Height(content)
+	gap := m.height - contentHeight - footerHeight - 1
+	if gap > 0 {
+		content += strings.Repeat("\n", gap)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, content, status, footer)
+}
+
+func (m Model) viewSpawn() string {
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorAccent).
+		Padding(1, 2).
+		Width(70)
+
+	title := ui.AgentName.Render("Spawn New Agent")
+	if m.spawnCloneOf != "" {
+		title = ui.AgentName.Render("Clone " + m.spawnCloneOf)
+	}
+
+	// Render backend selector (vertical radio-style list)
+	var backendLines []string
+	if len(m.spawnBackends) > 1 {
+		backendLines = append(backendLines, "Backend:")
+		for i, b := range m.spawnBackends {
+			indicator := "○"
+			style := lipgloss.NewStyle().Foreground(ui.ColorDim)
+			if i == m.spawnBackendIdx {
+				indicator = "●"
+				style = lipgloss.NewStyle().Foreground(ui.ColorAccent).Bold(true)
+			}
+			prefix := "  "
+			if m.spawnFocus == focusBackend && i == m.spawnBackendIdx {
+				prefix = "> "
+			}
+			backendLines = append(backendLines, style.Render(prefix+indicator+" "+b.Name()))
+		}
+	} else if len(m.spawnBackends) == 1 {
+		backendLines = append(backendLines, "Backend:  "+m.spawnBackends[0].Name())
+	}
+
+	fields := lipgloss.JoinVertical(lipgloss.Left,
+		"Directory:", m.spawnDir.View(),
+	)
+
+	// Render suggestion list (max 8 visible)
+	maxShow := 8
+	if len(m.spawnSuggestions) < maxShow {
+		maxShow = len(m.spawnSuggestions)
+	}
+	var suggLines []string
+	for i := 0; i < maxShow; i++ {
+		name := filepath.Base(m.spawnSuggestions[i])
+		if i < m.spawnQuick {
+			// Quick picks show the full path, starred when a favorite
+			mark := "↺ "
+			if m.store.IsFavoriteDir(expandTilde(m.spawnSuggestions[i])) {
+				mark = "★ "
+			}
+			name = mark + m.spawnSuggestions[i]
+		}
+		if i == m.spawnSelIdx {
+			suggLines = append(suggLines, lipgloss.NewStyle().
+				Foreground(ui.ColorAccent).Bold(true).
+				Render("  > "+name))
+		} else {
+			suggLines = append(suggLines, lipgloss.NewStyle().
+				Foreground(ui.ColorDim).
+				Render("    "+name))
+		}
+	}
+	suggestions := strings.Join(suggLines, "\n")
+
+	help := ui.HelpStyle.Render("[Enter] select/spawn  [↑/↓/Tab] navigate  [←/→] choose  [*] star dir  [Ctrl+J] newline  [Esc] cancel")
+
+	var parts []string
+	parts = append(parts, title, "")
+	if len(backendLines) > 0 {
+		parts = append(parts, backendLines...)
+		parts = append(parts, "")
+	}
+	parts = append(parts, fields)
+	if suggestions != "" {
+		parts = append(parts, suggestions)
+	}
+	parts = append(parts, "", "Prompt:", m.spawnPrompt.View())
+
+	// Auto-approve toggle (only shown if backend supports it)
+	if m.spawnSelectedBackendSupportsAutoApprove() {
+		checkmark := "\u2610" // ☐
+		if m.spawnAutoApprove {
+			checkmark = "\u2611" // ☑
+		}
+		approveStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+		approvePrefix := "  "
+		if m.spawnFocus == focusApprove {
+			approveStyle = lipgloss.NewStyle().Foreground(ui.ColorAccent).Bold(true)
+			approvePrefix = "> "
+		}
+		approveLine := approveStyle.Render(approvePrefix + checkmark + " Auto-approve (skip permissions)")
+		parts = append(parts, "", approveLine)
+	}
+
+	branchStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+	branchPrefix := "  "
+	if m.spawnFocus == focusBranch {
+		branchStyle = lipgloss.NewStyle().Foreground(ui.ColorAccent).Bold(true)
+		branchPrefix = "> "
+	}
+	parts = append(parts, "", branchStyle.Render(branchPrefix+"Git: ◂ "+m.spawnBranch.Label()+" ▸"))
+
+	// Dependency picker (only shown when there's an agent to wait for)
+	if len(m.spawnAfter) > 0 {
+		after := "none, send right away"
+		if m.spawnAfterIdx >= 0 {
+			after = "when " + m.spawnAfter[m.spawnAfterIdx].Name + " finishes"
+		}
+		afterStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+		afterPrefix := "  "
+		if m.spawnFocus == focusAfter {
+			afterStyle = lipgloss.NewStyle().Foreground(ui.ColorAccent).Bold(true)
+			afterPrefix = "> "
+		}
+		parts = append(parts, "", afterStyle.Render(afterPrefix+"Send prompt: ◂ "+after+" ▸"))
+	}
+
+	parts = append(parts, "", help)
+
+	content := lipgloss.JoinVertical(lipgloss.Left, parts...)
+
+	rendered := dialog.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
+}
+
+func (m Model) viewSend() string {
+	if m.broadcastTo == nil && m.selected >= len(m.agents) {
+		return ""
+	}
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorAccent).
+		Padding(1, 2).
+		Width(70)
+
+	var title string
+	if m.broadcastTo != nil {
+		names := make([]string, len(m.broadcastTo))
+		for i, a := range m.broadcastTo {
+			names[i] = a.Name
+		}
+		list := strings.Join(names, ", ")
+		if r := []rune(list); len(r) > 50 {
+			list = string(r[:49]) + "…"
+		}
+		title = ui.AgentName.Render(fmt.Sprintf("Send to %d agents: %s", len(names), list))
+	} else {
+		title = ui.AgentName.Render(fmt.Sprintf("Send to: %s", m.agents[m.selected].Name))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		title, "",
+		"Message:", m.sendInput.View(), "",
+		ui.HelpStyle.Render("[Enter] send  [Esc] cancel"),
+	)
+
+	rendered := dialog.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
+}
+
+func (m Model) viewRename() string {
+	if m.selected >= len(m.agents) {
+		return ""
+	}
+	agent := m.agents[m.selected]
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorAccent).
+		Padding(1, 2).
+		Width(60)
+
+	title := ui.AgentName.Render(fmt.Sprintf("Rename: %s", agent.Name))
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		title, "",
+		"New name:", m.renameInput.View(), "",
+		ui.HelpStyle.Render("[Enter] rename  [Esc] cancel"),
+	)
+
+	rendered := dialog.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
+}
+
+func (m Model) viewTimeoutDialog() string {
+	if m.selected >= len(m.agents) {
+		return ""
+	}
+	agent := m.agents[m.selected]
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorAccent).
+		Padding(1, 2).
+		Width(60)
+
+	title := ui.AgentName.Render(fmt.Sprintf("Timeout: %s", agent.Name))
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		title, "",
+		"Max runtime:", m.timeoutInput.View(), "",
+		ui.DimText.Render(fmt.Sprintf("Running for %s. Past the limit it goes TIMED-OUT (on_timeout: %s).",
+			formatTimeout(time.Since(runStart(agent)).Truncate(time.Minute)), m.timeoutAction)), "",
+		ui.HelpStyle.Render("[Enter] set  [Esc] cancel"),
+	)
+
+	rendered := dialog.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
+}
+
+func (m Model) viewNudgeDialog() string {
+	if m.selected >= len(m.agents) {
+		return ""
+	}
+	agent := m.agents[m.selected]
 
-	// Safety clip: trim any overflow without scroll math
-	carousel = clipHeight(carousel, carouselHeight)
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorAccent).
+		Padding(1, 2).
+		Width(76)
 
-	content := lipgloss.JoinVertical(lipgloss.Left, title, "", carousel)
+	title := ui.AgentName.Render(fmt.Sprintf("Nudge: %s", agent.Name))
 
-	contentHeight := lipgloss.Height(content)
-	gap := m.height - contentHeight - footerHeight - 1
-	if gap > 0 {
-		content += strings.Repeat("\n", gap)
-	}
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		title, "",
+		"Rule ([<count>x] [<message>] [until <marker>]):", m.nudgeInput.View(), "",
+		ui.DimText.Render("When the agent goes IDLE with work left, the message is sent to it,"),
+		ui.DimText.Render("up to count times (3 by default). Work is left until its last message"),
+		ui.DimText.Render("contains the marker, or with no marker, until its task list is done."), "",
+		ui.HelpStyle.Render("[Enter] set  [Esc] cancel"),
+	)
 
-	return lipgloss.JoinVertical(lipgloss.Left, content, status, footer)
+	rendered := dialog.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
 }
 
-func (m Model) viewSpawn() string {
+func (m Model) viewRulesDialog() string {
+	if m.selected >= len(m.agents) {
+		return ""
+	}
+	agent := m.agents[m.selected]
+
 	dialog := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(ui.ColorAccent).
 		Padding(1, 2).
-		Width(70)
+		Width(76)
 
-	title := ui.AgentName.Render("Spawn New Agent")
+	title := ui.AgentName.Render(fmt.Sprintf("Approval rules: %s", agent.Name))
 
-	// Render backend selector (vertical radio-style list)
-	var backendLines []string
-	if len(m.spawnBackends) > 1 {
-		backendLines = append(backendLines, "Backend:")
-		for i, b := range m.spawnBackends {
-			indicator := "○"
-			style := lipgloss.NewStyle().Foreground(ui.ColorDim)
-			if i == m.spawnBackendIdx {
-				indicator = "●"
-				style = lipgloss.NewStyle().Foreground(ui.ColorAccent).Bold(true)
-			}
-			prefix := "  "
-			if m.spawnFocus == focusBackend && i == m.spawnBackendIdx {
-				prefix = "> "
-			}
-			backendLines = append(backendLines, style.Render(prefix+indicator+" "+b.Name()))
+	global := "none"
+	if len(m.approvalRules) > 0 {
+		var names []string
+		for _, r := range m.approvalRules {
+			names = append(names, r.String())
 		}
-	} else if len(m.spawnBackends) == 1 {
-		backendLines = append(backendLines, "Backend:  "+m.spawnBackends[0].Name())
+		global = strings.Join(names, "; ")
 	}
 
-	fields := lipgloss.JoinVertical(lipgloss.Left,
-		"Directory:", m.spawnDir.View(),
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		title, "",
+		"Rules (approve|deny|ask <tool|*> [words]), separated by ;", m.rulesInput.View(), "",
+		ui.DimText.Render("Checked before the global rules: "+global), "",
+		ui.HelpStyle.Render("[Enter] save  [Esc] cancel"),
 	)
 
-	// Render suggestion list (max 8 visible)
-	maxShow := 8
-	if len(m.spawnSuggestions) < maxShow {
-		maxShow = len(m.spawnSuggestions)
-	}
-	var suggLines []string
-	for i := 0; i < maxShow; i++ {
-		name := filepath.Base(m.spawnSuggestions[i])
-		if i == m.spawnSelIdx {
-			suggLines = append(suggLines, lipgloss.NewStyle().
-				Foreground(ui.ColorAccent).Bold(true).
-				Render("  > "+name))
-		} else {
-			suggLines = append(suggLines, lipgloss.NewStyle().
-				Foreground(ui.ColorDim).
-				Render("    "+name))
-		}
-	}
-	suggestions := strings.Join(suggLines, "\n")
-
-	help := ui.HelpStyle.Render("[Enter] select/spawn  [↑/↓] navigate  [Esc] cancel")
+	rendered := dialog.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
+}
 
-	var parts []string
-	parts = append(parts, title, "")
-	if len(backendLines) > 0 {
-		parts = append(parts, backendLines...)
-		parts = append(parts, "")
-	}
-	parts = append(parts, fields)
-	if suggestions != "" {
-		parts = append(parts, suggestions)
+func (m Model) viewTags() string {
+	if m.selected >= len(m.agents) {
+		return ""
 	}
+	agent := m.agents[m.selected]
 
-	// Auto-approve toggle (only shown if backend supports it)
-	if m.spawnSelectedBackendSupportsAutoApprove() {
-		checkmark := "\u2610" // ☐
-		if m.spawnAutoApprove {
-			checkmark = "\u2611" // ☑
-		}
-		approveStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
-		approvePrefix := "  "
-		if m.spawnFocus == focusApprove {
-			approveStyle = lipgloss.NewStyle().Foreground(ui.ColorAccent).Bold(true)
-			approvePrefix = "> "
-		}
-		approveLine := approveStyle.Render(approvePrefix + checkmark + " Auto-approve (skip permissions)")
-		parts = append(parts, "", approveLine)
-	}
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorAccent).
+		Padding(1, 2).
+		Width(70)
 
-	parts = append(parts, "", help)
+	title := ui.AgentName.Render(fmt.Sprintf("Tags: %s", agent.Name))
 
-	content := lipgloss.JoinVertical(lipgloss.Left, parts...)
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		title, "",
+		"Tags:", m.tagInput.View(), "",
+		ui.HelpStyle.Render("[Enter] save  [Esc] cancel  (filter by tag with /#tag)"),
+	)
 
 	rendered := dialog.Render(content)
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
 }
 
-func (m Model) viewSend() string {
+func (m Model) viewNoteDialog() string {
 	if m.selected >= len(m.agents) {
 		return ""
 	}
@@ -1822,12 +5382,12 @@ func (m Model) viewSend() string {
 		Padding(1, 2).
 		Width(70)
 
-	title := ui.AgentName.Render(fmt.Sprintf("Send to: %s", agent.Name))
+	title := ui.AgentName.Render(fmt.Sprintf("Note: %s", agent.Name))
 
 	content := lipgloss.JoinVertical(lipgloss.Left,
 		title, "",
-		"Message:", m.sendInput.View(), "",
-		ui.HelpStyle.Render("[Enter] send  [Esc] cancel"),
+		m.noteInput.View(), "",
+		ui.HelpStyle.Render("[Enter] save  [Ctrl+J] newline  [Esc] cancel  (empty clears)"),
 	)
 
 	rendered := dialog.Render(content)
@@ -1877,7 +5437,7 @@ func (m Model) viewConfirmAutoApprove() string {
 
 	dialog := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#FBBF24")).
+		BorderForeground(ui.ColorWarn).
 		Padding(1, 2).
 		Width(55)
 
@@ -1912,7 +5472,7 @@ func (m *Model) openBatchDialog() {
 	for _, a := range m.agents {
 		totalCount++
 		switch a.Status {
-		case StatusDone:
+		case StatusDone, StatusError:
 			doneCount++
 		case StatusWaiting:
 			waitingCount++
@@ -1922,12 +5482,16 @@ func (m *Model) openBatchDialog() {
 	if doneCount > 0 {
 		opts = append(opts, batchOption{
 			key:   fmt.Sprintf("%d", keyNum),
-			label: fmt.Sprintf("Kill all DONE agents (%d)", doneCount),
+			label: fmt.Sprintf("Kill all DONE and ERROR agents (%d)", doneCount),
 			count: doneCount,
 			action: func(m *Model) {
-				n := m.store.ClearDone()
-				m.agents = m.store.List()
-				m.setStatus(fmt.Sprintf("Killed %d DONE agents", n))
+				cleared := m.store.ClearDoneBefore(time.Time{})
+				m.archiveAll(cleared, "cleared")
+				m.archiveRemoved(cleared, "cleared")
+				kept := removeWorktrees(cleared)
+				m.rememberUndo(cleared, false)
+				m.refreshAgents()
+				m.setStatus(fmt.Sprintf("Killed %d DONE and ERROR agents%s%s", len(cleared), worktreeNote(kept), undoHint(len(cleared))))
 				if m.selected >= len(m.agents) && len(m.agents) > 0 {
 					m.selected = len(m.agents) - 1
 				}
@@ -1942,22 +5506,42 @@ func (m *Model) openBatchDialog() {
 			label: fmt.Sprintf("Kill all agents (%d)", totalCount),
 			count: totalCount,
 			action: func(m *Model) {
-				for _, a := range m.store.List() {
-					sess := m.manager.GetSession(a)
-					if sess != nil {
-						_ = m.manager.Kill(a.ID)
-					} else if a.SessionName != "" {
-						_ = KillBySession(a.SessionName)
-					}
-					a.Backend().CleanHookStatus(a.ID)
-					m.store.Remove(a.ID)
-				}
-				m.agents = m.store.List()
+				kept := m.killAgents(m.store.List(), "kill all")
 				m.selected = 0
-				m.setStatus(fmt.Sprintf("Killed all %d agents", totalCount))
+				m.setStatus(fmt.Sprintf("Killed all %d agents%s%s", totalCount, worktreeNote(kept), undoHint(totalCount)))
+			},
+		})
+		keyNum++
+	}
+
+	// Group actions for the selected agent's swarm
+	if m.selected < len(m.agents) && m.agents[m.selected].Swarm != "" {
+		swarm := m.agents[m.selected].Swarm
+		members := swarmMembers(m.store.List(), swarm)
+		opts = append(opts, batchOption{
+			key:   fmt.Sprintf("%d", keyNum),
+			label: fmt.Sprintf("Kill swarm %s (%d)", swarm, len(members)),
+			count: len(members),
+			action: func(m *Model) {
+				kept := m.killAgents(members, "swarm "+swarm)
+				if m.selected >= len(m.agents) {
+					m.selected = max(len(m.agents)-1, 0)
+				}
+				m.setStatus(fmt.Sprintf("Killed swarm %s (%d agents)%s%s", swarm, len(members), worktreeNote(kept), undoHint(len(members))))
 			},
 		})
 		keyNum++
+		if live := broadcastTargets(members); len(live) > 0 {
+			opts = append(opts, batchOption{
+				key:   fmt.Sprintf("%d", keyNum),
+				label: fmt.Sprintf("Send a message to swarm %s (%d)", swarm, len(live)),
+				count: len(live),
+				action: func(m *Model) {
+					m.openBroadcastDialog(live)
+				},
+			})
+			keyNum++
+		}
 	}
 
 	if waitingCount > 0 {
@@ -1976,6 +5560,22 @@ func (m *Model) openBatchDialog() {
 				m.setStatus(fmt.Sprintf("Sent \"y\" to %d WAITING agents", sent))
 			},
 		})
+		keyNum++
+	}
+
+	if live := broadcastTargets(m.agents); len(live) > 0 {
+		label := fmt.Sprintf("Send a message to all live agents (%d)", len(live))
+		if m.filter != "" {
+			label = fmt.Sprintf("Send a message to the live agents shown (%d)", len(live))
+		}
+		opts = append(opts, batchOption{
+			key:   fmt.Sprintf("%d", keyNum),
+			label: label,
+			count: len(live),
+			action: func(m *Model) {
+				m.openBroadcastDialog(live)
+			},
+		})
 	}
 
 	if len(opts) == 0 {
@@ -1986,6 +5586,30 @@ func (m *Model) openBatchDialog() {
 	m.view = viewBatch
 }
 
+// killAgents kills agents and takes them off the board, undoably, logging
+// each kill with note. It returns why worktrees with uncommitted changes
+// were kept.
+func (m *Model) killAgents(killed []*Agent, note string) []error {
+	m.archiveAll(killed, "killed")
+	m.archiveRemoved(killed, "killed")
+	for _, a := range killed {
+		sess := m.manager.GetSession(a)
+		if sess != nil {
+			_ = m.manager.Kill(a.ID)
+		} else if a.SessionName != "" {
+			_ = KillBySession(a.SessionName)
+		}
+		a.Backend().CleanHookStatus(a.ID)
+		m.store.Remove(a.ID)
+		m.events.Add(EventKill, a.Name, note)
+		m.manager.fireLifecycle(LifecycleKill, a)
+	}
+	kept := removeWorktrees(killed)
+	m.rememberUndo(killed, true)
+	m.refreshAgents()
+	return kept
+}
+
 func (m *Model) handleBatchKey(key string) (tea.Model, tea.Cmd) {
 	returnView := viewBoard
 	if m.columns == 1 {
@@ -1999,14 +5623,200 @@ func (m *Model) handleBatchKey(key string) (tea.Model, tea.Cmd) {
 
 	for _, opt := range m.batchOptions {
 		if key == opt.key {
-			opt.action(m)
+			// Actions can open a dialog of their own
 			m.view = returnView
+			opt.action(m)
 			return m, nil
 		}
 	}
 
-	m.view = returnView
-	return m, nil
+	m.view = returnView
+	return m, nil
+}
+
+// openPinDialog offers the current layout's columns as pin targets.
+func (m *Model) openPinDialog() {
+	if len(m.agents) == 0 || m.selected >= len(m.agents) || m.columns == 1 {
+		return
+	}
+	m.view = viewPin
+}
+
+func (m *Model) handlePinKey(key string) (tea.Model, tea.Cmd) {
+	m.view = viewBoard
+	if m.selected >= len(m.agents) {
+		return m, nil
+	}
+	agent := m.agents[m.selected]
+	layout := m.layout()
+
+	var pin string
+	switch {
+	case key == "0" || key == "u":
+		pin = ""
+	case len(key) == 1 && key[0] >= '1' && int(key[0]-'0') <= len(layout):
+		pin = layout[key[0]-'1'].Title
+	default:
+		return m, nil
+	}
+
+	m.store.SetPin(agent.ID, pin)
+	m.refreshAgents()
+	m.cachedCards = m.buildCardData()
+	if pin == "" {
+		m.setStatus(fmt.Sprintf("Unpinned %s", agent.Name))
+	} else {
+		m.setStatus(fmt.Sprintf("Pinned %s to %s", agent.Name, pin))
+	}
+	// Follow the agent to its new column
+	for i, a := range m.agents {
+		if a.ID == agent.ID {
+			m.selected = i
+		}
+	}
+	m.ensureSelectedVisible()
+	return m, nil
+}
+
+func (m Model) viewPinDialog() string {
+	if m.selected >= len(m.agents) {
+		return ""
+	}
+	agent := m.agents[m.selected]
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorAccent).
+		Padding(1, 2).
+		Width(50)
+
+	lines := []string{
+		ui.AgentName.Render(fmt.Sprintf("Pin: %s", agent.Name)),
+		"",
+	}
+	for i, c := range m.layout() {
+		label := c.Title
+		if strings.EqualFold(agent.Pin, c.Title) {
+			label += " (current)"
+		}
+		lines = append(lines, fmt.Sprintf("  [%d] %s", i+1, label))
+	}
+	if agent.Pin != "" {
+		lines = append(lines, "  [0] Unpin (follow status)")
+	}
+	lines = append(lines, "", ui.HelpStyle.Render("[Esc] Cancel"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	rendered := dialog.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
+}
+
+// --- Answering permission prompts from the board ---
+
+// waitingAgent returns the selected agent if it is waiting for input, or
+// sets a status message and returns nil.
+func (m *Model) waitingAgent() *Agent {
+	if m.selected >= len(m.agents) {
+		return nil
+	}
+	agent := m.agents[m.selected]
+	if agent.Status != StatusWaiting {
+		m.setStatus(fmt.Sprintf("%s isn't waiting for input", agent.Name))
+		return nil
+	}
+	return agent
+}
+
+// approvePrompt answers the selected agent's permission prompt with its
+// first option ("Yes" / "Allow once") without zooming in.
+func (m *Model) approvePrompt() {
+	agent := m.waitingAgent()
+	if agent == nil {
+		return
+	}
+	// Pick the first option explicitly; Enter takes whatever is highlighted
+	key, label := "Enter", "Approved"
+	if content, err := CapturePanePlain(agent.SessionName); err == nil {
+		if opts := parsePromptOptions(content); len(opts) > 0 {
+			key = opts[0].Key
+			label += " (" + opts[0].Label + ")"
+		}
+	}
+	if err := SendKeyNames(agent.SessionName, key); err != nil {
+		m.setStatus(fmt.Sprintf("Answer failed: %v", err))
+		return
+	}
+	m.setStatus(fmt.Sprintf("%s %s", label, agent.Name))
+}
+
+// openApproveDialog lists the options detected in the selected agent's
+// permission prompt.
+func (m *Model) openApproveDialog() {
+	agent := m.waitingAgent()
+	if agent == nil {
+		return
+	}
+	content, err := CapturePanePlain(agent.SessionName)
+	if err != nil {
+		m.setStatus(fmt.Sprintf("Capture failed: %v", err))
+		return
+	}
+	m.approveOptions = parsePromptOptions(content)
+	if len(m.approveOptions) == 0 {
+		m.setStatus(fmt.Sprintf("No prompt options found for %s — press y or zoom in", agent.Name))
+		return
+	}
+	m.approveID = agent.ID
+	m.view = viewApprove
+}
+
+func (m *Model) handleApproveKey(key string) (tea.Model, tea.Cmd) {
+	m.view = viewBoard
+	if m.columns == 1 {
+		m.view = viewCarousel
+	}
+	agent := m.store.Get(m.approveID)
+	if agent == nil {
+		return m, nil
+	}
+	for _, o := range m.approveOptions {
+		if key != o.Key {
+			continue
+		}
+		if err := SendKeyNames(agent.SessionName, o.Key); err != nil {
+			m.setStatus(fmt.Sprintf("Answer failed: %v", err))
+		} else {
+			m.setStatus(fmt.Sprintf("%s: %s", agent.Name, o.Label))
+		}
+		break
+	}
+	return m, nil
+}
+
+func (m Model) viewApproveDialog() string {
+	agent := m.store.Get(m.approveID)
+	if agent == nil {
+		return ""
+	}
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorWaiting).
+		Padding(1, 2).
+		Width(60)
+
+	lines := []string{
+		ui.AgentName.Render(fmt.Sprintf("Answer: %s", agent.Name)),
+		"",
+	}
+	for _, o := range m.approveOptions {
+		lines = append(lines, fmt.Sprintf("  [%s] %s", o.Key, o.Label))
+	}
+	lines = append(lines, "", ui.HelpStyle.Render("[Esc] Cancel"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	rendered := dialog.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
 }
 
 func (m Model) viewBatchDialog() string {
@@ -2030,18 +5840,20 @@ func (m Model) viewBatchDialog() string {
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
 }
 
-// restartStuckAgent restarts a STUCK agent by killing and respawning it.
+// restartStuckAgent restarts a STUCK or ERROR agent by killing and
+// respawning it.
 func (m *Model) restartStuckAgent() (tea.Model, tea.Cmd) {
 	if len(m.agents) == 0 || m.selected >= len(m.agents) {
 		return m, nil
 	}
 	agent := m.agents[m.selected]
-	if agent.Status != StatusError {
-		m.setStatus("Only STUCK agents can be restarted (use R)")
+	if agent.Status != StatusStuck && agent.Status != StatusError {
+		m.setStatus("Only STUCK or ERROR agents can be restarted (use r)")
 		return m, nil
 	}
 
 	// Kill and respawn
+	m.archiveAll([]*Agent{agent}, "restarted")
 	_ = m.manager.Kill(agent.ID)
 	if agent.SessionName != "" {
 		_ = KillBySession(agent.SessionName)
@@ -2054,11 +5866,355 @@ func (m *Model) restartStuckAgent() (tea.Model, tea.Cmd) {
 	}
 	m.store.UpdateSessionName(agent.ID, agent.SessionName)
 	m.store.Update(agent.ID, StatusRunning)
-	m.agents = m.store.List()
-	m.setStatus(fmt.Sprintf("Restarted: %s", agent.Name))
+	m.refreshAgents()
+	m.setStatus(fmt.Sprintf("Restarted: %s%s", agent.Name, noResumeNote(agent)))
+	return m, nil
+}
+
+// noResumeNote warns that a respawned agent starts a new conversation
+// because its backend can't resume one.
+func noResumeNote(agent *Agent) string {
+	b := agent.Backend()
+	if b.Capabilities().SupportsResume {
+		return ""
+	}
+	return fmt.Sprintf(" (%s can't resume — new conversation)", b.Name())
+}
+
+// retrySelected starts the selected agent's task over in a fresh session:
+// the original prompt goes to a new conversation instead of resuming the
+// old one. Dir, backend and settings stay, and the card counts the attempt.
+func (m *Model) retrySelected() {
+	if m.selected >= len(m.agents) {
+		return
+	}
+	agent := m.agents[m.selected]
+	if !retryable(agent) {
+		m.setStatus(fmt.Sprintf("%s is %s; only agents that have stopped can be retried", agent.Name, agent.Status))
+		return
+	}
+
+	m.archiveAll([]*Agent{agent}, "retried")
+	if sess := m.manager.GetSession(agent); sess != nil {
+		_ = m.manager.Kill(agent.ID)
+	} else if agent.SessionName != "" {
+		_ = KillBySession(agent.SessionName)
+	}
+	agent.Backend().CleanHookStatus(agent.ID)
+	if agent.Worktree != nil {
+		if err := restoreWorktree(agent.Worktree, agent.Branch); err != nil {
+			m.setStatus(fmt.Sprintf("Retry failed: %v", err))
+			return
+		}
+	}
+	n := m.store.RecordRetry(agent.ID)
+	delete(m.usage, agent.ID)
+	delete(m.checks, agent.ID)
+
+	var args []string
+	if agent.AutoApprove {
+		args = agent.Backend().AutoApproveArgs()
+	}
+	if err := m.manager.SpawnAgent(agent, args); err != nil {
+		m.store.SetExitReason(agent.ID, fmt.Sprintf("retry couldn't start: %v", err))
+		m.store.Update(agent.ID, StatusDone)
+		m.refreshAgents()
+		m.setStatus(fmt.Sprintf("Retry failed: %v", err))
+		return
+	}
+	m.store.UpdateSessionName(agent.ID, agent.SessionName)
+	m.store.Update(agent.ID, StatusRunning)
+	switch {
+	case agent.Stream:
+		m.store.MarkPromptSent(agent.ID, time.Now())
+	case agent.Prompt != "":
+		m.store.MarkPromptSent(agent.ID, time.Now().Add(promptStartupDelay))
+		go SendPromptAfterDelay(agent.SessionName, agent.Prompt)
+	}
+	m.events.Add(EventRetry, agent.Name, fmt.Sprintf("started over, attempt %d", n))
+	m.refreshAgents()
+	m.cachedCards = m.buildCardData()
+	m.setStatus(fmt.Sprintf("Retrying %s from its original prompt (attempt %d)", agent.Name, n))
+}
+
+// --- Handing an agent to another backend ---
+
+func (m *Model) openHandoffDialog() {
+	if m.selected >= len(m.agents) {
+		return
+	}
+	agent := m.agents[m.selected]
+	m.handoffBackends = nil
+	for _, b := range AvailableBackends() {
+		if b.ID() != agent.Backend().ID() {
+			m.handoffBackends = append(m.handoffBackends, b)
+		}
+	}
+	sort.Slice(m.handoffBackends, func(i, j int) bool {
+		return m.handoffBackends[i].ID() < m.handoffBackends[j].ID()
+	})
+	if len(m.handoffBackends) == 0 {
+		m.setStatus("No other backend installed")
+		return
+	}
+	m.handoffID = agent.ID
+	m.view = viewHandoff
+}
+
+func (m *Model) handleHandoffKey(key string) (tea.Model, tea.Cmd) {
+	m.view = viewBoard
+	if m.columns == 1 {
+		m.view = viewCarousel
+	}
+	agent := m.store.Get(m.handoffID)
+	if agent == nil || len(key) != 1 || key[0] < '1' || int(key[0]-'0') > len(m.handoffBackends) {
+		return m, nil
+	}
+	m.handoffAgent(agent, m.handoffBackends[key[0]-'1'])
+	return m, nil
+}
+
+// handoffAgent kills the agent's session and starts its task over under
+// another backend, in the same dir and with the same auto-approve setting.
+// The original prompt and note go in as the first message. The agent keeps
+// its ID, name, tags and history, so the card just changes hands.
+func (m *Model) handoffAgent(agent *Agent, to Backend) {
+	from := agent.Backend().Name()
+
+	m.archiveAll([]*Agent{agent}, "handed off")
+	if sess := m.manager.GetSession(agent); sess != nil {
+		_ = m.manager.Kill(agent.ID)
+	} else if agent.SessionName != "" {
+		_ = KillBySession(agent.SessionName)
+	}
+	agent.Backend().CleanHookStatus(agent.ID)
+
+	m.store.SetBackend(agent.ID, to.ID())
+	m.store.UpdateDiscovered(agent.ID, false)
+	delete(m.usage, agent.ID)
+	agent.Stream = false
+
+	var args []string
+	if agent.AutoApprove {
+		args = to.AutoApproveArgs()
+	}
+	if err := m.manager.SpawnAgent(agent, args); err != nil {
+		m.store.SetExitReason(agent.ID, fmt.Sprintf("handoff couldn't start: %v", err))
+		m.store.Update(agent.ID, StatusDone)
+		m.refreshAgents()
+		m.setStatus(fmt.Sprintf("Handoff failed: %v", err))
+		return
+	}
+	m.store.UpdateSessionName(agent.ID, agent.SessionName)
+	m.store.Update(agent.ID, StatusRunning)
+	if prompt := handoffPrompt(agent); prompt != "" {
+		m.store.LogPrompt(agent.ID, "handoff", prompt)
+		go SendPromptAfterDelay(agent.SessionName, prompt)
+	}
+	m.events.Add(EventHandoff, agent.Name, fmt.Sprintf("%s → %s", from, to.Name()))
+	m.refreshAgents()
+	m.cachedCards = m.buildCardData()
+	m.setStatus(fmt.Sprintf("Handed %s from %s to %s", agent.Name, from, to.Name()))
+}
+
+func (m Model) viewHandoffDialog() string {
+	agent := m.store.Get(m.handoffID)
+	if agent == nil {
+		return ""
+	}
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorAccent).
+		Padding(1, 2).
+		Width(56)
+
+	lines := []string{
+		ui.AgentName.Render(fmt.Sprintf("Hand off %s (%s) to:", agent.Name, agent.Backend().Name())),
+		"",
+	}
+	for i, b := range m.handoffBackends {
+		lines = append(lines, fmt.Sprintf("  [%d] %s", i+1, b.Name()))
+	}
+	carry := "Starts over in " + shortenPath(agent.Dir)
+	if handoffPrompt(agent) != "" {
+		carry += " with the original prompt and note"
+	}
+	lines = append(lines, "",
+		ui.DimText.Render("Kills the current session. "+carry+"."),
+		"", ui.HelpStyle.Render("[Esc] Cancel"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	rendered := dialog.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
+}
+
+// --- Backends overlay ---
+
+// backendHealthCmd probes the backends in the background: version checks
+// run each CLI and can take a few seconds.
+func backendHealthCmd() tea.Cmd {
+	return func() tea.Msg {
+		return backendHealthMsg{rows: backendHealth()}
+	}
+}
+
+func (m *Model) handleBackendsKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "esc", "q", "B":
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+	}
+	return m, nil
+}
+
+func (m Model) viewBackendsDialog() string {
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorAccent).
+		Padding(1, 2).
+		Width(72)
+
+	lines := []string{ui.AgentName.Render("Backends"), ""}
+	if m.backendRows == nil {
+		lines = append(lines, ui.DimText.Render("Checking installed CLIs..."))
+	}
+	for _, h := range m.backendRows {
+		mark := lipgloss.NewStyle().Foreground(ui.ColorRunning).Render("●")
+		detail := h.Version
+		if !h.Installed {
+			mark = ui.DimText.Render("○")
+			detail = "not installed: " + h.Hint
+		}
+		hooks := "hooks " + h.Hooks
+		if h.Hooks == "missing" && h.Installed {
+			hooks = lipgloss.NewStyle().Foreground(ui.ColorWaiting).Render(hooks)
+		} else {
+			hooks = ui.DimText.Render(hooks)
+		}
+		lines = append(lines,
+			fmt.Sprintf("%s %-10s %s", mark, h.Name, hooks),
+			ui.DimText.Render("    "+detail))
+	}
+	lines = append(lines, "", ui.HelpStyle.Render("[Esc] Close  ·  tickettok hooks install fixes missing hooks"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	rendered := dialog.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
+}
+
+// --- Quitting ---
+
+// requestQuit quits with the configured action, asking first when on_quit
+// is "ask" or some agents are still RUNNING.
+func (m *Model) requestQuit() (tea.Model, tea.Cmd) {
+	if m.quitAction == QuitAsk || len(m.runningManaged()) > 0 {
+		m.view = viewQuit
+		return m, nil
+	}
+	return m.quit(m.quitAction == QuitKill)
+}
+
+// quit stops the remote server and exits. With kill, every managed agent's
+// tmux session is killed first; the agents stay in state as DONE and resume
+// on the next zoom. Discovered agents are never touched.
+func (m *Model) quit(kill bool) (tea.Model, tea.Cmd) {
+	if kill {
+		for _, a := range quitKills(m.store.List()) {
+			m.archiveAll([]*Agent{a}, "killed on quit")
+			if sess := m.manager.GetSession(a); sess != nil {
+				_ = m.manager.Kill(a.ID)
+			} else if a.SessionName != "" {
+				_ = KillBySession(a.SessionName)
+			}
+			a.Backend().CleanHookStatus(a.ID)
+			m.store.SetExitReason(a.ID, "killed on quit")
+			m.store.Update(a.ID, StatusDone)
+		}
+	}
+	if m.webServer != nil {
+		m.webServer.Stop()
+	}
+	return m, tea.Quit
+}
+
+// quitKills returns the agents quitting with kill ends: the managed ones
+// that haven't already. Those DONE or ERROR had their output archived
+// when they were killed, or will when they're cleared, which also closes
+// any session left open for it.
+func quitKills(agents []*Agent) []*Agent {
+	var out []*Agent
+	for _, a := range agents {
+		if !a.Discovered && a.Status != StatusDone && a.Status != StatusError {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// runningManaged returns the managed agents still RUNNING.
+func (m Model) runningManaged() []*Agent {
+	var out []*Agent
+	for _, a := range m.store.List() {
+		if a.Status == StatusRunning && !a.Discovered {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func (m *Model) handleQuitKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "enter", "q", "y", "Y":
+		return m.quit(m.quitAction == QuitKill)
+	case "d", "D":
+		return m.quit(false)
+	case "k", "K":
+		return m.quit(true)
+	case "esc", "n", "N":
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+	}
 	return m, nil
 }
 
+func (m Model) viewQuitDialog() string {
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorWaiting).
+		Padding(1, 2).
+		Width(56)
+
+	lines := []string{ui.AgentName.Render("Quit TicketTok?"), ""}
+	if running := m.runningManaged(); len(running) > 0 {
+		var names []string
+		for _, a := range running {
+			names = append(names, a.Name)
+		}
+		lines = append(lines,
+			lipgloss.NewStyle().Foreground(ui.ColorWaiting).Render(
+				fmt.Sprintf("%d still RUNNING: %s", len(running), strings.Join(names, ", "))),
+			"")
+	}
+
+	detach, kill := "  [d] Detach — sessions keep running in tmux", "  [k] Kill all managed sessions"
+	if m.quitAction == QuitKill {
+		kill += " (default)"
+	} else {
+		detach += " (default)"
+	}
+	lines = append(lines, detach, kill, "",
+		ui.HelpStyle.Render("[Enter] default  [Esc] Cancel"))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
+	rendered := dialog.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
+}
+
 // --- Workspace dialog ---
 
 func (m *Model) openWorkspaceDialog() {
@@ -2163,6 +6319,8 @@ func (m *Model) doWorkspaceLoad(name string) (tea.Model, tea.Cmd) {
 
 	// Kill all current agents
 	for _, a := range m.store.List() {
+		m.archiveAll([]*Agent{a}, "replaced by a workspace")
+		m.archiveRemoved([]*Agent{a}, "replaced by a workspace")
 		sess := m.manager.GetSession(a)
 		if sess != nil {
 			_ = m.manager.Kill(a.ID)
@@ -2171,10 +6329,12 @@ func (m *Model) doWorkspaceLoad(name string) (tea.Model, tea.Cmd) {
 		}
 		a.Backend().CleanHookStatus(a.ID)
 		m.store.Remove(a.ID)
+		removeWorktrees([]*Agent{a})
 	}
 
-	count := spawnWorkspaceAgents(wf, m.store, m.manager)
-	m.agents = m.store.List()
+	count, prompts := spawnWorkspaceAgents(wf, m.store, m.manager, m.maxRunning)
+	go sendInitialPrompts(prompts)
+	m.refreshAgents()
 	m.selected = 0
 	m.activeWorkspace = name
 	m.setStatus(fmt.Sprintf("Loaded workspace %q: %d agent(s)", name, count))
@@ -2192,8 +6352,9 @@ func (m *Model) doWorkspaceAdd(name string) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	count := spawnWorkspaceAgents(wf, m.store, m.manager)
-	m.agents = m.store.List()
+	count, prompts := spawnWorkspaceAgents(wf, m.store, m.manager, m.maxRunning)
+	go sendInitialPrompts(prompts)
+	m.refreshAgents()
 	m.activeWorkspace = name
 	m.setStatus(fmt.Sprintf("Added workspace %q: %d agent(s)", name, count))
 	m.view = viewBoard
@@ -2272,6 +6433,143 @@ func (m Model) viewWorkspace() string {
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
 }
 
+// --- Board switcher ---
+
+func (m *Model) openBoardsDialog() {
+	m.boardChoices = boardChoices()
+	m.boardSelected = 0
+	for i, b := range m.boardChoices {
+		if b.Current {
+			m.boardSelected = i
+		}
+	}
+	m.boardNewMode = false
+	m.view = viewBoards
+}
+
+func (m *Model) handleBoardsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	if m.boardNewMode {
+		switch key {
+		case "esc":
+			m.boardNewMode = false
+			m.boardInput.Blur()
+			return m, nil
+		case "enter":
+			name := strings.TrimSpace(m.boardInput.Value())
+			if err := checkBoardName(name); err != nil {
+				m.setStatus(err.Error())
+				return m, nil
+			}
+			return m.openBoard(boardChoice{Label: name, Args: []string{"--board", name}, Current: projectRoot == "" && name == currentBoard})
+		}
+		var cmd tea.Cmd
+		m.boardInput, cmd = m.boardInput.Update(msg)
+		return m, cmd
+	}
+
+	switch key {
+	case "esc", "q", "v":
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+	case "j", "down":
+		if m.boardSelected < len(m.boardChoices)-1 {
+			m.boardSelected++
+		}
+	case "k", "up":
+		if m.boardSelected > 0 {
+			m.boardSelected--
+		}
+	case "n":
+		m.boardNewMode = true
+		m.boardInput.SetValue("")
+		m.boardInput.Focus()
+	case "g":
+		// Between the project board and the global one
+		if len(m.boardChoices) > 1 && m.boardChoices[0].Project {
+			if m.boardChoices[0].Current {
+				return m.openBoard(m.boardChoices[1])
+			}
+			return m.openBoard(m.boardChoices[0])
+		}
+		m.setStatus("Not in a project with a .tickettok directory")
+	case "enter":
+		return m.openBoard(m.boardChoices[m.boardSelected])
+	}
+	return m, nil
+}
+
+// openBoard leaves this board for another: the TUI quits, leaving this
+// board's sessions running, and reopens on board.
+func (m *Model) openBoard(board boardChoice) (tea.Model, tea.Cmd) {
+	if board.Current {
+		m.view = viewBoard
+		if m.columns == 1 {
+			m.view = viewCarousel
+		}
+		return m, nil
+	}
+	m.switchBoard = append([]string{}, board.Args...)
+	return m.quit(false)
+}
+
+func (m Model) viewBoardsDialog() string {
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorAccent).
+		Padding(1, 2).
+		Width(60)
+
+	title := ui.AgentName.Render("Boards")
+
+	var content string
+	if m.boardNewMode {
+		content = lipgloss.JoinVertical(lipgloss.Left,
+			title, "",
+			"New board:", "",
+			m.boardInput.View(), "",
+			ui.HelpStyle.Render("[Enter] open  [Esc] cancel"),
+		)
+	} else {
+		var lines []string
+		for i, b := range m.boardChoices {
+			label := fmt.Sprintf("%s (%d agents)", b.Label, boardAgentCount(b.Dir))
+			if b.Current {
+				label += " — current"
+			}
+			if i == m.boardSelected {
+				lines = append(lines, lipgloss.NewStyle().Foreground(ui.ColorAccent).Bold(true).Render("> "+label))
+			} else {
+				lines = append(lines, ui.DimText.Render("  "+label))
+			}
+		}
+		content = lipgloss.JoinVertical(lipgloss.Left,
+			title, "",
+			strings.Join(lines, "\n"), "",
+			ui.DimText.Render("Switching leaves this board's agents running."), "",
+			ui.HelpStyle.Render("[Enter] switch  [g] project/global  [n] new board  [Esc] close"),
+		)
+	}
+
+	rendered := dialog.Render(content)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, rendered)
+}
+
+// titleLabel is what the title bar shows beside the name: the board, when
+// it isn't the default one, and the active workspace.
+func (m Model) titleLabel() string {
+	var parts []string
+	if board := boardLabel(); board != "" {
+		parts = append(parts, board)
+	}
+	if m.activeWorkspace != "" {
+		parts = append(parts, m.activeWorkspace)
+	}
+	return strings.Join(parts, " · ")
+}
+
 // clipHeight trims rendered content to maxLines without any scroll offset math.
 // Used as a safety net after card-level slicing in the renderers.
 func clipHeight(content string, maxLines int) string {
@@ -2286,6 +6584,7 @@ func clipHeight(content string, maxLines int) string {
 // Results are cached in m.cachedCards; call only on tick or state changes.
 func (m Model) buildCardData() []ui.CardData {
 	now := time.Now()
+	all := m.store.List()
 	cards := make([]ui.CardData, len(m.agents))
 	for i, a := range m.agents {
 		info := m.manager.GetPaneInfo(a, 13)
@@ -2300,7 +6599,33 @@ func (m Model) buildCardData() []ui.CardData {
 			Preview:     info.Preview,
 			Selected:    i == m.selected,
 			Discovered:  a.Discovered,
+			Backend:     a.BackendLabel(),
 			AutoApprove: a.AutoApprove,
+			KeepAlive:   a.KeepAlive,
+			Priority:    a.Priority,
+			Attempt:     attempt(a),
+			Prompt:      a.Prompt,
+			Tags:        a.Tags,
+			Swarm:       a.Swarm,
+			Pin:         a.Pin,
+			Note:        a.Note,
+			Chain:       dependencyLine(a, all),
+			Race:        raceNotice(a, all),
+			Branch:      a.Branch,
+			Idle:        m.idleShutdown.notice(a, now),
+			Throttle:    throttleNotice(a, now),
+			Failure:     failureNotice(a),
+			Exit:        exitNotice(a),
+			Broken:      buildFailureNotice(a, info.Broken),
+			Check:       m.checks[a.ID].Info(),
+			Summary:     summaryNotice(a),
+			Question:    askNotice(a),
+			Diff:        m.diffStats[a.Dir].Info(),
+			Repo:        m.repoStates[a.Dir].Info(),
+			Usage:       m.usage[a.ID].Max(info.Usage).Info(),
+			Res:         m.resources[a.SessionName].Info(m.memoryWarn),
+			Todos:       info.Todos.Info(),
+			Subagents:   subagentInfos(info.Subagents, now),
 		}
 	}
 	return cards
@@ -2328,11 +6653,7 @@ func (m Model) getCards() []ui.CardData {
 // discoverCmd runs discovery asynchronously and returns a discoverMsg.
 func discoverCmd() tea.Cmd {
 	return func() tea.Msg {
-		var found []DiscoveredAgent
-		for _, b := range AllBackends() {
-			found = append(found, b.Discover()...)
-		}
-		return discoverMsg{found: found}
+		return discoverMsg{found: discoverAll()}
 	}
 }
 
@@ -2342,6 +6663,7 @@ func reconcileCmd(store *Store) tea.Cmd {
 		for _, a := range store.List() {
 			if a.Discovered && a.Status != StatusDone {
 				if !IsSessionAlive(a.SessionName) {
+					store.SetExitReason(a.ID, "session gone when tickettok started")
 					store.Update(a.ID, StatusDone)
 				}
 			}
@@ -2350,12 +6672,62 @@ func reconcileCmd(store *Store) tea.Cmd {
 	}
 }
 
-// mergeDiscovered adds newly found external agents that aren't already tracked.
+// stateWatchInterval is how often the TUI looks for other processes'
+// writes to the state file.
+const stateWatchInterval = 250 * time.Millisecond
+
+// stateWatchMaxBackoff caps how long the watcher waits between tries at
+// a state file it can't read.
+const stateWatchMaxBackoff = 30 * time.Second
+
+// stateWatchBackoff is how long the watcher waits after the state file
+// failed to read fails times in a row: doubling from stateWatchInterval.
+func stateWatchBackoff(fails int) time.Duration {
+	d := stateWatchInterval
+	for i := 0; i < fails && d < stateWatchMaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, stateWatchMaxBackoff)
+}
+
+// watchStateCmd waits for another process to write the state file, so
+// two TUIs on one board — one per monitor, or one over SSH — show the
+// same agents. It first looks after wait, then every stateWatchInterval.
+func watchStateCmd(store *Store, wait time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		for {
+			time.Sleep(wait)
+			wait = stateWatchInterval
+			if store.Changed() {
+				return stateChangedMsg{}
+			}
+		}
+	}
+}
+
+// flushStateCmd has the store's held changes written out after a while.
+// The write happens in Update rather than on a timer's goroutine, as it
+// reads agents the update loop changes without the store's lock.
+func flushStateCmd() tea.Cmd {
+	return tea.Tick(stateSaveDelay, func(time.Time) tea.Msg {
+		return stateFlushMsg{}
+	})
+}
+
+// mergeDiscovered adds newly found external agents that aren't already
+// tracked, each under the backend its pane looks like (see detectBackendID).
 func (m *Model) mergeDiscovered(found []DiscoveredAgent) {
+	foundBy := make(map[string][]string)
+	for _, d := range found {
+		foundBy[d.SessionName] = append(foundBy[d.SessionName], d.BackendID)
+	}
+	// The whole store, not m.agents: filtered-out or folded agents are
+	// still tracked, and a session found by several backends is added once
+	tracked := m.store.List()
 	for _, d := range found {
 		// Check if already tracked by session name
 		var match *Agent
-		for _, a := range m.agents {
+		for _, a := range tracked {
 			if a.SessionName == d.SessionName {
 				match = a
 				break
@@ -2363,16 +6735,22 @@ func (m *Model) mergeDiscovered(found []DiscoveredAgent) {
 		}
 		if match != nil {
 			// Revive dead agents whose session came back (reused tmux session name)
-			if match.Status == StatusDone {
+			if match.Ended() {
 				m.store.Update(match.ID, StatusRunning)
 				m.store.UpdateDiscovered(match.ID, true)
 			}
 			continue
 		}
-		agent := m.store.Add(d.Name, d.Dir)
+		var content string
+		if d.SessionName != "" {
+			content, _ = CapturePanePlain(d.SessionName)
+		}
+		agent := m.store.AddWithBackend(d.Name, d.Dir, detectBackendID(content, foundBy[d.SessionName]))
 		agent.SessionName = d.SessionName
 		agent.Discovered = true
+		tracked = append(tracked, agent)
 		m.store.UpdateSessionName(agent.ID, d.SessionName)
 		m.store.UpdateDiscovered(agent.ID, true)
+		m.events.Add(EventDiscover, d.Name, fmt.Sprintf("session %s in %s", d.SessionName, d.Dir))
 	}
 }
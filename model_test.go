@@ -3,6 +3,9 @@ package main
 import (
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/sns45/tickettok/ui"
 )
 
 func TestColumnForStatus(t *testing.T) {
@@ -33,6 +36,43 @@ func TestColumnForStatus(t *testing.T) {
 	}
 }
 
+func TestPausedColumn(t *testing.T) {
+	paused := &Agent{Status: StatusPaused}
+	m := &Model{columns: 3, agents: []*Agent{{Status: StatusRunning}}}
+	if n := len(m.layout()); n != 3 {
+		t.Fatalf("layout without paused agents has %d columns, want 3", n)
+	}
+
+	m.agents = append(m.agents, paused)
+	layout := m.layout()
+	if n := len(layout); n != 4 || layout[3].Title != "PAUSED" {
+		t.Fatalf("layout with a paused agent = %d columns, want PAUSED added", n)
+	}
+	if got := m.columnFor(paused); got != 3 {
+		t.Errorf("columnFor(paused) = %d, want 3", got)
+	}
+	if n := len(ui.ThreeColumnLayout); n != 3 {
+		t.Errorf("adding the PAUSED column changed the shared layout (%d columns)", n)
+	}
+
+	// A custom layout with its own place for PAUSED isn't extended
+	m.customColumns = []ui.Column{{Title: "ALL", Statuses: []string{"RUNNING", "PAUSED"}}}
+	if n := len(m.layout()); n != 1 {
+		t.Errorf("custom layout claiming PAUSED has %d columns, want 1", n)
+	}
+}
+
+func TestReviewColumn(t *testing.T) {
+	m := &Model{columns: 3, agents: []*Agent{{Status: StatusPaused}, {Status: StatusReview}}}
+	layout := m.layout()
+	if n := len(layout); n != 5 || layout[3].Title != "PAUSED" || layout[4].Title != "REVIEW" {
+		t.Fatalf("layout = %d columns, want PAUSED and REVIEW added", n)
+	}
+	if got := m.columnFor(m.agents[1]); got != 4 {
+		t.Errorf("columnFor(NEEDS-REVIEW) = %d, want 4", got)
+	}
+}
+
 func TestNextInSameColumn(t *testing.T) {
 	agents := []*Agent{
 		{ID: "1", Status: StatusIdle},
@@ -132,6 +172,38 @@ func TestNextInColumn(t *testing.T) {
 			t.Errorf("nextInColumn(-1) skipping empty col1 = %d, want 0", next)
 		}
 	})
+
+	t.Run("skips collapsed column", func(t *testing.T) {
+		prefs := map[string]ColumnPref{"WAITING": {Collapsed: true}}
+		m := &Model{agents: agents, selected: 0, columns: 3, columnPrefs: prefs}
+		if next := m.nextInColumn(+1); next != 2 {
+			t.Errorf("nextInColumn(+1) past collapsed WAITING = %d, want 2", next)
+		}
+
+		// A selection stranded in the collapsed column moves out
+		m.selected = 1
+		m.selectVisibleColumn()
+		if m.selected != 0 && m.selected != 2 {
+			t.Errorf("selectVisibleColumn() left selection on %d", m.selected)
+		}
+	})
+}
+
+func TestNextInSameColumnSorted(t *testing.T) {
+	agents := []*Agent{
+		{ID: "1", Name: "charlie", Status: StatusIdle},
+		{ID: "2", Name: "alpha", Status: StatusIdle},
+		{ID: "3", Name: "bravo", Status: StatusIdle},
+	}
+	m := &Model{agents: agents, selected: 1, columns: 3, sortMode: ui.SortName}
+
+	// Name order is alpha(1) → bravo(2) → charlie(0)
+	if got := m.nextInSameColumn(+1); got != 2 {
+		t.Errorf("nextInSameColumn(+1) from alpha = %d, want 2 (bravo)", got)
+	}
+	if got := m.visualRow(0); got != 2 {
+		t.Errorf("visualRow(charlie) = %d, want 2", got)
+	}
 }
 
 func TestClipHeight(t *testing.T) {
@@ -165,3 +237,111 @@ func TestClipHeight(t *testing.T) {
 		}
 	})
 }
+
+func TestScrollWindow(t *testing.T) {
+	content := "1\n2\n3\n4\n5"
+
+	got := scrollWindow(content, 0, 3)
+	if strings.Join(got, ",") != "3,4,5" {
+		t.Errorf("bottom window = %v, want [3 4 5]", got)
+	}
+	got = scrollWindow(content, 2, 3)
+	if strings.Join(got, ",") != "1,2,3" {
+		t.Errorf("scrolled window = %v, want [1 2 3]", got)
+	}
+	got = scrollWindow("a", 0, 3)
+	if len(got) != 3 || got[0] != "a" || got[2] != "" {
+		t.Errorf("short content should pad to 3 lines, got %q", got)
+	}
+}
+
+func TestStatusSummary(t *testing.T) {
+	now := time.Now()
+	agents := []*Agent{
+		{Status: StatusRunning, History: []StatusChange{
+			{Status: StatusRunning, At: now.Add(-30 * time.Second)},
+			{Status: StatusIdle, At: now.Add(-90 * time.Second)},
+			{Status: StatusRunning, At: now.Add(-time.Hour)}, // outside the window
+		}},
+		{Status: StatusWaiting},
+		{Status: StatusWaiting},
+	}
+
+	s := statusSummary(agents, now, 0)
+	if s.Counts["WAITING"] != 2 || s.Counts["RUNNING"] != 1 {
+		t.Errorf("Counts = %v", s.Counts)
+	}
+	if len(s.Activity) != activityMinutes {
+		t.Fatalf("Activity has %d buckets, want %d", len(s.Activity), activityMinutes)
+	}
+	last := activityMinutes - 1
+	if s.Activity[last] != 1 || s.Activity[last-1] != 1 {
+		t.Errorf("Activity = %v, want one change in each of the last two minutes", s.Activity)
+	}
+	if len(s.Stale) != 0 {
+		t.Errorf("Stale = %v, want none with the threshold off", s.Stale)
+	}
+
+	agents = []*Agent{
+		{Name: "stuck", Status: StatusRunning, StatusSince: now.Add(-45 * time.Minute)},
+		{Name: "busy", Status: StatusRunning, StatusSince: now.Add(-5 * time.Minute)},
+		{Name: "idle", Status: StatusIdle, StatusSince: now.Add(-2 * time.Hour)},
+	}
+	s = statusSummary(agents, now, 30*time.Minute)
+	if len(s.Stale) != 1 || s.Stale[0] != "stuck" {
+		t.Errorf("Stale = %v, want [stuck]", s.Stale)
+	}
+}
+
+func TestNextWaiting(t *testing.T) {
+	agents := []*Agent{
+		{Status: StatusWaiting},
+		{Status: StatusRunning},
+		{Status: StatusWaiting},
+		{Status: StatusIdle},
+	}
+	tests := []struct{ from, want int }{
+		{0, 2},
+		{1, 2},
+		{2, 0}, // wraps
+		{3, 0},
+	}
+	for _, tt := range tests {
+		if got := nextWaiting(agents, tt.from); got != tt.want {
+			t.Errorf("nextWaiting(from %d) = %d, want %d", tt.from, got, tt.want)
+		}
+	}
+	if got := nextWaiting(agents[1:2], 0); got != -1 {
+		t.Errorf("nextWaiting(none waiting) = %d, want -1", got)
+	}
+	if got := nextWaiting(agents[:1], 0); got != 0 {
+		t.Errorf("nextWaiting(only self waiting) = %d, want 0", got)
+	}
+
+	// High priority first, low priority last, wherever they are
+	agents[0].Priority = PriorityLow
+	agents[3].Status, agents[3].Priority = StatusWaiting, PriorityHigh
+	if got := nextWaiting(agents, 3); got != 3 {
+		t.Errorf("nextWaiting(high waiting) = %d, want 3", got)
+	}
+	agents[3].Status = StatusIdle
+	if got := nextWaiting(agents, 2); got != 2 {
+		t.Errorf("nextWaiting(low waiting) = %d, want the normal agent 2", got)
+	}
+}
+
+func TestUndoEntryLive(t *testing.T) {
+	now := time.Now()
+	agents := []*Agent{{ID: "1"}}
+
+	var none *undoEntry
+	if none.live(now) {
+		t.Error("nil entry should not be live")
+	}
+	if !(&undoEntry{agents: agents, at: now.Add(-10 * time.Second)}).live(now) {
+		t.Error("10s-old entry should be live")
+	}
+	if (&undoEntry{agents: agents, at: now.Add(-undoWindow)}).live(now) {
+		t.Error("entry at the window edge should have expired")
+	}
+}
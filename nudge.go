@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Nudge defaults for a rule that doesn't say
+const (
+	defaultNudgeMessage = "continue"
+	defaultNudgeMax     = 3
+)
+
+// Nudge is an agent's rule for carrying on by itself: when it goes IDLE
+// with work left, Message is typed into it, at most Max times.
+type Nudge struct {
+	Message string `json:"message"`
+	Max     int    `json:"max"`
+	// Until is a completion marker: with one set, the agent has work left
+	// until its last message contains it. Without, until its task list
+	// is done.
+	Until string `json:"until,omitempty"`
+}
+
+// A nudge rule's leading count, e.g. "3x" or "3"
+var nudgeCountRe = regexp.MustCompile(`^(\d+)x?$`)
+
+// parseNudge reads a nudge rule typed by the user:
+//
+//	[<count>x] [<message>] [until <marker>]
+//
+// e.g. "5x keep going until ALL TESTS PASS". The count defaults to 3 and
+// the message to "continue". Empty, "off" and "none" turn nudging off.
+func parseNudge(s string) (*Nudge, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "", "off", "none":
+		return nil, nil
+	}
+	n := &Nudge{Max: defaultNudgeMax}
+	first, rest, _ := strings.Cut(s, " ")
+	if m := nudgeCountRe.FindStringSubmatch(strings.ToLower(first)); m != nil {
+		n.Max, _ = strconv.Atoi(m[1])
+		if n.Max < 1 {
+			return nil, fmt.Errorf("nudge count must be at least 1")
+		}
+		s = strings.TrimSpace(rest)
+	}
+	if strings.HasPrefix(s, "until ") {
+		s = " " + s
+	}
+	if i := strings.LastIndex(s, " until "); i >= 0 {
+		n.Until = strings.TrimSpace(s[i+len(" until "):])
+		if n.Until == "" {
+			return nil, fmt.Errorf("nothing after \"until\"")
+		}
+		s = s[:i]
+	}
+	n.Message = strings.TrimSpace(s)
+	if n.Message == "" {
+		n.Message = defaultNudgeMessage
+	}
+	return n, nil
+}
+
+// String writes the rule the way parseNudge reads it.
+func (n *Nudge) String() string {
+	s := fmt.Sprintf("%dx %s", n.Max, n.Message)
+	if n.Until != "" {
+		s += " until " + n.Until
+	}
+	return s
+}
+
+// nudgeDue reports whether an agent that just went IDLE should be nudged:
+// it has a rule with nudges left, and work left by the rule's measure —
+// no completion marker in its last message, or with no marker, an
+// unfinished task list. An agent without a task list or marker is taken
+// at its word. An ASK agent asked something, and is left for someone to
+// answer.
+func nudgeDue(a *Agent, todos TodoProgress, last string) bool {
+	n := a.Nudge
+	if n == nil || a.Discovered || a.Status != StatusIdle || a.Nudges >= n.Max {
+		return false
+	}
+	if n.Until != "" {
+		return !strings.Contains(last, n.Until)
+	}
+	return todos.Done < todos.Total
+}
+
+// nudgeNotice describes an agent's rule for the detail panel, "" when it
+// has none.
+func nudgeNotice(a *Agent) string {
+	n := a.Nudge
+	if n == nil {
+		return ""
+	}
+	s := fmt.Sprintf("%q up to %dx", n.Message, n.Max)
+	if n.Until != "" {
+		s += fmt.Sprintf(" until %q", n.Until)
+	} else {
+		s += " while tasks remain"
+	}
+	return s + fmt.Sprintf(", %d sent", a.Nudges)
+}
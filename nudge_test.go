@@ -0,0 +1,76 @@
+package main
+
+import "testing"
+
+func TestParseNudge(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // "" for off
+	}{
+		{"", ""},
+		{"off", ""},
+		{"continue", "3x continue"},
+		{"5x keep going until ALL TESTS PASS", "5x keep going until ALL TESTS PASS"},
+		{"2", "2x continue"},
+		{"until DONE", "3x continue until DONE"},
+		{"go on until told until DONE", "3x go on until told until DONE"},
+	}
+	for _, tt := range tests {
+		n, err := parseNudge(tt.in)
+		if err != nil {
+			t.Errorf("parseNudge(%q): %v", tt.in, err)
+			continue
+		}
+		got := ""
+		if n != nil {
+			got = n.String()
+		}
+		if got != tt.want {
+			t.Errorf("parseNudge(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	for _, bad := range []string{"0x continue", "0"} {
+		if _, err := parseNudge(bad); err == nil {
+			t.Errorf("parseNudge(%q) accepted", bad)
+		}
+	}
+}
+
+func TestNudgeDue(t *testing.T) {
+	todo := &Nudge{Message: "continue", Max: 2}
+	marker := &Nudge{Message: "continue", Max: 2, Until: "ALL DONE"}
+	unfinished := TodoProgress{Done: 1, Total: 3}
+	tests := []struct {
+		name  string
+		agent Agent
+		todos TodoProgress
+		last  string
+		want  bool
+	}{
+		{"no rule", Agent{Status: StatusIdle}, unfinished, "", false},
+		{"tasks left", Agent{Status: StatusIdle, Nudge: todo}, unfinished, "", true},
+		{"tasks done", Agent{Status: StatusIdle, Nudge: todo}, TodoProgress{Done: 3, Total: 3}, "", false},
+		{"no task list", Agent{Status: StatusIdle, Nudge: todo}, TodoProgress{}, "", false},
+		{"nudges used up", Agent{Status: StatusIdle, Nudge: todo, Nudges: 2}, unfinished, "", false},
+		{"not idle", Agent{Status: StatusWaiting, Nudge: todo}, unfinished, "", false},
+		{"asked a question", Agent{Status: StatusAsk, Nudge: marker}, unfinished, "Shall I go on?", false},
+		{"marker missing", Agent{Status: StatusIdle, Nudge: marker}, TodoProgress{}, "Shall I go on?", true},
+		{"marker seen", Agent{Status: StatusIdle, Nudge: marker}, unfinished, "Finished. ALL DONE", false},
+	}
+	for _, tt := range tests {
+		if got := nudgeDue(&tt.agent, tt.todos, tt.last); got != tt.want {
+			t.Errorf("%s: nudgeDue() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNudgeHidesOnlyThatAgentsStop(t *testing.T) {
+	transitions := []statusTransition{
+		{"1", "shop", StatusRunning, StatusIdle},
+		{"2", "shop", StatusRunning, StatusIdle},
+	}
+	kept := needingAttention(transitions, nil, map[string]bool{"2": true})
+	if len(kept) != 1 || kept[0].id != "1" {
+		t.Errorf("needingAttention = %+v, want only agent 1's stop", kept)
+	}
+}
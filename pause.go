@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// panePID returns the PID of the process running in a session's pane.
+func panePID(session string) (int, error) {
+	out, err := exec.Command("tmux", "display-message", "-p", "-t", session, "#{pane_pid}").Output()
+	if err != nil {
+		return 0, fmt.Errorf("tmux display-message: %w", err)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+// processTree returns pid followed by all of its descendants, parents
+// before children.
+func processTree(pid int) []int {
+	pids := []int{pid}
+	for i := 0; i < len(pids); i++ {
+		out, err := exec.Command("pgrep", "-P", strconv.Itoa(pids[i])).Output()
+		if err != nil {
+			continue
+		}
+		for _, f := range strings.Fields(string(out)) {
+			if child, err := strconv.Atoi(f); err == nil {
+				pids = append(pids, child)
+			}
+		}
+	}
+	return pids
+}
+
+// signalSession sends sig to every process in a session's pane: the agent
+// CLI and whatever it has started, like a running build or test.
+func signalSession(session string, sig syscall.Signal) error {
+	if session == "" {
+		return fmt.Errorf("no session")
+	}
+	pid, err := panePID(session)
+	if err != nil {
+		return err
+	}
+	for _, p := range processTree(pid) {
+		// Children can exit in the meantime; only the pane process matters
+		if err := syscall.Kill(p, sig); err != nil && p == pid {
+			return fmt.Errorf("signal %d: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// pauseSession freezes an agent's processes with SIGSTOP. They keep their
+// memory and conversation, and carry on where they were on resume.
+func pauseSession(session string) error {
+	return signalSession(session, syscall.SIGSTOP)
+}
+
+// resumeSession continues processes frozen by pauseSession.
+func resumeSession(session string) error {
+	return signalSession(session, syscall.SIGCONT)
+}
+
+// statusBeforePause returns the status an agent had when it was paused,
+// for putting it back on resume. RUNNING if the history doesn't say.
+func statusBeforePause(a *Agent) AgentStatus {
+	return statusBefore(a, StatusPaused, StatusRunning)
+}
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestStatusBeforePause(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []AgentStatus
+		want    AgentStatus
+	}{
+		{"no history", nil, StatusRunning},
+		{"paused right after spawn", []AgentStatus{StatusPaused}, StatusRunning},
+		{"paused while waiting", []AgentStatus{StatusRunning, StatusWaiting, StatusPaused}, StatusWaiting},
+		{"paused twice", []AgentStatus{StatusIdle, StatusPaused, StatusRunning, StatusPaused}, StatusRunning},
+	}
+	for _, tt := range tests {
+		a := &Agent{}
+		for _, s := range tt.history {
+			a.History = append(a.History, StatusChange{Status: s})
+		}
+		if got := statusBeforePause(a); got != tt.want {
+			t.Errorf("%s: statusBeforePause() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pluginPrefix names external backend executables: tickettok-backend-<id>.
+const pluginPrefix = "tickettok-backend-"
+
+// Plugin call timeouts. detect_status runs every tick for every agent on
+// the backend, so it gets the least slack.
+const (
+	pluginCallTimeout     = 5 * time.Second
+	pluginDetectTimeout   = 1 * time.Second
+	pluginDiscoverTimeout = 10 * time.Second
+)
+
+// pluginDetectCacheSize is how many detect_status results a plugin
+// backend remembers, by pane content, for the TUI.
+const pluginDetectCacheSize = 64
+
+// PluginBackend is a backend implemented by an external executable found on
+// PATH. Each call runs `<executable> <method>` with a JSON request on stdin
+// and reads a JSON response from stdout:
+//
+//	describe       {}                  → {"name", "version", "resume_args", "auto_approve_args"}
+//	spawn_command  {"args"}            → {"command", "strip_env"}
+//	detect_status  {"content"}         → {"status", "confident"}
+//	discover       {}                  → {"agents": [{"name", "dir", "session_name", "pid"}]}
+//
+// describe and discover are optional. A plugin that fails a call gets the
+// same fallbacks as a backend without that feature: its ID as the CLI
+// name, a non-confident RUNNING status, nothing discovered.
+type PluginBackend struct {
+	id   string
+	path string
+
+	once sync.Once
+	desc pluginDescription
+
+	// detect_status results by pane content, and the calls under way
+	mu       sync.Mutex
+	detected map[string]StatusResult
+	pending  map[string]bool
+}
+
+type pluginDescription struct {
+	Name            string   `json:"name"`
+	Version         string   `json:"version"`
+	ResumeArgs      []string `json:"resume_args"`
+	AutoApproveArgs []string `json:"auto_approve_args"`
+}
+
+// findPlugins returns plugin executables on pathList keyed by backend ID.
+// Earlier PATH entries win, as they would for the shell.
+func findPlugins(pathList string) map[string]string {
+	found := make(map[string]string)
+	for _, dir := range filepath.SplitList(pathList) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			id := strings.TrimPrefix(e.Name(), pluginPrefix)
+			if id == e.Name() || id == "" || found[id] != "" {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if info, err := os.Stat(path); err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+				continue
+			}
+			found[id] = path
+		}
+	}
+	return found
+}
+
+// registerPlugins registers every plugin on PATH whose ID isn't already
+// taken by a built-in backend.
+func registerPlugins() {
+	for id, path := range findPlugins(os.Getenv("PATH")) {
+		if GetBackend(id) != nil {
+			continue
+		}
+		RegisterBackend(&PluginBackend{id: id, path: path})
+	}
+}
+
+// call runs method and decodes its response into out.
+func (p *PluginBackend) call(timeout time.Duration, method string, req, out interface{}) error {
+	in, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.path, method)
+	cmd.Stdin = bytes.NewReader(in)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	data, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s %s: %w: %s", filepath.Base(p.path), method, err, msg)
+		}
+		return fmt.Errorf("%s %s: %w", filepath.Base(p.path), method, err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s %s: bad response: %w", filepath.Base(p.path), method, err)
+	}
+	return nil
+}
+
+// describe fetches the plugin's static description once.
+func (p *PluginBackend) describe() pluginDescription {
+	p.once.Do(func() {
+		_ = p.call(pluginCallTimeout, "describe", struct{}{}, &p.desc)
+	})
+	return p.desc
+}
+
+func (p *PluginBackend) Name() string {
+	if name := p.describe().Name; name != "" {
+		return name
+	}
+	return p.id
+}
+
+func (p *PluginBackend) ID() string { return p.id }
+
+// SpawnCommand asks the plugin for the launch command, falling back to a
+// CLI named after the backend ID.
+func (p *PluginBackend) SpawnCommand(args []string) (string, []string) {
+	var resp struct {
+		Command  string   `json:"command"`
+		StripEnv []string `json:"strip_env"`
+	}
+	req := struct {
+		Args []string `json:"args"`
+	}{args}
+	if err := p.call(pluginCallTimeout, "spawn_command", req, &resp); err != nil || resp.Command == "" {
+		return strings.Join(append([]string{p.id}, args...), " "), nil
+	}
+	return resp.Command, resp.StripEnv
+}
+
+// Version returns the version from describe, if the plugin gives one.
+func (p *PluginBackend) Version() string { return p.describe().Version }
+
+// Capabilities reports resume when describe lists resume args. The
+// protocol has no hooks, modes or cost.
+func (p *PluginBackend) Capabilities() Capabilities {
+	return Capabilities{SupportsResume: len(p.describe().ResumeArgs) > 0}
+}
+
+func (p *PluginBackend) ResumeArgs() []string      { return p.describe().ResumeArgs }
+func (p *PluginBackend) AutoApproveArgs() []string { return p.describe().AutoApproveArgs }
+
+// CheckDeps verifies the plugin executable is still there.
+func (p *PluginBackend) CheckDeps() error {
+	if _, err := os.Stat(p.path); err != nil {
+		return fmt.Errorf("%s", p.path)
+	}
+	return nil
+}
+
+// DetectStatus hands the pane content to the plugin.
+func (p *PluginBackend) DetectStatus(content string) StatusResult {
+	var resp struct {
+		Status    string `json:"status"`
+		Confident bool   `json:"confident"`
+	}
+	req := struct {
+		Content string `json:"content"`
+	}{content}
+	if err := p.call(pluginDetectTimeout, "detect_status", req, &resp); err != nil {
+		return StatusResult{StatusRunning, false}
+	}
+	status, ok := ParseStatus(resp.Status)
+	if !ok {
+		return StatusResult{StatusRunning, false}
+	}
+	return StatusResult{status, resp.Confident}
+}
+
+// DetectStatusInBackground answers from the plugin's last result for the
+// same pane content, otherwise calling detect_status in the background,
+// so the TUI's tick never waits on the plugin. A pane that hasn't changed
+// since the last tick — most of them — gets its status at once.
+func (p *PluginBackend) DetectStatusInBackground(content string) (StatusResult, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if r, ok := p.detected[content]; ok {
+		return r, true
+	}
+	if p.pending[content] {
+		return StatusResult{}, false
+	}
+	if p.pending == nil {
+		p.pending = make(map[string]bool)
+	}
+	p.pending[content] = true
+	go func() {
+		r := p.DetectStatus(content)
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		delete(p.pending, content)
+		if len(p.detected) >= pluginDetectCacheSize {
+			p.detected = nil
+		}
+		if p.detected == nil {
+			p.detected = make(map[string]StatusResult)
+		}
+		p.detected[content] = r
+	}()
+	return StatusResult{}, false
+}
+
+// DetectMode returns empty — the protocol has no modes.
+func (p *PluginBackend) DetectMode(content string) string {
+	return ""
+}
+
+// DetectUsage returns nothing — the protocol doesn't report usage.
+func (p *PluginBackend) DetectUsage(content string) Usage {
+	return Usage{}
+}
+
+// StripChrome returns lines as-is.
+func (p *PluginBackend) StripChrome(lines []string, waiting bool) []string {
+	return lines
+}
+
+// LooksLikeMe returns false; plugins find their sessions through discover.
+func (p *PluginBackend) LooksLikeMe(content string) bool {
+	return false
+}
+
+// Discover asks the plugin for running instances.
+func (p *PluginBackend) Discover() []DiscoveredAgent {
+	var resp struct {
+		Agents []struct {
+			Name        string `json:"name"`
+			Dir         string `json:"dir"`
+			SessionName string `json:"session_name"`
+			PID         int    `json:"pid"`
+		} `json:"agents"`
+	}
+	if err := p.call(pluginDiscoverTimeout, "discover", struct{}{}, &resp); err != nil {
+		return nil
+	}
+	var found []DiscoveredAgent
+	for _, a := range resp.Agents {
+		if a.SessionName != "" && strings.HasPrefix(a.SessionName, sessionPrefix) {
+			continue
+		}
+		name := a.Name
+		if name == "" {
+			name = deriveNameFromDir(a.Dir)
+		}
+		found = append(found, DiscoveredAgent{
+			Name:        name,
+			Dir:         a.Dir,
+			SessionName: a.SessionName,
+			PID:         a.PID,
+		})
+	}
+	return found
+}
+
+// InstallHooks is never called (see Capabilities). A plugin that wants
+// hook-based status installs its own hooks writing to
+// $TICKETTOK_HOME/status/<agent-id>.json (~/.tickettok by default).
+func (p *PluginBackend) InstallHooks() error   { return nil }
+func (p *PluginBackend) UninstallHooks() error { return nil }
+func (p *PluginBackend) HooksInstalled() bool  { return false }
+
+// ReadHookStatus reads the status file, in case the plugin's own hooks write one.
+func (p *PluginBackend) ReadHookStatus(agentID string) (AgentStatus, bool) {
+	return readHookStatusFile(agentID)
+}
+
+// CleanHookStatus removes the status file for an agent.
+func (p *PluginBackend) CleanHookStatus(agentID string) {
+	cleanHookStatusFile(agentID)
+}
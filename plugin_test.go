@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testPlugin = `#!/bin/sh
+cat >/dev/null
+case "$1" in
+  describe) echo '{"name": "Foo Agent", "resume_args": ["--resume"]}' ;;
+  spawn_command) echo '{"command": "foo --tui"}' ;;
+  detect_status) echo '{"status": "waiting", "confident": true}' ;;
+  discover) echo '{"agents": [{"dir": "/srv/api", "session_name": "work"}, {"session_name": "tickettok_9"}]}' ;;
+  *) exit 1 ;;
+esac
+`
+
+func TestPluginBackend(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, pluginPrefix+"foo")
+	if err := os.WriteFile(path, []byte(testPlugin), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, pluginPrefix+"noexec"), []byte(testPlugin), 0644)
+	os.WriteFile(filepath.Join(dir, "unrelated"), []byte(testPlugin), 0755)
+
+	found := findPlugins(dir + string(os.PathListSeparator) + filepath.Join(dir, "missing"))
+	if len(found) != 1 || found["foo"] != path {
+		t.Fatalf("findPlugins() = %v, want only foo", found)
+	}
+
+	p := &PluginBackend{id: "foo", path: path}
+	if p.Name() != "Foo Agent" || len(p.ResumeArgs()) != 1 {
+		t.Errorf("describe: Name() = %q, ResumeArgs() = %v", p.Name(), p.ResumeArgs())
+	}
+	if cmd, _ := p.SpawnCommand(nil); cmd != "foo --tui" {
+		t.Errorf("SpawnCommand() = %q, want foo --tui", cmd)
+	}
+	if got := p.DetectStatus("Allow? (y/n)"); got != (StatusResult{StatusWaiting, true}) {
+		t.Errorf("DetectStatus() = %+v, want confident WAITING", got)
+	}
+	agents := p.Discover()
+	if len(agents) != 1 || agents[0].Name != "api" || agents[0].SessionName != "work" {
+		t.Errorf("Discover() = %+v, want one agent named after its dir, own sessions skipped", agents)
+	}
+
+	broken := &PluginBackend{id: "bar", path: filepath.Join(dir, "missing")}
+	if cmd, _ := broken.SpawnCommand([]string{"-v"}); cmd != "bar -v" {
+		t.Errorf("fallback SpawnCommand() = %q, want bar -v", cmd)
+	}
+	if got := broken.DetectStatus(""); got.Confident {
+		t.Errorf("failed DetectStatus() = %+v, want non-confident", got)
+	}
+	if broken.CheckDeps() == nil {
+		t.Error("CheckDeps() should fail for a missing executable")
+	}
+}
+
+func TestPluginDetectStatusInBackground(t *testing.T) {
+	path := filepath.Join(t.TempDir(), pluginPrefix+"slow")
+	slow := "#!/bin/sh\ncat >/dev/null\nsleep 0.3\necho '{\"status\": \"idle\", \"confident\": true}'\n"
+	if err := os.WriteFile(path, []byte(slow), 0755); err != nil {
+		t.Fatal(err)
+	}
+	p := &PluginBackend{id: "slow", path: path}
+
+	start := time.Now()
+	if _, ok := detectStatusNow(p, "> "); ok {
+		t.Fatal("got a status before the plugin answered")
+	}
+	if _, ok := detectStatusNow(p, "> "); ok || time.Since(start) > 100*time.Millisecond {
+		t.Fatalf("detectStatusNow waited %v on the plugin", time.Since(start))
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if got, ok := detectStatusNow(p, "> "); ok {
+			if got != (StatusResult{StatusIdle, true}) {
+				t.Errorf("status = %+v, want confident IDLE", got)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the plugin's answer never came in")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if _, ok := detectStatusNow(&ClaudeBackend{}, "> "); !ok {
+		t.Error("a built-in backend's status wasn't detected at once")
+	}
+}
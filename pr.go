@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prTitleMax is about where GitHub starts truncating titles in lists.
+const prTitleMax = 72
+
+// prTitle is the pull request title for an agent's work: the first line of
+// its task, else its name.
+func prTitle(a *Agent) string {
+	title := strings.TrimSpace(strings.SplitN(strings.TrimSpace(a.Prompt), "\n", 2)[0])
+	if title == "" {
+		return a.Name
+	}
+	if r := []rune(title); len(r) > prTitleMax {
+		title = strings.TrimSpace(string(r[:prTitleMax-1])) + "…"
+	}
+	return title
+}
+
+// prBody is the pull request description: the task the agent was given,
+// then what it said when it finished.
+func prBody(a *Agent, summary string) string {
+	var parts []string
+	if p := strings.TrimSpace(a.Prompt); p != "" {
+		parts = append(parts, "## Task\n\n"+p)
+	}
+	if s := strings.TrimSpace(summary); s != "" {
+		parts = append(parts, "## Summary\n\n"+s)
+	}
+	parts = append(parts, fmt.Sprintf("_Opened by tickettok from agent %s (%s)._", a.Name, a.Backend().Name()))
+	return strings.Join(parts, "\n\n")
+}
+
+// finalSummary returns the agent's last message, from its event stream or
+// its session transcript. "" when neither is available.
+func finalSummary(a *Agent) string {
+	if a.Stream {
+		if st, ok := readStreamFile(a.ID); ok {
+			return strings.Join(st.Text, "\n")
+		}
+	}
+	if t := liveTranscript(a); t != "" {
+		return strings.Join(readTranscript(t).text, "\n")
+	}
+	return ""
+}
+
+// prBranch returns the branch to open a pull request from: the one
+// tickettok created for the agent, else whatever its checkout is on.
+func prBranch(a *Agent) (string, error) {
+	if a.Branch != "" {
+		return a.Branch, nil
+	}
+	branch, err := git(a.Dir, "symbolic-ref", "-q", "--short", "HEAD")
+	if err != nil || branch == "" {
+		return "", fmt.Errorf("%s is not on a branch", a.Dir)
+	}
+	return branch, nil
+}
+
+// openPR pushes an agent's branch to origin and opens a pull request for
+// it with gh. Returns the pull request's URL.
+func openPR(a *Agent) (string, error) {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return "", fmt.Errorf("gh not found (https://cli.github.com)")
+	}
+	branch, err := prBranch(a)
+	if err != nil {
+		return "", err
+	}
+	if _, err := git(a.Dir, "push", "-q", "-u", "origin", branch); err != nil {
+		return "", err
+	}
+	cmd := exec.Command("gh", "pr", "create", "--head", branch, "--title", prTitle(a), "--body", prBody(a, finalSummary(a)))
+	cmd.Dir = a.Dir
+	out, err := cmd.CombinedOutput()
+	msg := strings.TrimSpace(string(out))
+	if err != nil {
+		if msg == "" {
+			msg = err.Error()
+		}
+		lines := strings.Split(msg, "\n")
+		return "", fmt.Errorf("gh pr create: %s", lines[len(lines)-1])
+	}
+	// gh prints progress before the URL
+	lines := strings.Split(msg, "\n")
+	return lines[len(lines)-1], nil
+}
+
+// prMsg reports a pull request opened from the TUI.
+type prMsg struct {
+	name string
+	url  string
+	err  error
+}
+
+// prCmd opens a pull request for an agent in the background; pushing can
+// take a while.
+func prCmd(a *Agent) tea.Cmd {
+	agent := *a
+	return func() tea.Msg {
+		url, err := openPR(&agent)
+		return prMsg{name: agent.Name, url: url, err: err}
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPRTitle(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt string
+		want   string
+	}{
+		{"fix-auth", "", "fix-auth"},
+		{"fix-auth", "Fix the login redirect\nIt loops on Safari.", "Fix the login redirect"},
+		{"fix-auth", "\n  Add retries  \n", "Add retries"},
+		{"long", strings.Repeat("a", 100), strings.Repeat("a", prTitleMax-1) + "…"},
+	}
+	for _, tt := range tests {
+		if got := prTitle(&Agent{Name: tt.name, Prompt: tt.prompt}); got != tt.want {
+			t.Errorf("prTitle(%q) = %q, want %q", tt.prompt, got, tt.want)
+		}
+	}
+}
+
+func TestPRBody(t *testing.T) {
+	a := &Agent{Name: "fix-auth", BackendID: "claude", Prompt: "Fix the login redirect"}
+	body := prBody(a, "Done: the redirect now keeps the query string.\n")
+	for _, want := range []string{
+		"## Task\n\nFix the login redirect\n\n",
+		"## Summary\n\nDone: the redirect now keeps the query string.\n\n",
+		"agent fix-auth",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+
+	body = prBody(&Agent{Name: "bare", BackendID: "claude"}, "")
+	if strings.Contains(body, "##") {
+		t.Errorf("body without task or summary has sections:\n%s", body)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Agent priorities. Normal is the zero value, so it isn't saved.
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = ""
+	PriorityLow    = "low"
+)
+
+// parsePriority reads a priority typed by the user.
+func parsePriority(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "high", "h":
+		return PriorityHigh, nil
+	case "normal", "n", "":
+		return PriorityNormal, nil
+	case "low", "l":
+		return PriorityLow, nil
+	}
+	return "", fmt.Errorf("unknown priority %q (want high, normal or low)", s)
+}
+
+// nextPriority is the priority after p when cycling through them from the
+// TUI: normal, high, low.
+func nextPriority(p string) string {
+	switch p {
+	case PriorityNormal:
+		return PriorityHigh
+	case PriorityHigh:
+		return PriorityLow
+	}
+	return PriorityNormal
+}
+
+// priorityName is how a priority is shown, "normal" for the zero value.
+func priorityName(p string) string {
+	if p == PriorityNormal {
+		return "normal"
+	}
+	return p
+}
+
+// priorityRank orders priorities, most important first.
+func priorityRank(p string) int {
+	switch p {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestParsePriority(t *testing.T) {
+	for in, want := range map[string]string{"high": PriorityHigh, "H": PriorityHigh, "normal": PriorityNormal, "": PriorityNormal, "low": PriorityLow} {
+		if got, err := parsePriority(in); err != nil || got != want {
+			t.Errorf("parsePriority(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := parsePriority("urgent"); err == nil {
+		t.Error("parsePriority(urgent) accepted")
+	}
+}
+
+func TestNextPriority(t *testing.T) {
+	p := PriorityNormal
+	var seen []string
+	for i := 0; i < 3; i++ {
+		p = nextPriority(p)
+		seen = append(seen, priorityName(p))
+	}
+	if got := seen[0] + "," + seen[1] + "," + seen[2]; got != "high,low,normal" {
+		t.Errorf("cycle = %s, want high,low,normal", got)
+	}
+}
+
+func TestNotifyTransitionsUsesAgentsOwnPriority(t *testing.T) {
+	s := newTestStore(t)
+	api := s.Add("api", "/src/api")
+	// Agents in one repo share its name
+	low := s.Add("shop", "/src/shop")
+	high := s.Add("shop", "/src/shop")
+	s.SetPriority(low.ID, PriorityLow)
+	s.SetPriority(high.ID, PriorityHigh)
+
+	m := Model{store: s}
+	m.notifyTransitions([]statusTransition{
+		{api.ID, api.Name, StatusRunning, StatusIdle},
+		{high.ID, high.Name, StatusRunning, StatusIdle},
+	})
+	if want := "shop: RUNNING → IDLE (+1 more)"; m.statusMsg != want {
+		t.Errorf("status = %q, want the high-priority shop first: %q", m.statusMsg, want)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// prunePlan lists the state drift found by cross-referencing state.json,
+// hook status files, and live tickettok tmux sessions.
+type prunePlan struct {
+	OrphanSessions []string      // live tickettok_* sessions of this board with no agent in state
+	DeadAgents     []*Agent      // agents whose tmux session is gone
+	StaleStatus    []string      // agent IDs with a hook status file but no agent in state
+	MergedBranches []AgentBranch // agent branches merged into their repo's HEAD, see planBranchPrune
+}
+
+// Empty reports whether there is nothing to prune.
+func (p prunePlan) Empty() bool {
+	return len(p.OrphanSessions) == 0 && len(p.DeadAgents) == 0 && len(p.StaleStatus) == 0 && len(p.MergedBranches) == 0
+}
+
+// planPrune compares agents against live sessions and hook status file IDs.
+// Sessions and status files of other boards' agents are left alone.
+func planPrune(agents []*Agent, liveSessions []string, statusIDs []string) prunePlan {
+	var plan prunePlan
+
+	live := make(map[string]bool, len(liveSessions))
+	for _, s := range liveSessions {
+		live[s] = true
+	}
+
+	known := make(map[string]bool, len(agents))
+	tracked := make(map[string]bool, len(agents))
+	for _, a := range agents {
+		known[a.ID] = true
+		if a.SessionName != "" {
+			tracked[a.SessionName] = true
+		}
+		if a.SessionName == "" || !live[a.SessionName] {
+			plan.DeadAgents = append(plan.DeadAgents, a)
+		}
+	}
+
+	for _, s := range liveSessions {
+		if strings.HasPrefix(s, sessionPrefix) && ownedID(strings.TrimPrefix(s, sessionPrefix)) && !tracked[s] {
+			plan.OrphanSessions = append(plan.OrphanSessions, s)
+		}
+	}
+
+	for _, id := range statusIDs {
+		if !known[id] && ownedID(id) {
+			plan.StaleStatus = append(plan.StaleStatus, id)
+		}
+	}
+
+	return plan
+}
+
+// listTmuxSessions returns the names of all live tmux sessions. Pruning
+// takes every agent without one for dead, so anything but a tmux server
+// that isn't running is an error rather than no sessions.
+func listTmuxSessions() ([]string, error) {
+	out, err := exec.Command("tmux", "list-sessions", "-F", "#{session_name}").Output()
+	return parseTmuxSessions(out, err)
+}
+
+// parseTmuxSessions reads the output of tmux list-sessions, run with err.
+func parseTmuxSessions(out []byte, err error) ([]string, error) {
+	if err != nil {
+		var exit *exec.ExitError
+		if !errors.As(err, &exit) {
+			return nil, fmt.Errorf("tmux list-sessions: %w", err)
+		}
+		msg := strings.TrimSpace(string(exit.Stderr))
+		// No server, or no socket for one: no sessions at all
+		if strings.HasPrefix(msg, "no server running") || strings.HasPrefix(msg, "error connecting to") && strings.HasSuffix(msg, "(No such file or directory)") {
+			return nil, nil
+		}
+		if msg == "" {
+			return nil, fmt.Errorf("tmux list-sessions: %w", err)
+		}
+		return nil, fmt.Errorf("tmux list-sessions: %s", msg)
+	}
+	var names []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			names = append(names, line)
+		}
+	}
+	return names, nil
+}
+
+// listHookStatusIDs returns the agent IDs that have a hook status file on disk.
+func listHookStatusIDs() []string {
+	entries, err := os.ReadDir(hookStatusDir())
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, ".json"))
+	}
+	return ids
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+func TestPlanPrune(t *testing.T) {
+	agents := []*Agent{
+		{ID: "1", SessionName: "tickettok_1"},
+		{ID: "2", SessionName: "tickettok_2"},
+		{ID: "3"},
+		{ID: "4", SessionName: "work", Discovered: true},
+	}
+	live := []string{"tickettok_1", "tickettok_9", "work", "personal"}
+	statusIDs := []string{"1", "2", "7"}
+
+	plan := planPrune(agents, live, statusIDs)
+
+	if len(plan.OrphanSessions) != 1 || plan.OrphanSessions[0] != "tickettok_9" {
+		t.Errorf("OrphanSessions = %v, want [tickettok_9]", plan.OrphanSessions)
+	}
+
+	var dead []string
+	for _, a := range plan.DeadAgents {
+		dead = append(dead, a.ID)
+	}
+	if len(dead) != 2 || dead[0] != "2" || dead[1] != "3" {
+		t.Errorf("DeadAgents = %v, want [2 3]", dead)
+	}
+
+	if len(plan.StaleStatus) != 1 || plan.StaleStatus[0] != "7" {
+		t.Errorf("StaleStatus = %v, want [7]", plan.StaleStatus)
+	}
+}
+
+func TestPlanPruneEmpty(t *testing.T) {
+	agents := []*Agent{{ID: "1", SessionName: "tickettok_1"}}
+	plan := planPrune(agents, []string{"tickettok_1"}, []string{"1"})
+	if !plan.Empty() {
+		t.Errorf("planPrune on consistent state = %+v, want empty", plan)
+	}
+}
+
+func TestParseTmuxSessions(t *testing.T) {
+	exit := func(stderr string) error { return &exec.ExitError{Stderr: []byte(stderr)} }
+	tests := []struct {
+		name    string
+		out     string
+		err     error
+		want    []string
+		wantErr bool
+	}{
+		{"sessions", "tickettok_1\nwork\n", nil, []string{"tickettok_1", "work"}, false},
+		{"no server", "", exit("no server running on /tmp/tmux-1000/default\n"), nil, false},
+		{"no socket", "", exit("error connecting to /tmp/tmux-1000/default (No such file or directory)\n"), nil, false},
+		{"bad socket", "", exit("error connecting to /tmp/tmux-1000/default (Permission denied)\n"), nil, true},
+		{"no tmux", "", exec.ErrNotFound, nil, true},
+		{"silent failure", "", exit(""), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTmuxSessions([]byte(tt.out), tt.err)
+			if (err != nil) != tt.wantErr || !slices.Equal(got, tt.want) {
+				t.Errorf("parseTmuxSessions = %v, %v; want %v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import "sort"
+
+// runningCount returns how many agents are RUNNING. WAITING, IDLE and STUCK
+// agents aren't burning CPU or tokens, so they don't take a slot.
+func runningCount(agents []*Agent) int {
+	n := 0
+	for _, a := range agents {
+		if a.Status == StatusRunning {
+			n++
+		}
+	}
+	return n
+}
+
+// hasCapacity reports whether another agent may start with limit RUNNING
+// at most; a limit of 0 means no limit.
+func hasCapacity(agents []*Agent, limit int) bool {
+	return limit <= 0 || runningCount(agents) < limit
+}
+
+// startablePending returns the PENDING agents that fit in the free slots,
+// oldest first.
+func startablePending(agents []*Agent, limit int) []*Agent {
+	var pending []*Agent
+	for _, a := range agents {
+		if a.Status == StatusPending {
+			pending = append(pending, a)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+	})
+	if limit > 0 {
+		free := limit - runningCount(agents)
+		if free < 0 {
+			free = 0
+		}
+		if len(pending) > free {
+			pending = pending[:free]
+		}
+	}
+	return pending
+}
+
+// startQueued launches a PENDING agent's session and marks it RUNNING.
+// An agent with a task starts fresh; one without resumes the conversation
+// it was saved with (workspace templates), if any.
+func startQueued(store *Store, manager *AgentManager, agent *Agent) error {
+	var args []string
+	if agent.Prompt == "" && agent.SessionID != "" {
+		args = resumeArgs(agent)
+	}
+	if agent.AutoApprove {
+		args = append(args, agent.Backend().AutoApproveArgs()...)
+	}
+	if err := manager.SpawnAgent(agent, args); err != nil {
+		return err
+	}
+	store.UpdateSessionName(agent.ID, agent.SessionName)
+	store.Update(agent.ID, StatusRunning)
+	return nil
+}
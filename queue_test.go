@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStartablePending(t *testing.T) {
+	t0 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	agents := []*Agent{
+		{Name: "busy", Status: StatusRunning, CreatedAt: t0},
+		{Name: "later", Status: StatusPending, CreatedAt: t0.Add(2 * time.Minute)},
+		{Name: "waiting", Status: StatusWaiting, CreatedAt: t0},
+		{Name: "first", Status: StatusPending, CreatedAt: t0.Add(time.Minute)},
+	}
+
+	if hasCapacity(agents, 1) {
+		t.Error("one RUNNING agent should fill a limit of 1")
+	}
+	if !hasCapacity(agents, 0) {
+		t.Error("0 should mean no limit")
+	}
+
+	if got := startablePending(agents, 1); len(got) != 0 {
+		t.Errorf("limit 1: got %v, want none (WAITING doesn't take a slot, RUNNING does)", names(got))
+	}
+	if got := startablePending(agents, 2); len(got) != 1 || got[0].Name != "first" {
+		t.Errorf("limit 2: got %v, want the oldest, first", names(got))
+	}
+	if got := startablePending(agents, 0); len(got) != 2 || got[0].Name != "first" {
+		t.Errorf("no limit: got %v, want first, later", names(got))
+	}
+}
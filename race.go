@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// maxSplitPanes caps how many agents split view shows side by side; a race
+// on more backends shows its first ones.
+const maxSplitPanes = 4
+
+// parseRaceBackends reads a race's --backends list: distinct, installed
+// backends, at least two of them.
+func parseRaceBackends(s string) ([]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(s, ",") {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if id == "" || seen[id] {
+			continue
+		}
+		b := GetBackend(id)
+		if b == nil {
+			return nil, fmt.Errorf("unknown backend: %s", id)
+		}
+		if err := b.CheckDeps(); err != nil {
+			return nil, fmt.Errorf("backend %s not installed: %v", id, err)
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) < 2 {
+		return nil, fmt.Errorf("a race needs at least two backends")
+	}
+	return ids, nil
+}
+
+// raceMembers returns the agents racing in race, in board order. None for
+// race "".
+func raceMembers(agents []*Agent, race string) []*Agent {
+	if race == "" {
+		return nil
+	}
+	var out []*Agent
+	for _, a := range agents {
+		if a.Race == race {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// raceNotice is the line a racing agent's card shows: who it's up against.
+// "" for agents not in a race.
+func raceNotice(a *Agent, all []*Agent) string {
+	var rivals []string
+	for _, r := range raceMembers(all, a.Race) {
+		if r.ID != a.ID {
+			rivals = append(rivals, r.Backend().ID())
+		}
+	}
+	if len(rivals) == 0 {
+		return ""
+	}
+	return "racing " + strings.Join(rivals, ", ") + " — | to compare"
+}
+
+// spawnRace spawns the same prompt on each backend, each agent in a
+// worktree of its own on its own branch and named after the backend.
+// Agents past max_running are queued. It returns the agents spawned and
+// those whose prompt is due now.
+func spawnRace(store *Store, manager *AgentManager, dir, name, prompt string, backends []string, autoApprove bool, maxRunning int) ([]*Agent, []*Agent, error) {
+	var racers, send []*Agent
+	for _, id := range backends {
+		queued := !hasCapacity(store.List(), maxRunning)
+		agent := store.AddWithBackend(name+"-"+id, dir, id)
+		if err := isolateAgent(store, agent); err != nil {
+			store.Remove(agent.ID)
+			return racers, send, fmt.Errorf("%s: %w", agent.Name, err)
+		}
+		if len(racers) == 0 {
+			agent.Race = agent.ID
+		} else {
+			agent.Race = racers[0].Race
+		}
+		agent.AutoApprove = autoApprove
+		agent.Prompt = prompt
+		racers = append(racers, agent)
+
+		if queued {
+			// Sent when the TUI starts it
+			store.Update(agent.ID, StatusPending)
+			continue
+		}
+		var extraArgs []string
+		if autoApprove {
+			extraArgs = agent.Backend().AutoApproveArgs()
+		}
+		if err := manager.SpawnAgent(agent, extraArgs); err != nil {
+			store.SetExitReason(agent.ID, fmt.Sprintf("couldn't start: %v", err))
+			store.Update(agent.ID, StatusDone)
+			fmt.Fprintf(os.Stderr, "Failed to spawn %q: %v\n", agent.Name, err)
+			continue
+		}
+		store.UpdateSessionName(agent.ID, agent.SessionName)
+		store.MarkPromptSent(agent.ID, time.Now().Add(promptStartupDelay))
+		send = append(send, agent)
+	}
+	store.Save()
+	return racers, send, nil
+}
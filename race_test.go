@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestRaceMembers(t *testing.T) {
+	agents := []*Agent{
+		{ID: "1", Race: "1", BackendID: "claude"},
+		{ID: "2"},
+		{ID: "3", Race: "1", BackendID: "codex"},
+		{ID: "4", Race: "1", BackendID: "gemini"},
+	}
+	if got := raceMembers(agents, "1"); len(got) != 3 || got[1].ID != "3" {
+		t.Fatalf("raceMembers() = %d agents, want 1, 3 and 4", len(got))
+	}
+	if got := raceMembers(agents, ""); got != nil {
+		t.Errorf("raceMembers(\"\") = %d agents, want none", len(got))
+	}
+	if got, want := raceNotice(agents[2], agents), "racing claude, gemini — | to compare"; got != want {
+		t.Errorf("raceNotice() = %q, want %q", got, want)
+	}
+	if got := raceNotice(agents[1], agents); got != "" {
+		t.Errorf("raceNotice() outside a race = %q", got)
+	}
+}
+
+func TestParseRaceBackends(t *testing.T) {
+	for _, bad := range []string{"", "nope,claude", "interpreter, interpreter"} {
+		if _, err := parseRaceBackends(bad); err == nil {
+			t.Errorf("parseRaceBackends(%q) accepted", bad)
+		}
+	}
+}
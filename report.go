@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// A report is the board written up for people rather than for import:
+// `tickettok export --format markdown` for a standup-style summary to
+// paste, csv for a spreadsheet.
+
+// reportRow is one agent in a report.
+type reportRow struct {
+	ID       string
+	Name     string
+	Repo     string
+	Branch   string
+	Status   AgentStatus
+	Duration time.Duration // from spawn until it finished, or until now
+	Task     string
+	Summary  string // its last message, or why it failed or ended
+}
+
+// reportRows describes agents as of now, with repoOf naming each one's
+// repository.
+func reportRows(agents []*Agent, now time.Time, repoOf func(*Agent) string) []reportRow {
+	rows := make([]reportRow, 0, len(agents))
+	for _, a := range agents {
+		end := now
+		if a.Status == StatusDone || a.Status == StatusError {
+			end = a.StatusSince
+		}
+		summary := a.Summary
+		if summary == "" {
+			summary = a.Failure
+		}
+		if summary == "" {
+			summary = exitNotice(a)
+		}
+		rows = append(rows, reportRow{
+			ID:       a.ID,
+			Name:     a.Name,
+			Repo:     repoOf(a),
+			Branch:   a.Branch,
+			Status:   a.Status,
+			Duration: end.Sub(a.CreatedAt),
+			Task:     a.Prompt,
+			Summary:  summary,
+		})
+	}
+	return rows
+}
+
+// agentRepo names the repository an agent works in: the checkout its
+// worktree came from, the top level of its dir, or the dir itself outside
+// git.
+func agentRepo(a *Agent) string {
+	if a.Worktree != nil {
+		return filepath.Base(a.Worktree.Repo)
+	}
+	if repo, err := repoToplevel(a.Dir); err == nil {
+		return filepath.Base(repo)
+	}
+	return filepath.Base(a.Dir)
+}
+
+// reportSections groups statuses for the markdown report, in order.
+var reportSections = []struct {
+	Title    string
+	Statuses []AgentStatus
+}{
+	{"Done", []AgentStatus{StatusDone}},
+	{"In progress", []AgentStatus{StatusRunning, StatusPending, StatusThrottled, StatusPaused}},
+	{"Waiting on you", []AgentStatus{StatusWaiting, StatusAsk, StatusIdle, StatusReview}},
+	{"Stuck or failed", []AgentStatus{StatusStuck, StatusTimeout, StatusError}},
+}
+
+// reportLineMax caps a task or summary in the markdown report.
+const reportLineMax = 300
+
+// writeMarkdownReport writes rows up as a standup-style markdown report
+// titled with title, one section per group of statuses.
+func writeMarkdownReport(w io.Writer, title string, rows []reportRow) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", title)
+	if len(rows) == 0 {
+		b.WriteString("\nNo agents.\n")
+	}
+	placed := make(map[int]bool, len(rows))
+	for _, sec := range reportSections {
+		var items []string
+		for i, r := range rows {
+			if placed[i] || !hasStatus(sec.Statuses, r.Status) {
+				continue
+			}
+			placed[i] = true
+			items = append(items, markdownReportItem(r))
+		}
+		if len(items) > 0 {
+			fmt.Fprintf(&b, "\n## %s\n\n%s", sec.Title, strings.Join(items, ""))
+		}
+	}
+	var other []string
+	for i, r := range rows {
+		if !placed[i] {
+			other = append(other, markdownReportItem(r))
+		}
+	}
+	if len(other) > 0 {
+		fmt.Fprintf(&b, "\n## Other\n\n%s", strings.Join(other, ""))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownReportItem is an agent's bullet in the markdown report.
+func markdownReportItem(r reportRow) string {
+	head := fmt.Sprintf("- **%s** (%s) · %s", r.Name, r.ID, r.Repo)
+	if r.Branch != "" {
+		head += fmt.Sprintf(" `%s`", r.Branch)
+	}
+	head += fmt.Sprintf(" · %s · %s\n", r.Status, formatStatDuration(r.Duration, true))
+	if r.Task != "" {
+		head += "  - Task: " + reportLine(r.Task) + "\n"
+	}
+	if r.Summary != "" {
+		head += "  - Latest: " + reportLine(r.Summary) + "\n"
+	}
+	return head
+}
+
+// reportLine flattens text onto one line for a markdown bullet, cut at
+// reportLineMax.
+func reportLine(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > reportLineMax {
+		s = string(r[:reportLineMax-1]) + "…"
+	}
+	return s
+}
+
+// hasStatus reports whether s is among statuses.
+func hasStatus(statuses []AgentStatus, s AgentStatus) bool {
+	for _, st := range statuses {
+		if st == s {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCSVReport writes rows as CSV with a header, durations in whole
+// seconds so spreadsheets can add them up.
+func writeCSVReport(w io.Writer, rows []reportRow) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"id", "name", "repo", "branch", "status", "duration_seconds", "task", "summary"})
+	for _, r := range rows {
+		_ = cw.Write([]string{r.ID, r.Name, r.Repo, r.Branch, string(r.Status), fmt.Sprint(int64(r.Duration.Seconds())), r.Task, r.Summary})
+	}
+	cw.Flush()
+	return cw.Error()
+}
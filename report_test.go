@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	agents := []*Agent{
+		{ID: "1", Name: "api", Dir: "/src/api", Status: StatusDone, CreatedAt: now.Add(-2 * time.Hour), StatusSince: now.Add(-time.Hour), Branch: "tickettok/agent-1-fix", Prompt: "Fix the flaky login test", Summary: "Fixed it,\nall tests pass."},
+		{ID: "2", Name: "web", Dir: "/src/web", Status: StatusRunning, CreatedAt: now.Add(-5 * time.Minute)},
+		{ID: "3", Name: "cli", Dir: "/src/cli", Status: StatusError, CreatedAt: now.Add(-10 * time.Minute), StatusSince: now.Add(-9 * time.Minute), Failure: "exit status 1"},
+		{ID: "4", Name: "docs", Dir: "/src/docs", Status: StatusWaiting, CreatedAt: now.Add(-time.Minute)},
+	}
+	rows := reportRows(agents, now, func(a *Agent) string { return strings.TrimPrefix(a.Dir, "/src/") })
+	if rows[0].Duration != time.Hour || rows[1].Duration != 5*time.Minute || rows[2].Duration != time.Minute {
+		t.Errorf("durations = %v, %v, %v: finished agents stop the clock", rows[0].Duration, rows[1].Duration, rows[2].Duration)
+	}
+	if rows[2].Summary != "exit status 1" {
+		t.Errorf("a failed agent's summary = %q, want why it failed", rows[2].Summary)
+	}
+
+	var md bytes.Buffer
+	if err := writeMarkdownReport(&md, "Agents", rows); err != nil {
+		t.Fatal(err)
+	}
+	out := md.String()
+	for _, want := range []string{
+		"# Agents\n",
+		"## Done\n\n- **api** (1) · api `tickettok/agent-1-fix` · DONE · 1h\n  - Task: Fix the flaky login test\n  - Latest: Fixed it, all tests pass.\n",
+		"## In progress\n\n- **web** (2) · web · RUNNING · 5m\n",
+		"## Waiting on you\n\n- **docs** (4)",
+		"## Stuck or failed\n\n- **cli** (3)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown report missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "## Done") > strings.Index(out, "## In progress") {
+		t.Error("sections out of order")
+	}
+
+	var buf bytes.Buffer
+	if err := writeCSVReport(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 || records[0][0] != "id" || records[1][5] != "3600" || records[1][7] != "Fixed it,\nall tests pass." {
+		t.Errorf("csv = %q", records)
+	}
+}
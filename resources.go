@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sns45/tickettok/ui"
+)
+
+// defaultMemoryWarnMB is the memory use past which a card warns, unless
+// the config says otherwise.
+const defaultMemoryWarnMB = 2048
+
+// Resources is what an agent's processes use: the CLI in its pane and
+// everything it has started.
+type Resources struct {
+	CPU float64 // percent of one core
+	RSS int64   // resident memory in bytes
+}
+
+// Add returns the sum of r and o.
+func (r Resources) Add(o Resources) Resources {
+	return Resources{CPU: r.CPU + o.CPU, RSS: r.RSS + o.RSS}
+}
+
+// Info converts the reading for rendering, warning past warnBytes (0 for
+// never).
+func (r Resources) Info(warnBytes int64) ui.ResourceInfo {
+	return ui.ResourceInfo{CPU: r.CPU, RSS: r.RSS, Warn: warnBytes > 0 && r.RSS > warnBytes}
+}
+
+// process is one line of ps output.
+type process struct {
+	ppid int
+	res  Resources
+}
+
+// parsePS reads `ps -A -o pid=,ppid=,pcpu=,rss=` output, RSS in KiB.
+func parsePS(out string) map[int]process {
+	procs := make(map[int]process)
+	for _, line := range strings.Split(out, "\n") {
+		f := strings.Fields(line)
+		if len(f) != 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(f[0])
+		ppid, err2 := strconv.Atoi(f[1])
+		cpu, err3 := strconv.ParseFloat(f[2], 64)
+		rss, err4 := strconv.ParseInt(f[3], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			continue
+		}
+		procs[pid] = process{ppid: ppid, res: Resources{CPU: cpu, RSS: rss * 1024}}
+	}
+	return procs
+}
+
+// treeResources sums what pid and all of its descendants use.
+func treeResources(procs map[int]process, pid int) Resources {
+	children := make(map[int][]int)
+	for p, proc := range procs {
+		children[proc.ppid] = append(children[proc.ppid], p)
+	}
+	var total Resources
+	for queue := []int{pid}; len(queue) > 0; queue = queue[1:] {
+		if proc, ok := procs[queue[0]]; ok {
+			total = total.Add(proc.res)
+		}
+		queue = append(queue, children[queue[0]]...)
+	}
+	return total
+}
+
+// sessionResources returns what each tmux session's pane processes use,
+// keyed by session name, from one ps and one tmux call.
+func sessionResources() map[string]Resources {
+	panes, err := exec.Command("tmux", "list-panes", "-a", "-F", "#{session_name} #{pane_pid}").Output()
+	if err != nil {
+		return nil
+	}
+	ps, err := exec.Command("ps", "-A", "-o", "pid=,ppid=,pcpu=,rss=").Output()
+	if err != nil {
+		return nil
+	}
+	procs := parsePS(string(ps))
+	res := make(map[string]Resources)
+	for _, line := range strings.Split(strings.TrimSpace(string(panes)), "\n") {
+		session, pid, ok := strings.Cut(line, " ")
+		if n, err := strconv.Atoi(pid); ok && err == nil {
+			res[session] = res[session].Add(treeResources(procs, n))
+		}
+	}
+	return res
+}
+
+// resourcesMsg carries a sessionResources reading back to the model.
+type resourcesMsg struct{ res map[string]Resources }
+
+// resourcesCmd reads the sessions' resource use in the background.
+func resourcesCmd() tea.Cmd {
+	return func() tea.Msg {
+		return resourcesMsg{res: sessionResources()}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestTreeResources(t *testing.T) {
+	procs := parsePS(`
+    1     0  0.0  1024
+  100     1  2.5  2048
+  101   100 40.0 512000
+  102   101  1.5  1000
+  200     1 99.0 900000
+garbage line
+`)
+	if len(procs) != 5 {
+		t.Fatalf("parsePS read %d processes, want 5", len(procs))
+	}
+	got := treeResources(procs, 100)
+	if got.CPU != 44 || got.RSS != (2048+512000+1000)*1024 {
+		t.Errorf("treeResources(100) = %+v", got)
+	}
+	if got := treeResources(procs, 999); got != (Resources{}) {
+		t.Errorf("treeResources(gone) = %+v, want zero", got)
+	}
+}
+
+func TestResourcesWarn(t *testing.T) {
+	r := Resources{CPU: 10, RSS: 3 << 30}
+	if !r.Info(Config{}.MemoryWarn()).Warn {
+		t.Error("3G not past the default limit")
+	}
+	if r.Info(Config{MemoryWarnMB: -1}.MemoryWarn()).Warn {
+		t.Error("warning not turned off")
+	}
+	if r.Info(Config{MemoryWarnMB: 4096}.MemoryWarn()).Warn {
+		t.Error("3G past a 4G limit")
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+// retryable reports whether an agent's task can be started over: it was
+// spawned here and has stopped working, whether it finished, failed, got
+// stuck or ran past its timeout.
+func retryable(a *Agent) bool {
+	if a.Discovered {
+		return false
+	}
+	switch a.Status {
+	case StatusDone, StatusError, StatusStuck, StatusTimeout, StatusIdle, StatusAsk:
+		return true
+	}
+	return false
+}
+
+// attempt is which try at its task an agent is on, 1 until it's retried.
+func attempt(a *Agent) int {
+	return a.Retries + 1
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestRetryable(t *testing.T) {
+	for _, tc := range []struct {
+		agent Agent
+		want  bool
+	}{
+		{Agent{Status: StatusDone}, true},
+		{Agent{Status: StatusError}, true},
+		{Agent{Status: StatusStuck}, true},
+		{Agent{Status: StatusIdle}, true},
+		{Agent{Status: StatusAsk}, true},
+		{Agent{Status: StatusRunning}, false},
+		{Agent{Status: StatusWaiting}, false},
+		{Agent{Status: StatusPending}, false},
+		{Agent{Status: StatusPaused}, false},
+		{Agent{Status: StatusDone, Discovered: true}, false},
+	} {
+		if got := retryable(&tc.agent); got != tc.want {
+			t.Errorf("retryable(%s, discovered=%v) = %v, want %v", tc.agent.Status, tc.agent.Discovered, got, tc.want)
+		}
+	}
+}
+
+func TestRecordRetry(t *testing.T) {
+	s := newTestStore(t)
+	a := s.Add("api", "/tmp")
+	a.SessionID = "conv-1"
+	a.Failure = "exit status 1"
+	a.Summary = "Gave up"
+	a.Nudges = 2
+
+	if n := s.RecordRetry(a.ID); n != 2 {
+		t.Errorf("first retry is attempt %d, want 2", n)
+	}
+	if a.SessionID != "" || a.Failure != "" || a.Summary != "" || a.Nudges != 0 {
+		t.Errorf("retry kept the last attempt's state: %+v", a)
+	}
+	if n := s.RecordRetry(a.ID); n != 3 {
+		t.Errorf("second retry is attempt %d, want 3", n)
+	}
+	if n := s.RecordRetry("nope"); n != 0 {
+		t.Errorf("unknown agent: attempt %d, want 0", n)
+	}
+}
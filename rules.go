@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Approval rule actions.
+const (
+	RuleApprove = "approve" // pick the prompt's first option
+	RuleDeny    = "deny"    // pick its "No" option
+	RuleAsk     = "ask"     // leave the prompt for a human
+)
+
+// ApprovalRule answers permission prompts for one tool, optionally only
+// when the request mentions a pattern. Rules are written as text:
+// "approve Read", "ask Bash rm", "deny * curl".
+type ApprovalRule struct {
+	Action  string
+	Tool    string // first word of the prompt's heading, "*" for any
+	Pattern string // words the request must contain, "" for any
+	re      *regexp.Regexp
+}
+
+// parseRule reads a rule written as "<action> <tool> [pattern]".
+func parseRule(s string) (ApprovalRule, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 2 {
+		return ApprovalRule{}, fmt.Errorf("rule %q: want <approve|deny|ask> <tool> [pattern]", s)
+	}
+	r := ApprovalRule{Action: strings.ToLower(fields[0]), Tool: fields[1], Pattern: strings.Join(fields[2:], " ")}
+	switch r.Action {
+	case RuleApprove, RuleDeny, RuleAsk:
+	default:
+		return ApprovalRule{}, fmt.Errorf("rule %q: unknown action %q (want approve, deny, or ask)", s, fields[0])
+	}
+	if r.Pattern != "" {
+		// Whole words only, so "rm" doesn't match "format"
+		r.re = regexp.MustCompile(`(?i)(^|[^\w-])` + regexp.QuoteMeta(r.Pattern) + `($|[^\w-])`)
+	}
+	return r, nil
+}
+
+// parseRules reads a list of rules, stopping at the first bad one.
+func parseRules(list []string) ([]ApprovalRule, error) {
+	var rules []ApprovalRule
+	for _, s := range list {
+		r, err := parseRule(s)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// splitRules splits rules typed on one line, separated by semicolons.
+func splitRules(s string) []string {
+	var out []string
+	for _, r := range strings.Split(s, ";") {
+		if r = strings.Join(strings.Fields(r), " "); r != "" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (r ApprovalRule) String() string {
+	return strings.TrimSpace(r.Action + " " + r.Tool + " " + r.Pattern)
+}
+
+// PermissionRequest is what a permission prompt asks to do.
+type PermissionRequest struct {
+	Tool string // first word of the heading, e.g. Bash, Read or Edit
+	Text string // the heading and details shown with it
+}
+
+// Summary is a one-line description of the request for the event log.
+func (p PermissionRequest) Summary() string {
+	lines := strings.Split(p.Text, "\n")
+	s := lines[0]
+	if len(lines) > 1 {
+		s += ": " + lines[1]
+	}
+	if r := []rune(s); len(r) > 80 {
+		s = string(r[:79]) + "…"
+	}
+	return s
+}
+
+func (r ApprovalRule) matches(req PermissionRequest) bool {
+	if r.Tool != "*" && !strings.EqualFold(r.Tool, req.Tool) {
+		return false
+	}
+	return r.re == nil || r.re.MatchString(req.Text)
+}
+
+// matchRule returns the first rule that covers req.
+func matchRule(rules []ApprovalRule, req PermissionRequest) (ApprovalRule, bool) {
+	for _, r := range rules {
+		if r.matches(req) {
+			return r, true
+		}
+	}
+	return ApprovalRule{}, false
+}
+
+// requestScanLines is how far above a prompt's options its heading can be.
+const requestScanLines = 10
+
+// parsePermissionRequest reads the permission prompt at the bottom of plain
+// pane content: what it asks for and the options it offers. The request is
+// the block above the options, up to a box edge or horizontal rule; its
+// first line is the heading.
+func parsePermissionRequest(content string) (PermissionRequest, []PromptOption, bool) {
+	opts := parsePromptOptions(content)
+	if opts == nil {
+		return PermissionRequest{}, nil, false
+	}
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	first := len(lines)
+	for i := len(lines) - 1; i >= 0 && i >= len(lines)-promptScanLines; i-- {
+		if m := promptOptionRe.FindStringSubmatch(lines[i]); m != nil && m[1] == "1" {
+			first = i
+			break
+		}
+	}
+
+	var block []string
+	for i := first - 1; i >= 0 && first-i <= requestScanLines; i-- {
+		raw := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(raw, "╭") || strings.HasPrefix(raw, "┌") || isSeparatorLine(raw) {
+			break
+		}
+		block = append([]string{strings.TrimSpace(strings.Trim(raw, "│"))}, block...)
+	}
+
+	var text []string
+	for _, l := range block {
+		if l == "" || len(text) > 0 && strings.HasSuffix(l, "?") {
+			continue
+		}
+		text = append(text, l)
+	}
+	if len(text) == 0 {
+		return PermissionRequest{}, nil, false
+	}
+	tool := strings.FieldsFunc(text[0], func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	if len(tool) == 0 {
+		return PermissionRequest{}, nil, false
+	}
+	return PermissionRequest{Tool: tool[0], Text: strings.Join(text, "\n")}, opts, true
+}
+
+// ruleAnswer returns the key that carries out rule on a prompt with opts,
+// and what it did. ok is false for ask rules.
+func ruleAnswer(rule ApprovalRule, opts []PromptOption) (key, did string, ok bool) {
+	switch rule.Action {
+	case RuleApprove:
+		return opts[0].Key, "approved", true
+	case RuleDeny:
+		for _, o := range opts {
+			if strings.HasPrefix(strings.ToLower(o.Label), "no") {
+				return o.Key, "denied", true
+			}
+		}
+		return "Escape", "denied", true
+	}
+	return "", "", false
+}
@@ -0,0 +1,126 @@
+package main
+
+import "testing"
+
+func TestParseRule(t *testing.T) {
+	r, err := parseRule("Approve  Bash git   status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Action != RuleApprove || r.Tool != "Bash" || r.Pattern != "git status" {
+		t.Errorf("got %+v", r)
+	}
+	if r.String() != "approve Bash git status" {
+		t.Errorf("String() = %q", r.String())
+	}
+	for _, bad := range []string{"", "approve", "allow Read"} {
+		if _, err := parseRule(bad); err == nil {
+			t.Errorf("parseRule(%q) accepted", bad)
+		}
+	}
+}
+
+func TestSplitRules(t *testing.T) {
+	got := splitRules(" approve Read ;; deny  Bash rm;")
+	if len(got) != 2 || got[0] != "approve Read" || got[1] != "deny Bash rm" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRuleMatchesWholeWords(t *testing.T) {
+	rules, err := parseRules([]string{"deny Bash rm", "approve *"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Bash command\nrm -rf build", RuleDeny},
+		{"Bash command\ncd app && RM old.txt", RuleDeny},
+		{"Bash command\ngofmt -w format.go", RuleApprove},
+		{"Bash command\nnpm run rm-cache", RuleApprove},
+	}
+	for _, tt := range tests {
+		r, ok := matchRule(rules, PermissionRequest{Tool: "Bash", Text: tt.text})
+		if !ok || r.Action != tt.want {
+			t.Errorf("%q matched %v (%v), want %s", tt.text, r, ok, tt.want)
+		}
+	}
+	if _, ok := matchRule(rules[:1], PermissionRequest{Tool: "Edit", Text: "Edit file\nrm.go"}); ok {
+		t.Error("Bash rule matched an Edit request")
+	}
+}
+
+func TestParsePermissionRequest(t *testing.T) {
+	content := "● Cleaning up.\n" +
+		"╭──────────────────────────────────────╮\n" +
+		"│ Bash command                         │\n" +
+		"│                                      │\n" +
+		"│   rm -rf build                       │\n" +
+		"│   Remove the build output            │\n" +
+		"│                                      │\n" +
+		"│ Do you want to proceed?              │\n" +
+		"│ ❯ 1. Yes                             │\n" +
+		"│   2. Yes, and don't ask again        │\n" +
+		"│   3. No, and tell Claude what to do  │\n" +
+		"╰──────────────────────────────────────╯\n"
+	req, opts, ok := parsePermissionRequest(content)
+	if !ok {
+		t.Fatal("no request found")
+	}
+	if req.Tool != "Bash" {
+		t.Errorf("Tool = %q, want Bash", req.Tool)
+	}
+	if want := "Bash command\nrm -rf build\nRemove the build output"; req.Text != want {
+		t.Errorf("Text = %q, want %q", req.Text, want)
+	}
+	if req.Summary() != "Bash command: rm -rf build" {
+		t.Errorf("Summary() = %q", req.Summary())
+	}
+	if len(opts) != 3 {
+		t.Fatalf("got %d options", len(opts))
+	}
+
+	// Unboxed prompts stop at a horizontal rule
+	content = "old output\n────────────────────\nRead file\n  src/main.go\n\n❯ 1. Yes\n  2. No\n"
+	if req, _, ok := parsePermissionRequest(content); !ok || req.Tool != "Read" || req.Text != "Read file\nsrc/main.go" {
+		t.Errorf("got %+v, %v", req, ok)
+	}
+
+	if _, _, ok := parsePermissionRequest("Working...\n> "); ok {
+		t.Error("found a request without a prompt")
+	}
+}
+
+func TestRuleAnswer(t *testing.T) {
+	opts := []PromptOption{{Key: "1", Label: "Yes"}, {Key: "2", Label: "Always"}, {Key: "3", Label: "No, and tell Claude"}}
+	rule := func(s string) ApprovalRule {
+		r, _ := parseRule(s)
+		return r
+	}
+	if key, did, ok := ruleAnswer(rule("approve Read"), opts); !ok || key != "1" || did != "approved" {
+		t.Errorf("approve: %q %q %v", key, did, ok)
+	}
+	if key, _, ok := ruleAnswer(rule("deny Bash"), opts); !ok || key != "3" {
+		t.Errorf("deny: %q %v", key, ok)
+	}
+	if key, _, _ := ruleAnswer(rule("deny Bash"), opts[:2]); key != "Escape" {
+		t.Errorf("deny without a No option: %q", key)
+	}
+	if _, _, ok := ruleAnswer(rule("ask Bash"), opts); ok {
+		t.Error("ask answered the prompt")
+	}
+}
+
+func TestRuleAnswerHidesOnlyThatAgentsAlert(t *testing.T) {
+	// Two agents in one repo, named after it; a rule answered the first
+	transitions := []statusTransition{
+		{"1", "shop", StatusRunning, StatusWaiting},
+		{"2", "shop", StatusRunning, StatusWaiting},
+	}
+	kept := needingAttention(transitions, map[string]bool{"1": true}, nil)
+	if len(kept) != 1 || kept[0].id != "2" {
+		t.Errorf("needingAttention = %+v, want only agent 2's prompt", kept)
+	}
+}
@@ -1,20 +1,22 @@
 #!/bin/bash
 # Claude Code hook for TicketTok reactive state detection.
 # Reads JSON on stdin, checks if running inside a tickettok tmux session,
-# writes status to ~/.tickettok/status/<agent_id>.json
+# writes status to $TICKETTOK_HOME/status/<agent_id>.json (~/.tickettok by default)
 
 set -euo pipefail
 
 INPUT=$(cat)
 EVENT=$(echo "$INPUT" | jq -r '.hook_event_name // empty')
 NTYPE=$(echo "$INPUT" | jq -r '.notification_type // empty')
+TRANSCRIPT=$(echo "$INPUT" | jq -r '.transcript_path // empty')
+SESSION_ID=$(echo "$INPUT" | jq -r '.session_id // empty')
 
 # Only act inside tickettok-managed tmux sessions
 SESS=$(tmux display-message -p '#{session_name}' 2>/dev/null || true)
 [[ "$SESS" == tickettok_* ]] || exit 0
 
 AGENT_ID="${SESS#tickettok_}"
-STATUS_DIR="$HOME/.tickettok/status"
+STATUS_DIR="${TICKETTOK_HOME:-$HOME/.tickettok}/status"
 mkdir -p "$STATUS_DIR"
 STATUS_FILE="$STATUS_DIR/${AGENT_ID}.json"
 
@@ -37,5 +39,8 @@ esac
 
 # Atomic write
 TMP=$(mktemp "$STATUS_DIR/.tmp.XXXXXX")
-echo "{\"state\":\"$STATE\",\"ts\":$(date +%s)}" > "$TMP"
+jq -nc --arg state "$STATE" --argjson ts "$(date +%s)" --arg transcript "$TRANSCRIPT" --arg session "$SESSION_ID" \
+  '{state: $state, ts: $ts}
+   + (if $transcript != "" then {transcript: $transcript} else {} end)
+   + (if $session != "" then {session: $session} else {} end)' > "$TMP"
 mv "$TMP" "$STATUS_FILE"
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
@@ -12,70 +13,229 @@ import (
 type AgentStatus string
 
 const (
-	StatusRunning AgentStatus = "RUNNING"
-	StatusIdle    AgentStatus = "IDLE"
-	StatusWaiting AgentStatus = "WAITING"
-	StatusDone    AgentStatus = "DONE"
-	StatusError   AgentStatus = "STUCK"
+	StatusRunning   AgentStatus = "RUNNING"
+	StatusIdle      AgentStatus = "IDLE"
+	StatusWaiting   AgentStatus = "WAITING"
+	StatusAsk       AgentStatus = "ASK" // IDLE on an open question to the user
+	StatusDone      AgentStatus = "DONE"
+	StatusStuck     AgentStatus = "STUCK"        // RUNNING with no sign of life for 10 minutes
+	StatusError     AgentStatus = "ERROR"        // crashed or exited abnormally
+	StatusPending   AgentStatus = "PENDING"      // queued until fewer than max_running agents are RUNNING
+	StatusTimeout   AgentStatus = "TIMED-OUT"    // still working past its Timeout
+	StatusPaused    AgentStatus = "PAUSED"       // processes frozen with SIGSTOP until resumed
+	StatusThrottled AgentStatus = "THROTTLED"    // hit a provider rate limit; held until ResetAt
+	StatusReview    AgentStatus = "NEEDS-REVIEW" // marked by hand: output still to be read
 )
 
 type Agent struct {
-	ID          string      `json:"id"`
-	Name        string      `json:"name"`
-	Dir         string      `json:"dir"`
-	Status      AgentStatus `json:"status"`
-	CreatedAt   time.Time   `json:"created_at"`
-	StatusSince time.Time   `json:"status_since"`
-	SessionName string      `json:"session_name,omitempty"`
-	Discovered  bool        `json:"discovered,omitempty"`
-	BackendID   string      `json:"backend,omitempty"`
-	AutoApprove bool        `json:"auto_approve,omitempty"`
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Dir         string         `json:"dir"`
+	Status      AgentStatus    `json:"status"`
+	CreatedAt   time.Time      `json:"created_at"`
+	StatusSince time.Time      `json:"status_since"`
+	SessionName string         `json:"session_name,omitempty"`
+	SessionID   string         `json:"session_id,omitempty"` // backend conversation ID for --resume, not the tmux session
+	Discovered  bool           `json:"discovered,omitempty"`
+	BackendID   string         `json:"backend,omitempty"`
+	AutoApprove bool           `json:"auto_approve,omitempty"`
+	Prompt      string         `json:"prompt,omitempty"`     // initial task sent at spawn
+	Stream      bool           `json:"stream,omitempty"`     // running headless with a stream-json event log
+	After       string         `json:"after,omitempty"`      // ID of the agent whose finish releases Prompt
+	Race        string         `json:"race,omitempty"`       // ID shared by agents racing the same prompt on different backends
+	Swarm       string         `json:"swarm,omitempty"`      // name of the swarm manifest it was started from
+	PromptAt    time.Time      `json:"prompt_at,omitempty"`  // when Prompt was (or will be) typed in
+	KeepAlive   bool           `json:"keep_alive,omitempty"` // respawn automatically if the session dies
+	Checkpoint  bool           `json:"checkpoint,omitempty"` // commit its changes when it goes IDLE or DONE
+	Priority    string         `json:"priority,omitempty"`   // PriorityHigh or PriorityLow, "" for normal
+	Timeout     time.Duration  `json:"timeout,omitempty"`    // max runtime, 0 for none
+	Detached    bool           `json:"detached,omitempty"`   // session left running unwatched by idle shutdown
+	Rules       []string       `json:"rules,omitempty"`      // approval rules, checked before the global ones
+	Nudge       *Nudge         `json:"nudge,omitempty"`      // carry on by itself when it stops with work left
+	Nudges      int            `json:"nudges,omitempty"`     // nudges sent since it was last given work
+	ResetAt     time.Time      `json:"reset_at,omitempty"`   // when the rate limit of a THROTTLED agent resets
+	Failure     string         `json:"failure,omitempty"`    // why it went ERROR, e.g. "exit status 1: panic: …"
+	ExitReason  string         `json:"exit,omitempty"`       // why it went DONE, e.g. "process exited"
+	Summary     string         `json:"summary,omitempty"`    // its last message when it last went IDLE or DONE
+	Restarts    int            `json:"restarts,omitempty"`   // keep-alive restarts, see recentRestarts
+	Retries     int            `json:"retries,omitempty"`    // times its task was started over in a fresh session
+	RestartAt   time.Time      `json:"restart_at,omitempty"` // last keep-alive restart
+	Worktree    *Worktree      `json:"worktree,omitempty"`   // checkout of its own, when spawned isolated
+	Branch      string         `json:"branch,omitempty"`     // branch created for it at spawn
+	Tags        []string       `json:"tags,omitempty"`
+	Pin         string         `json:"pin,omitempty"`  // board column title overriding status placement
+	Note        string         `json:"note,omitempty"` // free-text reminder edited from the TUI
+	History     []StatusChange `json:"history,omitempty"`
+	Tally       *AgentStats    `json:"tally,omitempty"` // stats of history trimmed off, see trimHistory
+	Sent        []SentPrompt   `json:"sent,omitempty"`  // messages typed into it, oldest first
+}
+
+// StatusChange records when an agent entered a status.
+type StatusChange struct {
+	Status AgentStatus `json:"status"`
+	At     time.Time   `json:"at"`
+}
+
+// maxStatusHistory caps how many status changes are kept per agent.
+const maxStatusHistory = 20
+
+// SentPrompt records a message typed into an agent: its task, something
+// sent from the send dialog or a broadcast, a nudge and so on.
+type SentPrompt struct {
+	At   time.Time `json:"at"`
+	Via  string    `json:"via"` // what sent it, e.g. "task" or "send"
+	Text string    `json:"text"`
+}
+
+// maxSentPrompts caps how many sent messages are kept per agent.
+const maxSentPrompts = 50
+
+// statusBefore returns the status an agent had when it last entered s, or
+// fallback if the history doesn't say.
+func statusBefore(a *Agent, s, fallback AgentStatus) AgentStatus {
+	for i := len(a.History) - 1; i > 0; i-- {
+		if a.History[i].Status == s {
+			return a.History[i-1].Status
+		}
+	}
+	return fallback
+}
+
+// Ended reports whether the agent's session has finished, cleanly (DONE)
+// or not (ERROR).
+func (a *Agent) Ended() bool {
+	return a.Status == StatusDone || a.Status == StatusError
+}
+
+// HasTag reports whether the agent carries tag, ignoring case and a leading '#'.
+func (a *Agent) HasTag(tag string) bool {
+	tag = strings.TrimPrefix(tag, "#")
+	for _, t := range a.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTags trims tags, strips a leading '#', splits on commas, and drops
+// empties and case-insensitive duplicates while keeping first-seen order.
+func normalizeTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, raw := range tags {
+		for _, t := range strings.Split(raw, ",") {
+			t = strings.TrimPrefix(strings.TrimSpace(t), "#")
+			if t == "" || seen[strings.ToLower(t)] {
+				continue
+			}
+			seen[strings.ToLower(t)] = true
+			out = append(out, t)
+		}
+	}
+	return out
 }
 
 type StateFile struct {
-	Agents []*Agent `json:"agents"`
+	Agents       []*Agent              `json:"agents"`
+	RecentDirs   []string              `json:"recent_dirs,omitempty"`   // newest first
+	FavoriteDirs []string              `json:"favorite_dirs,omitempty"` // starred in the spawn dialog
+	ColumnPrefs  map[string]ColumnPref `json:"column_prefs,omitempty"`  // keyed by column title
+	Branches     []AgentBranch         `json:"branches,omitempty"`      // created for agents, until pruned
 }
 
+// ColumnPref is the user's per-column board view setting.
+type ColumnPref struct {
+	Collapsed bool `json:"collapsed,omitempty"`
+	Weight    int  `json:"weight,omitempty"` // relative width, 0 = default (1)
+}
+
+// maxRecentDirs caps how many spawn directories are remembered.
+const maxRecentDirs = 10
+
 type Store struct {
-	mu       sync.RWMutex
-	path     string
-	agents   []*Agent
-	nextID   int
+	mu           sync.RWMutex
+	path         string
+	agents       []*Agent
+	nextID       int
+	recentDirs   []string
+	favoriteDirs []string
+	columnPrefs  map[string]ColumnPref
+	branches     []AgentBranch
+	synced       syncedState // the state file as this store last saw it
+	syncedFile   os.FileInfo // the state file as this store last saw it, see stateFileChanged
+	idPrefix     string      // starts agent IDs on a named board, see boardIDPrefix
+
+	deferSaves bool // saves are held for Flush, see DeferSaves
+	dirty      bool // changed since it was last written out
 }
 
+// stateSaveDelay is how often the TUI writes out the changes it holds, so
+// those made meanwhile go out in the same write.
+const stateSaveDelay = time.Second
+
+// stateDirEnv names the environment variable that moves tickettok's state
+// out of ~/.tickettok. --state-dir sets it, so agents' hook scripts and
+// anything else tickettok starts follow.
+const stateDirEnv = "TICKETTOK_HOME"
+
+// stateDir is where tickettok keeps its state, config, hook scripts and
+// hook status files: $TICKETTOK_HOME, or ~/.tickettok.
 func stateDir() string {
+	if dir := os.Getenv(stateDirEnv); dir != "" {
+		return expandTilde(dir)
+	}
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, ".tickettok")
 }
 
+// takeStateDirFlag removes `--state-dir <dir>` from args and points
+// $TICKETTOK_HOME at the directory, made absolute as agents run elsewhere.
+func takeStateDirFlag(args []string) ([]string, error) {
+	args, dir, err := takeFlag(args, "--state-dir")
+	if err != nil || dir == "" {
+		return args, err
+	}
+	abs, err := filepath.Abs(expandTilde(dir))
+	if err != nil {
+		return nil, err
+	}
+	return args, os.Setenv(stateDirEnv, abs)
+}
+
 func statePath() string {
-	return filepath.Join(stateDir(), "state.json")
+	return filepath.Join(boardDir(), "state.json")
 }
 
 func NewStore() (*Store, error) {
-	dir := stateDir()
+	dir := boardDir()
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("create state dir: %w", err)
 	}
 
 	s := &Store{
-		path:   statePath(),
-		nextID: 1,
+		path:     statePath(),
+		nextID:   1,
+		idPrefix: boardIDPrefix(),
 	}
 
 	if err := s.load(); err != nil && !os.IsNotExist(err) {
 		return nil, fmt.Errorf("load state: %w", err)
 	}
 
-	// Set nextID based on existing agents
+	s.syncNextID()
+
+	return s, nil
+}
+
+// syncNextID bumps nextID past the highest numeric agent ID.
+func (s *Store) syncNextID() {
 	for _, a := range s.agents {
 		var id int
-		if _, err := fmt.Sscanf(a.ID, "%d", &id); err == nil && id >= s.nextID {
+		if _, err := fmt.Sscanf(strings.TrimPrefix(a.ID, s.idPrefix), "%d", &id); err == nil && id >= s.nextID {
 			s.nextID = id + 1
 		}
 	}
-
-	return s, nil
 }
 
 func (s *Store) load() error {
@@ -87,52 +247,230 @@ func (s *Store) load() error {
 	if err := json.Unmarshal(data, &sf); err != nil {
 		return fmt.Errorf("parse state: %w", err)
 	}
+	s.apply(sf)
+	s.synced = snapshot(sf)
+	if info, err := os.Stat(s.path); err == nil {
+		s.syncedFile = info
+	}
+	return nil
+}
+
+// apply replaces the store's state with sf's.
+func (s *Store) apply(sf StateFile) {
 	s.agents = sf.Agents
 	if s.agents == nil {
 		s.agents = []*Agent{}
 	}
+	s.recentDirs = sf.RecentDirs
+	s.favoriteDirs = sf.FavoriteDirs
+	s.columnPrefs = sf.ColumnPrefs
+	s.branches = sf.Branches
 	// Migrate: default empty BackendID to "claude"
 	for _, a := range s.agents {
 		if a.BackendID == "" {
 			a.BackendID = "claude"
 		}
 	}
-	return nil
 }
 
-// Save persists the current state to disk.
+// stateFile is the store's state as it's written to disk.
+func (s *Store) stateFile() StateFile {
+	return StateFile{Agents: s.agents, RecentDirs: s.recentDirs, FavoriteDirs: s.favoriteDirs, ColumnPrefs: s.columnPrefs, Branches: s.branches}
+}
+
+// Reload re-reads state from disk, picking up changes made by other processes.
+func (s *Store) Reload() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Save persists the current state to disk, merged with other processes'
+// changes to it.
 func (s *Store) Save() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	_ = s.save()
 }
 
+// save writes the store out, or with saves deferred, marks it to be
+// written out at the next Flush along with whatever else changes meanwhile.
 func (s *Store) save() error {
-	sf := StateFile{Agents: s.agents}
-	data, err := json.MarshalIndent(sf, "", "  ")
+	if !s.deferSaves {
+		return s.commit(nil)
+	}
+	s.dirty = true
+	return nil
+}
+
+// DeferSaves holds changes for Flush rather than writing each as it's
+// made. Writing marshals the agents, so Flush is the caller's to schedule
+// on the goroutine that changes their fields — for the TUI, its update
+// loop — and to call once more before exiting.
+func (s *Store) DeferSaves() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deferSaves = true
+}
+
+// Flush writes out any changes a deferred save is holding.
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.dirty {
+		return nil
+	}
+	return s.commit(nil)
+}
+
+// Sync folds in what other processes wrote to the state file since the
+// store last read or wrote it, if anything, leaving changes it holds to be
+// written as usual. Writing only merges in other processes' changes, so a
+// store that's idle — or defers its saves — calls this to keep up. It
+// reports whether there was anything to fold in, and why not if the file
+// couldn't be read.
+func (s *Store) Sync() (bool, error) {
+	if !s.Changed() {
+		return false, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.pull(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Changed reports whether another process has written the state file since
+// the store last read or wrote it.
+func (s *Store) Changed() bool {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return stateFileChanged(s.syncedFile, info)
+}
+
+// stateFileChanged tells whether the state file now is another write than
+// was. Every write renames a new file into place, so the inode changes;
+// the mtime and size catch the inode number being reused.
+func stateFileChanged(was, now os.FileInfo) bool {
+	return was == nil || !os.SameFile(was, now) || !was.ModTime().Equal(now.ModTime()) || was.Size() != now.Size()
+}
+
+// Export serializes the full agent list in the state.json format.
+func (s *Store) Export() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, err := json.MarshalIndent(StateFile{Agents: s.agents}, "", "  ")
 	if err != nil {
-		return fmt.Errorf("marshal state: %w", err)
+		return nil, fmt.Errorf("marshal state: %w", err)
+	}
+	return data, nil
+}
+
+// Import loads agents from an exported state file. With replace, the current
+// agent list is swapped out wholesale (IDs and session names kept, for restoring
+// a backup). Otherwise agents are appended under fresh IDs with their session
+// cleared and status DONE, so they resume via the backend on next zoom; links
+// between them follow the new IDs, and worktrees and branches are left behind.
+// Returns the number of agents imported.
+func (s *Store) Import(data []byte, replace bool) (int, error) {
+	var sf StateFile
+	if err := json.Unmarshal(data, &sf); err != nil {
+		return 0, fmt.Errorf("parse import: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range sf.Agents {
+		if a.BackendID == "" {
+			a.BackendID = "claude"
+		}
 	}
-	return os.WriteFile(s.path, data, 0644)
+
+	if replace {
+		return len(sf.Agents), s.commit(func() {
+			s.agents = sf.Agents
+			if s.agents == nil {
+				s.agents = []*Agent{}
+			}
+			s.syncNextID()
+		})
+	}
+
+	now := time.Now()
+	// IDs are handed out under the lock, past other processes' agents
+	return len(sf.Agents), s.commit(func() {
+		ids := make(map[string]string, len(sf.Agents)) // old ID -> new
+		for _, a := range sf.Agents {
+			id := fmt.Sprintf("%s%d", s.idPrefix, s.nextID)
+			s.nextID++
+			ids[a.ID] = id
+			a.ID = id
+		}
+		races := make(map[string]string) // races whose first agent wasn't exported
+		for _, a := range sf.Agents {
+			// an After outside the file would wait on whichever local agent
+			// has that ID now
+			a.After = ids[a.After]
+			if a.Race != "" {
+				if id, ok := ids[a.Race]; ok {
+					a.Race = id
+				} else {
+					if races[a.Race] == "" {
+						races[a.Race] = a.ID
+					}
+					a.Race = races[a.Race]
+				}
+			}
+			// the worktree and branch belong to the exporting checkout; a
+			// merged copy resumes in Dir without claiming them
+			a.Worktree = nil
+			a.Branch = ""
+			a.SessionName = ""
+			a.Discovered = false
+			a.Status = StatusDone
+			a.StatusSince = now
+			s.agents = append(s.agents, a)
+		}
+	})
 }
 
+// Add creates an agent on the default backend.
 func (s *Store) Add(name, dir string) *Agent {
+	return s.AddWithBackend(name, dir, DefaultBackend().ID())
+}
+
+// AddWithBackend creates an agent recording backendID, falling back to the
+// default backend when backendID is empty or unknown.
+func (s *Store) AddWithBackend(name, dir, backendID string) *Agent {
+	if backendID == "" || GetBackend(backendID) == nil {
+		backendID = DefaultBackend().ID()
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	now := time.Now()
 	a := &Agent{
-		ID:          fmt.Sprintf("%d", s.nextID),
 		Name:        name,
 		Dir:         dir,
 		Status:      StatusRunning,
 		CreatedAt:   now,
 		StatusSince: now,
-		BackendID:   DefaultBackend().ID(),
+		BackendID:   backendID,
 	}
-	s.nextID++
-	s.agents = append(s.agents, a)
-	_ = s.save()
+	// The ID is handed out under the lock, past other processes' agents
+	_ = s.commit(func() {
+		a.ID = fmt.Sprintf("%s%d", s.idPrefix, s.nextID)
+		s.nextID++
+		s.agents = append(s.agents, a)
+	})
 	return a
 }
 
@@ -157,101 +495,746 @@ func (s *Store) Update(id string, status AgentStatus) {
 	for _, a := range s.agents {
 		if a.ID == id {
 			if a.Status != status {
+				if status != StatusDone {
+					a.ExitReason = ""
+				}
 				a.Status = status
 				a.StatusSince = time.Now()
+				a.History = append(a.History, StatusChange{Status: status, At: a.StatusSince})
+				trimHistory(a)
+				_ = s.save()
 			}
-			break
+			return
 		}
 	}
-	_ = s.save()
 }
 
-func (s *Store) UpdateSessionName(id string, sessName string) {
+// Rename changes an agent's display name. Returns false if the agent is not found.
+func (s *Store) Rename(id, name string) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	for _, a := range s.agents {
 		if a.ID == id {
-			a.SessionName = sessName
-			break
+			a.Name = name
+			_ = s.save()
+			return true
 		}
 	}
-	_ = s.save()
+	return false
 }
 
-func (s *Store) Get(id string) *Agent {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// SetTags replaces an agent's tags. Returns false if the agent doesn't exist.
+func (s *Store) SetTags(id string, tags []string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	for _, a := range s.agents {
 		if a.ID == id {
-			return a
+			a.Tags = normalizeTags(tags)
+			_ = s.save()
+			return true
 		}
 	}
-	return nil
+	return false
 }
 
-func (s *Store) GetByName(name string) *Agent {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// SetNote replaces an agent's note. Returns false if the agent doesn't exist.
+func (s *Store) SetNote(id, note string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	for _, a := range s.agents {
-		if a.Name == name {
-			return a
+		if a.ID == id {
+			a.Note = strings.TrimSpace(note)
+			_ = s.save()
+			return true
 		}
 	}
-	return nil
+	return false
 }
 
-func (s *Store) List() []*Agent {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// SetBackend moves an agent to another backend. The old backend's session
+// ID means nothing to the new one, so it is dropped.
+func (s *Store) SetBackend(id, backendID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	out := make([]*Agent, len(s.agents))
-	copy(out, s.agents)
-	return out
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.BackendID = backendID
+			a.SessionID = ""
+			_ = s.save()
+			return true
+		}
+	}
+	return false
 }
 
-func (s *Store) UpdateDiscovered(id string, discovered bool) {
+// SetSessionID records the backend conversation an agent is in.
+func (s *Store) SetSessionID(id, sessionID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.SessionID = sessionID
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// SetAfter makes an agent wait for another before its prompt is sent; an
+// empty after releases it.
+func (s *Store) SetAfter(id, after string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.After = after
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// MarkPromptSent records when an agent's initial prompt goes in, logging
+// it among the messages sent to it.
+func (s *Store) MarkPromptSent(id string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.PromptAt = at
+			if a.Prompt != "" {
+				logSent(a, at, "task", a.Prompt)
+			}
+			_ = s.save()
+			return
+		}
+	}
+}
+
+// LogPrompt records a message sent to an agent now, via the given route.
+func (s *Store) LogPrompt(id, via, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			logSent(a, time.Now(), via, text)
+			_ = s.save()
+			return
+		}
+	}
+}
+
+// logSent appends a message to the agent's sent log, dropping the oldest
+// past maxSentPrompts.
+func logSent(a *Agent, at time.Time, via, text string) {
+	a.Sent = append(a.Sent, SentPrompt{At: at, Via: via, Text: text})
+	if n := len(a.Sent) - maxSentPrompts; n > 0 {
+		a.Sent = a.Sent[n:]
+	}
+}
+
+// SetWorktree records the worktree an agent was given and moves the agent
+// into dir inside it.
+func (s *Store) SetWorktree(id string, wt *Worktree, dir string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Worktree = wt
+			a.Dir = dir
+			_ = s.save()
+			return
+		}
+	}
+}
+
+// SetBranch records the branch created for an agent. The branch is also
+// remembered apart from the agent, for prune to delete once it's merged.
+func (s *Store) SetBranch(id string, b AgentBranch) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	for _, a := range s.agents {
 		if a.ID == id {
-			a.Discovered = discovered
-			break
+			a.Branch = b.Name
 		}
 	}
+	s.branches = append(s.branches, b)
 	_ = s.save()
 }
 
-// Backend returns the Backend for this agent, falling back to the default.
-func (a *Agent) Backend() Backend {
-	if b := GetBackend(a.BackendID); b != nil {
-		return b
+// Branches returns the branches created for agents that haven't been
+// pruned yet.
+func (s *Store) Branches() []AgentBranch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]AgentBranch(nil), s.branches...)
+}
+
+// ForgetBranch drops a branch from the ones prune looks at.
+func (s *Store) ForgetBranch(b AgentBranch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, have := range s.branches {
+		if have == b {
+			s.branches = append(s.branches[:i:i], s.branches[i+1:]...)
+			_ = s.save()
+			return
+		}
 	}
-	return DefaultBackend()
 }
 
-func (s *Store) ClearDone() int {
+// SetCheckpoint turns checkpoint commits on or off for an agent.
+func (s *Store) SetCheckpoint(id string, on bool) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	var kept []*Agent
-	removed := 0
 	for _, a := range s.agents {
-		if a.Status == StatusDone {
-			removed++
-		} else {
-			kept = append(kept, a)
+		if a.ID == id {
+			a.Checkpoint = on
+			_ = s.save()
+			return true
 		}
 	}
-	if removed > 0 {
-		s.agents = kept
-		if s.agents == nil {
-			s.agents = []*Agent{}
+	return false
+}
+
+// SetTimeout sets an agent's max runtime; 0 removes it.
+func (s *Store) SetTimeout(id string, d time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Timeout = d
+			_ = s.save()
+			return true
 		}
-		_ = s.save()
 	}
-	return removed
+	return false
+}
+
+// SetPriority sets an agent's priority.
+func (s *Store) SetPriority(id, priority string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Priority = priority
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// SetRace moves an agent into race, or out of its race for "".
+func (s *Store) SetRace(id, race string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Race = race
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// SetNudge sets an agent's nudge rule (nil for none) and starts its count
+// over.
+func (s *Store) SetNudge(id string, n *Nudge) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Nudge = n
+			a.Nudges = 0
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// SetNudges records how many nudges an agent has been sent since it was
+// last given work.
+func (s *Store) SetNudges(id string, count int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Nudges = count
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// SetRules replaces an agent's approval rules.
+func (s *Store) SetRules(id string, rules []string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Rules = rules
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// SetDetached marks an agent's session as left running unwatched, or
+// watched again.
+func (s *Store) SetDetached(id string, on bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Detached = on
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// SetExitReason records why an agent went, or is about to go, DONE. It's
+// dropped once the agent moves on to any other status.
+func (s *Store) SetExitReason(id, reason string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.ExitReason = reason
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// SetFailure records why an agent went ERROR.
+func (s *Store) SetFailure(id, reason string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Failure = reason
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// SetSummary records what an agent said when it finished its turn.
+func (s *Store) SetSummary(id, summary string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Summary = summary
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// SetResetAt records when the rate limit an agent hit resets.
+func (s *Store) SetResetAt(id string, t time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.ResetAt = t
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// SetKeepAlive turns automatic restarts on or off for an agent, starting
+// its restart count over.
+func (s *Store) SetKeepAlive(id string, on bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.KeepAlive = on
+			a.Restarts = 0
+			a.RestartAt = time.Time{}
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+// RecordRestart counts a keep-alive restart at now and returns how many
+// restarts count against the agent's limit.
+func (s *Store) RecordRestart(id string, now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Restarts = recentRestarts(a, now) + 1
+			a.RestartAt = now
+			_ = s.save()
+			return a.Restarts
+		}
+	}
+	return 0
+}
+
+// RecordRetry starts an agent's task over: it counts the retry and
+// forgets what the last attempt left behind — its conversation, failure,
+// summary and nudges. It returns the new attempt's number.
+func (s *Store) RecordRetry(id string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Retries++
+			a.SessionID = ""
+			a.Failure = ""
+			a.Summary = ""
+			a.Nudges = 0
+			_ = s.save()
+			return attempt(a)
+		}
+	}
+	return 0
+}
+
+// SetPin pins an agent to a board column by title; an empty column unpins.
+// Returns false if the agent doesn't exist.
+func (s *Store) SetPin(id, column string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			a.Pin = column
+			_ = s.save()
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) UpdateSessionName(id string, sessName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			if a.SessionName != sessName {
+				a.SessionName = sessName
+				_ = s.save()
+			}
+			return
+		}
+	}
+}
+
+func (s *Store) Get(id string) *Agent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+func (s *Store) GetByName(name string) *Agent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, a := range s.agents {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// GetBySession returns the agent tracking the given tmux session, or nil.
+func (s *Store) GetBySession(sessionName string) *Agent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, a := range s.agents {
+		if a.SessionName == sessionName {
+			return a
+		}
+	}
+	return nil
+}
+
+func (s *Store) List() []*Agent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*Agent, len(s.agents))
+	copy(out, s.agents)
+	return out
+}
+
+func (s *Store) UpdateDiscovered(id string, discovered bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, a := range s.agents {
+		if a.ID == id {
+			if a.Discovered != discovered {
+				a.Discovered = discovered
+				_ = s.save()
+			}
+			return
+		}
+	}
+}
+
+// MatchAgents returns the agents matching pattern and status. pattern may be an
+// exact ID, an exact name, or a glob on names (e.g. "api-*"); empty matches all.
+// An empty status matches any status.
+func MatchAgents(agents []*Agent, pattern string, status AgentStatus) ([]*Agent, error) {
+	if pattern != "" {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("bad pattern %q: %w", pattern, err)
+		}
+	}
+
+	var out []*Agent
+	for _, a := range agents {
+		if status != "" && a.Status != status {
+			continue
+		}
+		if pattern != "" && a.ID != pattern && a.Name != pattern {
+			if ok, _ := filepath.Match(pattern, a.Name); !ok {
+				continue
+			}
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// ParseStatus converts user input (case-insensitive) to a known AgentStatus.
+func ParseStatus(s string) (AgentStatus, bool) {
+	switch st := AgentStatus(strings.ToUpper(s)); st {
+	case StatusRunning, StatusIdle, StatusWaiting, StatusAsk, StatusDone, StatusStuck, StatusError, StatusPending, StatusTimeout, StatusPaused, StatusThrottled, StatusReview:
+		return st, true
+	}
+	return "", false
+}
+
+// Backend returns the Backend for this agent, falling back to the default.
+func (a *Agent) Backend() Backend {
+	if b := GetBackend(a.BackendID); b != nil {
+		return b
+	}
+	return DefaultBackend()
+}
+
+// BackendLabel returns the short tag for this agent's backend, e.g. "CC".
+func (a *Agent) BackendLabel() string {
+	return backendLabel(a.Backend().ID())
+}
+
+func (s *Store) ClearDone() int {
+	return len(s.ClearDoneBefore(time.Time{}))
+}
+
+// ClearDoneBefore removes DONE and ERROR agents that finished before cutoff
+// and returns them. A zero cutoff clears every one.
+func (s *Store) ClearDoneBefore(cutoff time.Time) []*Agent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var kept, removed []*Agent
+	for _, a := range s.agents {
+		if isClearable(a, cutoff) {
+			removed = append(removed, a)
+		} else {
+			kept = append(kept, a)
+		}
+	}
+	if len(removed) > 0 {
+		s.agents = kept
+		if s.agents == nil {
+			s.agents = []*Agent{}
+		}
+		_ = s.save()
+	}
+	return removed
+}
+
+// isClearable reports whether a is DONE or ERROR and has been since before
+// cutoff.
+func isClearable(a *Agent, cutoff time.Time) bool {
+	if !a.Ended() {
+		return false
+	}
+	return cutoff.IsZero() || a.StatusSince.Before(cutoff)
+}
+
+// Restore puts previously removed agents back under their original IDs.
+// Agents whose ID is already in the store are skipped. Returns the number
+// restored.
+func (s *Store) Restore(agents []*Agent) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, a := range agents {
+		if s.indexOf(a.ID) >= 0 {
+			continue
+		}
+		s.agents = append(s.agents, a)
+		n++
+	}
+	if n > 0 {
+		s.syncNextID()
+		_ = s.save()
+	}
+	return n
+}
+
+// indexOf returns the position of agent id, or -1. Callers hold s.mu.
+func (s *Store) indexOf(id string) int {
+	for i, a := range s.agents {
+		if a.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddRecentDir records dir as the most recent spawn directory.
+func (s *Store) AddRecentDir(dir string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dirs := []string{dir}
+	for _, d := range s.recentDirs {
+		if d != dir && len(dirs) < maxRecentDirs {
+			dirs = append(dirs, d)
+		}
+	}
+	s.recentDirs = dirs
+	_ = s.save()
+}
+
+// ToggleFavoriteDir stars or unstars dir and reports whether it is now a
+// favorite.
+func (s *Store) ToggleFavoriteDir(dir string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, d := range s.favoriteDirs {
+		if d == dir {
+			s.favoriteDirs = append(s.favoriteDirs[:i:i], s.favoriteDirs[i+1:]...)
+			_ = s.save()
+			return false
+		}
+	}
+	s.favoriteDirs = append(s.favoriteDirs, dir)
+	_ = s.save()
+	return true
+}
+
+// IsFavoriteDir reports whether dir is starred.
+func (s *Store) IsFavoriteDir(dir string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, d := range s.favoriteDirs {
+		if d == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// QuickDirs returns favorite directories followed by recent ones, without
+// duplicates, for the spawn dialog quick-pick list.
+func (s *Store) QuickDirs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, list := range [][]string{s.favoriteDirs, s.recentDirs} {
+		for _, d := range list {
+			if !seen[d] {
+				seen[d] = true
+				dirs = append(dirs, d)
+			}
+		}
+	}
+	return dirs
+}
+
+// ColumnPrefs returns a copy of the saved per-column view settings.
+func (s *Store) ColumnPrefs() map[string]ColumnPref {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	prefs := make(map[string]ColumnPref, len(s.columnPrefs))
+	for title, p := range s.columnPrefs {
+		prefs[title] = p
+	}
+	return prefs
+}
+
+// SetColumnPref saves the view setting for a column. A default setting
+// removes the entry.
+func (s *Store) SetColumnPref(title string, p ColumnPref) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p == (ColumnPref{}) {
+		delete(s.columnPrefs, title)
+	} else {
+		if s.columnPrefs == nil {
+			s.columnPrefs = make(map[string]ColumnPref)
+		}
+		s.columnPrefs[title] = p
+	}
+	_ = s.save()
 }
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -104,6 +106,39 @@ func TestStoreGetByName(t *testing.T) {
 	}
 }
 
+func TestStoreGetBySession(t *testing.T) {
+	s := newTestStore(t)
+
+	a := s.Add("external", "/tmp/ext")
+	s.UpdateSessionName(a.ID, "work")
+
+	if got := s.GetBySession("work"); got == nil || got.ID != a.ID {
+		t.Errorf("GetBySession(work) = %v, want agent %s", got, a.ID)
+	}
+	if got := s.GetBySession("missing"); got != nil {
+		t.Error("GetBySession(missing) should return nil")
+	}
+}
+
+func TestStoreRename(t *testing.T) {
+	s := newTestStore(t)
+
+	a := s.Add("old-name", "/tmp/a")
+	if !s.Rename(a.ID, "new-name") {
+		t.Fatal("Rename(existing) returned false, want true")
+	}
+	if got := s.Get(a.ID); got.Name != "new-name" {
+		t.Errorf("Name after Rename = %q, want %q", got.Name, "new-name")
+	}
+	if s.GetByName("old-name") != nil {
+		t.Error("GetByName(old-name) should return nil after rename")
+	}
+
+	if s.Rename("nonexistent", "x") {
+		t.Error("Rename(unknown) returned true, want false")
+	}
+}
+
 func TestStoreClearDone(t *testing.T) {
 	s := newTestStore(t)
 
@@ -160,3 +195,418 @@ func TestStorePersistence(t *testing.T) {
 		t.Errorf("Persisted agent name = %q, want %q", agents[0].Name, "persist-me")
 	}
 }
+
+func TestStoreReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	writer := &Store{path: path, agents: []*Agent{}, nextID: 1}
+	reader := &Store{path: path, agents: []*Agent{}, nextID: 1}
+
+	writer.Add("late-arrival", "/tmp/late")
+	if len(reader.List()) != 0 {
+		t.Fatal("reader should not see agents before Reload")
+	}
+
+	if err := reader.Reload(); err != nil {
+		t.Fatalf("Reload() error: %v", err)
+	}
+	if got := reader.GetByName("late-arrival"); got == nil {
+		t.Error("reader should see agent added by writer after Reload")
+	}
+}
+
+func TestStoreExportImport(t *testing.T) {
+	src := newTestStore(t)
+	src.Add("alpha", "/tmp/a")
+	b := src.Add("beta", "/tmp/b")
+	src.UpdateSessionName(b.ID, SessionName(b.ID))
+
+	data, err := src.Export()
+	if err != nil {
+		t.Fatalf("Export() error: %v", err)
+	}
+
+	t.Run("merge assigns fresh IDs", func(t *testing.T) {
+		dst := newTestStore(t)
+		dst.Add("existing", "/tmp/x")
+
+		n, err := dst.Import(data, false)
+		if err != nil {
+			t.Fatalf("Import() error: %v", err)
+		}
+		if n != 2 {
+			t.Errorf("Import() = %d, want 2", n)
+		}
+		agents := dst.List()
+		if len(agents) != 3 {
+			t.Fatalf("List() has %d agents, want 3", len(agents))
+		}
+		seen := map[string]bool{}
+		for _, a := range agents {
+			if seen[a.ID] {
+				t.Errorf("duplicate ID %q after merge import", a.ID)
+			}
+			seen[a.ID] = true
+		}
+		imported := dst.GetByName("beta")
+		if imported.SessionName != "" {
+			t.Errorf("merged agent SessionName = %q, want empty", imported.SessionName)
+		}
+		if imported.Status != StatusDone {
+			t.Errorf("merged agent Status = %q, want %q", imported.Status, StatusDone)
+		}
+	})
+
+	t.Run("merge remaps links and drops worktrees", func(t *testing.T) {
+		src := newTestStore(t)
+		api := src.Add("api", "/tmp/a")
+		web := src.Add("web", "/tmp/b")
+		src.SetAfter(web.ID, api.ID)
+		src.SetRace(api.ID, api.ID)
+		src.SetRace(web.ID, api.ID)
+		src.SetWorktree(web.ID, &Worktree{Repo: "/tmp/b", Path: "/tmp/wt/web"}, "/tmp/wt/web")
+		src.SetBranch(web.ID, AgentBranch{Repo: "/tmp/b", Name: "tickettok/web"})
+		data, err := src.Export()
+		if err != nil {
+			t.Fatalf("Export() error: %v", err)
+		}
+
+		dst := newTestStore(t)
+		dst.Add("x", "/tmp/x")
+		dst.Add("y", "/tmp/y")
+		if _, err := dst.Import(data, false); err != nil {
+			t.Fatalf("Import() error: %v", err)
+		}
+		newAPI, newWeb := dst.GetByName("api"), dst.GetByName("web")
+		if newWeb.After != newAPI.ID {
+			t.Errorf("web.After = %q, want %q", newWeb.After, newAPI.ID)
+		}
+		if newAPI.Race != newAPI.ID || newWeb.Race != newAPI.ID {
+			t.Errorf("Race = %q, %q; want %q", newAPI.Race, newWeb.Race, newAPI.ID)
+		}
+		if newWeb.Worktree != nil || newWeb.Branch != "" {
+			t.Errorf("web kept worktree %v, branch %q", newWeb.Worktree, newWeb.Branch)
+		}
+	})
+
+	t.Run("merge drops links to agents not exported", func(t *testing.T) {
+		data := []byte(`{"agents":[{"id":"9","name":"web","after":"1","race":"1"},{"id":"8","name":"docs","race":"1"}]}`)
+		dst := newTestStore(t)
+		dst.Add("x", "/tmp/x")
+		if _, err := dst.Import(data, false); err != nil {
+			t.Fatalf("Import() error: %v", err)
+		}
+		web, docs := dst.GetByName("web"), dst.GetByName("docs")
+		if web.After != "" {
+			t.Errorf("web.After = %q, want empty", web.After)
+		}
+		if web.Race == "1" || web.Race != docs.Race {
+			t.Errorf("Race = %q, %q; want one new race", web.Race, docs.Race)
+		}
+	})
+
+	t.Run("replace keeps IDs and sessions", func(t *testing.T) {
+		dst := newTestStore(t)
+		dst.Add("existing", "/tmp/x")
+
+		if _, err := dst.Import(data, true); err != nil {
+			t.Fatalf("Import() error: %v", err)
+		}
+		if dst.GetByName("existing") != nil {
+			t.Error("replace import should drop existing agents")
+		}
+		got := dst.GetByName("beta")
+		if got == nil || got.ID != b.ID || got.SessionName != SessionName(b.ID) {
+			t.Errorf("replaced agent = %+v, want ID %s with session kept", got, b.ID)
+		}
+		if next := dst.Add("gamma", "/tmp/c"); next.ID != "3" {
+			t.Errorf("Add() after replace import ID = %q, want %q", next.ID, "3")
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		dst := newTestStore(t)
+		if _, err := dst.Import([]byte("not json"), false); err == nil {
+			t.Error("Import(invalid) should return error")
+		}
+	})
+}
+
+func TestMatchAgents(t *testing.T) {
+	agents := []*Agent{
+		{ID: "1", Name: "api-users", Status: StatusIdle},
+		{ID: "2", Name: "api-billing", Status: StatusRunning},
+		{ID: "3", Name: "web", Status: StatusIdle},
+		{ID: "4", Name: "worker", Status: StatusDone},
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		status  AgentStatus
+		want    []string
+	}{
+		{"exact name", "web", "", []string{"3"}},
+		{"exact id", "4", "", []string{"4"}},
+		{"glob", "api-*", "", []string{"1", "2"}},
+		{"glob with status", "api-*", StatusIdle, []string{"1"}},
+		{"status only", "", StatusIdle, []string{"1", "3"}},
+		{"all", "", "", []string{"1", "2", "3", "4"}},
+		{"no match", "nope-*", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchAgents(agents, tt.pattern, tt.status)
+			if err != nil {
+				t.Fatalf("MatchAgents() error: %v", err)
+			}
+			var ids []string
+			for _, a := range got {
+				ids = append(ids, a.ID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("MatchAgents(%q, %q) = %v, want %v", tt.pattern, tt.status, ids, tt.want)
+			}
+		})
+	}
+
+	if _, err := MatchAgents(agents, "[", ""); err == nil {
+		t.Error("MatchAgents with malformed pattern should return error")
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	if st, ok := ParseStatus("done"); !ok || st != StatusDone {
+		t.Errorf("ParseStatus(done) = %q, %v", st, ok)
+	}
+	if _, ok := ParseStatus("bogus"); ok {
+		t.Error("ParseStatus(bogus) should fail")
+	}
+}
+
+func TestStoreAddWithBackend(t *testing.T) {
+	s := newTestStore(t)
+
+	if a := s.AddWithBackend("g", "/tmp/g", "gemini"); a.BackendID != "gemini" {
+		t.Errorf("AddWithBackend(gemini).BackendID = %q, want gemini", a.BackendID)
+	}
+	if a := s.AddWithBackend("x", "/tmp/x", "nonexistent"); a.BackendID != DefaultBackend().ID() {
+		t.Errorf("AddWithBackend(unknown).BackendID = %q, want default", a.BackendID)
+	}
+	if a := s.Add("d", "/tmp/d"); a.BackendID != DefaultBackend().ID() {
+		t.Errorf("Add().BackendID = %q, want default", a.BackendID)
+	}
+
+	// Backend must be on disk immediately, not only after a later Save
+	if err := s.load(); err != nil {
+		t.Fatalf("load() error: %v", err)
+	}
+	if got := s.GetByName("g"); got == nil || got.BackendID != "gemini" {
+		t.Errorf("persisted BackendID = %v, want gemini", got)
+	}
+}
+
+func TestStoreSetTags(t *testing.T) {
+	s := newTestStore(t)
+	a := s.Add("agent", "/tmp/a")
+
+	if !s.SetTags(a.ID, []string{"#backend", "urgent,Backend", " ", "api"}) {
+		t.Fatal("SetTags() = false for existing agent")
+	}
+	want := []string{"backend", "urgent", "api"}
+	if strings.Join(a.Tags, ",") != strings.Join(want, ",") {
+		t.Errorf("Tags = %v, want %v", a.Tags, want)
+	}
+	if !a.HasTag("#URGENT") || a.HasTag("urg") {
+		t.Error("HasTag should match whole tags case-insensitively")
+	}
+	if s.SetTags("nope", []string{"x"}) {
+		t.Error("SetTags() = true for missing agent")
+	}
+}
+
+func TestStoreSetPin(t *testing.T) {
+	s := newTestStore(t)
+	a := s.Add("agent", "/tmp/a")
+
+	if !s.SetPin(a.ID, "REVIEW") || a.Pin != "REVIEW" {
+		t.Errorf("SetPin(REVIEW) left Pin = %q", a.Pin)
+	}
+	if !s.SetPin(a.ID, "") || a.Pin != "" {
+		t.Errorf("SetPin(\"\") left Pin = %q", a.Pin)
+	}
+	if s.SetPin("nope", "X") {
+		t.Error("SetPin() = true for missing agent")
+	}
+}
+
+func TestStoreUpdateHistory(t *testing.T) {
+	s := newTestStore(t)
+	a := s.Add("agent", "/tmp/a")
+
+	s.Update(a.ID, StatusIdle)
+	s.Update(a.ID, StatusIdle) // no change, no entry
+	s.Update(a.ID, StatusWaiting)
+	if len(a.History) != 2 || a.History[1].Status != StatusWaiting {
+		t.Fatalf("History = %+v, want IDLE then WAITING", a.History)
+	}
+
+	for i := 0; i < maxStatusHistory; i++ {
+		if i%2 == 0 {
+			s.Update(a.ID, StatusRunning)
+		} else {
+			s.Update(a.ID, StatusIdle)
+		}
+	}
+	if len(a.History) != maxStatusHistory {
+		t.Errorf("len(History) = %d, want capped at %d", len(a.History), maxStatusHistory)
+	}
+}
+
+func TestStoreLogPrompt(t *testing.T) {
+	s := newTestStore(t)
+	a := s.Add("agent", "/tmp/a")
+
+	// No task, nothing to log
+	s.MarkPromptSent(a.ID, time.Now())
+	if len(a.Sent) != 0 {
+		t.Fatalf("Sent = %+v, want nothing for an agent without a task", a.Sent)
+	}
+	a.Prompt = "Fix the build"
+	s.MarkPromptSent(a.ID, time.Now())
+	s.LogPrompt(a.ID, "send", "and run the tests")
+	if len(a.Sent) != 2 || a.Sent[0].Via != "task" || a.Sent[0].Text != "Fix the build" || a.Sent[1].Via != "send" {
+		t.Fatalf("Sent = %+v, want the task then the message", a.Sent)
+	}
+
+	for i := 0; i < maxSentPrompts; i++ {
+		s.LogPrompt(a.ID, "nudge", "keep going")
+	}
+	if len(a.Sent) != maxSentPrompts || a.Sent[0].Via != "nudge" {
+		t.Errorf("Sent has %d messages starting with %q, want capped at %d without the oldest", len(a.Sent), a.Sent[0].Via, maxSentPrompts)
+	}
+}
+
+func TestStoreRecentDirs(t *testing.T) {
+	s := newTestStore(t)
+
+	for i := 0; i < maxRecentDirs+2; i++ {
+		s.AddRecentDir(fmt.Sprintf("/dev/p%d", i))
+	}
+	s.AddRecentDir("/dev/p5")
+
+	got := s.QuickDirs()
+	if len(got) != maxRecentDirs {
+		t.Fatalf("kept %d recent dirs, want %d", len(got), maxRecentDirs)
+	}
+	if got[0] != "/dev/p5" || got[1] != "/dev/p11" {
+		t.Errorf("recent dirs not newest first without duplicates: %v", got)
+	}
+}
+
+func TestStoreFavoriteDirs(t *testing.T) {
+	s := newTestStore(t)
+	s.AddRecentDir("/dev/a")
+	s.AddRecentDir("/dev/b")
+
+	if !s.ToggleFavoriteDir("/dev/a") {
+		t.Fatal("first toggle should star the dir")
+	}
+	if got := s.QuickDirs(); len(got) != 2 || got[0] != "/dev/a" || got[1] != "/dev/b" {
+		t.Errorf("favorites should lead without duplicates, got %v", got)
+	}
+
+	reloaded := &Store{path: s.path}
+	if err := reloaded.load(); err != nil {
+		t.Fatalf("load() error: %v", err)
+	}
+	if !reloaded.IsFavoriteDir("/dev/a") || len(reloaded.QuickDirs()) != 2 {
+		t.Errorf("favorites and recents not persisted: %v", reloaded.QuickDirs())
+	}
+
+	if s.ToggleFavoriteDir("/dev/a") || s.IsFavoriteDir("/dev/a") {
+		t.Error("second toggle should unstar the dir")
+	}
+}
+
+func TestStoreSetNote(t *testing.T) {
+	s := newTestStore(t)
+	a := s.Add("api", "/tmp/api")
+
+	if !s.SetNote(a.ID, "  waiting on CI\n") {
+		t.Fatal("SetNote() = false for existing agent")
+	}
+	if got := s.Get(a.ID).Note; got != "waiting on CI" {
+		t.Errorf("Note = %q, want trimmed %q", got, "waiting on CI")
+	}
+	if s.SetNote("missing", "x") {
+		t.Error("SetNote() = true for missing agent")
+	}
+}
+
+func TestStoreRestore(t *testing.T) {
+	s := newTestStore(t)
+
+	a := s.Add("api", "/tmp/api")
+	s.Add("web", "/tmp/web")
+	s.Remove(a.ID)
+
+	if n := s.Restore([]*Agent{a}); n != 1 {
+		t.Fatalf("Restore() = %d, want 1", n)
+	}
+	if got := s.Get(a.ID); got == nil || got.Name != "api" {
+		t.Errorf("Get(%s) = %v, want api restored under its old ID", a.ID, got)
+	}
+	if n := s.Restore([]*Agent{a}); n != 0 {
+		t.Errorf("second Restore() = %d, want 0 (already present)", n)
+	}
+	if next := s.Add("new", "/tmp/new"); next.ID == a.ID {
+		t.Errorf("new agent reused restored ID %s", a.ID)
+	}
+}
+
+func TestReviewMark(t *testing.T) {
+	s := newTestStore(t)
+	a := s.Add("a", "/tmp/a")
+	s.Update(a.ID, StatusDone)
+	s.Update(a.ID, StatusReview)
+
+	if n := s.ClearDone(); n != 0 {
+		t.Errorf("ClearDone() cleared %d agents, want the marked one kept", n)
+	}
+	if got := statusBefore(a, StatusReview, StatusIdle); got != StatusDone {
+		t.Errorf("statusBefore(NEEDS-REVIEW) = %s, want DONE", got)
+	}
+	if got, ok := ParseStatus("needs-review"); !ok || got != StatusReview {
+		t.Errorf("ParseStatus(needs-review) = %q, %v", got, ok)
+	}
+}
+
+func TestStateDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(stateDirEnv, "")
+	if got := stateDir(); got != filepath.Join(home, ".tickettok") {
+		t.Errorf("stateDir() = %q, want ~/.tickettok", got)
+	}
+
+	t.Setenv(stateDirEnv, "~/profiles/work")
+	if got := stateDir(); got != filepath.Join(home, "profiles", "work") {
+		t.Errorf("stateDir() = %q with $%s set", got, stateDirEnv)
+	}
+	if hookStatusDir() != filepath.Join(stateDir(), "status") || workspaceDir() != filepath.Join(stateDir(), "workspaces") {
+		t.Error("hook status and workspaces should follow the state dir")
+	}
+
+	t.Chdir(home)
+	rest, err := takeStateDirFlag([]string{"list", "--state-dir", "tt"})
+	if err != nil || len(rest) != 1 || rest[0] != "list" {
+		t.Fatalf("takeStateDirFlag = %q, %v", rest, err)
+	}
+	if got := stateDir(); got != filepath.Join(home, "tt") {
+		t.Errorf("stateDir() = %q after --state-dir tt, want it absolute", got)
+	}
+	if _, err := takeStateDirFlag([]string{"--state-dir"}); err == nil {
+		t.Error("--state-dir without a dir should fail")
+	}
+}
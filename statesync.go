@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// The TUI and CLI commands each hold a Store of their own over the same
+// state file. Writes take the file's lock and merge in what the others
+// wrote since, so a `tickettok add` or `kill` run next to the TUI isn't
+// lost the next time the TUI saves.
+
+// syncedState is what a store last read from or wrote to the state file,
+// as JSON, so that saving can tell its own changes from other processes'.
+type syncedState struct {
+	agents   map[string]json.RawMessage // each agent, by ID
+	sections map[string]json.RawMessage // the file's other top-level fields
+}
+
+// snapshot records sf as synced.
+func snapshot(sf StateFile) syncedState {
+	s := syncedState{agents: make(map[string]json.RawMessage, len(sf.Agents))}
+	for _, a := range sf.Agents {
+		s.agents[a.ID], _ = json.Marshal(a)
+	}
+	s.sections = sectionFields(sf)
+	return s
+}
+
+// lockState takes an exclusive lock beside the state file at path, waiting
+// for any other tickettok process holding it. Calling the returned func
+// releases it.
+func lockState(path string) (func(), error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+// commit writes the store out under the state file's lock: it merges in
+// the file's changes since the store last synced, applies change (nil for
+// none) on top, and writes the result. Callers hold s.mu. Without the lock
+// the change is still made, but only in memory.
+func (s *Store) commit(change func()) error {
+	unlock, err := lockState(s.path)
+	if err == nil {
+		defer unlock()
+		s.mergeDisk()
+	}
+	if change != nil {
+		change()
+	}
+	if err != nil {
+		return err
+	}
+	return s.write()
+}
+
+// write replaces the state file with the store's state in one rename, so
+// readers never see it half written, backing up the old one first.
+func (s *Store) write() error {
+	sf := s.stateFile()
+	data, err := json.MarshalIndent(sf, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	// Renaming keeps the inode and mtime, so Changed can tell this write
+	// from the next process's
+	info, err := os.Stat(tmp.Name())
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	// A backup that fails doesn't hold up the save
+	_ = backupState(s.path, false)
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	s.synced = snapshot(sf)
+	s.syncedFile = info
+	s.dirty = false
+	return nil
+}
+
+// pull folds in the state file's changes since the store last synced and
+// takes the file as synced, without writing: the store's own changes are
+// still its own at its next write. Callers hold s.mu.
+func (s *Store) pull() error {
+	unlock, err := lockState(s.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return err
+	}
+	disk, ok := s.mergeDisk()
+	if !ok {
+		return fmt.Errorf("unreadable state file %s", s.path)
+	}
+	s.synced = snapshot(disk)
+	s.syncedFile = info
+	return nil
+}
+
+// mergeDisk folds what other processes wrote to the state file since the
+// store last synced into the store, returning the file as read. Where both
+// changed the same field, the store's own value wins. An unreadable file
+// is left to be overwritten. Callers hold the lock.
+func (s *Store) mergeDisk() (StateFile, bool) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return StateFile{}, false
+	}
+	var disk StateFile
+	if err := json.Unmarshal(data, &disk); err != nil {
+		return StateFile{}, false
+	}
+	sf := s.stateFile()
+	sf.Agents = mergeAgents(sf.Agents, disk.Agents, s.synced.agents)
+	sections := merge3(sectionFields(sf), sectionFields(disk), s.synced.sections)
+	if data, err := json.Marshal(sections); err == nil {
+		var merged StateFile
+		if json.Unmarshal(data, &merged) == nil {
+			merged.Agents = sf.Agents
+			sf = merged
+		}
+	}
+	s.apply(sf)
+	s.syncNextID()
+	return disk, true
+}
+
+// mergeAgents merges the store's agents with those on disk, given the
+// agents as last synced: agents added on either side are kept, those
+// removed on either side dropped — unless the store has changed one
+// another process removed — and agents on both sides are merged field by
+// field. The store's agents are updated in place, as callers hold them.
+func mergeAgents(ours, disk []*Agent, base map[string]json.RawMessage) []*Agent {
+	onDisk := make(map[string]*Agent, len(disk))
+	for _, d := range disk {
+		onDisk[d.ID] = d
+	}
+	out := []*Agent{}
+	mine := make(map[string]bool, len(ours))
+	for _, a := range ours {
+		mine[a.ID] = true
+		was, synced := base[a.ID]
+		d, there := onDisk[a.ID]
+		switch {
+		case !synced:
+			// Added here
+		case !there:
+			// Removed elsewhere
+			if now, _ := json.Marshal(a); bytes.Equal(now, was) {
+				continue
+			}
+		default:
+			if now, _ := json.Marshal(d); !bytes.Equal(now, was) {
+				mergeAgent(a, d, was)
+			}
+		}
+		out = append(out, a)
+	}
+	for _, d := range disk {
+		if _, synced := base[d.ID]; !synced && !mine[d.ID] {
+			// Added elsewhere
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// mergeAgent updates a with the fields d changed from base, keeping the
+// ones a changed.
+func mergeAgent(a, d *Agent, base json.RawMessage) {
+	merged := merge3(jsonFields(a), jsonFields(d), jsonFields(base))
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return
+	}
+	var m Agent
+	if json.Unmarshal(data, &m) == nil {
+		*a = m
+	}
+}
+
+// merge3 merges two versions of a JSON object field by field: a field
+// ours left as it was in base takes theirs, present or not; one ours
+// changed keeps its own.
+func merge3(ours, theirs, base map[string]json.RawMessage) map[string]json.RawMessage {
+	out := make(map[string]json.RawMessage)
+	for k, v := range theirs {
+		if bytes.Equal(ours[k], base[k]) {
+			out[k] = v
+		}
+	}
+	for k, v := range ours {
+		if !bytes.Equal(v, base[k]) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// jsonFields splits v's JSON encoding into its top-level fields.
+func jsonFields(v any) map[string]json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+// sectionFields is jsonFields of sf without its agents.
+func sectionFields(sf StateFile) map[string]json.RawMessage {
+	sf.Agents = nil
+	fields := jsonFields(sf)
+	delete(fields, "agents")
+	return fields
+}
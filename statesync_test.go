@@ -0,0 +1,263 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// openStore loads the state file at path the way a tickettok process does.
+func openStore(t *testing.T, path string) *Store {
+	t.Helper()
+	s := &Store{path: path, agents: []*Agent{}, nextID: 1}
+	if err := s.Reload(); err != nil && !os.IsNotExist(err) {
+		t.Fatalf("Reload() error: %v", err)
+	}
+	s.syncNextID()
+	return s
+}
+
+// synced syncs s, failing the test if the state file can't be read.
+func synced(t *testing.T, s *Store) bool {
+	t.Helper()
+	changed, err := s.Sync()
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	return changed
+}
+
+func TestStoreKeepsOtherProcessesWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	seed := &Store{path: path, agents: []*Agent{}, nextID: 1}
+	api := seed.Add("api", "/src/api")
+	seed.Add("web", "/src/web")
+
+	tui := openStore(t, path)
+	cli := openStore(t, path)
+
+	// A script adds an agent, kills one and notes another while the TUI
+	// runs with its own copy
+	cli.Add("docs", "/src/docs")
+	cli.Remove(cli.GetByName("web").ID)
+	cli.SetNote(api.ID, "from the CLI")
+	tui.Update(api.ID, StatusIdle)
+
+	for _, s := range []*Store{tui, openStore(t, path)} {
+		if s.GetByName("docs") == nil {
+			t.Error("agent added by the other process was lost")
+		}
+		if s.GetByName("web") != nil {
+			t.Error("agent removed by the other process came back")
+		}
+		a := s.Get(api.ID)
+		if a == nil || a.Status != StatusIdle || a.Note != "from the CLI" {
+			t.Errorf("api = %+v, want both processes' changes", a)
+		}
+	}
+}
+
+func TestStoreAddsDistinctIDsAcrossProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	stores := []*Store{openStore(t, path), openStore(t, path), openStore(t, path)}
+
+	var wg sync.WaitGroup
+	for _, s := range stores {
+		wg.Add(1)
+		go func(s *Store) {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				s.Add("agent", "/tmp")
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for _, a := range openStore(t, path).List() {
+		if seen[a.ID] {
+			t.Errorf("ID %s handed out twice", a.ID)
+		}
+		seen[a.ID] = true
+	}
+	if len(seen) != 15 {
+		t.Errorf("state file has %d agents, want 15", len(seen))
+	}
+}
+
+func TestStoreKeepsChangedAgentRemovedElsewhere(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	seed := &Store{path: path, agents: []*Agent{}, nextID: 1}
+	a := seed.Add("api", "/src/api")
+
+	tui := openStore(t, path)
+	cli := openStore(t, path)
+	cli.Remove(a.ID)
+	tui.SetNote(a.ID, "still wanted")
+
+	if openStore(t, path).Get(a.ID) == nil {
+		t.Error("agent changed here was dropped because another process removed it")
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	fields := func(s string) map[string]json.RawMessage {
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	base := fields(`{"a":1,"b":2,"c":3,"d":4}`)
+	ours := fields(`{"a":10,"b":2,"c":3,"d":4,"e":5}`)
+	theirs := fields(`{"a":1,"b":20,"c":30,"f":6}`)
+
+	got, _ := json.Marshal(merge3(ours, theirs, base))
+	if want := `{"a":10,"b":20,"c":30,"e":5,"f":6}`; string(got) != want {
+		t.Errorf("merge3() = %s, want %s", got, want)
+	}
+}
+
+func TestStoreDeferredSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	seed := &Store{path: path, agents: []*Agent{}, nextID: 1}
+	api := seed.Add("api", "/src/api")
+
+	tui := openStore(t, path)
+	tui.DeferSaves()
+	tui.Update(api.ID, StatusIdle)
+	tui.SetNote(api.ID, "held")
+	if a := openStore(t, path).Get(api.ID); a.Status == StatusIdle || a.Note != "" {
+		t.Fatalf("deferred changes written before the flush: %+v", a)
+	}
+
+	// Another process's write is picked up by Sync while the TUI's own
+	// changes wait
+	cli := openStore(t, path)
+	cli.Add("docs", "/src/docs")
+	tui.Sync()
+	if tui.GetByName("docs") == nil {
+		t.Error("Sync() missed the agent another process added")
+	}
+
+	if err := tui.Flush(); err != nil {
+		t.Fatalf("Flush() error: %v", err)
+	}
+	a := openStore(t, path).Get(api.ID)
+	if a == nil || a.Status != StatusIdle || a.Note != "held" {
+		t.Errorf("after Flush() api = %+v, want its held changes", a)
+	}
+	if tui.dirty {
+		t.Error("Flush() left changes marked as held")
+	}
+}
+
+// Run with -race: the TUI changes agent fields without the store's lock,
+// so nothing may write the store out behind its update loop's back.
+func TestStoreDeferredSaveFlushesInUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := &Store{path: path, agents: []*Agent{}, nextID: 1}
+	api := s.Add("api", "/src/api")
+	s.DeferSaves()
+	s.Update(api.ID, StatusIdle)
+
+	// As doSpawn and refreshStatuses do, well past stateSaveDelay
+	time.Sleep(stateSaveDelay + 100*time.Millisecond)
+	s.Get(api.ID).Note = "set directly"
+	if openStore(t, path).Get(api.ID).Status == StatusIdle {
+		t.Fatal("deferred save written outside the update loop")
+	}
+
+	m := Model{store: s}
+	if _, cmd := m.Update(stateFlushMsg{}); cmd == nil {
+		t.Error("stateFlushMsg didn't schedule the next flush")
+	}
+	if a := openStore(t, path).Get(api.ID); a.Status != StatusIdle || a.Note != "set directly" {
+		t.Errorf("after stateFlushMsg api = %+v, want its held changes", a)
+	}
+}
+
+func TestStoresConvergeBySync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	seed := &Store{path: path, agents: []*Agent{}, nextID: 1}
+	api := seed.Add("api", "/src/api")
+	web := seed.Add("web", "/src/web")
+
+	// Two TUIs on the same board
+	a, b := openStore(t, path), openStore(t, path)
+	a.DeferSaves()
+	b.DeferSaves()
+	if a.Changed() || b.Changed() {
+		t.Fatal("Changed() before anyone wrote")
+	}
+
+	a.SetNote(api.ID, "from a")
+	a.Remove(web.ID)
+	if err := a.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	b.Update(api.ID, StatusIdle)
+	if !b.Changed() || !synced(t, b) {
+		t.Fatal("b didn't see a's write")
+	}
+	if b.Get(web.ID) != nil || b.Get(api.ID).Note != "from a" || b.Get(api.ID).Status != StatusIdle {
+		t.Fatalf("b after Sync() = %+v, want a's changes beside its own", b.List())
+	}
+	// Syncing writes nothing, so a has nothing new to pick up yet
+	if a.Changed() {
+		t.Error("b's Sync() wrote the state file")
+	}
+
+	if err := b.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if !synced(t, a) || a.Get(api.ID).Status != StatusIdle || a.Get(web.ID) != nil {
+		t.Errorf("a after Sync() = %+v, want b's status and web still gone", a.List())
+	}
+	if a.Changed() || b.Changed() || synced(t, a) {
+		t.Error("stores still see changes once they've converged")
+	}
+}
+
+func TestUnreadableStateBacksOff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := &Store{path: path, agents: []*Agent{}, nextID: 1}
+	s.Add("api", "/src/api")
+	s.DeferSaves()
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Sync(); err == nil {
+		t.Fatal("Sync() of a broken state file didn't fail")
+	}
+
+	m := Model{store: s}
+	for i := 0; i < 3; i++ {
+		m.statusMsg = ""
+		next, _ := m.Update(stateChangedMsg{})
+		m = next.(Model)
+		if shown := m.statusMsg != ""; shown != (i == 0) {
+			t.Errorf("update %d: status %q; want the error shown once", i, m.statusMsg)
+		}
+	}
+	if m.stateSyncFails != 3 {
+		t.Errorf("stateSyncFails = %d, want 3", m.stateSyncFails)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"agents":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	next, _ := m.Update(stateChangedMsg{})
+	if m = next.(Model); m.stateSyncFails != 0 {
+		t.Errorf("stateSyncFails = %d after a good read, want 0", m.stateSyncFails)
+	}
+
+	for fails, want := range map[int]time.Duration{0: stateWatchInterval, 1: 2 * stateWatchInterval, 3: 8 * stateWatchInterval, 20: stateWatchMaxBackoff} {
+		if got := stateWatchBackoff(fails); got != want {
+			t.Errorf("stateWatchBackoff(%d) = %v, want %v", fails, got, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// AgentStats is what an agent's status history adds up to.
+type AgentStats struct {
+	Running   time.Duration `json:"running,omitempty"`    // time spent RUNNING (or TIMED-OUT)
+	FirstWait time.Duration `json:"first_wait,omitempty"` // from spawn to its first permission prompt
+	Waited    bool          `json:"waited,omitempty"`     // whether it has hit a permission prompt yet
+	Prompts   int           `json:"prompts,omitempty"`    // permission prompts: times it went WAITING
+	Turns     int           `json:"turns,omitempty"`      // turns finished: times it went IDLE or ASK
+}
+
+// add counts a stretch of time an agent entered status s at from and left
+// at to.
+func (st *AgentStats) add(created time.Time, s AgentStatus, from, to time.Time) {
+	switch s {
+	case StatusRunning, StatusTimeout:
+		if to.After(from) {
+			st.Running += to.Sub(from)
+		}
+	case StatusWaiting:
+		st.Prompts++
+		if !st.Waited {
+			st.Waited = true
+			st.FirstWait = from.Sub(created)
+		}
+	case StatusIdle, StatusAsk:
+		st.Turns++
+	}
+}
+
+// historyStats adds up the first n entries of a's status history, the last
+// lasting until end, on top of what history already trimmed off counted.
+// Until its first status change an agent was RUNNING since it spawned.
+func historyStats(a *Agent, n int, end time.Time) AgentStats {
+	var st AgentStats
+	if a.Tally != nil {
+		st = *a.Tally
+	} else {
+		to := end
+		if n > 0 {
+			to = a.History[0].At
+		}
+		st.add(a.CreatedAt, StatusRunning, a.CreatedAt, to)
+	}
+	for i := 0; i < n; i++ {
+		to := end
+		if i+1 < n {
+			to = a.History[i+1].At
+		}
+		st.add(a.CreatedAt, a.History[i].Status, a.History[i].At, to)
+	}
+	return st
+}
+
+// Stats adds up the agent's status history until now.
+func (a *Agent) Stats(now time.Time) AgentStats {
+	return historyStats(a, len(a.History), now)
+}
+
+// trimHistory drops the oldest status changes past maxStatusHistory,
+// counting them in the agent's tally first so its stats don't lose them.
+func trimHistory(a *Agent) {
+	cut := len(a.History) - maxStatusHistory
+	if cut <= 0 {
+		return
+	}
+	tally := historyStats(a, cut, a.History[cut].At)
+	a.Tally = &tally
+	a.History = a.History[cut:]
+}
+
+// StatsSummary sums up the stats of a set of agents.
+type StatsSummary struct {
+	Agents    int
+	Running   time.Duration
+	FirstWait time.Duration // average over the agents that have waited
+	Waited    int           // agents that have hit a permission prompt
+	Prompts   int
+	Turns     int
+	Done      int // agents that finished cleanly
+	Failed    int // agents that ended in ERROR
+}
+
+// summarizeStats adds up the stats of agents until now.
+func summarizeStats(agents []*Agent, now time.Time) StatsSummary {
+	var sum StatsSummary
+	var waits time.Duration
+	for _, a := range agents {
+		st := a.Stats(now)
+		sum.Agents++
+		sum.Running += st.Running
+		sum.Prompts += st.Prompts
+		sum.Turns += st.Turns
+		if st.Waited {
+			sum.Waited++
+			waits += st.FirstWait
+		}
+		switch a.Status {
+		case StatusDone:
+			sum.Done++
+		case StatusError:
+			sum.Failed++
+		}
+	}
+	if sum.Waited > 0 {
+		sum.FirstWait = waits / time.Duration(sum.Waited)
+	}
+	return sum
+}
+
+// String sums the summary up on one line.
+func (s StatsSummary) String() string {
+	noun := "agents"
+	if s.Agents == 1 {
+		noun = "agent"
+	}
+	line := fmt.Sprintf("%d %s · %s running · %d permission prompts", s.Agents, noun, formatStatDuration(s.Running, true), s.Prompts)
+	if s.Waited > 0 {
+		line += fmt.Sprintf(" (first after %s on average)", formatStatDuration(s.FirstWait, true))
+	}
+	return line + fmt.Sprintf(" · %d turns · %d done, %d failed", s.Turns, s.Done, s.Failed)
+}
+
+// formatStatDuration writes a stats duration to the second, "—" for
+// none.
+func formatStatDuration(d time.Duration, ok bool) string {
+	if !ok {
+		return "—"
+	}
+	return formatTimeout(d.Truncate(time.Second))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgentStats(t *testing.T) {
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return start.Add(time.Duration(min) * time.Minute) }
+	a := &Agent{CreatedAt: start, History: []StatusChange{
+		{StatusWaiting, at(5)},
+		{StatusRunning, at(6)},
+		{StatusIdle, at(16)},
+		{StatusRunning, at(20)},
+		{StatusWaiting, at(22)},
+		{StatusRunning, at(23)},
+		{StatusDone, at(30)},
+	}}
+
+	st := a.Stats(at(60))
+	if st.Running != 24*time.Minute {
+		t.Errorf("Running = %v, want 24m", st.Running)
+	}
+	if !st.Waited || st.FirstWait != 5*time.Minute {
+		t.Errorf("FirstWait = %v (waited %v), want 5m", st.FirstWait, st.Waited)
+	}
+	if st.Prompts != 2 || st.Turns != 1 {
+		t.Errorf("Prompts, Turns = %d, %d, want 2, 1", st.Prompts, st.Turns)
+	}
+
+	// Still running: the last stretch counts until now
+	running := &Agent{CreatedAt: start}
+	if st := running.Stats(at(10)); st.Running != 10*time.Minute || st.Waited {
+		t.Errorf("new agent stats = %+v, want 10m running, no wait", st)
+	}
+}
+
+func TestTrimHistoryKeepsStats(t *testing.T) {
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	a := &Agent{CreatedAt: start}
+	for i := 0; i < 3*maxStatusHistory; i++ {
+		status := StatusRunning
+		if i%2 == 0 {
+			status = StatusWaiting
+		}
+		a.History = append(a.History, StatusChange{status, start.Add(time.Duration(i+1) * time.Minute)})
+		want := historyStats(a, len(a.History), start.Add(time.Hour))
+		trimHistory(a)
+		if got := a.Stats(start.Add(time.Hour)); got != want {
+			t.Fatalf("after %d changes: stats %+v, want %+v", i+1, got, want)
+		}
+	}
+	if len(a.History) != maxStatusHistory || a.Tally == nil {
+		t.Errorf("history of %d with tally %v, want %d trimmed into a tally", len(a.History), a.Tally, maxStatusHistory)
+	}
+}
+
+func TestSummarizeStats(t *testing.T) {
+	start := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	agents := []*Agent{
+		{Status: StatusDone, CreatedAt: start, History: []StatusChange{{StatusWaiting, start.Add(2 * time.Minute)}, {StatusDone, start.Add(3 * time.Minute)}}},
+		{Status: StatusError, CreatedAt: start, History: []StatusChange{{StatusWaiting, start.Add(4 * time.Minute)}, {StatusError, start.Add(5 * time.Minute)}}},
+		{Status: StatusRunning, CreatedAt: start.Add(9 * time.Minute)},
+	}
+	sum := summarizeStats(agents, start.Add(10*time.Minute))
+	if sum.Agents != 3 || sum.Done != 1 || sum.Failed != 1 || sum.Prompts != 2 || sum.Waited != 2 {
+		t.Errorf("summary = %+v", sum)
+	}
+	if sum.Running != 7*time.Minute {
+		t.Errorf("Running = %v, want 7m", sum.Running)
+	}
+	if sum.FirstWait != 3*time.Minute {
+		t.Errorf("FirstWait = %v, want the 3m average", sum.FirstWait)
+	}
+	if got := sum.String(); got != "3 agents · 7m running · 2 permission prompts (first after 3m on average) · 0 turns · 1 done, 1 failed" {
+		t.Errorf("String() = %q", got)
+	}
+}
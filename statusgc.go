@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Hook status files are only removed by the paths that kill or clear an
+// agent themselves, so agents that vanish otherwise — killed by another
+// board's TUI, dropped from state by hand, a crash — leave theirs behind.
+// The TUI sweeps the status directory when it starts and every minute.
+
+const (
+	// statusFileMaxAge is how long any file in the status directory is
+	// kept after its last write. A live agent's hook rewrites its file at
+	// every turn, and one that old is ignored as stale anyway.
+	statusFileMaxAge = 24 * time.Hour
+	// statusFileGrace keeps the fresh status file of an agent that isn't in
+	// this board's state yet, such as one another process is spawning.
+	statusFileGrace = time.Minute
+	// statusGCTicks is how many ticks (2s each) apart the sweeps are.
+	statusGCTicks = 30
+)
+
+// gcHookStatus removes the files in the status directory dir that belong
+// to no agent on the current board, or that haven't been written to in a
+// day, returning the names removed. Other boards' files are left to age
+// out, as their agents aren't known here; agents holds this board's
+// agent IDs.
+func gcHookStatus(dir string, agents map[string]bool, now time.Time) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var removed []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		age := now.Sub(info.ModTime())
+		name := e.Name()
+		id, isStatus := strings.CutSuffix(name, ".json")
+		// Hook scripts write a dotfile and rename it into place
+		isStatus = isStatus && !strings.HasPrefix(name, ".")
+		orphan := isStatus && ownedID(id) && !agents[id] && age > statusFileGrace
+		if !orphan && age <= statusFileMaxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err == nil {
+			removed = append(removed, name)
+		}
+	}
+	return removed
+}
+
+// statusGCCmd sweeps the hook status directory in the background, given
+// the board's agents as they are now.
+func statusGCCmd(agents []*Agent) tea.Cmd {
+	ids := make(map[string]bool, len(agents))
+	for _, a := range agents {
+		ids[a.ID] = true
+	}
+	return func() tea.Msg {
+		gcHookStatus(hookStatusDir(), ids, time.Now())
+		return nil
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestGCHookStatus(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	files := map[string]time.Duration{
+		"1.json":      time.Hour,        // live agent
+		"2.json":      time.Hour,        // killed elsewhere
+		"3.json":      10 * time.Second, // just spawned by another process
+		"4.json":      48 * time.Hour,   // live, but silent for two days
+		"work-7.json": time.Hour,        // another board's agent
+		"work-8.json": 48 * time.Hour,   // another board's, long gone
+		".tmp.abc123": 30 * time.Hour,   // a hook's write that never got renamed
+		".tmp.def456": time.Second,      // one in progress
+		"notes.txt":   2 * time.Hour,    // not a status file
+	}
+	for name, age := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(`{"state":"IDLE"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed := gcHookStatus(dir, map[string]bool{"1": true, "4": true}, now)
+	sort.Strings(removed)
+	want := []string{".tmp.abc123", "2.json", "4.json", "work-8.json"}
+	if !slices.Equal(removed, want) {
+		t.Fatalf("removed %v, want %v", removed, want)
+	}
+	for name := range files {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if gone := os.IsNotExist(err); gone != slices.Contains(want, name) {
+			t.Errorf("%s: removed = %v", name, gone)
+		}
+	}
+
+	if got := gcHookStatus(filepath.Join(dir, "missing"), nil, now); got != nil {
+		t.Errorf("gcHookStatus on a missing dir = %v", got)
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// streamPollInterval is how often a stream watcher checks its log for new
+// events once it has caught up.
+const streamPollInterval = 250 * time.Millisecond
+
+// streamSpawner is implemented by backends that can run a task headless,
+// printing a machine-readable event stream instead of a TUI.
+type streamSpawner interface {
+	// StreamSpawnCommand returns a command that runs prompt and tees the
+	// event stream to logPath.
+	StreamSpawnCommand(args []string, prompt, logPath string) (string, []string)
+}
+
+// canStream reports whether b can launch agents in stream mode.
+func canStream(b Backend) bool {
+	_, ok := b.(streamSpawner)
+	return ok
+}
+
+func streamLogPath(agentID string) string {
+	return filepath.Join(stateDir(), "streams", agentID+".jsonl")
+}
+
+// streamState is what an agent's event stream says about it so far.
+type streamState struct {
+	Status    AgentStatus
+	Tool      string   // tool the agent is running, "" between tool calls
+	Text      []string // lines of the last assistant message
+	Usage     Usage
+	Todos     TodoProgress
+	SessionID string
+	done      bool // result event seen; nothing more will be written
+}
+
+// streamEvent is the part of a Claude Code stream-json event tickettok
+// reads. Events are one JSON object per line.
+type streamEvent struct {
+	Type      string  `json:"type"` // system, assistant, user, result
+	Subtype   string  `json:"subtype"`
+	SessionID string  `json:"session_id"`
+	IsError   bool    `json:"is_error"`
+	TotalCost float64 `json:"total_cost_usd"`
+	Message   struct {
+		Content []struct {
+			Type  string          `json:"type"` // text, tool_use, tool_result
+			Text  string          `json:"text"`
+			Name  string          `json:"name"`
+			Input json.RawMessage `json:"input"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens         int64 `json:"input_tokens"`
+			OutputTokens        int64 `json:"output_tokens"`
+			CacheCreationTokens int64 `json:"cache_creation_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// apply folds one event into the state.
+func (s *streamState) apply(ev streamEvent) {
+	if ev.SessionID != "" {
+		s.SessionID = ev.SessionID
+	}
+	switch ev.Type {
+	case "system":
+		s.Status = StatusRunning
+	case "assistant":
+		s.Status = StatusRunning
+		s.Tool = ""
+		for _, c := range ev.Message.Content {
+			switch c.Type {
+			case "tool_use":
+				s.Tool = c.Name
+				if p, ok := parseTodoWrite(c.Input); c.Name == todoWriteTool && ok {
+					s.Todos = p
+				}
+			case "text":
+				if t := strings.TrimSpace(c.Text); t != "" {
+					s.Text = strings.Split(t, "\n")
+				}
+			}
+		}
+		// Same sum as transcriptTokens: cache reads are left out
+		u := ev.Message.Usage
+		s.Usage.Tokens += u.InputTokens + u.CacheCreationTokens + u.OutputTokens
+	case "user":
+		// Tool results going back to the model
+		s.Status = StatusRunning
+		s.Tool = ""
+	case "result":
+		s.Status = StatusDone
+		if ev.IsError || ev.Subtype != "success" {
+			s.Status = StatusError
+		}
+		s.Tool = ""
+		s.Usage.Cost = ev.TotalCost
+		s.done = true
+	}
+}
+
+// readStream applies every complete line in r to s and returns the number
+// of bytes consumed. A partial trailing line is left for the next read.
+func readStream(r io.Reader, s *streamState) int64 {
+	var n int64
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if err != nil {
+			return n
+		}
+		n += int64(len(line))
+		var ev streamEvent
+		if json.Unmarshal(line, &ev) == nil {
+			s.apply(ev)
+		}
+	}
+}
+
+// readStreamFile parses a whole stream log. It's for one-off readers like
+// the CLI; the TUI keeps a watcher per agent instead.
+func readStreamFile(agentID string) (streamState, bool) {
+	f, err := os.Open(streamLogPath(agentID))
+	if err != nil {
+		return streamState{}, false
+	}
+	defer f.Close()
+	var s streamState
+	readStream(f, &s)
+	return s, s.Status != ""
+}
+
+// streams holds the state each watcher has parsed so far.
+var streams = struct {
+	sync.Mutex
+	states map[string]*streamState
+	stop   map[string]chan struct{}
+}{states: map[string]*streamState{}, stop: map[string]chan struct{}{}}
+
+// watchStream starts a goroutine tailing an agent's stream log, unless one
+// is already running. It exits once the result event arrives or
+// dropStream is called.
+func watchStream(agentID string) {
+	streams.Lock()
+	defer streams.Unlock()
+	if _, ok := streams.stop[agentID]; ok {
+		return
+	}
+	if s, ok := streams.states[agentID]; ok && s.done {
+		return
+	}
+	stop := make(chan struct{})
+	streams.stop[agentID] = stop
+	streams.states[agentID] = &streamState{}
+	go tailStream(agentID, stop)
+}
+
+func tailStream(agentID string, stop chan struct{}) {
+	var offset int64
+	for {
+		if f, err := os.Open(streamLogPath(agentID)); err == nil {
+			if _, err := f.Seek(offset, io.SeekStart); err == nil {
+				streams.Lock()
+				s := streams.states[agentID]
+				if s != nil {
+					offset += readStream(f, s)
+				}
+				done := s == nil || s.done
+				if done && streams.stop[agentID] == stop {
+					delete(streams.stop, agentID)
+				}
+				streams.Unlock()
+				if done {
+					f.Close()
+					return
+				}
+			}
+			f.Close()
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(streamPollInterval):
+		}
+	}
+}
+
+// streamStatus returns an agent's parsed stream state, starting a watcher
+// if this process doesn't have one yet (e.g. after a TUI restart).
+func streamStatus(agentID string) (streamState, bool) {
+	watchStream(agentID)
+	streams.Lock()
+	defer streams.Unlock()
+	s, ok := streams.states[agentID]
+	if !ok || s.Status == "" {
+		return streamState{}, false
+	}
+	out := *s
+	out.Text = append([]string(nil), s.Text...)
+	return out, true
+}
+
+// dropStream stops an agent's watcher and removes its log.
+func dropStream(agentID string) {
+	streams.Lock()
+	if stop, ok := streams.stop[agentID]; ok {
+		close(stop)
+		delete(streams.stop, agentID)
+	}
+	delete(streams.states, agentID)
+	streams.Unlock()
+	_ = os.Remove(streamLogPath(agentID))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadStream(t *testing.T) {
+	log := `{"type":"system","subtype":"init","session_id":"abc-123"}
+{"type":"assistant","message":{"content":[{"type":"text","text":"Looking at the tests."},{"type":"tool_use","name":"Bash"}],"usage":{"input_tokens":100,"output_tokens":20,"cache_creation_input_tokens":5}}}
+`
+	var s streamState
+	n := readStream(strings.NewReader(log+`{"type":"user"`), &s)
+	if n != int64(len(log)) {
+		t.Errorf("consumed %d bytes, want %d (partial line left)", n, len(log))
+	}
+	if s.Status != StatusRunning || s.Tool != "Bash" || s.SessionID != "abc-123" {
+		t.Errorf("state = %+v, want RUNNING in Bash for session abc-123", s)
+	}
+	if s.Usage.Tokens != 125 {
+		t.Errorf("tokens = %d, want 125", s.Usage.Tokens)
+	}
+	if len(s.Text) != 1 || s.Text[0] != "Looking at the tests." {
+		t.Errorf("text = %q", s.Text)
+	}
+
+	readStream(strings.NewReader(`{"type":"user","message":{"content":[{"type":"tool_result"}]}}
+{"type":"result","subtype":"success","is_error":false,"total_cost_usd":0.42}
+`), &s)
+	if s.Status != StatusDone || s.Tool != "" || !s.done || s.Usage.Cost != 0.42 {
+		t.Errorf("after result: %+v, want DONE with cost 0.42", s)
+	}
+
+	var failed streamState
+	readStream(strings.NewReader(`{"type":"result","subtype":"error_max_turns","is_error":true}
+`), &failed)
+	if failed.Status != StatusError {
+		t.Errorf("failed run status = %s, want %s", failed.Status, StatusError)
+	}
+}
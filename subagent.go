@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/sns45/tickettok/ui"
+)
+
+// taskTools are the tool names Claude Code starts subagents with; newer
+// versions call it Agent.
+var taskTools = map[string]bool{"Task": true, "Agent": true}
+
+// Subagent is a subagent a Claude agent started, tracked from the tool
+// call that launched it to the result that ends it.
+type Subagent struct {
+	ID          string // tool_use ID, echoed by the tool_result
+	Description string
+	Type        string // subagent_type, e.g. "general-purpose"
+	Status      AgentStatus
+	Started     time.Time
+	Finished    time.Time
+}
+
+// newSubagent reads a Task tool call.
+func newSubagent(id string, input json.RawMessage, at time.Time) Subagent {
+	var in struct {
+		Description  string `json:"description"`
+		SubagentType string `json:"subagent_type"`
+	}
+	_ = json.Unmarshal(input, &in)
+	return Subagent{
+		ID:          id,
+		Description: in.Description,
+		Type:        in.SubagentType,
+		Status:      StatusRunning,
+		Started:     at,
+	}
+}
+
+// finishSubagent marks the subagent started by tool call id as finished.
+func finishSubagent(subs []Subagent, id string, failed bool, at time.Time) {
+	for i := range subs {
+		if subs[i].ID == id && subs[i].Status == StatusRunning {
+			subs[i].Status = StatusDone
+			if failed {
+				subs[i].Status = StatusError
+			}
+			subs[i].Finished = at
+		}
+	}
+}
+
+// subagentInfos converts subagents for card rendering. Running ones show
+// how long they've been going, finished ones how long they took.
+func subagentInfos(subs []Subagent, now time.Time) []ui.SubagentInfo {
+	var out []ui.SubagentInfo
+	for _, s := range subs {
+		name := s.Description
+		if name == "" {
+			name = s.Type
+		}
+		end := s.Finished
+		if s.Status == StatusRunning {
+			end = now
+		}
+		var took time.Duration
+		if !s.Started.IsZero() && !end.IsZero() {
+			took = end.Sub(s.Started)
+		}
+		out = append(out, ui.SubagentInfo{Name: name, Status: string(s.Status), Age: took})
+	}
+	return out
+}
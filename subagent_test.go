@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTranscriptSubagents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	data := `{"type":"assistant","timestamp":"2026-01-02T10:00:00Z","message":{"content":[{"type":"tool_use","id":"t1","name":"Task","input":{"description":"Find callers","subagent_type":"Explore"}},{"type":"tool_use","id":"t2","name":"Task","input":{"subagent_type":"general-purpose"}}]}}
+{"type":"user","timestamp":"2026-01-02T10:03:00Z","message":{"content":[{"type":"tool_result","tool_use_id":"t1"}]}}
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	subs := readTranscript(path).subs
+	if len(subs) != 2 {
+		t.Fatalf("got %d subagents, want 2", len(subs))
+	}
+	if subs[0].Status != StatusDone || subs[1].Status != StatusRunning {
+		t.Errorf("statuses = %s, %s; want DONE, RUNNING", subs[0].Status, subs[1].Status)
+	}
+
+	now := time.Date(2026, 1, 2, 10, 10, 0, 0, time.UTC)
+	infos := subagentInfos(subs, now)
+	if infos[0].Name != "Find callers" || infos[0].Age != 3*time.Minute {
+		t.Errorf("finished subagent = %+v, want Find callers taking 3m", infos[0])
+	}
+	if infos[1].Name != "general-purpose" || infos[1].Age != 10*time.Minute {
+		t.Errorf("running subagent = %+v, want its type as name, running 10m", infos[1])
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import "strings"
+
+// summaryPaneLines is how much of the end of a pane stands in for the last
+// message of a backend without a transcript.
+const summaryPaneLines = 8
+
+// summaryMaxRunes caps a stored summary; long final messages keep their end.
+const summaryMaxRunes = 2000
+
+// agentSummary returns what an agent said last: its final message from the
+// event stream or transcript, else the end of its pane without the
+// backend's chrome, else fallback (the last preview seen).
+func agentSummary(a *Agent, fallback string) string {
+	s := strings.TrimSpace(finalSummary(a))
+	if s == "" && a.SessionName != "" {
+		if content, err := CapturePane(a.SessionName); err == nil {
+			strip := func(lines []string) []string { return a.Backend().StripChrome(lines, false) }
+			s = strings.Join(PreviewFromContent(content, summaryPaneLines, strip), "\n")
+		}
+	}
+	if s == "" {
+		s = strings.TrimSpace(fallback)
+	}
+	if r := []rune(s); len(r) > summaryMaxRunes {
+		s = "…" + strings.TrimSpace(string(r[len(r)-summaryMaxRunes+1:]))
+	}
+	return s
+}
+
+// summaryNotice is the line an agent's card shows about what it concluded:
+// the first line of its summary, while it's IDLE, DONE or marked for
+// review. "" otherwise.
+func summaryNotice(a *Agent) string {
+	switch a.Status {
+	case StatusIdle, StatusDone, StatusReview:
+	default:
+		return ""
+	}
+	for _, l := range strings.Split(a.Summary, "\n") {
+		// A heading like "## Summary" says nothing on its own
+		if l = strings.TrimSpace(l); strings.HasPrefix(l, "#") {
+			continue
+		}
+		l = strings.ReplaceAll(strings.TrimLeft(l, "*-•● "), "**", "")
+		if l = strings.TrimSpace(l); l != "" {
+			return l
+		}
+	}
+	return ""
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummaryNotice(t *testing.T) {
+	a := &Agent{Status: StatusDone, Summary: "## Summary\n\n- **Fixed** the login redirect\n- Added a test"}
+	if got, want := summaryNotice(a), "Fixed the login redirect"; got != want {
+		t.Errorf("summaryNotice() = %q, want %q", got, want)
+	}
+	a.Summary = "**\n\nAll tests pass."
+	if got, want := summaryNotice(a), "All tests pass."; got != want {
+		t.Errorf("summaryNotice() = %q, want %q", got, want)
+	}
+	a.Status = StatusRunning
+	if got := summaryNotice(a); got != "" {
+		t.Errorf("summaryNotice(RUNNING) = %q, want empty", got)
+	}
+}
+
+func TestAgentSummaryFallback(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	a := &Agent{ID: "1", BackendID: "codex"}
+	if got := agentSummary(a, "  Done: 3 files changed\n"); got != "Done: 3 files changed" {
+		t.Errorf("agentSummary() = %q", got)
+	}
+	long := strings.Repeat("x", summaryMaxRunes) + "the end"
+	got := agentSummary(a, long)
+	if n := len([]rune(got)); n != summaryMaxRunes || !strings.HasSuffix(got, "the end") {
+		t.Errorf("long summary: %d runes, ends %q", n, got[len(got)-10:])
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// swarmGroupPrefix marks a swarm's key among the grouped view's project
+// roots.
+const swarmGroupPrefix = "swarm:"
+
+// SwarmManifest describes a team of agents working on one feature: their
+// templates, as in a workspace file, and a context block each of them is
+// given with its task.
+type SwarmManifest struct {
+	Name    string           `json:"name"`
+	Context string           `json:"context,omitempty"`
+	Agents  []WorkspaceAgent `json:"agents"`
+}
+
+// LoadSwarmManifest reads a swarm manifest. Agent dirs may be relative to
+// the manifest's own directory, and the swarm is named after the file
+// unless the manifest says otherwise.
+func LoadSwarmManifest(path string) (*SwarmManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	var s SwarmManifest
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse manifest %s: %w", path, err)
+	}
+	if s.Name = strings.TrimSpace(s.Name); s.Name == "" {
+		s.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if len(s.Agents) == 0 {
+		return nil, fmt.Errorf("manifest %s has no agents", path)
+	}
+	base, _ := filepath.Abs(filepath.Dir(path))
+	seen := make(map[string]bool)
+	for i, t := range s.Agents {
+		if t.Dir == "" {
+			return nil, fmt.Errorf("agent %d of manifest %s has no dir", i+1, path)
+		}
+		if !filepath.IsAbs(t.Dir) && !strings.HasPrefix(t.Dir, "~/") {
+			s.Agents[i].Dir = filepath.Join(base, t.Dir)
+		}
+		if t.Name != "" && seen[t.Name] {
+			return nil, fmt.Errorf("manifest %s names two agents %q", path, t.Name)
+		}
+		seen[t.Name] = true
+	}
+	return &s, nil
+}
+
+// prompt is the first prompt of the swarm's i'th agent: its own task, then
+// the shared context and who else is on the team.
+func (s *SwarmManifest) prompt(i int) string {
+	var b strings.Builder
+	if task := strings.TrimSpace(s.Agents[i].Prompt); task != "" {
+		b.WriteString(task + "\n\n")
+	}
+	fmt.Fprintf(&b, "You're one of %d agents in the %q swarm, working together on one feature.", len(s.Agents), s.Name)
+	if ctx := strings.TrimSpace(s.Context); ctx != "" {
+		b.WriteString(" Shared context:\n\n" + ctx)
+	}
+	var team []string
+	for j, t := range s.Agents {
+		if j == i {
+			continue
+		}
+		member := "- " + t.Name
+		if task := firstLine(t.Prompt); task != "" {
+			member += ": " + task
+		}
+		team = append(team, member)
+	}
+	if len(team) > 0 {
+		b.WriteString("\n\nThe rest of the team:\n" + strings.Join(team, "\n"))
+	}
+	return b.String()
+}
+
+// firstLine returns the first non-blank line of s, trimmed.
+func firstLine(s string) string {
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			return l
+		}
+	}
+	return ""
+}
+
+// workspace converts the manifest to a workspace whose templates carry the
+// swarm's name and their full first prompts.
+func (s *SwarmManifest) workspace() *WorkspaceFile {
+	wf := &WorkspaceFile{Name: s.Name}
+	for i, t := range s.Agents {
+		if t.Name == "" {
+			t.Name = deriveNameFromDir(t.Dir)
+			s.Agents[i].Name = t.Name
+		}
+	}
+	for i, t := range s.Agents {
+		t.Swarm = s.Name
+		t.Prompt = s.prompt(i)
+		// A task of its own starts a fresh conversation
+		t.SessionID = ""
+		wf.Agents = append(wf.Agents, t)
+	}
+	return wf
+}
+
+// swarmMembers returns the agents in the named swarm, in board order.
+func swarmMembers(agents []*Agent, swarm string) []*Agent {
+	var out []*Agent
+	for _, a := range agents {
+		if swarm != "" && a.Swarm == swarm {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// swarmNames returns the swarms agents are in, sorted.
+func swarmNames(agents []*Agent) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, a := range agents {
+		if a.Swarm != "" && !seen[a.Swarm] {
+			seen[a.Swarm] = true
+			names = append(names, a.Swarm)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// swarmStatus sums up a swarm by status, like "2 RUNNING, 1 WAITING",
+// busiest statuses first.
+func swarmStatus(members []*Agent) string {
+	counts := make(map[AgentStatus]int)
+	var order []AgentStatus
+	for _, a := range members {
+		if counts[a.Status] == 0 {
+			order = append(order, a.Status)
+		}
+		counts[a.Status]++
+	}
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	parts := make([]string, len(order))
+	for i, st := range order {
+		parts[i] = fmt.Sprintf("%d %s", counts[st], st)
+	}
+	return strings.Join(parts, ", ")
+}
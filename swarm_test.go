@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSwarmManifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checkout.json")
+	manifest := `{
+  "context": "Spec in docs/checkout.md",
+  "agents": [
+    {"name": "api", "dir": ".", "prompt": "Build the endpoints"},
+    {"name": "ui", "dir": "/src/web", "prompt": "Build the page\nwith the new design"}
+  ]
+}`
+	if err := os.WriteFile(path, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadSwarmManifest(path)
+	if err != nil {
+		t.Fatalf("LoadSwarmManifest() error: %v", err)
+	}
+	if s.Name != "checkout" {
+		t.Errorf("Name = %q, want the file name", s.Name)
+	}
+	if s.Agents[0].Dir != dir || s.Agents[1].Dir != "/src/web" {
+		t.Errorf("dirs = %q, %q, want the relative one taken from the manifest's dir", s.Agents[0].Dir, s.Agents[1].Dir)
+	}
+
+	wf := s.workspace()
+	api := wf.Agents[0]
+	if api.Swarm != "checkout" {
+		t.Errorf("Swarm = %q, want checkout", api.Swarm)
+	}
+	for _, want := range []string{"Build the endpoints\n\n", "Spec in docs/checkout.md", "- ui: Build the page"} {
+		if !strings.Contains(api.Prompt, want) {
+			t.Errorf("prompt %q lacks %q", api.Prompt, want)
+		}
+	}
+	if strings.Contains(api.Prompt, "- api") || strings.Contains(api.Prompt, "new design") {
+		t.Errorf("prompt %q should list only the teammates' first lines", api.Prompt)
+	}
+}
+
+func TestLoadSwarmManifestErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, manifest := range map[string]string{
+		"empty":     `{"name": "x", "agents": []}`,
+		"no dir":    `{"agents": [{"name": "api"}]}`,
+		"duplicate": `{"agents": [{"name": "api", "dir": "."}, {"name": "api", "dir": "."}]}`,
+		"bad json":  `{"agents": [`,
+	} {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "-")+".json")
+		if err := os.WriteFile(path, []byte(manifest), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadSwarmManifest(path); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+}
+
+func TestSwarmMembersAndStatus(t *testing.T) {
+	agents := []*Agent{
+		{Name: "api", Swarm: "checkout", Status: StatusRunning},
+		{Name: "solo", Status: StatusIdle},
+		{Name: "ui", Swarm: "checkout", Status: StatusWaiting},
+		{Name: "tests", Swarm: "checkout", Status: StatusRunning},
+		{Name: "docs", Swarm: "auth", Status: StatusIdle},
+	}
+	members := swarmMembers(agents, "checkout")
+	if len(members) != 3 {
+		t.Fatalf("got %d members, want 3", len(members))
+	}
+	if got := swarmStatus(members); got != "2 RUNNING, 1 WAITING" {
+		t.Errorf("swarmStatus() = %q", got)
+	}
+	if got := strings.Join(swarmNames(agents), ","); got != "auth,checkout" {
+		t.Errorf("swarmNames() = %s", got)
+	}
+	if swarmMembers(agents, "") != nil {
+		t.Error("swarm \"\" should have no members")
+	}
+}
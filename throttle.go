@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// throttleScanLines is how far up from the bottom of a pane a rate-limit
+// message counts; older ones have been dealt with.
+const throttleScanLines = 8
+
+// defaultThrottleBackoff is how long an agent stays THROTTLED when the
+// message doesn't say when the limit resets.
+const defaultThrottleBackoff = 5 * time.Minute
+
+var (
+	// Rate and usage limit messages of the supported CLIs and their APIs
+	throttleRe = regexp.MustCompile(`(?i)(limit reached|hit your (?:usage |rate )?limit|rate limit exceeded|rate_limit_error|quota exceeded|resource_exhausted|too many requests)`)
+
+	// "Claude AI usage limit reached|1760540400"
+	resetUnixRe = regexp.MustCompile(`\|(\d{10})\b`)
+	// "resets 3pm (Europe/Berlin)", "will reset at 15:30"
+	resetClockRe = regexp.MustCompile(`(?i)\bresets?\s+(?:at\s+)?(\d{1,2})(?::(\d{2}))?\s*([ap]m)?(?:\s*\(([^)]+)\))?`)
+	// "try again in 2 hours 13 minutes", "retry in 31.5s"
+	resetInRe  = regexp.MustCompile(`(?i)\b(?:try again|retry|resets?)\s+in\s+((?:[\d.]+\s*[a-z]+[\s,]*(?:and\s+)?)+)`)
+	waitPartRe = regexp.MustCompile(`(?i)([\d.]+)\s*([a-z]+)`)
+)
+
+// throttleCandidate is a stopped agent's pane to scan for a rate-limit
+// message, with what the agent was doing when the scan started.
+type throttleCandidate struct {
+	id, session string
+	status      AgentStatus
+	since       time.Time
+	seen        string // pane tail it was last released from THROTTLED with
+}
+
+// throttleFound is a rate-limit message the scan found in a pane.
+type throttleFound struct {
+	id     string
+	status AgentStatus
+	since  time.Time
+	th     Throttle
+}
+
+// throttleScanMsg carries what a background throttle scan found.
+type throttleScanMsg struct{ found []throttleFound }
+
+// throttleScanCmd captures the candidates' panes in the background, off
+// the TUI's update loop, looking for rate-limit messages.
+func throttleScanCmd(candidates []throttleCandidate) tea.Cmd {
+	return func() tea.Msg {
+		return throttleScanMsg{found: scanThrottles(candidates, CapturePanePlain, time.Now())}
+	}
+}
+
+// scanThrottles reads each candidate's pane with capture, skipping the
+// ones still showing the message they were last released with.
+func scanThrottles(candidates []throttleCandidate, capture func(string) (string, error), now time.Time) []throttleFound {
+	var found []throttleFound
+	for _, c := range candidates {
+		content, err := capture(c.session)
+		if err != nil || paneTail(content) == c.seen {
+			continue
+		}
+		if th, ok := detectThrottle(content, now); ok {
+			found = append(found, throttleFound{c.id, c.status, c.since, th})
+		}
+	}
+	return found
+}
+
+// Throttle is a rate-limit message found in a pane.
+type Throttle struct {
+	Message string
+	Until   time.Time // when the limit resets, zero if the message doesn't say
+}
+
+// detectThrottle looks for a rate-limit message near the bottom of plain
+// pane content and reads when the limit resets from it.
+func detectThrottle(content string, now time.Time) (Throttle, bool) {
+	lines := strings.Split(paneTail(content), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if !throttleRe.MatchString(lines[i]) {
+			continue
+		}
+		// The reset time can be on the line after the message
+		rest := strings.Join(lines[i:], " ")
+		return Throttle{
+			Message: strings.TrimSpace(strings.Trim(strings.TrimSpace(lines[i]), "│⎿●✗×!")),
+			Until:   parseReset(rest, now),
+		}, true
+	}
+	return Throttle{}, false
+}
+
+// parseReset reads when a limit resets from a rate-limit message: a Unix
+// timestamp, a time of day, or a wait. Zero if it says none of these.
+func parseReset(s string, now time.Time) time.Time {
+	if m := resetUnixRe.FindStringSubmatch(s); m != nil {
+		sec, _ := strconv.ParseInt(m[1], 10, 64)
+		return time.Unix(sec, 0)
+	}
+	if m := resetInRe.FindStringSubmatch(s); m != nil {
+		if d := parseWait(m[1]); d > 0 {
+			return now.Add(d)
+		}
+	}
+	if m := resetClockRe.FindStringSubmatch(s); m != nil {
+		return nextClock(m[1], m[2], m[3], m[4], now)
+	}
+	return time.Time{}
+}
+
+// parseWait adds up a wait like "2 hours 13 minutes" or "1h5m30s".
+func parseWait(s string) time.Duration {
+	var total time.Duration
+	for _, m := range waitPartRe.FindAllStringSubmatch(s, -1) {
+		n, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			continue
+		}
+		var unit time.Duration
+		switch u := strings.ToLower(m[2]); {
+		case u == "ms":
+			unit = time.Millisecond
+		case strings.HasPrefix(u, "s"):
+			unit = time.Second
+		case strings.HasPrefix(u, "m"):
+			unit = time.Minute
+		case strings.HasPrefix(u, "h"):
+			unit = time.Hour
+		case strings.HasPrefix(u, "d"):
+			unit = 24 * time.Hour
+		default:
+			continue
+		}
+		total += time.Duration(n * float64(unit))
+	}
+	return total
+}
+
+// nextClock returns the next time after now that a clock shows hour:minute,
+// in the named time zone if there is one.
+func nextClock(hour, minute, ampm, zone string, now time.Time) time.Time {
+	h, _ := strconv.Atoi(hour)
+	mm, _ := strconv.Atoi(minute)
+	switch strings.ToLower(ampm) {
+	case "am":
+		if h == 12 {
+			h = 0
+		}
+	case "pm":
+		if h < 12 {
+			h += 12
+		}
+	}
+	if h > 23 || mm > 59 {
+		return time.Time{}
+	}
+	loc := now.Location()
+	if zone != "" {
+		if l, err := time.LoadLocation(strings.TrimSpace(zone)); err == nil {
+			loc = l
+		}
+	}
+	local := now.In(loc)
+	t := time.Date(local.Year(), local.Month(), local.Day(), h, mm, 0, 0, loc)
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// paneTail returns the part of plain pane content detectThrottle reads.
+func paneTail(content string) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if len(lines) > throttleScanLines {
+		lines = lines[len(lines)-throttleScanLines:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// throttleNotice is the line a THROTTLED agent's card shows: when its
+// limit resets. "" for other agents.
+func throttleNotice(a *Agent, now time.Time) string {
+	if a.Status != StatusThrottled || a.ResetAt.IsZero() {
+		return ""
+	}
+	at := a.ResetAt.Local().Format("15:04")
+	if a.ResetAt.Local().YearDay() != now.Local().YearDay() {
+		at = a.ResetAt.Local().Format("Mon 15:04")
+	}
+	left := a.ResetAt.Sub(now)
+	if left < time.Minute {
+		return fmt.Sprintf("limit resets %s, under a minute left", at)
+	}
+	return fmt.Sprintf("limit resets %s, in %s", at, formatTimeout((left + time.Minute - 1).Truncate(time.Minute)))
+}
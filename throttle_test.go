@@ -0,0 +1,116 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectThrottle(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tz database")
+	}
+	now := time.Date(2026, 3, 10, 11, 0, 0, 0, ny)
+
+	tests := []struct {
+		name    string
+		content string
+		until   time.Time
+	}{
+		{"claude timestamp", "> fix it\n\nClaude AI usage limit reached|1773158400\n", time.Unix(1773158400, 0)},
+		{"claude reset time", "● Working on it\n  ⎿  5-hour limit reached ∙ resets 3pm (America/New_York)\n     /upgrade to increase your usage limit.\n", time.Date(2026, 3, 10, 15, 0, 0, 0, ny)},
+		{"reset time on next line", "Claude usage limit reached.\nYour limit will reset at 9:30am.\n", time.Date(2026, 3, 11, 9, 30, 0, 0, ny)},
+		{"codex wait", "■ You've hit your usage limit. Try again in 2 hours 13 minutes.\n", now.Add(2*time.Hour + 13*time.Minute)},
+		{"gemini wait", "✕ [API Error: 429 Too Many Requests. Please retry in 31.5s.]\n", now.Add(31500 * time.Millisecond)},
+		{"no reset given", "Error: rate limit exceeded\n> ", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th, ok := detectThrottle(tt.content, now)
+			if !ok {
+				t.Fatal("no throttle found")
+			}
+			if !th.Until.Equal(tt.until) {
+				t.Errorf("Until = %v, want %v", th.Until, tt.until)
+			}
+			if th.Message == "" {
+				t.Error("empty message")
+			}
+		})
+	}
+
+	old := "Rate limit exceeded\n" + strings.Repeat("more work\n", throttleScanLines)
+	if _, ok := detectThrottle(old, now); ok {
+		t.Error("message scrolled out of the tail still counts")
+	}
+	if _, ok := detectThrottle("Added a rate limiter to the API\n> ", now); ok {
+		t.Error("talk about rate limits counts as a limit")
+	}
+}
+
+func TestParseWait(t *testing.T) {
+	tests := map[string]time.Duration{
+		"2 hours 13 minutes": 2*time.Hour + 13*time.Minute,
+		"1h5m30s":            time.Hour + 5*time.Minute + 30*time.Second,
+		"20s":                20 * time.Second,
+		"1 day and 2 hours":  26 * time.Hour,
+		"500ms":              500 * time.Millisecond,
+	}
+	for in, want := range tests {
+		if got := parseWait(in); got != want {
+			t.Errorf("parseWait(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestThrottleNotice(t *testing.T) {
+	now := time.Date(2026, 3, 10, 11, 0, 0, 0, time.Local)
+	a := &Agent{Status: StatusThrottled, ResetAt: now.Add(90*time.Minute + 30*time.Second)}
+	if got, want := throttleNotice(a, now), "limit resets 12:30, in 1h31m"; got != want {
+		t.Errorf("throttleNotice() = %q, want %q", got, want)
+	}
+	a.Status = StatusIdle
+	if got := throttleNotice(a, now); got != "" {
+		t.Errorf("throttleNotice(IDLE) = %q, want empty", got)
+	}
+}
+
+func TestScanThrottles(t *testing.T) {
+	now := time.Now()
+	panes := map[string]string{
+		"s1": "> fix it\nClaude usage limit reached. Try again in 2 hours\n",
+		"s2": "> done\nAll tests pass\n",
+		"s3": "old\nRate limit exceeded, retry in 30s\n",
+	}
+	capture := func(session string) (string, error) { return panes[session], nil }
+	candidates := []throttleCandidate{
+		{id: "1", session: "s1", status: StatusIdle, since: now},
+		{id: "2", session: "s2", status: StatusIdle, since: now},
+		// Released from this very message before
+		{id: "3", session: "s3", status: StatusWaiting, since: now, seen: paneTail(panes["s3"])},
+	}
+	found := scanThrottles(candidates, capture, now)
+	if len(found) != 1 || found[0].id != "1" || !found[0].th.Until.Equal(now.Add(2*time.Hour)) {
+		t.Fatalf("scanThrottles = %+v, want agent 1 limited for 2h", found)
+	}
+
+	s := newTestStore(t)
+	a := s.Add("api", "/src/api")
+	b := s.Add("web", "/src/web")
+	s.Update(a.ID, StatusIdle)
+	s.Update(b.ID, StatusIdle)
+	m := Model{store: s, events: OpenEventLog(filepath.Join(t.TempDir(), "events.jsonl"))}
+	stale := s.Get(b.ID).StatusSince.Add(-time.Minute) // b moved on since the scan began
+	m.applyThrottleScan([]throttleFound{
+		{a.ID, StatusIdle, s.Get(a.ID).StatusSince, found[0].th},
+		{b.ID, StatusIdle, stale, found[0].th},
+	}, now)
+	if got := s.Get(a.ID); got.Status != StatusThrottled || !got.ResetAt.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("api = %s until %v, want THROTTLED for 2h", got.Status, got.ResetAt)
+	}
+	if got := s.Get(b.ID).Status; got != StatusIdle {
+		t.Errorf("web = %s, want IDLE: its scan was out of date", got)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Timeout actions for Config.OnTimeout.
+const (
+	TimeoutNotify    = "notify"
+	TimeoutInterrupt = "interrupt"
+	TimeoutKill      = "kill"
+)
+
+// runStart is when an agent started running: its spawn, or when it left
+// the queue if it was spawned PENDING.
+func runStart(a *Agent) time.Time {
+	if len(a.History) == 0 || a.History[0].Status != StatusPending {
+		return a.CreatedAt
+	}
+	for _, c := range a.History {
+		if c.Status != StatusPending {
+			return c.At
+		}
+	}
+	return a.StatusSince
+}
+
+// overTime reports whether an agent has been running longer than its
+// timeout allows.
+func overTime(a *Agent, now time.Time) bool {
+	return a.Timeout > 0 && now.Sub(runStart(a)) > a.Timeout
+}
+
+// timeoutDue reports whether an agent should go TIMED-OUT: it's still
+// working past its timeout. Agents that finished in time are left alone.
+func timeoutDue(a *Agent, now time.Time) bool {
+	if a.Discovered || (a.Status != StatusRunning && a.Status != StatusStuck) {
+		return false
+	}
+	return overTime(a, now)
+}
+
+// formatTimeout writes a timeout the way parseAge reads it, e.g. 2h,
+// 1h30m or 3d.
+func formatTimeout(d time.Duration) string {
+	if d > 0 && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	s := d.Round(time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// parseTimeout reads a timeout typed by the user. Empty, "0" and "off"
+// turn it off.
+func parseTimeout(s string) (time.Duration, error) {
+	switch s = strings.TrimSpace(strings.ToLower(s)); s {
+	case "", "0", "off", "none":
+		return 0, nil
+	}
+	return parseAge(s)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeoutDue(t *testing.T) {
+	now := time.Now()
+	created := now.Add(-3 * time.Hour)
+	tests := []struct {
+		name  string
+		agent Agent
+		want  bool
+	}{
+		{"no timeout", Agent{Status: StatusRunning, CreatedAt: created}, false},
+		{"within limit", Agent{Status: StatusRunning, CreatedAt: created, Timeout: 4 * time.Hour}, false},
+		{"running past limit", Agent{Status: StatusRunning, CreatedAt: created, Timeout: 2 * time.Hour}, true},
+		{"stuck past limit", Agent{Status: StatusStuck, CreatedAt: created, Timeout: 2 * time.Hour}, true},
+		{"finished in time", Agent{Status: StatusIdle, CreatedAt: created, Timeout: 2 * time.Hour}, false},
+		{"already timed out", Agent{Status: StatusTimeout, CreatedAt: created, Timeout: 2 * time.Hour}, false},
+		{"discovered", Agent{Status: StatusRunning, CreatedAt: created, Timeout: 2 * time.Hour, Discovered: true}, false},
+		{"queued until recently", Agent{
+			Status: StatusRunning, CreatedAt: created, Timeout: 2 * time.Hour,
+			History: []StatusChange{{StatusPending, created}, {StatusRunning, now.Add(-time.Hour)}},
+		}, false},
+	}
+	for _, tt := range tests {
+		if got := timeoutDue(&tt.agent, now); got != tt.want {
+			t.Errorf("%s: timeoutDue() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTimeout(t *testing.T) {
+	for _, d := range []time.Duration{90 * time.Second, 45 * time.Minute, 2 * time.Hour, 90 * time.Minute, 72 * time.Hour} {
+		s := formatTimeout(d)
+		got, err := parseTimeout(s)
+		if err != nil || got != d {
+			t.Errorf("parseTimeout(formatTimeout(%v) = %q) = %v, %v", d, s, got, err)
+		}
+	}
+	if s := formatTimeout(2 * time.Hour); s != "2h" {
+		t.Errorf("formatTimeout(2h) = %q", s)
+	}
+	for _, s := range []string{"", "0", "off", " OFF "} {
+		if d, err := parseTimeout(s); err != nil || d != 0 {
+			t.Errorf("parseTimeout(%q) = %v, %v; want 0", s, d, err)
+		}
+	}
+	if _, err := parseTimeout("soon"); err == nil {
+		t.Error("parseTimeout(soon) should fail")
+	}
+}
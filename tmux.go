@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	pty "github.com/creack/pty/v2"
 )
@@ -60,13 +61,20 @@ func CreateSession(name, workDir, command string, stripEnv []string) (*TmuxSessi
 		program = "env -u " + v + " " + program
 	}
 
-	cmd := exec.Command("tmux", "new-session", "-d", "-s", name, "-x", "200", "-y", "50", "-c", workDir, program)
+	args := []string{"new-session", "-d", "-s", name, "-x", "200", "-y", "50", "-c", workDir}
+	if dir := os.Getenv(stateDirEnv); dir != "" {
+		// The tmux server may predate it, so hook scripts find their status dir
+		args = append(args, "-e", stateDirEnv+"="+dir)
+	}
+	cmd := exec.Command("tmux", append(args, program)...)
 	if out, err := cmd.CombinedOutput(); err != nil {
 		return nil, fmt.Errorf("tmux new-session: %s: %w", strings.TrimSpace(string(out)), err)
 	}
 
 	// Enable extended keys (CSI u encoding) so modifier key info reaches the inner app.
 	_ = exec.Command("tmux", "set-option", "-t", name, "extended-keys", "on").Run()
+	// Tell a crash from a clean exit once the session is gone
+	_ = watchExit(name)
 
 	sess := &TmuxSession{Name: name, stripEnv: stripEnv}
 	if err := sess.attachPty(); err != nil {
@@ -84,6 +92,8 @@ func (t *TmuxSession) IsAlive() bool {
 // Kill destroys the tmux session.
 func (t *TmuxSession) Kill() error {
 	t.closePty()
+	// A paused agent would sit stopped on the hangup instead of exiting
+	_ = resumeSession(t.Name)
 	return exec.Command("tmux", "kill-session", "-t", t.Name).Run()
 }
 
@@ -103,6 +113,46 @@ func (t *TmuxSession) CapturePaneContent() (string, error) {
 	return string(out), nil
 }
 
+// SendPrompt types prompt into the session and submits it. Text is sent
+// literally so words like "Enter" aren't interpreted as key names. Multi-line
+// prompts go through a tmux paste buffer with bracketed paste, so embedded
+// newlines don't submit each line separately.
+func SendPrompt(sessionName, prompt string) error {
+	prompt = strings.TrimRight(prompt, "\r\n")
+	if strings.Contains(prompt, "\n") {
+		buf := "tickettok_prompt_" + strings.TrimPrefix(sessionName, sessionPrefix)
+		load := exec.Command("tmux", "load-buffer", "-b", buf, "-")
+		load.Stdin = strings.NewReader(prompt)
+		if err := load.Run(); err != nil {
+			return fmt.Errorf("load-buffer: %w", err)
+		}
+		if err := exec.Command("tmux", "paste-buffer", "-p", "-d", "-b", buf, "-t", sessionName).Run(); err != nil {
+			return fmt.Errorf("paste-buffer: %w", err)
+		}
+		// Give the TUI a moment to finish handling the paste before submitting
+		time.Sleep(300 * time.Millisecond)
+	} else if err := exec.Command("tmux", "send-keys", "-t", sessionName, "-l", prompt).Run(); err != nil {
+		return fmt.Errorf("send-keys: %w", err)
+	}
+	return exec.Command("tmux", "send-keys", "-t", sessionName, "Enter").Run()
+}
+
+// SendKeyNames sends tmux key names (e.g. "1", "Enter", "Escape") to a
+// session without a trailing Enter, for answering prompts in place.
+func SendKeyNames(sessionName string, keys ...string) error {
+	args := append([]string{"send-keys", "-t", sessionName}, keys...)
+	return exec.Command("tmux", args...).Run()
+}
+
+// RenameSession renames a tmux session (standalone, no PTY needed).
+func RenameSession(oldName, newName string) error {
+	out, err := exec.Command("tmux", "rename-session", "-t", oldName, newName).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("tmux rename-session: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
 // SetSize resizes the tmux pane to match the given dimensions.
 func (t *TmuxSession) SetSize(cols, rows int) error {
 	if t.ptmx != nil {
@@ -150,8 +200,10 @@ func CapturePanePlain(sessionName string) (string, error) {
 }
 
 // IsSessionAlive checks if a tmux session exists by name (standalone, no PTY needed).
+// A session whose process has exited but is kept open by watchExit is not alive.
 func IsSessionAlive(sessionName string) bool {
-	return exec.Command("tmux", "has-session", "-t", sessionName).Run() == nil
+	out, err := exec.Command("tmux", "list-panes", "-t", sessionName, "-F", "#{pane_dead}").Output()
+	return err == nil && strings.Contains(string(out), "0")
 }
 
 // --- Discovery ---
@@ -163,6 +215,7 @@ type DiscoveredAgent struct {
 	SessionName string
 	PaneID      string
 	PID         int
+	BackendID   string // backend whose Discover found it
 }
 
 // ANSI strip regex for status detection
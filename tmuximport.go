@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Projects from tmuxinator or teamocil become workspaces: each pane that
+// runs an agent CLI turns into an agent template in the pane's directory.
+// Panes running anything else — servers, log tails, shells — have no place
+// on the board and are skipped.
+
+// tmuxPane is a pane of a tmux session manager's project: where it starts
+// and the commands typed into it.
+type tmuxPane struct {
+	Name     string // the pane's name if it has one, else its window's
+	Dir      string
+	Commands []string
+}
+
+// parseTmuxProject reads a tmuxinator or teamocil project file, returning
+// the project's name ("" if it has none) and its panes. Directories that
+// aren't absolute or ~/ are relative to base.
+func parseTmuxProject(src, base string) (string, []tmuxPane, error) {
+	doc, err := parseYAML(src)
+	if err != nil {
+		return "", nil, err
+	}
+	top, ok := doc.(map[string]any)
+	if !ok {
+		return "", nil, fmt.Errorf("not a tmuxinator or teamocil project")
+	}
+	// teamocil 0.x nested everything under session
+	if s, ok := top["session"].(map[string]any); ok {
+		top = s
+	}
+	name := yamlString(top["name"])
+	root := joinTmuxDir(base, firstYAMLString(top, "root", "project_root"))
+	pre := yamlStrings(top["pre_window"])
+
+	windows, _ := top["windows"].([]any)
+	if windows == nil {
+		windows, _ = top["tabs"].([]any)
+	}
+	if windows == nil {
+		return "", nil, fmt.Errorf("no windows")
+	}
+	var panes []tmuxPane
+	for _, w := range windows {
+		wm, ok := w.(map[string]any)
+		if !ok {
+			continue
+		}
+		if winName, v, ok := tmuxinatorWindow(wm); ok {
+			panes = append(panes, tmuxWindowPanes(winName, root, pre, v)...)
+			continue
+		}
+		// teamocil: the window's settings sit beside its name
+		dir := joinTmuxDir(root, yamlString(wm["root"]))
+		panes = append(panes, tmuxWindowPanes(yamlString(wm["name"]), dir, pre, wm)...)
+	}
+	return name, panes, nil
+}
+
+// tmuxinatorWindow unpacks a tmuxinator window, a mapping from its name to
+// its settings or commands. teamocil windows name their settings instead.
+func tmuxinatorWindow(w map[string]any) (string, any, bool) {
+	if len(w) != 1 {
+		return "", nil, false
+	}
+	for k, v := range w {
+		switch k {
+		case "name", "root", "panes", "layout", "focus", "options", "clear":
+			return "", nil, false
+		}
+		return k, v, true
+	}
+	return "", nil, false
+}
+
+// tmuxWindowPanes lists a window's panes from its settings: a command, a
+// list of commands for its one pane, or a mapping with root, pre and panes.
+func tmuxWindowPanes(window, root string, pre []string, v any) []tmuxPane {
+	settings, ok := v.(map[string]any)
+	if !ok {
+		return []tmuxPane{{Name: window, Dir: root, Commands: append(append([]string{}, pre...), yamlStrings(v)...)}}
+	}
+	if _, teamocil := settings["name"]; !teamocil {
+		root = joinTmuxDir(root, yamlString(settings["root"]))
+	}
+	pre = append(append([]string{}, pre...), yamlStrings(settings["pre"])...)
+	list, _ := settings["panes"].([]any)
+	if list == nil {
+		// A window without panes has one running the given commands, if any
+		return []tmuxPane{{Name: window, Dir: root, Commands: pre}}
+	}
+	var panes []tmuxPane
+	for _, p := range list {
+		pane := tmuxPane{Name: window, Dir: root, Commands: append([]string{}, pre...)}
+		switch pv := p.(type) {
+		case map[string]any:
+			if cmds, ok := pv["commands"]; ok {
+				// teamocil
+				pane.Commands = append(pane.Commands, yamlStrings(cmds)...)
+			} else if cmd, ok := pv["cmd"]; ok {
+				// teamocil 0.x
+				pane.Commands = append(pane.Commands, yamlStrings(cmd)...)
+			} else {
+				// tmuxinator's named panes
+				for k, cmds := range pv {
+					pane.Name = k
+					pane.Commands = append(pane.Commands, yamlStrings(cmds)...)
+				}
+			}
+		default:
+			pane.Commands = append(pane.Commands, yamlStrings(pv)...)
+		}
+		panes = append(panes, pane)
+	}
+	return panes
+}
+
+// tmuxImport is what a project file turns into.
+type tmuxImport struct {
+	Agents  []WorkspaceAgent
+	Skipped []string // panes not running an agent CLI, as "name: commands"
+}
+
+// tmuxPaneAgents makes agent templates of the panes that run a backend's
+// CLI, following any cd before it. clis maps each CLI's command name to
+// its backend.
+func tmuxPaneAgents(panes []tmuxPane, clis map[string]Backend) tmuxImport {
+	var out tmuxImport
+	names := map[string]int{}
+	for _, p := range panes {
+		dir := p.Dir
+		var agent *WorkspaceAgent
+		for _, cmd := range p.Commands {
+			words := strings.Fields(cmd)
+			// Leading VAR=value assignments and exec don't change the program
+			for len(words) > 0 && (words[0] == "exec" || strings.Contains(words[0], "=") && !strings.HasPrefix(words[0], "-")) {
+				words = words[1:]
+			}
+			if len(words) == 0 {
+				continue
+			}
+			if words[0] == "cd" && len(words) == 2 {
+				dir = joinTmuxDir(dir, words[1])
+				continue
+			}
+			b, ok := clis[filepath.Base(words[0])]
+			if !ok {
+				continue
+			}
+			agent = &WorkspaceAgent{Dir: dir, BackendID: b.ID()}
+			for _, flag := range b.AutoApproveArgs() {
+				for _, w := range words[1:] {
+					if w == flag {
+						agent.AutoApprove = true
+					}
+				}
+			}
+			break
+		}
+		if agent == nil {
+			out.Skipped = append(out.Skipped, fmt.Sprintf("%s: %s", p.Name, strings.Join(p.Commands, "; ")))
+			continue
+		}
+		agent.Name = p.Name
+		if agent.Name == "" {
+			agent.Name = deriveNameFromDir(agent.Dir)
+		}
+		if names[agent.Name]++; names[agent.Name] > 1 {
+			agent.Name = fmt.Sprintf("%s-%d", agent.Name, names[agent.Name])
+		}
+		out.Agents = append(out.Agents, *agent)
+	}
+	return out
+}
+
+// backendCLIs maps the command each backend runs to the backend.
+func backendCLIs() map[string]Backend {
+	clis := map[string]Backend{}
+	for _, b := range AllBackends() {
+		cmd, _ := b.SpawnCommand(nil)
+		if f := strings.Fields(cmd); len(f) > 0 {
+			clis[filepath.Base(f[0])] = b
+		}
+	}
+	return clis
+}
+
+// ImportTmuxProject reads a tmuxinator or teamocil project file into a
+// workspace named name, or after the project, or the file, when name is
+// "". Relative directories are taken from the current one, as the tools
+// themselves do.
+func ImportTmuxProject(path, name string) (*WorkspaceFile, tmuxImport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, tmuxImport{}, err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, tmuxImport{}, err
+	}
+	project, panes, err := parseTmuxProject(string(data), wd)
+	if err != nil {
+		return nil, tmuxImport{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if name == "" {
+		name = project
+	}
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	imp := tmuxPaneAgents(panes, backendCLIs())
+	return &WorkspaceFile{Name: name, Agents: imp.Agents}, imp, nil
+}
+
+// joinTmuxDir resolves dir against base: absolute and ~/ directories
+// stand on their own.
+func joinTmuxDir(base, dir string) string {
+	switch {
+	case dir == "":
+		return base
+	case dir == "~":
+		home, _ := os.UserHomeDir()
+		return home
+	case strings.HasPrefix(dir, "~/") || filepath.IsAbs(dir):
+		return dir
+	}
+	return filepath.Join(base, dir)
+}
+
+// yamlString is a YAML scalar as a string, "" for anything else.
+func yamlString(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+// firstYAMLString returns the first of keys set to a string in m.
+func firstYAMLString(m map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if s := yamlString(m[k]); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// yamlStrings reads a command or list of commands.
+func yamlStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var out []string
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
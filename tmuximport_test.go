@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestImportTmuxProject(t *testing.T) {
+	clis := backendCLIs()
+
+	tmuxinator := `name: shop
+root: ~/code/shop
+pre_window: nvm use
+windows:
+  - agents:
+      layout: tiled
+      panes:
+        - claude --dangerously-skip-permissions
+        - web:
+          - cd web
+          - codex
+        - claude
+  - server: bundle exec rails s
+  - review:
+      root: ../shop-review
+      panes:
+        - exec gemini
+`
+	name, panes, err := parseTmuxProject(tmuxinator, "/home/me")
+	if err != nil || name != "shop" {
+		t.Fatalf("parseTmuxProject = %q, %v", name, err)
+	}
+	imp := tmuxPaneAgents(panes, clis)
+	want := []WorkspaceAgent{
+		{Name: "agents", Dir: "~/code/shop", BackendID: "claude", AutoApprove: true},
+		{Name: "web", Dir: "~/code/shop/web", BackendID: "codex"},
+		{Name: "agents-2", Dir: "~/code/shop", BackendID: "claude"},
+		{Name: "review", Dir: "~/code/shop-review", BackendID: "gemini"},
+	}
+	if !reflect.DeepEqual(imp.Agents, want) {
+		t.Errorf("tmuxinator agents =\n%+v\nwant\n%+v", imp.Agents, want)
+	}
+	if !reflect.DeepEqual(imp.Skipped, []string{"server: nvm use; bundle exec rails s"}) {
+		t.Errorf("Skipped = %q", imp.Skipped)
+	}
+
+	teamocil := `windows:
+  - name: api
+    root: /srv/api
+    panes:
+      - commands:
+          - git pull
+          - claude
+      - tail -f log/development.log
+`
+	name, panes, err = parseTmuxProject(teamocil, "/home/me")
+	if err != nil || name != "" {
+		t.Fatalf("parseTmuxProject = %q, %v", name, err)
+	}
+	imp = tmuxPaneAgents(panes, clis)
+	if !reflect.DeepEqual(imp.Agents, []WorkspaceAgent{{Name: "api", Dir: "/srv/api", BackendID: "claude"}}) || len(imp.Skipped) != 1 {
+		t.Errorf("teamocil import = %+v", imp)
+	}
+
+	if _, _, err := parseTmuxProject("- just\n- a list\n", "/"); err == nil {
+		t.Error("a file that isn't a project should fail")
+	}
+}
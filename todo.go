@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/sns45/tickettok/ui"
+)
+
+// todoWriteTool is Claude Code's task-list tool. Each call replaces the
+// whole list.
+const todoWriteTool = "TodoWrite"
+
+// TodoProgress is how far an agent is through its own task list.
+type TodoProgress struct {
+	Done  int
+	Total int
+}
+
+// Info converts the progress for card rendering.
+func (t TodoProgress) Info() ui.TodoInfo {
+	return ui.TodoInfo{Done: t.Done, Total: t.Total}
+}
+
+// parseTodoWrite reads the progress from a TodoWrite call's input.
+func parseTodoWrite(input json.RawMessage) (TodoProgress, bool) {
+	var in struct {
+		Todos []struct {
+			Status string `json:"status"` // pending, in_progress, completed
+		} `json:"todos"`
+	}
+	if json.Unmarshal(input, &in) != nil || in.Todos == nil {
+		return TodoProgress{}, false
+	}
+	p := TodoProgress{Total: len(in.Todos)}
+	for _, t := range in.Todos {
+		if t.Status == "completed" {
+			p.Done++
+		}
+	}
+	return p, true
+}
+
+// paneTodos reads the last task list Claude Code rendered in the pane:
+//
+//	⏺ Update Todos
+//	  ⎿  ☒ Explore the parser
+//	     ☐ Add tests
+//
+// It's the fallback for when there's no transcript to read.
+func paneTodos(content string) TodoProgress {
+	lines := strings.Split(stripAnsiStr(content), "\n")
+	start := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.Contains(lines[i], "Update Todos") {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return TodoProgress{}
+	}
+	var p TodoProgress
+	for _, line := range lines[start+1:] {
+		item := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "⎿"))
+		switch {
+		case strings.HasPrefix(item, "☒"), strings.HasPrefix(item, "☑"), strings.HasPrefix(item, "✔"):
+			p.Done++
+			p.Total++
+		case strings.HasPrefix(item, "☐"), strings.HasPrefix(item, "◻"), strings.HasPrefix(item, "◼"):
+			p.Total++
+		default:
+			return p
+		}
+	}
+	return p
+}
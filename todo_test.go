@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTodoWrite(t *testing.T) {
+	p, ok := parseTodoWrite([]byte(`{"todos":[{"content":"a","status":"completed"},{"content":"b","status":"in_progress"},{"content":"c","status":"pending"}]}`))
+	if !ok || p != (TodoProgress{Done: 1, Total: 3}) {
+		t.Errorf("parseTodoWrite() = %+v, %v; want 1/3", p, ok)
+	}
+	if _, ok := parseTodoWrite([]byte(`{"command":"ls"}`)); ok {
+		t.Error("parseTodoWrite() accepted input without todos")
+	}
+}
+
+func TestPaneTodos(t *testing.T) {
+	content := `⏺ Update Todos
+  ⎿  ☐ stale list
+
+⏺ Update Todos
+  ⎿  ☒ Explore the parser
+     ☒ Fix the off-by-one
+     ☐ Add tests
+
+✻ Working… (esc to interrupt)`
+	if got := paneTodos(content); got != (TodoProgress{Done: 2, Total: 3}) {
+		t.Errorf("paneTodos() = %+v, want 2/3 from the last list", got)
+	}
+	if got := paneTodos("no list here"); got.Total != 0 {
+		t.Errorf("paneTodos() = %+v, want none", got)
+	}
+}
+
+func TestTranscriptTodos(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	data := `{"type":"assistant","message":{"content":[{"type":"tool_use","name":"TodoWrite","input":{"todos":[{"status":"pending"},{"status":"pending"}]}}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","name":"TodoWrite","input":{"todos":[{"status":"completed"},{"status":"in_progress"}]}}]}}
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTranscript(path).todos; got != (TodoProgress{Done: 1, Total: 2}) {
+		t.Errorf("transcript todos = %+v, want the latest list at 1/2", got)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// tomlStringArray locates a top-level key whose value is an array of
+// strings, like Codex's `notify = ["cmd", "arg"]`.
+type tomlStringArray struct {
+	KeyStart   int // offset of the key at the start of its line
+	ValueStart int // offset of the opening [
+	ValueEnd   int // offset just past the closing ]
+	Values     []string
+}
+
+// readTOMLStringArray reads the top-level key of the TOML document src.
+// found is false if the key isn't set; err is non-nil if the document
+// isn't valid TOML or key's value isn't an array of strings.
+func readTOMLStringArray(src, key string) (values []string, found bool, err error) {
+	var doc map[string]any
+	if err := toml.Unmarshal([]byte(src), &doc); err != nil {
+		return nil, false, err
+	}
+	v, ok := doc[key]
+	if !ok {
+		return nil, false, nil
+	}
+	elems, ok := v.([]any)
+	if !ok {
+		return nil, false, fmt.Errorf("%s: not an array of strings", key)
+	}
+	values = make([]string, len(elems))
+	for i, e := range elems {
+		if values[i], ok = e.(string); !ok {
+			return nil, false, fmt.Errorf("%s: not an array of strings", key)
+		}
+	}
+	return values, true, nil
+}
+
+// findTOMLStringArray reads key like readTOMLStringArray and locates its
+// value in src, so that it can be replaced without disturbing the rest of
+// the file. The value is found among the keys before the first [table]
+// header, scanning over strings, comments, arrays and inline tables.
+func findTOMLStringArray(src, key string) (arr tomlStringArray, found bool, err error) {
+	values, found, err := readTOMLStringArray(src, key)
+	if err != nil || !found {
+		return arr, found, err
+	}
+	i := 0
+	for i < len(src) {
+		i = skipTOMLSpace(src, i)
+		if i >= len(src) {
+			break
+		}
+		switch src[i] {
+		case '\n', '\r':
+			i++
+			continue
+		case '#':
+			i = tomlLineEnd(src, i)
+			continue
+		case '[':
+			// First table header: no more top-level keys
+			return arr, false, fmt.Errorf("%s: not set as a top-level key = value", key)
+		}
+
+		keyStart := i
+		eq := strings.IndexByte(src[i:], '=')
+		if eq < 0 || strings.ContainsAny(src[i:i+eq], "\n") {
+			return arr, false, fmt.Errorf("line %d: expected key = value", tomlLine(src, i))
+		}
+		name := strings.TrimSpace(src[i : i+eq])
+		if unq, err := strconv.Unquote(name); err == nil {
+			name = unq
+		} else if len(name) >= 2 && name[0] == '\'' && name[len(name)-1] == '\'' {
+			name = name[1 : len(name)-1]
+		}
+
+		i = skipTOMLSpace(src, i+eq+1)
+		end, err := skipTOMLValue(src, i)
+		if err != nil {
+			return arr, false, err
+		}
+		if name == key {
+			return tomlStringArray{keyStart, i, end, values}, true, nil
+		}
+		i = tomlLineEnd(src, end)
+	}
+	return arr, false, fmt.Errorf("%s: not set as a top-level key = value", key)
+}
+
+// formatTOMLStringArray renders values as a one-line TOML array.
+func formatTOMLStringArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func skipTOMLSpace(src string, i int) int {
+	for i < len(src) && (src[i] == ' ' || src[i] == '\t') {
+		i++
+	}
+	return i
+}
+
+// tomlLineEnd returns the offset of the newline ending i's line.
+func tomlLineEnd(src string, i int) int {
+	if n := strings.IndexByte(src[i:], '\n'); n >= 0 {
+		return i + n
+	}
+	return len(src)
+}
+
+func tomlLine(src string, i int) int {
+	return strings.Count(src[:i], "\n") + 1
+}
+
+// skipTOMLValue returns the offset just past the value starting at i.
+func skipTOMLValue(src string, i int) (int, error) {
+	if i >= len(src) {
+		return i, fmt.Errorf("line %d: missing value", tomlLine(src, i))
+	}
+	switch src[i] {
+	case '"', '\'':
+		return skipTOMLString(src, i)
+	case '[', '{':
+		depth := 0
+		for i < len(src) {
+			switch src[i] {
+			case '"', '\'':
+				end, err := skipTOMLString(src, i)
+				if err != nil {
+					return i, err
+				}
+				i = end
+				continue
+			case '#':
+				i = tomlLineEnd(src, i)
+				continue
+			case '[', '{':
+				depth++
+			case ']', '}':
+				depth--
+				if depth == 0 {
+					return i + 1, nil
+				}
+			}
+			i++
+		}
+		return i, fmt.Errorf("line %d: unclosed array or table", tomlLine(src, i))
+	}
+	// Number, boolean or date: runs to a comment or the end of the line
+	end := tomlLineEnd(src, i)
+	if n := strings.IndexByte(src[i:end], '#'); n >= 0 {
+		end = i + n
+	}
+	return i + len(strings.TrimRight(src[i:end], " \t\r")), nil
+}
+
+// skipTOMLString returns the offset just past the string starting at i,
+// which may be basic ("), literal (') or either's multi-line form.
+func skipTOMLString(src string, i int) (int, error) {
+	q := src[i]
+	if strings.HasPrefix(src[i:], strings.Repeat(string(q), 3)) {
+		delim := strings.Repeat(string(q), 3)
+		for j := i + 3; j < len(src); j++ {
+			if q == '"' && src[j] == '\\' {
+				j++
+				continue
+			}
+			if strings.HasPrefix(src[j:], delim) {
+				// Up to two more quotes may end the content
+				end := j + 3
+				for k := 0; k < 2 && end < len(src) && src[end] == q; k++ {
+					end++
+				}
+				return end, nil
+			}
+		}
+		return i, fmt.Errorf("line %d: unterminated string", tomlLine(src, i))
+	}
+	for j := i + 1; j < len(src) && src[j] != '\n'; j++ {
+		if q == '"' && src[j] == '\\' {
+			j++
+			continue
+		}
+		if src[j] == q {
+			return j + 1, nil
+		}
+	}
+	return i, fmt.Errorf("line %d: unterminated string", tomlLine(src, i))
+}
@@ -0,0 +1,76 @@
+package main
+
+import "testing"
+
+func TestFindTOMLStringArray(t *testing.T) {
+	src := `model = "o3" # notify = ["not this"]
+approval = 'on-request'
+tags = [
+  "a", # "b"
+  "c",
+]
+profile = { name = "x]", retries = 2 }
+notes = """
+notify = ["nor this"]
+"""
+notify = ["python3", '/home/me/notify.py']
+
+[tui]
+notify = ["agent-turn-complete"]
+`
+	arr, found, err := findTOMLStringArray(src, "notify")
+	if err != nil || !found {
+		t.Fatalf("found = %v, err = %v", found, err)
+	}
+	if len(arr.Values) != 2 || arr.Values[0] != "python3" || arr.Values[1] != "/home/me/notify.py" {
+		t.Errorf("values = %q", arr.Values)
+	}
+	if got := src[arr.KeyStart:arr.ValueEnd]; got != `notify = ["python3", '/home/me/notify.py']` {
+		t.Errorf("span = %q", got)
+	}
+
+	arr, found, err = findTOMLStringArray("notify = [\n  \"\"\"py\nthon\"\"\",\n  'n.py', # why\n]\n", "notify")
+	if err != nil || !found || len(arr.Values) != 2 || arr.Values[0] != "py\nthon" {
+		t.Errorf("multi-line array: %q, %v, %v", arr.Values, found, err)
+	}
+	if _, found, err := findTOMLStringArray("[tui]\nnotify = [\"x\"]\n", "notify"); found || err != nil {
+		t.Errorf("found a key inside a table: %v", err)
+	}
+	if _, _, err := findTOMLStringArray("notify = [\"x\"\n", "notify"); err == nil {
+		t.Error("no error for a document that isn't TOML")
+	}
+	if _, _, err := findTOMLStringArray("notify = true\n", "notify"); err == nil {
+		t.Error("no error for a non-array value")
+	}
+}
+
+func TestCodexNotifyEditing(t *testing.T) {
+	const script = "/home/me/.tickettok/tickettok-codex-notify.sh"
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"no notify", "model = \"o3\"\n\n[tui]\nx = 1\n", "notify = [\"" + script + "\"]\nmodel = \"o3\"\n\n[tui]\nx = 1\n"},
+		{"existing command", "notify = [\"python3\", \"n.py\"] # mine\n", "notify = [\"" + script + "\", \"python3\", \"n.py\"] # mine\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := addCodexNotify(tt.in, script)
+			if err != nil || !changed || got != tt.want {
+				t.Fatalf("add = %q, %v, %v; want %q", got, changed, err, tt.want)
+			}
+			if again, changed, _ := addCodexNotify(got, script); changed || again != got {
+				t.Errorf("second add changed the file: %q", again)
+			}
+			back, changed, err := removeCodexNotify(got, script)
+			if err != nil || !changed || back != tt.in {
+				t.Errorf("remove = %q, %v, %v; want %q", back, changed, err, tt.in)
+			}
+		})
+	}
+
+	if _, _, err := addCodexNotify("notify = \"oops\"\n", script); err == nil {
+		t.Error("no error for a notify that isn't an array")
+	}
+}
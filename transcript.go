@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultTranscriptDir is where agent output is archived unless the
+// config says otherwise.
+func defaultTranscriptDir() string {
+	return filepath.Join(stateDir(), "transcripts")
+}
+
+// Characters kept out of transcript file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// transcriptFile is the file an agent's output is archived to:
+// <id>-<name>.txt under dir.
+func transcriptFile(dir string, a *Agent) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(a.Name, "-"), "-")
+	return filepath.Join(dir, a.ID+"-"+name+".txt")
+}
+
+// captureScrollback returns everything in a session's pane, history
+// included, as plain text.
+func captureScrollback(session string) (string, error) {
+	out, err := exec.Command("tmux", "capture-pane", "-p", "-J", "-S", "-", "-E", "-", "-t", session).Output()
+	if err != nil {
+		return "", fmt.Errorf("tmux capture-pane: %w", err)
+	}
+	return string(out), nil
+}
+
+// saveTranscript appends output to an agent's transcript under dir, after
+// a line saying when and why it was saved. Nothing is written when dir is
+// "" (archiving is off) or there is no output.
+func saveTranscript(dir string, a *Agent, output, why string, now time.Time) error {
+	output = strings.TrimRight(output, " \n")
+	if dir == "" || strings.TrimSpace(output) == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(transcriptFile(dir, a), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(f, "── %s (%s, %s) %s %s ──\n%s\n\n", a.Name, a.ID, a.Backend().ID(), why, now.Format("2006-01-02 15:04:05"), output)
+	return f.Close()
+}
+
+// archiveSession saves an agent's scrollback to its transcript before the
+// session goes away. A session kept open by watchExit after its process
+// exited is closed too, as nothing will reap it once the agent is gone.
+func archiveSession(dir string, a *Agent, why string) error {
+	if dir == "" || a.SessionName == "" {
+		return nil
+	}
+	out, err := captureScrollback(a.SessionName)
+	if err != nil {
+		// No session left to save
+		return nil
+	}
+	if !IsSessionAlive(a.SessionName) {
+		defer KillBySession(a.SessionName)
+	}
+	return saveTranscript(dir, a, out, why, time.Now())
+}
+
+// findTranscripts returns the transcripts in dir of the agent with ID
+// target, or failing that of agents named target, newest last.
+func findTranscripts(dir, target string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	name := "-" + strings.Trim(unsafeFileChars.ReplaceAllString(target, "-"), "-") + ".txt"
+	var byID, byName []os.DirEntry
+	for _, e := range entries {
+		switch {
+		case strings.HasPrefix(e.Name(), target+"-"):
+			byID = append(byID, e)
+		case strings.HasSuffix(e.Name(), name):
+			byName = append(byName, e)
+		}
+	}
+	if len(byID) == 0 {
+		byID = byName
+	}
+	return sortedByModTime(dir, byID)
+}
+
+// sortedByModTime returns the paths of entries in dir, oldest first.
+func sortedByModTime(dir string, entries []os.DirEntry) []string {
+	mod := func(e os.DirEntry) time.Time {
+		if info, err := e.Info(); err == nil {
+			return info.ModTime()
+		}
+		return time.Time{}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return mod(entries[i]).Before(mod(entries[j])) })
+	paths := make([]string, len(entries))
+	for i, e := range entries {
+		paths[i] = filepath.Join(dir, e.Name())
+	}
+	return paths
+}
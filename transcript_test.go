@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveTranscript(t *testing.T) {
+	dir := t.TempDir()
+	a := &Agent{ID: "7", Name: "api / auth", BackendID: "codex"}
+	now := time.Date(2026, 3, 10, 11, 0, 0, 0, time.Local)
+	if err := saveTranscript(dir, a, "first run\n\n", "restarted", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveTranscript(dir, a, "second run", "killed", now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveTranscript(dir, a, "  \n", "cleared", now); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "7-api-auth.txt")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "── api / auth (7, codex) restarted 2026-03-10 11:00:00 ──\nfirst run\n\n" +
+		"── api / auth (7, codex) killed 2026-03-10 12:00:00 ──\nsecond run\n\n"
+	if string(data) != want {
+		t.Errorf("transcript =\n%s\nwant\n%s", data, want)
+	}
+
+	if err := saveTranscript("", a, "output", "killed", now); err != nil {
+		t.Errorf("saving with archiving off: %v", err)
+	}
+}
+
+func TestFindTranscripts(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"1-api.txt", "12-web.txt", "3-api.txt", "4-2.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base := func(paths []string) string {
+		var names []string
+		for _, p := range paths {
+			names = append(names, filepath.Base(p))
+		}
+		return strings.Join(names, " ")
+	}
+	tests := map[string]string{
+		"1":   "1-api.txt",
+		"api": "1-api.txt 3-api.txt",
+		"web": "12-web.txt",
+		"4":   "4-2.txt",
+		"2":   "4-2.txt",
+		"9":   "",
+	}
+	for target, want := range tests {
+		if got := base(findTranscripts(dir, target)); got != want {
+			t.Errorf("findTranscripts(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
@@ -7,105 +7,107 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// RenderBoard renders the kanban board in 2 or 3 column mode.
+// RenderBoard renders the kanban board with one lane per layout column.
 // scrollOffset and maxVisible control the visible window of cards per column.
-func RenderBoard(agents []CardData, selected int, columns int, width, height, scrollOffset, maxVisible int) string {
-	// Categorize agents
-	var running, waiting, idle []CardData
-	var runIdx, waitIdx, idleIdx []int
+func RenderBoard(agents []CardData, layout []Column, selected int, width, height, scrollOffset, maxVisible int, sortMode SortMode) string {
+	n := len(layout)
+	if n == 0 {
+		return ""
+	}
 
+	// Categorize agents
+	indices := make([][]int, n)
 	for i, a := range agents {
-		switch a.Status {
-		case "RUNNING":
-			running = append(running, a)
-			runIdx = append(runIdx, i)
-		case "WAITING", "STUCK":
-			waiting = append(waiting, a)
-			waitIdx = append(waitIdx, i)
-		case "IDLE", "DONE":
-			idle = append(idle, a)
-			idleIdx = append(idleIdx, i)
+		col := PlaceCard(layout, a.Status, a.Pin)
+		indices[col] = append(indices[col], i)
+	}
+
+	widths := ColumnWidths(layout, width)
+
+	headers := make([]string, 0, 2*n)
+	cols := make([]string, 0, 2*n)
+	for i, c := range layout {
+		SortColumn(agents, indices[i], sortMode)
+		colWidth := widths[i]
+
+		hdr := ColumnHeader.Foreground(c.Color).Render(fmt.Sprintf("■ %s [%d]", c.Title, len(indices[i])))
+		var body string
+		switch {
+		case c.Collapsed:
+			hdr, body = collapsedColumn(c, len(indices[i]))
+		case len(indices[i]) == 0:
+			body = lipgloss.NewStyle().Width(colWidth).Foreground(ColorDim).Render(fmt.Sprintf("\n  No %s agents", strings.ToLower(c.Title)))
+		default:
+			body = renderColumnCards(pick(agents, indices[i]), indices[i], selected, colWidth, scrollOffset, maxVisible)
 		}
-	}
-
-	if columns == 2 {
-		return render2Col(agents, running, waiting, idle, runIdx, waitIdx, idleIdx, selected, width, height, scrollOffset, maxVisible)
-	}
-	return render3Col(agents, running, waiting, idle, runIdx, waitIdx, idleIdx, selected, width, height, scrollOffset, maxVisible)
-}
-
-func render3Col(agents []CardData, running, waiting, idle []CardData, runIdx, waitIdx, idleIdx []int, selected, width, height, scrollOffset, maxVisible int) string {
-	colWidth := (width - 6) / 3
-	if colWidth < 20 {
-		colWidth = 20
-	}
-
-	// Headers
-	hdrRun := ColumnHeader.Foreground(ColorRunning).Render(fmt.Sprintf("■ RUNNING [%d]", len(running)))
-	hdrWait := ColumnHeader.Foreground(ColorWaiting).Render(fmt.Sprintf("■ WAITING [%d]", len(waiting)))
-	hdrIdle := ColumnHeader.Foreground(ColorIdle).Render(fmt.Sprintf("■ IDLE [%d]", len(idle)))
-
-	hdrRun = lipgloss.NewStyle().Width(colWidth).Render(hdrRun)
-	hdrWait = lipgloss.NewStyle().Width(colWidth).Render(hdrWait)
-	hdrIdle = lipgloss.NewStyle().Width(colWidth).Render(hdrIdle)
-
-	header := lipgloss.JoinHorizontal(lipgloss.Top, hdrIdle, " ", hdrWait, " ", hdrRun)
-
-	// Cards per column (only the visible slice)
-	col1 := renderColumnCards(idle, idleIdx, selected, colWidth, scrollOffset, maxVisible)
-	col2 := renderColumnCards(waiting, waitIdx, selected, colWidth, scrollOffset, maxVisible)
-	col3 := renderColumnCards(running, runIdx, selected, colWidth, scrollOffset, maxVisible)
 
-	if len(idle) == 0 {
-		col1 = lipgloss.NewStyle().Width(colWidth).Foreground(ColorDim).Render("\n  No idle agents")
-	}
-	if len(waiting) == 0 {
-		col2 = lipgloss.NewStyle().Width(colWidth).Foreground(ColorDim).Render("\n  No waiting agents")
-	}
-	if len(running) == 0 {
-		col3 = lipgloss.NewStyle().Width(colWidth).Foreground(ColorDim).Render("\n  No running agents")
+		if i > 0 {
+			headers = append(headers, " ")
+			cols = append(cols, " ")
+		}
+		headers = append(headers, lipgloss.NewStyle().Width(colWidth).Render(hdr))
+		cols = append(cols, body)
 	}
 
-	body := lipgloss.JoinHorizontal(lipgloss.Top, col1, " ", col2, " ", col3)
+	header := lipgloss.JoinHorizontal(lipgloss.Top, headers...)
+	body := lipgloss.JoinHorizontal(lipgloss.Top, cols...)
 
 	return lipgloss.JoinVertical(lipgloss.Left, header, body)
 }
 
-func render2Col(agents []CardData, running, waiting, idle []CardData, runIdx, waitIdx, idleIdx []int, selected, width, height, scrollOffset, maxVisible int) string {
-	colWidth := (width - 4) / 2
-	if colWidth < 25 {
-		colWidth = 25
+// collapsedWidth is the width of a collapsed column strip.
+const collapsedWidth = 5
+
+// ColumnWidths splits the board width between columns. Collapsed columns get
+// a narrow strip; the rest share what's left in proportion to their Weight,
+// down to a minimum that keeps cards readable.
+func ColumnWidths(layout []Column, width int) []int {
+	n := len(layout)
+	avail := width - 2*n // one-cell gaps plus card borders
+	totalWeight := 0
+	for _, c := range layout {
+		if c.Collapsed {
+			avail -= collapsedWidth
+		} else {
+			totalWeight += max(c.Weight, 1)
+		}
 	}
-
-	// Active = running + waiting
-	var active []CardData
-	var activeIdx []int
-	active = append(active, running...)
-	activeIdx = append(activeIdx, runIdx...)
-	active = append(active, waiting...)
-	activeIdx = append(activeIdx, waitIdx...)
-
-	hdrActive := ColumnHeader.Foreground(ColorAccent).Render(fmt.Sprintf("■ ACTIVE [%d]", len(active)))
-	hdrIdle := ColumnHeader.Foreground(ColorIdle).Render(fmt.Sprintf("■ IDLE [%d]", len(idle)))
-
-	hdrActive = lipgloss.NewStyle().Width(colWidth).Render(hdrActive)
-	hdrIdle = lipgloss.NewStyle().Width(colWidth).Render(hdrIdle)
-
-	header := lipgloss.JoinHorizontal(lipgloss.Top, hdrIdle, " ", hdrActive)
-
-	col1 := renderColumnCards(idle, idleIdx, selected, colWidth, scrollOffset, maxVisible)
-	col2 := renderColumnCards(active, activeIdx, selected, colWidth, scrollOffset, maxVisible)
-
-	if len(idle) == 0 {
-		col1 = lipgloss.NewStyle().Width(colWidth).Foreground(ColorDim).Render("\n  No idle agents")
+	minWidth := 20
+	if n < 3 {
+		minWidth = 25
 	}
-	if len(active) == 0 {
-		col2 = lipgloss.NewStyle().Width(colWidth).Foreground(ColorDim).Render("\n  No active agents")
+
+	widths := make([]int, n)
+	for i, c := range layout {
+		if c.Collapsed {
+			widths[i] = collapsedWidth
+			continue
+		}
+		widths[i] = avail * max(c.Weight, 1) / totalWeight
+		if widths[i] < minWidth {
+			widths[i] = minWidth
+		}
 	}
+	return widths
+}
 
-	body := lipgloss.JoinHorizontal(lipgloss.Top, col1, " ", col2)
+// collapsedColumn renders a collapsed column's header (fold marker and
+// count) and its title spelled down the strip.
+func collapsedColumn(c Column, count int) (header, body string) {
+	style := lipgloss.NewStyle().Foreground(c.Color).Bold(true)
+	header = style.Render(fmt.Sprintf("▸%d", count))
+	letters := strings.Split(c.Title, "")
+	body = lipgloss.NewStyle().Width(collapsedWidth).Foreground(ColorDim).Render("\n" + strings.Join(letters, "\n"))
+	return header, body
+}
 
-	return lipgloss.JoinVertical(lipgloss.Left, header, body)
+// pick returns the cards at the given flat indices, in order.
+func pick(agents []CardData, idx []int) []CardData {
+	out := make([]CardData, len(idx))
+	for i, j := range idx {
+		out[i] = agents[j]
+	}
+	return out
 }
 
 func renderColumnCards(cards []CardData, indices []int, selected, width, scrollOffset, maxVisible int) string {
@@ -132,26 +134,39 @@ func renderColumnCards(cards []CardData, indices []int, selected, width, scrollO
 }
 
 // RenderTitle renders the title bar.
-// activeWorkspace is shown in parentheses next to the title when non-empty.
+// label (the board and active workspace) is shown in parentheses next to the title when non-empty.
 // updateVersion is shown as a bordered badge next to the title when non-empty (e.g. "0.6.0").
-func RenderTitle(width int, agentCount int, mode int, updateVersion string, activeWorkspace string) string {
+func RenderTitle(width int, agentCount int, mode int, updateVersion string, label string, usage UsageInfo, res ResourceInfo) string {
 	titleText := "TicketTok"
-	if activeWorkspace != "" {
-		titleText += fmt.Sprintf(" (%s)", activeWorkspace)
+	if label != "" {
+		titleText += fmt.Sprintf(" (%s)", label)
 	}
 	title := TitleBar.Render(titleText)
 
 	if updateVersion != "" {
 		badge := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#d97706")).
+			Foreground(ColorWarn).
 			Bold(true).
 			Render(fmt.Sprintf("(%s available — [U] to update)", updateVersion))
 		title += " " + badge
 	}
 
 	modeStr := fmt.Sprintf("[%d-col]", mode)
+	if mode == 4 {
+		modeStr = "[list]"
+	}
 	count := DimText.Render(fmt.Sprintf("%d agents", agentCount))
 	right := lipgloss.JoinHorizontal(lipgloss.Top, count, "  ", DimText.Render(modeStr))
+	if u := FormatUsage(usage); u != "" {
+		right = DimText.Render(u) + "  " + right
+	}
+	if r := FormatResources(res); r != "" {
+		style := DimText
+		if res.Warn {
+			style = lipgloss.NewStyle().Foreground(ColorFailed).Bold(true)
+		}
+		right = style.Render(r) + "  " + right
+	}
 
 	gap := width - lipgloss.Width(title) - lipgloss.Width(right) - 2
 	if gap < 1 {
@@ -169,19 +184,13 @@ func RenderTitle(width int, agentCount int, mode int, updateVersion string, acti
 // When updateAvailable is true, an [U]pdate hint is appended.
 // When remoteOn is true, a REMOTE badge and [Ctrl+R]emote toggle are shown.
 func RenderFooter(width int, mode int, updateAvailable bool, remoteOn bool) string {
-	var keys string
-	switch mode {
-	case 1:
-		keys = "[↑/↓]Nav  [N]ew  [Enter]Zoom  [X]Kill  [S]end  [A]uto-approve  [B]atch  [D]iscover  [C]lear  [W]orkspace  [Ctrl+R]emote  [1/2/3]Mode  [Q]uit"
-	default:
-		keys = "[↑/↓]Nav  [←/→]Column  [N]ew  [Enter]Zoom  [X]Kill  [S]end  [A]uto-approve  [B]atch  [D]iscover  [C]lear  [W]orkspace  [Ctrl+R]emote  [1/2/3]Mode  [Q]uit"
-	}
+	keys := FooterKeys(DashboardKeys, mode != 1)
 	if updateAvailable {
 		keys += "  [U]pdate"
 	}
 	if remoteOn {
 		badge := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#22c55e")).
+			Foreground(ColorRunning).
 			Bold(true).
 			Render(" REMOTE")
 		keys += "  " + badge
@@ -18,7 +18,7 @@ func TestRenderTitle(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := RenderTitle(tt.width, tt.agentCount, tt.mode, "", "")
+			got := RenderTitle(tt.width, tt.agentCount, tt.mode, "", "", UsageInfo{}, ResourceInfo{})
 			if !strings.Contains(got, "TicketTok") {
 				t.Error("RenderTitle does not contain 'TicketTok'")
 			}
@@ -32,7 +32,7 @@ func TestRenderTitle(t *testing.T) {
 	}
 
 	t.Run("shows update badge", func(t *testing.T) {
-		got := RenderTitle(120, 3, 3, "0.6.0", "")
+		got := RenderTitle(120, 3, 3, "0.6.0", "", UsageInfo{}, ResourceInfo{})
 		if !strings.Contains(got, "0.6.0") {
 			t.Error("RenderTitle should show update version")
 		}
@@ -40,6 +40,13 @@ func TestRenderTitle(t *testing.T) {
 			t.Error("RenderTitle should show 'available' badge")
 		}
 	})
+
+	t.Run("shows summed usage", func(t *testing.T) {
+		got := RenderTitle(120, 3, 3, "", "", UsageInfo{Tokens: 12_340, Cost: 0.5}, ResourceInfo{})
+		if !strings.Contains(got, "12.3k tok · $0.50") {
+			t.Errorf("RenderTitle should show token and cost totals, got %q", got)
+		}
+	})
 }
 
 func TestRenderFooter(t *testing.T) {
@@ -95,3 +102,70 @@ func TestRenderFooter(t *testing.T) {
 		}
 	})
 }
+
+func TestRenderBoardCustomLayout(t *testing.T) {
+	layout := []Column{
+		{Title: "TODO", Color: ColorIdle, Statuses: []string{"IDLE"}},
+		{Title: "REVIEW", Color: ColorAccent, Statuses: []string{"DONE"}},
+		{Title: "DOING", Color: ColorRunning, Statuses: []string{"RUNNING"}},
+		{Title: "BLOCKED", Color: ColorWaiting, Statuses: []string{"WAITING"}},
+	}
+	cards := []CardData{
+		{Name: "a", Status: "DONE"},
+		{Name: "b", Status: "RUNNING"},
+	}
+	got := RenderBoard(cards, layout, 0, 160, 40, 0, 3, SortCreated)
+	for _, want := range []string{"TODO [0]", "REVIEW [1]", "DOING [1]", "BLOCKED [0]", "No blocked agents"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderBoard() missing %q", want)
+		}
+	}
+}
+
+func TestRenderBoardPinned(t *testing.T) {
+	cards := []CardData{
+		{Name: "a", Status: "DONE", Pin: "RUNNING"},
+		{Name: "b", Status: "DONE", Pin: "NOWHERE"},
+	}
+	got := RenderBoard(cards, ThreeColumnLayout, 0, 160, 40, 0, 3, SortCreated)
+	// Pinned card moves to RUNNING; an unknown pin falls back to status
+	if !strings.Contains(got, "RUNNING [1]") || !strings.Contains(got, "IDLE [1]") {
+		t.Errorf("RenderBoard() did not honor pin:\n%s", got)
+	}
+}
+
+func TestColumnWidths(t *testing.T) {
+	layout := []Column{
+		{Title: "IDLE", Collapsed: true},
+		{Title: "WAITING"},
+		{Title: "RUNNING", Weight: 3},
+	}
+	got := ColumnWidths(layout, 166)
+	// 166 - 6 gaps - 5 collapsed = 155, split 1:3
+	if got[0] != collapsedWidth || got[1] != 38 || got[2] != 116 {
+		t.Errorf("ColumnWidths() = %v, want [%d 38 116]", got, collapsedWidth)
+	}
+
+	even := ColumnWidths(ThreeColumnLayout, 126)
+	if even[0] != 40 || even[1] != 40 || even[2] != 40 {
+		t.Errorf("ColumnWidths(default) = %v, want equal thirds of 120", even)
+	}
+}
+
+func TestRenderBoardCollapsed(t *testing.T) {
+	layout := []Column{
+		{Title: "IDLE", Color: ColorIdle, Statuses: []string{"IDLE"}, Collapsed: true},
+		{Title: "RUNNING", Color: ColorRunning, Statuses: []string{"RUNNING"}},
+	}
+	cards := []CardData{
+		{Name: "hidden-agent", Status: "IDLE"},
+		{Name: "busy", Status: "RUNNING"},
+	}
+	got := RenderBoard(cards, layout, 1, 120, 40, 0, 3, SortCreated)
+	if strings.Contains(got, "hidden-agent") {
+		t.Errorf("collapsed column should not render its cards:\n%s", got)
+	}
+	if !strings.Contains(got, "▸1") || !strings.Contains(got, "busy") {
+		t.Errorf("RenderBoard() = \n%s\nwant collapsed count and running card", got)
+	}
+}
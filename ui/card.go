@@ -21,7 +21,234 @@ type CardData struct {
 	Preview    []string
 	Selected    bool
 	Discovered  bool
+	Backend     string // short backend tag like "CC", "" to hide
 	AutoApprove bool
+	KeepAlive   bool     // restarted automatically if its session dies
+	Priority    string   // "high" or "low", "" for normal
+	Attempt     int      // which try at its task, shown once it's retried
+	Prompt      string   // initial task, shown as a one-line summary
+	Tags        []string // user labels, shown as #tag
+	Swarm       string   // swarm it was started in, shown before the tags
+	Pin         string   // column the agent is pinned to, "" if placed by status
+	Note        string   // user's free-text note, first line shown
+	Chain       string   // pending dependencies like "waiting for api; then tests"
+	Race        string   // the backends it's racing against, "" outside a race
+	Branch      string   // branch created for the agent at spawn
+	Idle        string   // idle shutdown warning, or that it was detached
+	Throttle    string   // when a THROTTLED agent's rate limit resets
+	Failure     string   // why an ERROR agent failed
+	Exit        string   // why a DONE agent ended
+	Broken      string   // output line of a failed build or test run
+	Summary     string   // first line of what a finished agent said last
+	Question    string   // the open question an ASK agent ended on
+	Diff        DiffInfo // uncommitted changes in Dir
+	Repo        RepoInfo // dirty/ahead/behind state of Dir's repo
+	Usage       UsageInfo
+	Res         ResourceInfo // CPU and memory of its processes
+	Check       CheckInfo    // result of the project's validation command
+	Todos       TodoInfo     // the agent's own task list, if it keeps one
+	Subagents   []SubagentInfo
+}
+
+// SubagentInfo is a subagent shown nested under its parent's card.
+type SubagentInfo struct {
+	Name   string
+	Status string
+	Age    time.Duration // running time so far, or total once finished
+}
+
+// maxSubagentLines caps the subagents listed on a card; older ones are
+// summed up in a single line.
+const maxSubagentLines = 4
+
+// subagentLines renders subagents as an indented tree under the card
+// header, newest last, or "" when there are none.
+func subagentLines(subs []SubagentInfo, width int) string {
+	if len(subs) == 0 {
+		return ""
+	}
+	var lines []string
+	if hidden := len(subs) - maxSubagentLines; hidden > 0 {
+		lines = append(lines, DimText.Render(fmt.Sprintf("↳ +%d earlier subagents", hidden)))
+		subs = subs[hidden:]
+	}
+	for _, s := range subs {
+		age := formatDuration(s.Age)
+		name := s.Name
+		if max := width - len(age) - 6; len(name) > max && max > 1 {
+			name = name[:max-1] + "…"
+		}
+		text := name + " " + DimText.Render(age)
+		if s.Status != "RUNNING" {
+			text = DimText.Render(name + " " + age)
+		}
+		lines = append(lines, DimText.Render("↳ ")+StatusDot(s.Status)+" "+text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// UsageInfo is an agent's token spend as reported by its backend.
+type UsageInfo struct {
+	Tokens int64
+	Cost   float64 // USD, 0 if unknown
+	// Partial marks a total whose Tokens include agents on backends that
+	// don't report cost, so Cost leaves their spend out
+	Partial bool
+}
+
+// FormatUsage renders "12.3k tok · $0.42", leaving out parts that are
+// unknown, or "" when nothing has been reported. A partial cost reads
+// "$0.42+".
+func FormatUsage(u UsageInfo) string {
+	var parts []string
+	if u.Tokens > 0 {
+		parts = append(parts, formatTokens(u.Tokens)+" tok")
+	}
+	if u.Cost > 0 {
+		cost := fmt.Sprintf("$%.2f", u.Cost)
+		if u.Partial {
+			cost += "+"
+		}
+		parts = append(parts, cost)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// formatTokens abbreviates a token count: 950, 12.3k, 1.2M.
+func formatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// usageSuffix appends the usage summary to a card's status time line.
+func usageSuffix(line string, u UsageInfo) string {
+	if s := FormatUsage(u); s != "" {
+		return line + DimText.Render("  "+s)
+	}
+	return line
+}
+
+// ResourceInfo is what an agent's processes use.
+type ResourceInfo struct {
+	CPU  float64 // percent of one core
+	RSS  int64   // resident memory in bytes
+	Warn bool    // memory past the configured limit
+}
+
+// FormatResources renders "12% cpu · 480M", or "" before the first
+// reading.
+func FormatResources(r ResourceInfo) string {
+	if r.RSS == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%% cpu · %s", r.CPU, formatBytes(r.RSS))
+}
+
+// formatBytes abbreviates a memory size: 950K, 480M, 2.1G.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1fG", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%dM", n>>20)
+	}
+	return fmt.Sprintf("%dK", n>>10)
+}
+
+// resourceSuffix appends the resource reading to a card's status time
+// line, in the failure color once memory is past its limit.
+func resourceSuffix(line string, r ResourceInfo) string {
+	s := FormatResources(r)
+	if s == "" {
+		return line
+	}
+	if r.Warn {
+		return line + "  " + lipgloss.NewStyle().Foreground(ColorFailed).Bold(true).Render("⚠ "+s)
+	}
+	return line + DimText.Render("  "+s)
+}
+
+// TodoInfo is progress through an agent's task list.
+type TodoInfo struct {
+	Done  int
+	Total int
+}
+
+// todoSuffix appends "☑ 3/7 tasks" to line when the agent has a task list.
+func todoSuffix(line string, t TodoInfo) string {
+	if t.Total == 0 {
+		return line
+	}
+	color := ColorAccent
+	if t.Done == t.Total {
+		color = ColorRunning
+	}
+	return line + "  " + lipgloss.NewStyle().Foreground(color).Render(fmt.Sprintf("☑ %d/%d tasks", t.Done, t.Total))
+}
+
+// RepoInfo is the git working tree and upstream state shown as icons.
+type RepoInfo struct {
+	Changed  int
+	Ahead    int
+	Behind   int
+	Upstream bool
+}
+
+// repoIcons renders "✎3 ↑2 ↓1" for uncommitted entries and commits
+// ahead/behind upstream, or "" when the repo is clean and in sync.
+func repoIcons(r RepoInfo) string {
+	var icons []string
+	if r.Changed > 0 {
+		icons = append(icons, lipgloss.NewStyle().Foreground(ColorWarn).Render(fmt.Sprintf("✎%d", r.Changed)))
+	}
+	if r.Ahead > 0 {
+		icons = append(icons, lipgloss.NewStyle().Foreground(ColorAccent).Render(fmt.Sprintf("↑%d", r.Ahead)))
+	}
+	if r.Behind > 0 {
+		icons = append(icons, lipgloss.NewStyle().Foreground(ColorIdle).Render(fmt.Sprintf("↓%d", r.Behind)))
+	}
+	return strings.Join(icons, " ")
+}
+
+// gitLine combines the diff summary and repo icons, or "" when there is
+// nothing to report.
+func gitLine(d DiffInfo, r RepoInfo) string {
+	diff, icons := diffLine(d), repoIcons(r)
+	switch {
+	case diff == "":
+		return icons
+	case icons == "":
+		return diff
+	}
+	return diff + "  " + icons
+}
+
+// DiffInfo summarizes an agent's uncommitted git changes.
+type DiffInfo struct {
+	Files   int
+	Added   int
+	Removed int
+	Stat    string // full `git diff --stat` output for the detail panel
+}
+
+// diffLine renders "+124 −37 across 6 files" with colored counts, or ""
+// for a clean tree.
+func diffLine(d DiffInfo) string {
+	if d.Files == 0 {
+		return ""
+	}
+	files := "files"
+	if d.Files == 1 {
+		files = "file"
+	}
+	return lipgloss.NewStyle().Foreground(ColorRunning).Render(fmt.Sprintf("+%d", d.Added)) + " " +
+		lipgloss.NewStyle().Foreground(ColorWaiting).Render(fmt.Sprintf("−%d", d.Removed)) +
+		DimText.Render(fmt.Sprintf(" across %d %s", d.Files, files))
 }
 
 // RenderCard renders a single agent card at the given width.
@@ -37,7 +264,13 @@ func RenderCard(d CardData, width int) string {
 	if d.Discovered {
 		nameStr += DimText.Render(" [ext]")
 	}
+	if d.Pin != "" {
+		nameStr += DimText.Render(" [pinned]")
+	}
 	name := AgentName.Render(nameStr)
+	if d.Backend != "" {
+		name = lipgloss.JoinHorizontal(lipgloss.Top, name, " ", BackendTag(d.Backend))
+	}
 	header := lipgloss.JoinHorizontal(lipgloss.Top, name, "  ", badge)
 	if d.Mode != "" {
 		modeTag := ModeBadgeFor(d.Mode)
@@ -46,6 +279,15 @@ func RenderCard(d CardData, width int) string {
 	if d.AutoApprove {
 		header = lipgloss.JoinHorizontal(lipgloss.Top, header, " ", BadgeAutoApprove.Render("AUTO"))
 	}
+	if d.KeepAlive {
+		header = lipgloss.JoinHorizontal(lipgloss.Top, header, " ", BadgeKeepAlive.Render("KEEP"))
+	}
+	if badge := priorityBadge(d.Priority); badge != "" {
+		header = lipgloss.JoinHorizontal(lipgloss.Top, header, " ", badge)
+	}
+	if badge := attemptBadge(d.Attempt); badge != "" {
+		header = lipgloss.JoinHorizontal(lipgloss.Top, header, " ", badge)
+	}
 
 	// Reactive subtitle from pane title
 	inner := width - 6 // border + padding
@@ -66,10 +308,14 @@ func RenderCard(d CardData, width int) string {
 	dirLine := DimText.Render("DIR: " + dir)
 
 	// Uptime
-	uptimeLine := statusTimeLine(d.Status, d.Uptime, d.Since)
+	uptimeLine := todoSuffix(resourceSuffix(usageSuffix(statusTimeLine(d.Status, d.Uptime, d.Since), d.Usage), d.Res), d.Todos)
 
 	// Separator
 	sep := Separator.Render(strings.Repeat("─", inner))
+	taskLine := promptLine(d.Prompt, inner)
+	tagsLine := tagLine(d.Swarm, d.Tags, inner)
+	notesLine := noteLine(d.Note, inner)
+	chainsLine := chainLine(d.Chain, inner)
 
 	// Preview
 	var previewStr string
@@ -84,7 +330,7 @@ func RenderCard(d CardData, width int) string {
 				lines[i] = l[:inner-1] + "…"
 			}
 		}
-		previewStr = PreviewText.Render(strings.Join(lines, "\n"))
+		previewStr = previewText(lines, d.Broken)
 	} else {
 		previewStr = DimText.Render("(no output yet)")
 	}
@@ -93,12 +339,196 @@ func RenderCard(d CardData, width int) string {
 	if titleLine != "" {
 		parts = append(parts, titleLine)
 	}
-	parts = append(parts, dirLine, uptimeLine, sep, previewStr)
+	parts = append(parts, dirLine)
+	if branch := branchLine(d.Branch, inner); branch != "" {
+		parts = append(parts, branch)
+	}
+	if git := gitLine(d.Diff, d.Repo); git != "" {
+		parts = append(parts, git)
+	}
+	if tagsLine != "" {
+		parts = append(parts, tagsLine)
+	}
+	if taskLine != "" {
+		parts = append(parts, taskLine)
+	}
+	if chainsLine != "" {
+		parts = append(parts, chainsLine)
+	}
+	if race := noticeLine("⚑", d.Race, ColorAccent, inner); race != "" {
+		parts = append(parts, race)
+	}
+	if failure := noticeLine("✗", d.Failure, ColorFailed, inner); failure != "" {
+		parts = append(parts, failure)
+	}
+	if exit := noticeLine("■", d.Exit, ColorDim, inner); exit != "" {
+		parts = append(parts, exit)
+	}
+	if broken := noticeLine("✗", d.Broken, ColorFailed, inner); broken != "" {
+		parts = append(parts, broken)
+	}
+	if check := checkLine(d.Check, inner); check != "" {
+		parts = append(parts, check)
+	}
+	if summary := noticeLine("»", d.Summary, ColorText, inner); summary != "" {
+		parts = append(parts, summary)
+	}
+	if question := noticeLine("?", d.Question, ColorWaiting, inner); question != "" {
+		parts = append(parts, question)
+	}
+	if idle := noticeLine("⏻", d.Idle, ColorWarn, inner); idle != "" {
+		parts = append(parts, idle)
+	}
+	if throttle := noticeLine("◷", d.Throttle, ColorWarn, inner); throttle != "" {
+		parts = append(parts, throttle)
+	}
+	if notesLine != "" {
+		parts = append(parts, notesLine)
+	}
+	if subs := subagentLines(d.Subagents, inner); subs != "" {
+		parts = append(parts, subs)
+	}
+	parts = append(parts, uptimeLine, sep, previewStr)
 	content := lipgloss.JoinVertical(lipgloss.Left, parts...)
 
 	return style.Render(content)
 }
 
+// noteLine renders the first line of a note truncated to width, or "" when
+// there is no note.
+func noteLine(note string, width int) string {
+	if note == "" {
+		return ""
+	}
+	first, _, _ := strings.Cut(note, "\n")
+	t := "NOTE: " + first
+	if len(t) > width {
+		t = t[:width-1] + "…"
+	}
+	return lipgloss.NewStyle().Foreground(ColorWarn).Render(t)
+}
+
+// branchLine renders the agent's own branch truncated to width, or "" when
+// it works on whatever the checkout has out.
+func branchLine(branch string, width int) string {
+	if branch == "" {
+		return ""
+	}
+	t := "BRANCH: " + branch
+	if len(t) > width {
+		t = t[:width-1] + "…"
+	}
+	return DimText.Render(t)
+}
+
+// chainLine renders an agent's pending dependencies truncated to width,
+// or "" when it's in no chain.
+func chainLine(chain string, width int) string {
+	if chain == "" {
+		return ""
+	}
+	t := "CHAIN: " + chain
+	if len(t) > width {
+		t = t[:width-1] + "…"
+	}
+	return lipgloss.NewStyle().Foreground(ColorAccent).Render(t)
+}
+
+// CheckInfo is the latest result of an agent's validation command.
+type CheckInfo struct {
+	Text    string // "go test ./... passed", or how it failed
+	Running bool
+	Failed  bool
+}
+
+// checkLine renders a validation result: green when it passed, red when
+// it failed, dim while it runs. "" when there is none.
+func checkLine(c CheckInfo, width int) string {
+	switch {
+	case c.Running:
+		return noticeLine("◌", c.Text, ColorDim, width)
+	case c.Failed:
+		return noticeLine("✗", c.Text, ColorFailed, width)
+	}
+	return noticeLine("✓", c.Text, ColorRunning, width)
+}
+
+// previewText renders preview lines dim, but the one reporting a failed
+// build or test run, if shown, in red.
+func previewText(lines []string, broken string) string {
+	if broken == "" {
+		return PreviewText.Render(strings.Join(lines, "\n"))
+	}
+	failing := lipgloss.NewStyle().Foreground(ColorFailed)
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		if isFailingLine(l, broken) {
+			out[i] = failing.Render(l)
+		} else {
+			out[i] = PreviewText.Render(l)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// isFailingLine reports whether preview line l is the failing line, either
+// of them possibly cut short with "…".
+func isFailingLine(l, broken string) bool {
+	l = strings.TrimSuffix(strings.TrimSpace(strings.TrimLeft(l, " \t⎿│●⏺")), "…")
+	broken = strings.TrimSuffix(broken, "…")
+	if l == "" {
+		return false
+	}
+	return strings.HasPrefix(l, broken) || strings.HasPrefix(broken, l)
+}
+
+// noticeLine renders a notice like an idle shutdown warning behind its
+// icon in color, truncated to width, or "" when there is none.
+func noticeLine(icon, notice string, color lipgloss.Color, width int) string {
+	if notice == "" {
+		return ""
+	}
+	t := icon + " " + notice
+	if len([]rune(t)) > width {
+		t = string([]rune(t)[:width-1]) + "…"
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(t)
+}
+
+// promptLine renders the first line of an initial prompt truncated to width,
+// or "" when there is no prompt.
+func promptLine(prompt string, width int) string {
+	if prompt == "" {
+		return ""
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(prompt), "\n")
+	t := "TASK: " + first
+	if len(t) > width {
+		t = t[:width-1] + "…"
+	}
+	return DimText.Render(t)
+}
+
+// tagLine renders an agent's swarm and tags as "⬡ swarm #a #b" truncated
+// to width, or "" when it has neither.
+func tagLine(swarm string, tags []string, width int) string {
+	var words []string
+	if swarm != "" {
+		words = append(words, "⬡ "+swarm)
+	}
+	for _, tag := range tags {
+		words = append(words, "#"+tag)
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	t := strings.Join(words, " ")
+	if r := []rune(t); len(r) > width {
+		t = string(r[:width-1]) + "…"
+	}
+	return lipgloss.NewStyle().Foreground(ColorAccent).Render(t)
+}
+
 // RenderCarouselCard renders an expanded card for carousel mode.
 func RenderCarouselCard(d CardData, width int, previewLines int) string {
 	style := CarouselCard.Width(width - 4)
@@ -108,7 +538,13 @@ func RenderCarouselCard(d CardData, width int, previewLines int) string {
 	if d.Discovered {
 		nameStr += DimText.Render(" [ext]")
 	}
+	if d.Pin != "" {
+		nameStr += DimText.Render(" [pinned]")
+	}
 	name := AgentName.Render(nameStr)
+	if d.Backend != "" {
+		name = lipgloss.JoinHorizontal(lipgloss.Top, name, " ", BackendTag(d.Backend))
+	}
 	header := lipgloss.JoinHorizontal(lipgloss.Top, name, "  ", badge)
 	if d.Mode != "" {
 		modeTag := ModeBadgeFor(d.Mode)
@@ -117,6 +553,15 @@ func RenderCarouselCard(d CardData, width int, previewLines int) string {
 	if d.AutoApprove {
 		header = lipgloss.JoinHorizontal(lipgloss.Top, header, " ", BadgeAutoApprove.Render("AUTO"))
 	}
+	if d.KeepAlive {
+		header = lipgloss.JoinHorizontal(lipgloss.Top, header, " ", BadgeKeepAlive.Render("KEEP"))
+	}
+	if badge := priorityBadge(d.Priority); badge != "" {
+		header = lipgloss.JoinHorizontal(lipgloss.Top, header, " ", badge)
+	}
+	if badge := attemptBadge(d.Attempt); badge != "" {
+		header = lipgloss.JoinHorizontal(lipgloss.Top, header, " ", badge)
+	}
 
 	// Reactive subtitle from pane title
 	inner := width - 8
@@ -135,9 +580,13 @@ func RenderCarouselCard(d CardData, width int, previewLines int) string {
 	dir := shortenDir(d.Dir)
 	dirLine := DimText.Render("PROJECT: " + dir)
 
-	uptimeLine := statusTimeLine(d.Status, d.Uptime, d.Since)
+	uptimeLine := todoSuffix(resourceSuffix(usageSuffix(statusTimeLine(d.Status, d.Uptime, d.Since), d.Usage), d.Res), d.Todos)
 
 	sep := Separator.Render(strings.Repeat("─", inner))
+	taskLine := promptLine(d.Prompt, inner)
+	tagsLine := tagLine(d.Swarm, d.Tags, inner)
+	notesLine := noteLine(d.Note, inner)
+	chainsLine := chainLine(d.Chain, inner)
 
 	// Extended preview
 	var previewStr string
@@ -151,7 +600,7 @@ func RenderCarouselCard(d CardData, width int, previewLines int) string {
 				lines[i] = l[:inner-1] + "…"
 			}
 		}
-		previewStr = PreviewText.Render(strings.Join(lines, "\n"))
+		previewStr = previewText(lines, d.Broken)
 	} else {
 		previewStr = DimText.Render("(no output yet)")
 	}
@@ -160,7 +609,56 @@ func RenderCarouselCard(d CardData, width int, previewLines int) string {
 	if titleLine != "" {
 		parts = append(parts, titleLine)
 	}
-	parts = append(parts, dirLine, uptimeLine, sep, previewStr)
+	parts = append(parts, dirLine)
+	if branch := branchLine(d.Branch, inner); branch != "" {
+		parts = append(parts, branch)
+	}
+	if git := gitLine(d.Diff, d.Repo); git != "" {
+		parts = append(parts, git)
+	}
+	if tagsLine != "" {
+		parts = append(parts, tagsLine)
+	}
+	if taskLine != "" {
+		parts = append(parts, taskLine)
+	}
+	if chainsLine != "" {
+		parts = append(parts, chainsLine)
+	}
+	if race := noticeLine("⚑", d.Race, ColorAccent, inner); race != "" {
+		parts = append(parts, race)
+	}
+	if failure := noticeLine("✗", d.Failure, ColorFailed, inner); failure != "" {
+		parts = append(parts, failure)
+	}
+	if exit := noticeLine("■", d.Exit, ColorDim, inner); exit != "" {
+		parts = append(parts, exit)
+	}
+	if broken := noticeLine("✗", d.Broken, ColorFailed, inner); broken != "" {
+		parts = append(parts, broken)
+	}
+	if check := checkLine(d.Check, inner); check != "" {
+		parts = append(parts, check)
+	}
+	if summary := noticeLine("»", d.Summary, ColorText, inner); summary != "" {
+		parts = append(parts, summary)
+	}
+	if question := noticeLine("?", d.Question, ColorWaiting, inner); question != "" {
+		parts = append(parts, question)
+	}
+	if idle := noticeLine("⏻", d.Idle, ColorWarn, inner); idle != "" {
+		parts = append(parts, idle)
+	}
+	if throttle := noticeLine("◷", d.Throttle, ColorWarn, inner); throttle != "" {
+		parts = append(parts, throttle)
+	}
+	if notesLine != "" {
+		parts = append(parts, notesLine)
+	}
+	if subs := subagentLines(d.Subagents, inner); subs != "" {
+		parts = append(parts, subs)
+	}
+	parts = append(parts, uptimeLine, sep, previewStr)
 	content := lipgloss.JoinVertical(lipgloss.Left, parts...)
 
 	return style.Render(content)
@@ -189,17 +687,48 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
 }
 
+// Time-in-status thresholds: fresh below ageFresh, aging below ageStale,
+// stale beyond.
+const (
+	ageFresh = 5 * time.Minute
+	ageStale = 30 * time.Minute
+)
+
+// AgeColor colors a time in status green, yellow, then red as it grows.
+func AgeColor(since time.Duration) lipgloss.Color {
+	switch {
+	case since < ageFresh:
+		return ColorRunning
+	case since < ageStale:
+		return ColorWarn
+	}
+	return ColorError
+}
+
 func statusTimeLine(status string, uptime, since time.Duration) string {
 	dur := formatDuration(since)
+	age := lipgloss.NewStyle().Foreground(AgeColor(since)).Render(dur)
 	switch status {
 	case "RUNNING":
-		return lipgloss.NewStyle().Foreground(ColorRunning).Render("IN-PROGRESS: " + dur)
+		return lipgloss.NewStyle().Foreground(ColorRunning).Render("IN-PROGRESS: ") + age
 	case "WAITING":
-		return lipgloss.NewStyle().Foreground(ColorWaiting).Bold(true).Render("WAITING: " + dur)
+		return lipgloss.NewStyle().Foreground(ColorWaiting).Bold(true).Render("WAITING: ") + age
+	case "ASK":
+		return lipgloss.NewStyle().Foreground(ColorWaiting).Bold(true).Render("ASKED: ") + age
 	case "IDLE":
-		return lipgloss.NewStyle().Foreground(ColorIdle).Render("IDLE: " + dur)
+		return lipgloss.NewStyle().Foreground(ColorIdle).Render("IDLE: ") + age
 	case "DONE":
 		return DimText.Render("DONE: " + dur + " ago")
+	case "PENDING":
+		return DimText.Render("QUEUED: " + dur)
+	case "PAUSED":
+		return DimText.Render("PAUSED: " + dur)
+	case "THROTTLED":
+		return lipgloss.NewStyle().Foreground(ColorWarn).Render("THROTTLED: ") + age
+	case "NEEDS-REVIEW":
+		return lipgloss.NewStyle().Foreground(ColorAccent).Render("REVIEW: ") + DimText.Render(dur+" ago")
+	case "ERROR":
+		return lipgloss.NewStyle().Foreground(ColorFailed).Bold(true).Render("ERROR: ") + DimText.Render(dur+" ago")
 	default:
 		return DimText.Render("UPTIME: " + formatDuration(uptime))
 	}
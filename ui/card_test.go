@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestFormatDuration(t *testing.T) {
@@ -53,3 +55,183 @@ func TestShortenDir(t *testing.T) {
 		})
 	}
 }
+
+func TestPromptLine(t *testing.T) {
+	if got := promptLine("", 40); got != "" {
+		t.Errorf("promptLine(\"\") = %q, want empty", got)
+	}
+	got := promptLine("Fix the login bug\nthen add tests", 40)
+	if !strings.Contains(got, "TASK: Fix the login bug") || strings.Contains(got, "then add tests") {
+		t.Errorf("promptLine() = %q, want first line only", got)
+	}
+	if got := promptLine(strings.Repeat("x", 100), 20); !strings.Contains(got, "…") {
+		t.Errorf("promptLine() = %q, want truncation", got)
+	}
+}
+
+func TestNoteLine(t *testing.T) {
+	if got := noteLine("", 40); got != "" {
+		t.Errorf("noteLine(\"\") = %q, want empty", got)
+	}
+	got := noteLine("waiting on review\nthen merge", 40)
+	if !strings.Contains(got, "NOTE: waiting on review") || strings.Contains(got, "then merge") {
+		t.Errorf("noteLine() = %q, want first line only", got)
+	}
+	card := RenderCard(CardData{Name: "api", Status: "IDLE", Note: "ask about auth"}, 50)
+	if !strings.Contains(card, "NOTE: ask about auth") {
+		t.Errorf("card should show the note:\n%s", card)
+	}
+}
+
+func TestChainLine(t *testing.T) {
+	if got := chainLine("", 40); got != "" {
+		t.Errorf("chainLine(\"\") = %q, want empty", got)
+	}
+	card := RenderCard(CardData{Name: "tests", Status: "IDLE", Chain: "waiting for api"}, 50)
+	if !strings.Contains(card, "CHAIN: waiting for api") {
+		t.Errorf("card should show the chain:\n%s", card)
+	}
+	if got := chainLine(strings.Repeat("x", 100), 20); !strings.Contains(got, "…") {
+		t.Errorf("chainLine() = %q, want truncation", got)
+	}
+}
+
+func TestBranchLine(t *testing.T) {
+	if got := branchLine("", 40); got != "" {
+		t.Errorf("branchLine(\"\") = %q, want empty", got)
+	}
+	card := RenderCarouselCard(CardData{Name: "api", Status: "RUNNING", Branch: "tickettok/agent-7-api"}, 60, 5)
+	if !strings.Contains(card, "BRANCH: tickettok/agent-7-api") {
+		t.Errorf("card should show the branch:\n%s", card)
+	}
+}
+
+func TestDiffLine(t *testing.T) {
+	if got := diffLine(DiffInfo{}); got != "" {
+		t.Errorf("clean tree diffLine() = %q, want empty", got)
+	}
+	got := diffLine(DiffInfo{Files: 6, Added: 124, Removed: 37})
+	for _, want := range []string{"+124", "−37", "across 6 files"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("diffLine() = %q, missing %q", got, want)
+		}
+	}
+	if got := diffLine(DiffInfo{Files: 1, Added: 1}); !strings.Contains(got, "across 1 file") || strings.Contains(got, "files") {
+		t.Errorf("diffLine() = %q, want singular file", got)
+	}
+}
+
+func TestGitLine(t *testing.T) {
+	if got := gitLine(DiffInfo{}, RepoInfo{}); got != "" {
+		t.Errorf("clean repo gitLine() = %q, want empty", got)
+	}
+	got := gitLine(DiffInfo{}, RepoInfo{Ahead: 2, Upstream: true})
+	if !strings.Contains(got, "↑2") || strings.Contains(got, "✎") || strings.Contains(got, "↓") {
+		t.Errorf("ahead-only gitLine() = %q, want just ↑2", got)
+	}
+	got = gitLine(DiffInfo{Files: 1, Added: 3}, RepoInfo{Changed: 1, Behind: 4})
+	for _, want := range []string{"+3", "✎1", "↓4"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("gitLine() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestFormatResources(t *testing.T) {
+	tests := []struct {
+		r    ResourceInfo
+		want string
+	}{
+		{ResourceInfo{}, ""},
+		{ResourceInfo{CPU: 0.4, RSS: 900 << 10}, "0% cpu · 900K"},
+		{ResourceInfo{CPU: 12.6, RSS: 480 << 20}, "13% cpu · 480M"},
+		{ResourceInfo{CPU: 150, RSS: 2200 << 20}, "150% cpu · 2.1G"},
+	}
+	for _, tt := range tests {
+		if got := FormatResources(tt.r); got != tt.want {
+			t.Errorf("FormatResources(%+v) = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestFormatUsage(t *testing.T) {
+	tests := []struct {
+		u    UsageInfo
+		want string
+	}{
+		{UsageInfo{}, ""},
+		{UsageInfo{Tokens: 950}, "950 tok"},
+		{UsageInfo{Tokens: 12_345}, "12.3k tok"},
+		{UsageInfo{Tokens: 1_260_000, Cost: 3.456}, "1.3M tok · $3.46"},
+		{UsageInfo{Cost: 0.1}, "$0.10"},
+		{UsageInfo{Tokens: 12_345, Cost: 0.42, Partial: true}, "12.3k tok · $0.42+"},
+		{UsageInfo{Tokens: 12_345, Partial: true}, "12.3k tok"},
+	}
+	for _, tt := range tests {
+		if got := FormatUsage(tt.u); got != tt.want {
+			t.Errorf("FormatUsage(%+v) = %q, want %q", tt.u, got, tt.want)
+		}
+	}
+}
+
+func TestAgeColor(t *testing.T) {
+	tests := []struct {
+		since time.Duration
+		want  lipgloss.Color
+	}{
+		{time.Minute, ColorRunning},
+		{10 * time.Minute, ColorWarn},
+		{time.Hour, ColorError},
+	}
+	for _, tt := range tests {
+		if got := AgeColor(tt.since); got != tt.want {
+			t.Errorf("AgeColor(%v) = %v, want %v", tt.since, got, tt.want)
+		}
+	}
+}
+
+func TestTodoSuffix(t *testing.T) {
+	if got := todoSuffix("3m", TodoInfo{}); got != "3m" {
+		t.Errorf("todoSuffix() without a list = %q, want the line unchanged", got)
+	}
+	if got := todoSuffix("3m", TodoInfo{Done: 3, Total: 7}); !strings.Contains(got, "3/7 tasks") {
+		t.Errorf("todoSuffix() = %q, want it to contain 3/7 tasks", got)
+	}
+}
+
+func TestSubagentLines(t *testing.T) {
+	if got := subagentLines(nil, 40); got != "" {
+		t.Errorf("subagentLines(nil) = %q, want empty", got)
+	}
+	var subs []SubagentInfo
+	for i := 0; i < 6; i++ {
+		subs = append(subs, SubagentInfo{Name: "explore", Status: "DONE", Age: time.Minute})
+	}
+	subs[5] = SubagentInfo{Name: "write tests", Status: "RUNNING", Age: 2 * time.Minute}
+	got := subagentLines(subs, 40)
+	if n := strings.Count(got, "\n") + 1; n != maxSubagentLines+1 {
+		t.Errorf("got %d lines, want %d", n, maxSubagentLines+1)
+	}
+	if !strings.Contains(got, "+2 earlier") || !strings.Contains(got, "write tests") {
+		t.Errorf("subagentLines() = %q, want the overflow line and the newest subagent", got)
+	}
+}
+
+func TestIsFailingLine(t *testing.T) {
+	broken := "FAIL\texample.com/pkg\t0.003s"
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"FAIL\texample.com/pkg\t0.003s", true},
+		{"  ⎿  FAIL\texample.com/pkg\t0.003s", true},
+		{"FAIL\texample.com/p…", true},
+		{"ok  \texample.com/other\t0.001s", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isFailingLine(tt.line, broken); got != tt.want {
+			t.Errorf("isFailingLine(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Column is one board lane. Agents land in the first column whose Statuses
+// include their status.
+type Column struct {
+	Title     string
+	Color     lipgloss.Color
+	Statuses  []string
+	Collapsed bool // drawn as a narrow strip with just its count
+	Weight    int  // share of the board width relative to other columns; 0 counts as 1
+}
+
+// ThreeColumnLayout is the default board: IDLE, WAITING, RUNNING.
+// TwoColumnLayout folds running and waiting agents into one ACTIVE lane.
+// Both are rebuilt by ApplyTheme so their colors follow the theme.
+var ThreeColumnLayout, TwoColumnLayout []Column
+
+// PausedColumn and ReviewColumn are added to the board while any agent is
+// PAUSED or NEEDS-REVIEW, unless the layout already has a column for them.
+var PausedColumn, ReviewColumn Column
+
+// columnPalette colors custom columns that don't set their own.
+var columnPalette []lipgloss.Color
+
+func buildLayouts() {
+	ThreeColumnLayout = []Column{
+		{Title: "IDLE", Color: ColorIdle, Statuses: []string{"IDLE", "DONE"}},
+		{Title: "WAITING", Color: ColorWaiting, Statuses: []string{"WAITING", "ASK", "ERROR", "STUCK", "TIMED-OUT"}},
+		{Title: "RUNNING", Color: ColorRunning, Statuses: []string{"RUNNING", "PENDING", "THROTTLED"}},
+	}
+	TwoColumnLayout = []Column{
+		{Title: "IDLE", Color: ColorIdle, Statuses: []string{"IDLE", "DONE"}},
+		{Title: "ACTIVE", Color: ColorAccent, Statuses: []string{"RUNNING", "WAITING", "ASK", "ERROR", "STUCK", "TIMED-OUT", "PENDING", "THROTTLED"}},
+	}
+	PausedColumn = Column{Title: "PAUSED", Color: ColorDone, Statuses: []string{"PAUSED"}}
+	ReviewColumn = Column{Title: "REVIEW", Color: ColorAccent, Statuses: []string{"NEEDS-REVIEW"}}
+	columnPalette = []lipgloss.Color{ColorIdle, ColorWaiting, ColorRunning, ColorAccent, ColorError, ColorDone}
+}
+
+// HasStatus reports whether a column in layout claims status.
+func HasStatus(layout []Column, status string) bool {
+	for _, c := range layout {
+		for _, s := range c.Statuses {
+			if strings.EqualFold(s, status) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PaletteColor returns a default color for the i-th custom column.
+func PaletteColor(i int) lipgloss.Color {
+	return columnPalette[i%len(columnPalette)]
+}
+
+// PlaceCard returns the layout column for an agent. A pin naming one of the
+// layout's columns wins; otherwise placement follows status.
+func PlaceCard(layout []Column, status, pin string) int {
+	if pin != "" {
+		for i, c := range layout {
+			if strings.EqualFold(c.Title, pin) {
+				return i
+			}
+		}
+	}
+	return ColumnIndex(layout, status)
+}
+
+// ColumnIndex returns the layout column for an agent status. Statuses no
+// column claims fall into the first column.
+func ColumnIndex(layout []Column, status string) int {
+	for i, c := range layout {
+		for _, s := range c.Statuses {
+			if strings.EqualFold(s, status) {
+				return i
+			}
+		}
+	}
+	return 0
+}
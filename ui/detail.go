@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HistoryEntry is one status change shown in the detail panel.
+type HistoryEntry struct {
+	Status string
+	At     time.Time
+}
+
+// SentEntry is one message sent to the agent, shown in the detail panel.
+type SentEntry struct {
+	At   time.Time
+	Via  string
+	Text string
+}
+
+// DetailData holds everything the detail panel shows for one agent.
+type DetailData struct {
+	CardData
+	Backend    string
+	Session    string
+	Restarts   string // keep-alive state, "" when the agent isn't kept alive
+	Branch     string // branch created for the agent, "" when it has none
+	Checkpoint string // checkpoint setting, "" when off
+	Timeout    string // max runtime and what's left of it, "" when none
+	Rules      string // the agent's own approval rules, "" when none
+	Nudge      string // the agent's nudge rule and how much of it is used, "" when none
+	History    []HistoryEntry
+	Sent       []SentEntry // messages sent to it, oldest first
+}
+
+// maxDetailHistory caps how many status changes the panel lists.
+const maxDetailHistory = 6
+
+// maxDetailSent caps how many sent messages the panel lists.
+const maxDetailSent = 5
+
+// maxDetailDiffFiles caps how many changed files the panel lists.
+const maxDetailDiffFiles = 6
+
+// RenderDetail renders the side panel for the selected agent at the given
+// size. Preview fills whatever height is left after the info sections.
+func RenderDetail(d DetailData, width, height int) string {
+	inner := width - 4 // border + padding
+	if inner < 10 {
+		inner = 10
+	}
+	clip := func(s string) string {
+		if len(s) > inner {
+			return s[:inner-1] + "…"
+		}
+		return s
+	}
+	label := lipgloss.NewStyle().Foreground(ColorDim).Width(9)
+	field := func(name, value string) string {
+		if value == "" {
+			value = "-"
+		}
+		return label.Render(name) + clip(value)
+	}
+
+	badge := StatusBadge(d.Status)
+	lines := []string{
+		AgentName.Render(clip(d.Name)) + "  " + badge,
+		statusTimeLine(d.Status, d.Uptime, d.Since),
+		"",
+		field("Path", d.Dir),
+		field("Backend", d.Backend),
+		field("Session", d.Session),
+	}
+	if d.Branch != "" {
+		lines = append(lines, field("Branch", d.Branch))
+	}
+	if d.Exit != "" {
+		lines = append(lines, field("Ended", d.Exit))
+	}
+	if d.AutoApprove {
+		lines = append(lines, field("Approve", "auto"))
+	}
+	if d.Restarts != "" {
+		lines = append(lines, field("Restart", d.Restarts))
+	}
+	if d.Checkpoint != "" {
+		lines = append(lines, field("Commit", d.Checkpoint))
+	}
+	if d.Timeout != "" {
+		lines = append(lines, field("Timeout", d.Timeout))
+	}
+	if d.Rules != "" {
+		lines = append(lines, field("Rules", d.Rules))
+	}
+	if d.Nudge != "" {
+		lines = append(lines, field("Nudge", d.Nudge))
+	}
+	if len(d.Tags) > 0 {
+		lines = append(lines, field("Tags", "#"+strings.Join(d.Tags, " #")))
+	}
+	if d.Pin != "" {
+		lines = append(lines, field("Pinned", d.Pin))
+	}
+	if icons := repoIcons(d.Repo); icons != "" {
+		lines = append(lines, label.Render("Git")+icons)
+	}
+
+	if d.Diff.Stat != "" {
+		lines = append(lines, "", ColumnHeader.Padding(0).Render("Changes")+"  "+diffLine(d.Diff))
+		stat := strings.Split(d.Diff.Stat, "\n")
+		// Drop the totals line (shown in the header) and cap the file list
+		stat = stat[:len(stat)-1]
+		if len(stat) > maxDetailDiffFiles {
+			more := len(stat) - maxDetailDiffFiles + 1
+			stat = append(stat[:maxDetailDiffFiles-1], fmt.Sprintf(" … %d more", more))
+		}
+		for _, l := range stat {
+			lines = append(lines, DimText.Render(clip(l)))
+		}
+	}
+
+	if d.Note != "" {
+		lines = append(lines, "", ColumnHeader.Padding(0).Render("Note"))
+		note := strings.Split(d.Note, "\n")
+		if len(note) > 4 {
+			note = append(note[:3], "…")
+		}
+		for _, l := range note {
+			lines = append(lines, clip(l))
+		}
+	}
+
+	if d.Prompt != "" {
+		lines = append(lines, "", ColumnHeader.Padding(0).Render("Prompt"))
+		prompt := strings.Split(strings.TrimSpace(d.Prompt), "\n")
+		if len(prompt) > 4 {
+			prompt = append(prompt[:3], "…")
+		}
+		for _, l := range prompt {
+			lines = append(lines, clip(l))
+		}
+	}
+
+	if len(d.Sent) > 0 {
+		lines = append(lines, "", ColumnHeader.Padding(0).Render("Sent"))
+		sent := d.Sent
+		if len(sent) > maxDetailSent {
+			sent = sent[len(sent)-maxDetailSent:]
+		}
+		// Newest first, a line each
+		for i := len(sent) - 1; i >= 0; i-- {
+			s := sent[i]
+			prefix := s.At.Format("Jan 02 15:04") + " " + s.Via + " "
+			line := clip(prefix + strings.Join(strings.Fields(s.Text), " "))
+			if strings.HasPrefix(line, prefix) {
+				lines = append(lines, DimText.Render(prefix)+line[len(prefix):])
+			} else {
+				lines = append(lines, DimText.Render(line))
+			}
+		}
+	}
+
+	if len(d.History) > 0 {
+		lines = append(lines, "", ColumnHeader.Padding(0).Render("History"))
+		hist := d.History
+		if len(hist) > maxDetailHistory {
+			hist = hist[len(hist)-maxDetailHistory:]
+		}
+		// Newest first
+		for i := len(hist) - 1; i >= 0; i-- {
+			h := hist[i]
+			lines = append(lines, DimText.Render(h.At.Format("Jan 02 15:04:05"))+" "+StatusDot(h.Status)+" "+h.Status)
+		}
+	}
+
+	lines = append(lines, "", ColumnHeader.Padding(0).Render("Output"))
+	// border(2) + the lines so far
+	room := height - 2 - len(lines)
+	preview := d.Preview
+	if room < 1 {
+		preview = nil
+	} else if len(preview) > room {
+		preview = preview[len(preview)-room:]
+	}
+	if len(preview) == 0 {
+		lines = append(lines, DimText.Render("(no output yet)"))
+	}
+	for _, l := range preview {
+		lines = append(lines, PreviewText.Render(clip(l)))
+	}
+
+	style := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorAccent).
+		Padding(0, 1).
+		Width(width - 2).
+		Height(height - 2)
+	return style.Render(strings.Join(lines, "\n"))
+}
+
+// DetailWidth returns the panel width for a terminal of the given width.
+func DetailWidth(total int) int {
+	w := total / 3
+	if w < 36 {
+		w = 36
+	}
+	return w
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderDetail(t *testing.T) {
+	now := time.Now()
+	d := DetailData{
+		CardData: CardData{
+			Name:    "api",
+			Dir:     "/home/me/api",
+			Status:  "WAITING",
+			Prompt:  "Fix the flaky test\nthen open a PR",
+			Tags:    []string{"backend"},
+			Preview: []string{"line one", "line two"},
+		},
+		Backend: "Claude Code",
+		Session: "tickettok_1",
+		History: []HistoryEntry{
+			{Status: "RUNNING", At: now.Add(-time.Minute)},
+			{Status: "WAITING", At: now},
+		},
+	}
+
+	got := RenderDetail(d, 60, 40)
+	for _, want := range []string{"/home/me/api", "Claude Code", "tickettok_1", "Fix the flaky test", "#backend", "History", "line two"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderDetail() missing %q", want)
+		}
+	}
+	// Newest history entry is listed first
+	if strings.Index(got, "WAITING\n") > strings.Index(got, "RUNNING") {
+		t.Error("RenderDetail() should list newest history first")
+	}
+}
+
+func TestRenderDetailSent(t *testing.T) {
+	at := time.Date(2026, 3, 4, 10, 0, 0, 0, time.Local)
+	d := DetailData{
+		CardData: CardData{Name: "api", Status: "IDLE"},
+		Sent: []SentEntry{
+			{At: at, Via: "task", Text: "Fix the flaky test"},
+			{At: at.Add(time.Minute), Via: "send", Text: "now open\na PR"},
+		},
+	}
+
+	got := RenderDetail(d, 60, 40)
+	for _, want := range []string{"Sent", "Mar 04 10:00 task", "Fix the flaky test", "now open a PR"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderDetail() missing %q:\n%s", want, got)
+		}
+	}
+	// Newest message is listed first
+	if strings.Index(got, "now open") > strings.Index(got, "Fix the flaky") {
+		t.Error("RenderDetail() should list newest messages first")
+	}
+}
+
+func TestRenderDetailDiff(t *testing.T) {
+	d := DetailData{CardData: CardData{
+		Name:   "api",
+		Status: "RUNNING",
+		Diff: DiffInfo{Files: 2, Added: 10, Removed: 3,
+			Stat: " a.go | 8 +++++++-\n b.go | 5 +++--\n 2 files changed, 10 insertions(+), 3 deletions(-)"},
+	}}
+
+	got := RenderDetail(d, 60, 40)
+	for _, want := range []string{"Changes", "+10", "a.go", "b.go"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("detail missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "2 files changed") {
+		t.Errorf("totals line should be folded into the header:\n%s", got)
+	}
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EventEntry is one line of the event feed view.
+type EventEntry struct {
+	At      time.Time
+	Kind    string
+	Agent   string
+	Message string
+}
+
+// EventLines renders entries oldest first, one line each. Timestamps drop
+// the date for events from today (relative to now).
+func EventLines(entries []EventEntry, width int, now time.Time) []string {
+	kindStyle := lipgloss.NewStyle().Bold(true).Width(9)
+	y, m, d := now.Date()
+
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		layout := "Jan 02 15:04:05"
+		if ey, em, ed := e.At.Date(); ey == y && em == m && ed == d {
+			layout = "15:04:05"
+		}
+		line := DimText.Render(lipgloss.NewStyle().Width(16).Render(e.At.Format(layout))) +
+			kindStyle.Foreground(eventColor(e.Kind)).Render(e.Kind) +
+			AgentName.Render(e.Agent)
+		if e.Message != "" {
+			line += "  " + e.Message
+		}
+		lines = append(lines, lipgloss.NewStyle().MaxWidth(width).Render(line))
+	}
+	return lines
+}
+
+func eventColor(kind string) lipgloss.Color {
+	switch kind {
+	case "SPAWN":
+		return ColorRunning
+	case "KILL":
+		return ColorWaiting
+	case "DISCOVER":
+		return ColorAccent
+	case "HANDOFF":
+		return ColorIdle
+	default:
+		return ColorDim
+	}
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventLines(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	entries := []EventEntry{
+		{At: now.Add(-48 * time.Hour), Kind: "SPAWN", Agent: "api", Message: "spawned in /dev/api"},
+		{At: now.Add(-time.Minute), Kind: "STATUS", Agent: "api", Message: "RUNNING → WAITING"},
+	}
+
+	lines := EventLines(entries, 100, now)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if !strings.Contains(lines[0], "Mar 08 12:00:00") || !strings.Contains(lines[0], "spawned in /dev/api") {
+		t.Errorf("older event should carry its date: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "11:59:00") || strings.Contains(lines[1], "Mar 10") {
+		t.Errorf("today's event should show time only: %q", lines[1])
+	}
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Group is a run of agents sharing a project (git repository). Cards of an
+// expanded group occupy cards[Start:Start+Count]; a collapsed group has no
+// cards and only its header is shown.
+type Group struct {
+	Name      string   // project label, usually the repo directory name
+	Path      string   // repo root, shown dimmed after the name
+	Start     int      // index of the group's first card
+	Count     int      // cards shown (0 when collapsed)
+	Statuses  []string // statuses of every agent in the group, shown or not
+	Collapsed bool
+}
+
+// groupRow is one scrollable row of a grouped view: a group header
+// (card < 0) or a card.
+type groupRow struct {
+	group int
+	card  int
+}
+
+func groupRows(groups []Group) []groupRow {
+	var rows []groupRow
+	for gi, g := range groups {
+		rows = append(rows, groupRow{group: gi, card: -1})
+		for i := 0; i < g.Count; i++ {
+			rows = append(rows, groupRow{group: gi, card: g.Start + i})
+		}
+	}
+	return rows
+}
+
+// GroupRowCount returns the number of scrollable rows (headers plus cards).
+func GroupRowCount(groups []Group) int {
+	return len(groupRows(groups))
+}
+
+// GroupRowOf returns the row index of card idx, or idx if it is not shown.
+func GroupRowOf(groups []Group, idx int) int {
+	for r, row := range groupRows(groups) {
+		if row.card == idx {
+			return r
+		}
+	}
+	return idx
+}
+
+// RenderGrouped renders agents under collapsible project headers. Scrolling
+// is by row, where a header and a card each count as one row. compact
+// selects single-line list rows instead of carousel cards.
+func RenderGrouped(agents []CardData, groups []Group, pos int, width, scrollOffset, maxVisible int, compact bool) string {
+	if len(groups) == 0 {
+		return DimText.Render("No agents. Press N to spawn one.")
+	}
+	rows := groupRows(groups)
+	start := scrollOffset
+	if start > len(rows) {
+		start = len(rows)
+	}
+	end := start + maxVisible
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	var rendered []string
+	for _, row := range rows[start:end] {
+		switch {
+		case row.card < 0:
+			rendered = append(rendered, RenderGroupHeader(groups[row.group], width))
+		case compact:
+			rendered = append(rendered, listRow(agents[row.card], row.card == pos, width))
+		default:
+			agents[row.card].Selected = row.card == pos
+			rendered = append(rendered, RenderCard(agents[row.card], width-2))
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rendered...)
+}
+
+// RenderGroupHeader renders a project header: fold marker, name, a status
+// dot per agent, and the agent count.
+func RenderGroupHeader(g Group, width int) string {
+	marker := "▾"
+	if g.Collapsed {
+		marker = "▸"
+	}
+	var dots []string
+	for _, s := range g.Statuses {
+		dots = append(dots, StatusDot(s))
+	}
+	noun := "agents"
+	if len(g.Statuses) == 1 {
+		noun = "agent"
+	}
+	line := ColumnHeader.Render(marker+" "+g.Name) + " " +
+		strings.Join(dots, "") + " " +
+		DimText.Render(fmt.Sprintf("%d %s", len(g.Statuses), noun))
+	if g.Path != "" && g.Path != g.Name {
+		line += DimText.Render("  " + shortenDir(g.Path))
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
+}
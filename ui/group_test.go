@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderGrouped(t *testing.T) {
+	cards := []CardData{
+		{Name: "api", Dir: "/srv/mono/api", Status: "RUNNING"},
+		{Name: "web", Dir: "/srv/mono/web", Status: "WAITING"},
+		{Name: "docs", Dir: "/srv/docs", Status: "IDLE"},
+	}
+	groups := []Group{
+		{Name: "mono", Path: "/srv/mono", Start: 0, Count: 2, Statuses: []string{"RUNNING", "WAITING"}},
+		{Name: "tools", Path: "/srv/tools", Statuses: []string{"IDLE", "IDLE"}, Collapsed: true},
+		{Name: "docs", Path: "/srv/docs", Start: 2, Count: 1, Statuses: []string{"IDLE"}},
+	}
+
+	if n := GroupRowCount(groups); n != 6 {
+		t.Errorf("GroupRowCount = %d, want 6 (3 headers + 3 cards)", n)
+	}
+	if r := GroupRowOf(groups, 2); r != 5 {
+		t.Errorf("GroupRowOf(docs) = %d, want 5", r)
+	}
+
+	got := RenderGrouped(cards, groups, 1, 100, 0, 10, true)
+	rows := strings.Split(got, "\n")
+	if len(rows) != 6 {
+		t.Fatalf("got %d rows, want 6:\n%s", len(rows), got)
+	}
+	if !strings.Contains(rows[0], "▾ mono") || !strings.Contains(rows[0], "2 agents") {
+		t.Errorf("header row = %q", rows[0])
+	}
+	if !strings.Contains(rows[3], "▸ tools") {
+		t.Errorf("collapsed header row = %q", rows[3])
+	}
+	if !strings.Contains(rows[2], "web") || !strings.Contains(rows[2], "▌") {
+		t.Errorf("selected row = %q", rows[2])
+	}
+	if !strings.Contains(rows[4], "1 agent") || !strings.Contains(rows[4], "/srv/docs") {
+		t.Errorf("docs header = %q, want singular count and repo path", rows[4])
+	}
+
+	scrolled := RenderGrouped(cards, groups, 1, 100, 4, 2, true)
+	if strings.Count(scrolled, "\n") != 1 || !strings.Contains(scrolled, "docs") {
+		t.Errorf("scrolled = %q, want the docs header and card", scrolled)
+	}
+}
@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// KeyBinding describes one key for the help overlay and footers.
+type KeyBinding struct {
+	Keys   string // as shown in the help overlay, e.g. "↑/↓ j/k"
+	Desc   string // what the key does
+	Footer string // compact footer label, "" to leave it out of the footer
+	Board  bool   // only applies in 2/3-column board mode
+}
+
+// KeySection groups the bindings active in one view.
+type KeySection struct {
+	Title    string
+	Bindings []KeyBinding
+}
+
+// DashboardKeys are the board and carousel bindings, in footer order.
+var DashboardKeys = []KeyBinding{
+	{Keys: "↑/↓ j/k", Desc: "Move selection", Footer: "[↑/↓]Nav"},
+	{Keys: "←/→ h/l", Desc: "Move between columns", Footer: "[←/→]Column", Board: true},
+	{Keys: "n", Desc: "Spawn new agent", Footer: "[N]ew"},
+	{Keys: "Enter", Desc: "Zoom into agent", Footer: "[Enter]Zoom"},
+	{Keys: "x K", Desc: "Kill selected agent", Footer: "[X]Kill"},
+	{Keys: "s", Desc: "Send message to agent", Footer: "[S]end"},
+	{Keys: "R", Desc: "Rename selected agent", Footer: "[R]ename"},
+	{Keys: "t", Desc: "Edit tags on selected agent", Footer: "[T]ags"},
+	{Keys: "e", Desc: "Edit note on selected agent", Footer: "[E]Note"},
+	{Keys: "y", Desc: "Approve a waiting agent's prompt (first option)", Footer: "[Y]es"},
+	{Keys: "Y", Desc: "Answer a waiting prompt: pick any option, e.g. deny"},
+	{Keys: "H", Desc: "Hand the task to another backend (restarts it there)"},
+	{Keys: "a", Desc: "Toggle auto-approve", Footer: "[A]uto-approve"},
+	{Keys: "A", Desc: "Adopt discovered agent"},
+	{Keys: "M", Desc: "Keep alive: restart the agent if its session dies"},
+	{Keys: "C", Desc: "Checkpoint: commit the agent's changes when it stops"},
+	{Keys: "P", Desc: "Push the agent's branch and open a pull request"},
+	{Keys: "D", Desc: "Clone agent: spawn another with its dir, backend and prompt"},
+	{Keys: "X", Desc: "Set a max runtime (timeout) for the agent"},
+	{Keys: "V", Desc: "Approval rules: answer permission prompts automatically"},
+	{Keys: "N", Desc: "Nudge: send \"continue\" when the agent stops with work left"},
+	{Keys: "F", Desc: "Pause (freeze) the agent / resume it"},
+	{Keys: "m", Desc: "Mark the agent NEEDS-REVIEW / unmark it once reviewed"},
+	{Keys: "!", Desc: "Cycle the agent's priority: normal, high, low"},
+	{Keys: "r", Desc: "Restart a STUCK or ERROR agent"},
+	{Keys: "O", Desc: "Start over: retry the task in a fresh session from its prompt"},
+	{Keys: "b", Desc: "Batch operations", Footer: "[B]atch"},
+	{Keys: "d", Desc: "Discover running instances", Footer: "[D]iscover"},
+	{Keys: "c", Desc: "Clear completed agents", Footer: "[C]lear"},
+	{Keys: "u", Desc: "Undo last kill or clear (within 30s)"},
+	{Keys: "/", Desc: "Filter agents (#tag for tags)", Footer: "[/]Filter"},
+	{Keys: "Esc", Desc: "Clear active filter"},
+	{Keys: "o", Desc: "Cycle column sort order", Footer: "[O]rder", Board: true},
+	{Keys: "p", Desc: "Pin agent to a column / unpin", Footer: "[P]in", Board: true},
+	{Keys: "i", Desc: "Toggle detail side panel", Footer: "[I]nfo", Board: true},
+	{Keys: "|", Desc: "Split view: selected + next agent", Footer: "[|]Split"},
+	{Keys: "L", Desc: "Event feed: spawns, status changes, kills", Footer: "[L]og"},
+	{Keys: "%", Desc: "Stats: running time, permission prompts and turns per agent"},
+	{Keys: "I", Desc: "Archive: search agents that were killed or cleared"},
+	{Keys: "B", Desc: "Backends: installed CLIs, versions, hooks"},
+	{Keys: "w", Desc: "Jump to next waiting agent", Footer: "[W]aiting"},
+	{Keys: "W", Desc: "Workspace manager", Footer: "[Shift+W]orkspace"},
+	{Keys: "v", Desc: "Boards: switch to another board or start a new one"},
+	{Keys: "Ctrl+R", Desc: "Toggle remote control", Footer: "[Ctrl+R]emote"},
+	{Keys: "g", Desc: "Group agents by project (git repo) or swarm", Footer: "[G]roup"},
+	{Keys: "z / Z", Desc: "Collapse selected column (or project when grouped) / expand all"},
+	{Keys: "+ / -", Desc: "Widen / narrow selected column", Board: true},
+	{Keys: "1/2/3/4", Desc: "Carousel, 2-column, full (custom) board, or compact list", Footer: "[1-4]Mode"},
+	{Keys: "U", Desc: "Install available update"},
+	{Keys: "?", Desc: "Show this help", Footer: "[?]Help"},
+	{Keys: "q", Desc: "Quit (asks first while agents are RUNNING)", Footer: "[Q]uit"},
+	{Keys: "Ctrl+C", Desc: "Quit at once, leaving sessions running"},
+}
+
+// ZoomKeys are handled by TicketTok while zoomed; everything else goes to
+// the agent.
+var ZoomKeys = []KeyBinding{
+	{Keys: "Ctrl+Q", Desc: "Return to dashboard", Footer: "[Ctrl+Q] dashboard"},
+	{Keys: "Ctrl+J", Desc: "Insert newline", Footer: "[Ctrl+J] newline"},
+	{Keys: "PgUp/PgDn", Desc: "Scroll output", Footer: "[PgUp/PgDn] scroll"},
+	{Keys: "Ctrl+F", Desc: "Search scrollback (also / while scrolled)", Footer: "[Ctrl+F] search"},
+	{Keys: "n/N", Desc: "Older/newer search match"},
+	{Keys: "Esc", Desc: "End search (while searching)"},
+	{Keys: "Ctrl+Y", Desc: "Copy mode: ↑/↓ move, v mark, y copy to clipboard", Footer: "[Ctrl+Y] copy"},
+	{Keys: "Ctrl+O", Desc: "Observe only: keys scroll locally, nothing sent", Footer: "[Ctrl+O] observe"},
+}
+
+// SplitKeys apply in the side-by-side split view.
+var SplitKeys = []KeyBinding{
+	{Keys: "Tab", Desc: "Switch focused pane", Footer: "[Tab] focus"},
+	{Keys: "↑/↓ PgUp/PgDn", Desc: "Scroll focused pane", Footer: "[↑/↓/PgUp/PgDn] scroll"},
+	{Keys: "G End", Desc: "Jump to latest output"},
+	{Keys: "←/→ h/l", Desc: "Show previous/next agent in pane", Footer: "[←/→] swap agent"},
+	{Keys: "Enter", Desc: "Zoom into focused agent", Footer: "[Enter] zoom"},
+	{Keys: "p", Desc: "Racing agents: pick the focused one, killing the others"},
+	{Keys: "Esc | q", Desc: "Back to dashboard", Footer: "[Esc] dashboard"},
+}
+
+// dialogSections documents keys inside the modal dialogs.
+var dialogSections = []KeySection{
+	{Title: "Spawn dialog", Bindings: []KeyBinding{
+		{Keys: "Tab ↑/↓", Desc: "Move between fields (↑/↓ pick the backend while its list has focus)"},
+		{Keys: "Enter", Desc: "Pick suggestion / spawn (★/↺ dirs spawn at once)"},
+		{Keys: "*", Desc: "Star/unstar highlighted directory"},
+		{Keys: "Ctrl+J", Desc: "Newline in prompt"},
+		{Keys: "Space", Desc: "Toggle auto-approve"},
+		{Keys: "←/→", Desc: "New branch or worktree / hold the prompt until another agent finishes"},
+		{Keys: "Esc", Desc: "Cancel"},
+	}},
+	{Title: "Send, rename, tags, note, filter", Bindings: []KeyBinding{
+		{Keys: "Enter", Desc: "Confirm"},
+		{Keys: "Ctrl+J", Desc: "Newline in note"},
+		{Keys: "Esc", Desc: "Cancel (clears filter)"},
+	}},
+	{Title: "Workspaces", Bindings: []KeyBinding{
+		{Keys: "↑/↓ j/k", Desc: "Select workspace"},
+		{Keys: "Enter", Desc: "Load (replace board)"},
+		{Keys: "a", Desc: "Add alongside current"},
+		{Keys: "s", Desc: "Save current board"},
+		{Keys: "d", Desc: "Delete workspace"},
+		{Keys: "Esc", Desc: "Close"},
+	}},
+	{Title: "Confirmations", Bindings: []KeyBinding{
+		{Keys: "y Enter", Desc: "Confirm"},
+		{Keys: "n Esc", Desc: "Cancel"},
+	}},
+	{Title: "Quit (q)", Bindings: []KeyBinding{
+		{Keys: "d", Desc: "Detach: leave sessions running in tmux"},
+		{Keys: "k", Desc: "Kill all managed sessions"},
+		{Keys: "Enter q", Desc: "Do the configured default (on_quit)"},
+		{Keys: "Esc", Desc: "Cancel"},
+	}},
+	{Title: "Prompt options (Y)", Bindings: []KeyBinding{
+		{Keys: "1-9", Desc: "Send that option to the agent"},
+		{Keys: "Esc", Desc: "Cancel"},
+	}},
+	{Title: "Hand off (H)", Bindings: []KeyBinding{
+		{Keys: "1-9", Desc: "Restart the task under that backend"},
+		{Keys: "Esc", Desc: "Cancel"},
+	}},
+}
+
+// HelpSections returns every view's bindings for the help overlay.
+func HelpSections() []KeySection {
+	sections := []KeySection{
+		{Title: "Board & carousel", Bindings: DashboardKeys},
+		{Title: "Zoom", Bindings: ZoomKeys},
+		{Title: "Split view", Bindings: SplitKeys},
+	}
+	return append(sections, dialogSections...)
+}
+
+// FooterKeys joins the footer labels of bindings. Board-only bindings are
+// skipped unless board is true.
+func FooterKeys(bindings []KeyBinding, board bool) string {
+	var labels []string
+	for _, b := range bindings {
+		if b.Footer == "" || (b.Board && !board) {
+			continue
+		}
+		labels = append(labels, b.Footer)
+	}
+	return strings.Join(labels, "  ")
+}
+
+// RenderHelp renders the full-screen keybinding overlay.
+func RenderHelp(width, height int) string {
+	keyStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true).Width(12)
+
+	renderSection := func(s KeySection) string {
+		lines := []string{ColumnHeader.Render(s.Title)}
+		for _, b := range s.Bindings {
+			lines = append(lines, keyStyle.Render(b.Keys)+" "+b.Desc)
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	// Dashboard keys on the left, everything else stacked on the right
+	sections := HelpSections()
+	left := renderSection(sections[0])
+	var rightParts []string
+	for _, s := range sections[1:] {
+		rightParts = append(rightParts, renderSection(s))
+	}
+	right := strings.Join(rightParts, "\n\n")
+
+	body := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(48).Render(left), "  ", right)
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		AgentName.Render("Keybindings"), "", body, "",
+		HelpStyle.Render("[?/Esc/q] close"),
+	)
+
+	dialog := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorAccent).
+		Padding(1, 2).
+		Render(content)
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, dialog)
+}
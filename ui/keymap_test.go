@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFooterKeys(t *testing.T) {
+	board := FooterKeys(DashboardKeys, true)
+	carousel := FooterKeys(DashboardKeys, false)
+
+	if !strings.Contains(board, "[←/→]Column") || !strings.Contains(board, "[O]rder") {
+		t.Errorf("board footer missing board-only keys: %q", board)
+	}
+	if strings.Contains(carousel, "Column") || strings.Contains(carousel, "[O]rder") {
+		t.Errorf("carousel footer should omit board-only keys: %q", carousel)
+	}
+	if strings.Contains(board, "Adopt") {
+		t.Errorf("footer should omit bindings without a footer label: %q", board)
+	}
+}
+
+func TestRenderHelp(t *testing.T) {
+	got := RenderHelp(160, 60)
+	for _, s := range HelpSections() {
+		if !strings.Contains(got, s.Title) {
+			t.Errorf("RenderHelp() missing section %q", s.Title)
+		}
+	}
+	if !strings.Contains(got, "Return to dashboard") {
+		t.Error("RenderHelp() should list zoom bindings")
+	}
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Column widths for the compact list view.
+const (
+	listNameWidth = 20
+	listDirWidth  = 26
+	listAgeWidth  = 7
+)
+
+// RenderList renders the compact list view: one row per agent with status
+// dot, name, dir, time in status, and the last line of output.
+func RenderList(agents []CardData, pos int, width, scrollOffset, maxVisible int) string {
+	if len(agents) == 0 {
+		return DimText.Render("No agents. Press N to spawn one.")
+	}
+	start := scrollOffset
+	if start > len(agents) {
+		start = len(agents)
+	}
+	end := start + maxVisible
+	if end > len(agents) {
+		end = len(agents)
+	}
+
+	var rows []string
+	for i := start; i < end; i++ {
+		rows = append(rows, listRow(agents[i], i == pos, width))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// listRow renders one agent as a single line of exactly width cells.
+func listRow(d CardData, selected bool, width int) string {
+	cell := func(s string, w int) string {
+		if len([]rune(s)) > w-1 {
+			s = string([]rune(s)[:w-2]) + "…"
+		}
+		return lipgloss.NewStyle().Width(w).Render(s)
+	}
+
+	name := d.Name
+	if d.Discovered {
+		name += " [ext]"
+	}
+	preview := ""
+	for i := len(d.Preview) - 1; i >= 0; i-- {
+		if p := strings.TrimSpace(d.Preview[i]); p != "" {
+			preview = p
+			break
+		}
+	}
+
+	marker := "  "
+	if selected {
+		marker = lipgloss.NewStyle().Foreground(ColorAccent).Bold(true).Render("▌ ")
+	}
+	nameStyle := AgentName
+	if !selected {
+		nameStyle = lipgloss.NewStyle().Foreground(ColorText)
+	}
+	ageStyle := DimText
+	if d.Status != "DONE" {
+		ageStyle = lipgloss.NewStyle().Foreground(AgeColor(d.Since))
+	}
+	row := marker + StatusDot(d.Status) + " " +
+		nameStyle.Render(cell(name, listNameWidth)) +
+		DimText.Render(cell(shortenDir(d.Dir), listDirWidth)) +
+		ageStyle.Render(cell(formatDuration(d.Since), listAgeWidth))
+
+	if room := width - lipgloss.Width(row); room > 4 {
+		row += PreviewText.Render(cell(preview, room))
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(row)
+}
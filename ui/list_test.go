@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestRenderList(t *testing.T) {
+	cards := []CardData{
+		{Name: "api", Dir: "/srv/api", Status: "RUNNING", Since: 3 * time.Minute, Preview: []string{"building", "running tests", ""}},
+		{Name: "web", Dir: "/srv/web", Status: "WAITING"},
+		{Name: "docs", Dir: "/srv/docs", Status: "IDLE"},
+	}
+
+	got := RenderList(cards, 1, 100, 0, 2)
+	rows := strings.Split(got, "\n")
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 (maxVisible):\n%s", len(rows), got)
+	}
+	for _, want := range []string{"api", "/srv/api", "3m", "running tests"} {
+		if !strings.Contains(rows[0], want) {
+			t.Errorf("row 0 missing %q: %q", want, rows[0])
+		}
+	}
+	if !strings.Contains(rows[1], "▌") || strings.Contains(rows[0], "▌") {
+		t.Errorf("only the selected row should carry the marker:\n%s", got)
+	}
+	for i, r := range rows {
+		if w := lipgloss.Width(r); w > 100 {
+			t.Errorf("row %d is %d cells wide, want <= 100", i, w)
+		}
+	}
+
+	if got := RenderList(cards, 0, 100, 2, 5); strings.Count(got, "\n") != 0 || !strings.Contains(got, "docs") {
+		t.Errorf("scrolled list = %q, want only the docs row", got)
+	}
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortMode selects the order of cards within each board column.
+type SortMode int
+
+const (
+	SortCreated      SortMode = iota // creation time, oldest first
+	SortStatusChange                 // most recent status change first
+	SortName                         // alphabetical by name
+	SortAttention                    // stuck/waiting first, longest wait first
+	sortModeCount
+)
+
+var sortModeNames = [...]string{"created", "last change", "name", "attention"}
+
+func (s SortMode) String() string {
+	if s < 0 || s >= sortModeCount {
+		return "created"
+	}
+	return sortModeNames[s]
+}
+
+// Next returns the following sort mode, wrapping around.
+func (s SortMode) Next() SortMode {
+	return (s + 1) % sortModeCount
+}
+
+// attentionRank orders statuses by how urgently they need a human.
+func attentionRank(status string) int {
+	switch status {
+	case "ERROR", "STUCK", "TIMED-OUT":
+		return 0
+	case "WAITING", "ASK":
+		return 1
+	case "IDLE":
+		return 2
+	case "RUNNING":
+		return 3
+	default:
+		return 4
+	}
+}
+
+// priorityRank orders agent priorities, high first.
+func priorityRank(priority string) int {
+	switch priority {
+	case "high":
+		return 0
+	case "low":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// SortColumn reorders idx, a column's flat indices into cards, by mode.
+// Whatever the mode, high-priority agents come first and low-priority ones
+// last. The sort is stable so ties keep insertion order.
+func SortColumn(cards []CardData, idx []int, mode SortMode) {
+	less := func(a, b CardData) bool { return a.Uptime > b.Uptime }
+	switch mode {
+	case SortStatusChange:
+		less = func(a, b CardData) bool { return a.Since < b.Since }
+	case SortName:
+		less = func(a, b CardData) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case SortAttention:
+		less = func(a, b CardData) bool {
+			if ra, rb := attentionRank(a.Status), attentionRank(b.Status); ra != rb {
+				return ra < rb
+			}
+			return a.Since > b.Since
+		}
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		a, b := cards[idx[i]], cards[idx[j]]
+		if pa, pb := priorityRank(a.Priority), priorityRank(b.Priority); pa != pb {
+			return pa < pb
+		}
+		return less(a, b)
+	})
+}
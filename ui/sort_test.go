@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSortColumn(t *testing.T) {
+	cards := []CardData{
+		{Name: "bravo", Status: "IDLE", Uptime: 3 * time.Hour, Since: 10 * time.Minute},
+		{Name: "alpha", Status: "WAITING", Uptime: 1 * time.Hour, Since: 2 * time.Minute},
+		{Name: "Charlie", Status: "STUCK", Uptime: 2 * time.Hour, Since: 30 * time.Minute},
+		{Name: "delta", Status: "WAITING", Uptime: 4 * time.Hour, Since: 20 * time.Minute},
+	}
+
+	tests := []struct {
+		mode SortMode
+		want []int
+	}{
+		{SortCreated, []int{3, 0, 2, 1}},
+		{SortStatusChange, []int{1, 0, 3, 2}},
+		{SortName, []int{1, 0, 2, 3}},
+		{SortAttention, []int{2, 3, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			idx := []int{0, 1, 2, 3}
+			SortColumn(cards, idx, tt.mode)
+			for i := range idx {
+				if idx[i] != tt.want[i] {
+					t.Fatalf("SortColumn(%s) = %v, want %v", tt.mode, idx, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSortColumnPriority(t *testing.T) {
+	cards := []CardData{
+		{Name: "alpha", Priority: "low"},
+		{Name: "bravo"},
+		{Name: "charlie", Priority: "high"},
+	}
+	idx := []int{0, 1, 2}
+	SortColumn(cards, idx, SortName)
+	if idx[0] != 2 || idx[1] != 1 || idx[2] != 0 {
+		t.Errorf("SortColumn = %v, want high, normal, low", idx)
+	}
+}
+
+func TestSortModeNext(t *testing.T) {
+	m := SortCreated
+	seen := map[SortMode]bool{}
+	for i := 0; i < int(sortModeCount); i++ {
+		seen[m] = true
+		m = m.Next()
+	}
+	if m != SortCreated || len(seen) != int(sortModeCount) {
+		t.Errorf("Next() should cycle through all %d modes and wrap", sortModeCount)
+	}
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StatsRow is one agent's line on the stats screen.
+type StatsRow struct {
+	Name      string
+	Status    string
+	Running   time.Duration // time spent working
+	FirstWait time.Duration // from spawn to its first permission prompt
+	Waited    bool          // whether FirstWait is known yet
+	Prompts   int           // permission prompts it stopped on
+	Turns     int           // turns it finished
+}
+
+// maxStatsName caps the width of the stats screen's name column.
+const maxStatsName = 24
+
+// StatsLines renders rows as a table, a header line first.
+func StatsLines(rows []StatsRow, width int) []string {
+	nameW := len("AGENT")
+	for _, r := range rows {
+		if n := len([]rune(r.Name)); n > nameW {
+			nameW = n
+		}
+	}
+	if nameW > maxStatsName {
+		nameW = maxStatsName
+	}
+	nameCol := lipgloss.NewStyle().Width(nameW + 2)
+	statusCol := lipgloss.NewStyle().Width(14)
+	numbers := "%9s  %10s  %7s  %5s"
+
+	header := nameCol.Render("AGENT") + "  " + statusCol.Render("STATUS") +
+		fmt.Sprintf(numbers, "RUNNING", "FIRST WAIT", "PROMPTS", "TURNS")
+	lines := []string{DimText.Render(header)}
+	for _, r := range rows {
+		name := r.Name
+		if rs := []rune(name); len(rs) > nameW {
+			name = string(rs[:nameW-1]) + "…"
+		}
+		wait := "—"
+		if r.Waited {
+			wait = formatDuration(r.FirstWait)
+		}
+		line := AgentName.Render(nameCol.Render(name)) + StatusDot(r.Status) + " " + statusCol.Render(r.Status) +
+			fmt.Sprintf(numbers, formatDuration(r.Running), wait, fmt.Sprint(r.Prompts), fmt.Sprint(r.Turns))
+		lines = append(lines, lipgloss.NewStyle().MaxWidth(width).Render(line))
+	}
+	return lines
+}
@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatsLines(t *testing.T) {
+	rows := []StatsRow{
+		{Name: "api", Status: "RUNNING", Running: 90 * time.Minute, FirstWait: 2 * time.Minute, Waited: true, Prompts: 3, Turns: 4},
+		{Name: "a-very-long-agent-name-that-goes-on", Status: "DONE", Running: 30 * time.Second},
+	}
+	lines := StatsLines(rows, 200)
+	if len(lines) != 3 || !strings.Contains(lines[0], "FIRST WAIT") {
+		t.Fatalf("want a header and a line per row, got %q", lines)
+	}
+	if !strings.Contains(lines[1], "1h30m") || !strings.Contains(lines[1], "2m") {
+		t.Errorf("row lacks its durations: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "—") || !strings.Contains(lines[2], "…") {
+		t.Errorf("agent that never waited should show — and a truncated name: %q", lines[2])
+	}
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StatusSummary is the fleet-wide aggregate shown in the status strip.
+type StatusSummary struct {
+	Counts     map[string]int // agents per status
+	Activity   []int          // status changes per minute, oldest first
+	Stale      []string       // names of agents RUNNING longer than StaleAfter
+	StaleAfter time.Duration
+}
+
+// stripStatuses is the order statuses appear in the strip.
+var stripStatuses = []string{"RUNNING", "PENDING", "THROTTLED", "WAITING", "ASK", "IDLE", "ERROR", "STUCK", "TIMED-OUT", "PAUSED", "NEEDS-REVIEW", "DONE"}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as block characters scaled to the largest one.
+func Sparkline(values []int) string {
+	peak := 0
+	for _, v := range values {
+		if v > peak {
+			peak = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if peak > 0 && v > 0 {
+			i = 1 + v*(len(sparkBlocks)-2)/peak
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
+
+// RenderStatusStrip renders per-status counts, a waiting alert, and an
+// activity sparkline on one line, cut to width.
+func RenderStatusStrip(s StatusSummary, width int) string {
+	var parts []string
+	for _, st := range stripStatuses {
+		// Waiting agents get the alert badge below instead of a plain count
+		if n := s.Counts[st]; n > 0 && st != "WAITING" {
+			parts = append(parts, StatusDot(st)+DimText.Render(fmt.Sprintf(" %d %s", n, strings.ToLower(st))))
+		}
+	}
+	if n := s.Counts["WAITING"]; n > 0 {
+		parts = append(parts, BadgeWaiting.Render(fmt.Sprintf("⚠ %d waiting", n)))
+	}
+	if len(s.Stale) > 0 {
+		who := strings.Join(s.Stale, ", ")
+		if len(s.Stale) > 2 {
+			who = fmt.Sprintf("%d agents", len(s.Stale))
+		}
+		warn := fmt.Sprintf("⏳ %s running >%s", who, formatDuration(s.StaleAfter))
+		parts = append(parts, lipgloss.NewStyle().Foreground(ColorError).Bold(true).Render(warn))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, DimText.Render("no agents"))
+	}
+	if len(s.Activity) > 0 {
+		spark := lipgloss.NewStyle().Foreground(ColorAccent).Render(Sparkline(s.Activity))
+		parts = append(parts, DimText.Render("activity ")+spark+DimText.Render(fmt.Sprintf(" %dm", len(s.Activity))))
+	}
+	line := " " + strings.Join(parts, "  ")
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
+}
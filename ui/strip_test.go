@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]int{0, 0, 0}); got != "▁▁▁" {
+		t.Errorf("Sparkline(zeros) = %q, want flat", got)
+	}
+	got := []rune(Sparkline([]int{0, 1, 4, 8}))
+	if got[0] != '▁' || got[3] != '█' {
+		t.Errorf("Sparkline scale = %q, want ▁ … █", string(got))
+	}
+	if !(got[1] < got[2] && got[2] < got[3]) {
+		t.Errorf("Sparkline should rise with values: %q", string(got))
+	}
+}
+
+func TestRenderStatusStrip(t *testing.T) {
+	s := StatusSummary{
+		Counts:   map[string]int{"RUNNING": 2, "WAITING": 3},
+		Activity: make([]int, 10),
+	}
+	got := RenderStatusStrip(s, 200)
+	for _, want := range []string{"2 running", "3 waiting", "⚠ 3 waiting", "activity", "10m"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("strip missing %q: %q", want, got)
+		}
+	}
+	if strings.Contains(got, "idle") {
+		t.Errorf("zero counts should be omitted: %q", got)
+	}
+
+	s.Stale, s.StaleAfter = []string{"api"}, 30*time.Minute
+	if got := RenderStatusStrip(s, 200); !strings.Contains(got, "⏳ api running >30m") {
+		t.Errorf("strip = %q, want stale warning", got)
+	}
+	s.Stale = []string{"a", "b", "c"}
+	if got := RenderStatusStrip(s, 200); !strings.Contains(got, "⏳ 3 agents running >30m") {
+		t.Errorf("strip = %q, want stale count", got)
+	}
+
+	if got := RenderStatusStrip(StatusSummary{}, 200); !strings.Contains(got, "no agents") {
+		t.Errorf("empty strip = %q, want \"no agents\"", got)
+	}
+}
@@ -1,124 +1,351 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a terminal color palette. ApplyTheme installs one by rebuilding
+// every package style from it.
+type Theme struct {
+	Name      string
+	Running   lipgloss.Color
+	Waiting   lipgloss.Color
+	Idle      lipgloss.Color
+	Done      lipgloss.Color
+	Accent    lipgloss.Color
+	Error     lipgloss.Color // STUCK and TIMED-OUT agents
+	Failed    lipgloss.Color // ERROR agents: crashed or exited abnormally
+	Dim       lipgloss.Color
+	Text      lipgloss.Color // primary text, e.g. agent names
+	BadgeText lipgloss.Color // text on bright badges
+	Bg        lipgloss.Color
+	CardBg    lipgloss.Color
+	Border    lipgloss.Color
+	Warn      lipgloss.Color // auto-approve, update notices
+	ModeEdits lipgloss.Color
+	ModePlan  lipgloss.Color
+}
+
+// DarkTheme is the default palette, tuned for dark terminals.
+var DarkTheme = Theme{
+	Name:      "dark",
+	Running:   "#22c55e", // green
+	Waiting:   "#ef4444", // red
+	Idle:      "#f97316", // orange
+	Done:      "#6b7280", // gray
+	Accent:    "#06b6d4", // cyan
+	Error:     "#a855f7", // purple
+	Failed:    "#dc2626", // deep red
+	Dim:       "#4b5563", // dim gray
+	Text:      "#f9fafb",
+	BadgeText: "#000000",
+	Bg:        "#1a1a2e",
+	CardBg:    "#16213e",
+	Border:    "#374151",
+	Warn:      "#FBBF24",
+	ModeEdits: "#AF87FF",
+	ModePlan:  "#4DD9D9",
+}
+
+// LightTheme uses darker, saturated colors that stay legible on white.
+var LightTheme = Theme{
+	Name:      "light",
+	Running:   "#15803d",
+	Waiting:   "#b91c1c",
+	Idle:      "#c2410c",
+	Done:      "#4b5563",
+	Accent:    "#0e7490",
+	Error:     "#7e22ce",
+	Failed:    "#991b1b",
+	Dim:       "#6b7280",
+	Text:      "#111827",
+	BadgeText: "#ffffff",
+	Bg:        "#ffffff",
+	CardBg:    "#f3f4f6",
+	Border:    "#9ca3af",
+	Warn:      "#b45309",
+	ModeEdits: "#6d28d9",
+	ModePlan:  "#0f766e",
+}
+
+// SolarizedTheme follows Ethan Schoonover's Solarized accents, readable on
+// both the dark and light Solarized backgrounds.
+var SolarizedTheme = Theme{
+	Name:      "solarized",
+	Running:   "#859900",
+	Waiting:   "#dc322f",
+	Idle:      "#cb4b16",
+	Done:      "#657b83",
+	Accent:    "#2aa198",
+	Error:     "#6c71c4",
+	Failed:    "#dc322f",
+	Dim:       "#586e75",
+	Text:      "#93a1a1",
+	BadgeText: "#002b36",
+	Bg:        "#002b36",
+	CardBg:    "#073642",
+	Border:    "#586e75",
+	Warn:      "#b58900",
+	ModeEdits: "#d33682",
+	ModePlan:  "#268bd2",
+}
+
+// Themes lists the built-in themes by name.
+var Themes = map[string]Theme{
+	DarkTheme.Name:      DarkTheme,
+	LightTheme.Name:     LightTheme,
+	SolarizedTheme.Name: SolarizedTheme,
+}
+
+// WithOverrides returns t with colors replaced by hex values keyed by
+// lowercase field name (e.g. "accent": "#ff00ff").
+func (t Theme) WithOverrides(colors map[string]string) (Theme, error) {
+	fields := map[string]*lipgloss.Color{
+		"running": &t.Running, "waiting": &t.Waiting, "idle": &t.Idle,
+		"done": &t.Done, "accent": &t.Accent, "error": &t.Error,
+		"failed": &t.Failed, "dim": &t.Dim, "text": &t.Text, "badge_text": &t.BadgeText,
+		"bg": &t.Bg, "card_bg": &t.CardBg, "border": &t.Border,
+		"warn": &t.Warn, "mode_edits": &t.ModeEdits, "mode_plan": &t.ModePlan,
+	}
+	for k, v := range colors {
+		f, ok := fields[strings.ToLower(k)]
+		if !ok {
+			return t, fmt.Errorf("unknown theme color %q", k)
+		}
+		if !hexColorRe.MatchString(v) {
+			return t, fmt.Errorf("theme color %s: %q is not a #rrggbb hex value", k, v)
+		}
+		*f = lipgloss.Color(v)
+	}
+	return t, nil
+}
+
+var hexColorRe = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
 
 var (
 	// Status colors
-	ColorRunning = lipgloss.Color("#22c55e") // green
-	ColorWaiting = lipgloss.Color("#ef4444") // red
-	ColorIdle    = lipgloss.Color("#f97316") // orange
-	ColorDone    = lipgloss.Color("#6b7280") // gray
-	ColorAccent  = lipgloss.Color("#06b6d4") // cyan
-	ColorError   = lipgloss.Color("#a855f7") // purple
-	ColorDim     = lipgloss.Color("#4b5563") // dim gray
-	ColorWhite   = lipgloss.Color("#f9fafb")
-	ColorBg      = lipgloss.Color("#1a1a2e")
-	ColorCardBg  = lipgloss.Color("#16213e")
-	ColorBorder  = lipgloss.Color("#374151")
+	ColorRunning lipgloss.Color
+	ColorWaiting lipgloss.Color
+	ColorIdle    lipgloss.Color
+	ColorDone    lipgloss.Color
+	ColorAccent  lipgloss.Color
+	ColorError   lipgloss.Color
+	ColorFailed  lipgloss.Color
+	ColorDim     lipgloss.Color
+	ColorText    lipgloss.Color
+	ColorBg      lipgloss.Color
+	ColorCardBg  lipgloss.Color
+	ColorBorder  lipgloss.Color
+	ColorWarn    lipgloss.Color
 
 	// Badge styles
+	BadgeRunning lipgloss.Style
+	BadgeWaiting lipgloss.Style
+	BadgeIdle    lipgloss.Style
+	BadgeDone    lipgloss.Style
+	BadgeError   lipgloss.Style
+	BadgeFailed  lipgloss.Style
+	BadgeReview  lipgloss.Style
+
+	// Card styles
+	CardSelected lipgloss.Style
+	CardNormal   lipgloss.Style
+
+	// Column header styles
+	ColumnHeader lipgloss.Style
+
+	// Title bar
+	TitleBar lipgloss.Style
+
+	// Footer / help
+	HelpStyle   lipgloss.Style
+	FooterStyle lipgloss.Style
+
+	// Dim text
+	DimText lipgloss.Style
+
+	// Agent name
+	AgentName lipgloss.Style
+
+	// Preview text
+	PreviewText lipgloss.Style
+
+	// Carousel-specific
+	CarouselCard lipgloss.Style
+
+	// Separator line
+	Separator lipgloss.Style
+
+	// Mode badges
+	ModeBadgeEdits   lipgloss.Style
+	ModeBadgePlan    lipgloss.Style
+	BadgeAutoApprove lipgloss.Style
+	BadgeKeepAlive   lipgloss.Style
+	BadgeHigh        lipgloss.Style
+	BadgeLow         lipgloss.Style
+
+	// Zoom scrollback search highlights
+	SearchMatch   lipgloss.Style
+	SearchCurrent lipgloss.Style
+)
+
+func init() {
+	ApplyTheme(DarkTheme)
+}
+
+// ApplyTheme sets the package colors from t and rebuilds every style and
+// built-in layout that depends on them. Call before rendering starts.
+func ApplyTheme(t Theme) {
+	ColorRunning = t.Running
+	ColorWaiting = t.Waiting
+	ColorIdle = t.Idle
+	ColorDone = t.Done
+	ColorAccent = t.Accent
+	ColorError = t.Error
+	ColorFailed = t.Failed
+	ColorDim = t.Dim
+	ColorText = t.Text
+	ColorBg = t.Bg
+	ColorCardBg = t.CardBg
+	ColorBorder = t.Border
+	ColorWarn = t.Warn
+
 	BadgeRunning = lipgloss.NewStyle().
-			Background(ColorRunning).
-			Foreground(lipgloss.Color("#000000")).
-			Bold(true).
-			Padding(0, 1)
+		Background(ColorRunning).
+		Foreground(t.BadgeText).
+		Bold(true).
+		Padding(0, 1)
 
 	BadgeWaiting = lipgloss.NewStyle().
-			Background(ColorWaiting).
-			Foreground(ColorWhite).
-			Bold(true).
-			Padding(0, 1)
+		Background(ColorWaiting).
+		Foreground(ColorText).
+		Bold(true).
+		Padding(0, 1)
 
 	BadgeIdle = lipgloss.NewStyle().
-			Background(ColorIdle).
-			Foreground(lipgloss.Color("#000000")).
-			Bold(true).
-			Padding(0, 1)
+		Background(ColorIdle).
+		Foreground(t.BadgeText).
+		Bold(true).
+		Padding(0, 1)
 
 	BadgeDone = lipgloss.NewStyle().
-			Background(ColorDone).
-			Foreground(ColorWhite).
-			Padding(0, 1)
+		Background(ColorDone).
+		Foreground(ColorText).
+		Padding(0, 1)
 
 	BadgeError = lipgloss.NewStyle().
-			Background(ColorError).
-			Foreground(ColorWhite).
-			Bold(true).
-			Padding(0, 1)
+		Background(ColorError).
+		Foreground(ColorText).
+		Bold(true).
+		Padding(0, 1)
+
+	BadgeFailed = lipgloss.NewStyle().
+		Background(ColorFailed).
+		Foreground(ColorText).
+		Bold(true).
+		Padding(0, 1)
+
+	BadgeReview = lipgloss.NewStyle().
+		Background(ColorAccent).
+		Foreground(t.BadgeText).
+		Bold(true).
+		Padding(0, 1)
 
-	// Card styles
 	CardSelected = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorAccent).
-			Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorAccent).
+		Padding(0, 1)
 
 	CardNormal = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorBorder).
-			Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorBorder).
+		Padding(0, 1)
 
-	// Column header styles
 	ColumnHeader = lipgloss.NewStyle().
-			Bold(true).
-			Padding(0, 1)
+		Bold(true).
+		Padding(0, 1)
 
-	// Title bar
 	TitleBar = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorAccent).
-			Padding(0, 1)
+		Bold(true).
+		Foreground(ColorAccent).
+		Padding(0, 1)
 
-	// Footer / help
 	HelpStyle = lipgloss.NewStyle().
-			Foreground(ColorDim)
+		Foreground(ColorDim)
 
 	FooterStyle = lipgloss.NewStyle().
-			Border(lipgloss.NormalBorder(), true, false, false, false).
-			BorderForeground(ColorBorder).
-			Padding(0, 1)
+		Border(lipgloss.NormalBorder(), true, false, false, false).
+		BorderForeground(ColorBorder).
+		Padding(0, 1)
 
-	// Dim text
 	DimText = lipgloss.NewStyle().
 		Foreground(ColorDim)
 
-	// Agent name
 	AgentName = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorWhite)
+		Bold(true).
+		Foreground(ColorText)
 
-	// Preview text
 	PreviewText = lipgloss.NewStyle().
-			Foreground(ColorDim)
+		Foreground(ColorDim)
 
-	// Carousel-specific
 	CarouselCard = lipgloss.NewStyle().
-			Border(lipgloss.DoubleBorder()).
-			BorderForeground(ColorAccent).
-			Padding(1, 2)
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(ColorAccent).
+		Padding(1, 2)
 
-	// Separator line
 	Separator = lipgloss.NewStyle().
-			Foreground(ColorBorder)
+		Foreground(ColorBorder)
 
-	// Mode badges
 	ModeBadgeEdits = lipgloss.NewStyle().
-			Background(lipgloss.Color("#AF87FF")).
-			Foreground(lipgloss.Color("#1a1a2e")).
-			Bold(true).
-			Padding(0, 1)
+		Background(t.ModeEdits).
+		Foreground(t.Bg).
+		Bold(true).
+		Padding(0, 1)
 
 	ModeBadgePlan = lipgloss.NewStyle().
-			Background(lipgloss.Color("#4DD9D9")).
-			Foreground(lipgloss.Color("#1a1a2e")).
-			Bold(true).
-			Padding(0, 1)
+		Background(t.ModePlan).
+		Foreground(t.Bg).
+		Bold(true).
+		Padding(0, 1)
 
 	BadgeAutoApprove = lipgloss.NewStyle().
-			Background(lipgloss.Color("#FBBF24")).
-			Foreground(lipgloss.Color("#1a1a2e")).
-			Bold(true).
-			Padding(0, 1)
-)
+		Background(ColorWarn).
+		Foreground(t.Bg).
+		Bold(true).
+		Padding(0, 1)
+
+	BadgeKeepAlive = lipgloss.NewStyle().
+		Background(ColorAccent).
+		Foreground(t.Bg).
+		Bold(true).
+		Padding(0, 1)
+
+	BadgeHigh = lipgloss.NewStyle().
+		Background(ColorFailed).
+		Foreground(t.Bg).
+		Bold(true).
+		Padding(0, 1)
+
+	BadgeLow = lipgloss.NewStyle().
+		Background(ColorDim).
+		Foreground(t.Bg).
+		Padding(0, 1)
+
+	SearchMatch = lipgloss.NewStyle().
+		Background(ColorWarn).
+		Foreground(t.BadgeText)
+
+	SearchCurrent = lipgloss.NewStyle().
+		Background(ColorAccent).
+		Foreground(t.BadgeText).
+		Bold(true)
+
+	buildLayouts()
+}
 
 func ModeBadgeFor(mode string) string {
 	switch mode {
@@ -129,18 +356,70 @@ func ModeBadgeFor(mode string) string {
 	}
 }
 
+// priorityBadge renders a card's "HIGH" or "LOW" badge, "" for normal
+// priority.
+func priorityBadge(priority string) string {
+	switch priority {
+	case "high":
+		return BadgeHigh.Render("HIGH")
+	case "low":
+		return BadgeLow.Render("LOW")
+	}
+	return ""
+}
+
+// attemptBadge marks a card whose task was started over, e.g. "TRY 2";
+// "" on the first attempt.
+func attemptBadge(n int) string {
+	if n < 2 {
+		return ""
+	}
+	return DimText.Render(fmt.Sprintf("TRY %d", n))
+}
+
+// BackendTag renders a backend label as a colored "[CC]", so mixed-backend
+// boards can be told apart at a glance.
+func BackendTag(label string) string {
+	color := ColorDim
+	switch label {
+	case "CC":
+		color = ColorWarn
+	case "CX":
+		color = ColorText
+	case "GM":
+		color = ColorAccent
+	case "QW":
+		color = ColorIdle
+	case "OI":
+		color = ColorRunning
+	}
+	return lipgloss.NewStyle().Foreground(color).Render("[" + label + "]")
+}
+
 func StatusBadge(status string) string {
 	switch status {
 	case "RUNNING":
 		return BadgeRunning.Render("IN-PROGRESS")
 	case "WAITING":
 		return BadgeWaiting.Render("WAITING")
+	case "ASK":
+		return BadgeWaiting.Render("ASK")
 	case "IDLE":
 		return BadgeIdle.Render("IDLE")
 	case "DONE":
 		return BadgeDone.Render("DONE")
 	case "STUCK":
 		return BadgeError.Render("STUCK")
+	case "TIMED-OUT":
+		return BadgeError.Render("TIMED-OUT")
+	case "ERROR":
+		return BadgeFailed.Render("ERROR")
+	case "PAUSED":
+		return BadgeDone.Render("PAUSED")
+	case "THROTTLED":
+		return BadgeIdle.Render("THROTTLED")
+	case "NEEDS-REVIEW":
+		return BadgeReview.Render("NEEDS-REVIEW")
 	default:
 		return BadgeDone.Render(status)
 	}
@@ -152,12 +431,26 @@ func StatusDot(status string) string {
 		return lipgloss.NewStyle().Foreground(ColorRunning).Render("●")
 	case "WAITING":
 		return lipgloss.NewStyle().Foreground(ColorWaiting).Render("▲")
+	case "ASK":
+		return lipgloss.NewStyle().Foreground(ColorWaiting).Render("?")
 	case "IDLE":
 		return lipgloss.NewStyle().Foreground(ColorIdle).Render("○")
 	case "DONE":
 		return lipgloss.NewStyle().Foreground(ColorDone).Render("✓")
 	case "STUCK":
 		return lipgloss.NewStyle().Foreground(ColorError).Render("⚠")
+	case "PENDING":
+		return lipgloss.NewStyle().Foreground(ColorDone).Render("◌")
+	case "TIMED-OUT":
+		return lipgloss.NewStyle().Foreground(ColorError).Render("⧗")
+	case "ERROR":
+		return lipgloss.NewStyle().Foreground(ColorFailed).Render("✗")
+	case "PAUSED":
+		return lipgloss.NewStyle().Foreground(ColorDone).Render("⏸")
+	case "THROTTLED":
+		return lipgloss.NewStyle().Foreground(ColorWarn).Render("◷")
+	case "NEEDS-REVIEW":
+		return lipgloss.NewStyle().Foreground(ColorAccent).Render("◆")
 	default:
 		return "·"
 	}
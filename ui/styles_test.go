@@ -69,3 +69,23 @@ func TestModeBadgeFor(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyTheme(t *testing.T) {
+	defer ApplyTheme(DarkTheme)
+
+	ApplyTheme(LightTheme)
+	if ColorAccent != LightTheme.Accent || ColorText != LightTheme.Text {
+		t.Errorf("ApplyTheme(light) left ColorAccent=%v ColorText=%v", ColorAccent, ColorText)
+	}
+	if ThreeColumnLayout[0].Color != LightTheme.Idle {
+		t.Error("ApplyTheme should rebuild built-in layouts with theme colors")
+	}
+}
+
+func TestBackendTag(t *testing.T) {
+	for _, label := range []string{"CC", "CX", "GM", "QW", "OL"} {
+		if got := BackendTag(label); !strings.Contains(got, "["+label+"]") {
+			t.Errorf("BackendTag(%q) = %q, want it to contain [%s]", label, got, label)
+		}
+	}
+}
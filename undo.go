@@ -0,0 +1,18 @@
+package main
+
+import "time"
+
+// undoWindow is how long the last kill or clear can be undone with u.
+const undoWindow = 30 * time.Second
+
+// undoEntry remembers the agents removed by the last kill or clear.
+type undoEntry struct {
+	agents  []*Agent
+	respawn bool // agents were killed, so their sessions must be re-created
+	at      time.Time
+}
+
+// live reports whether the entry can still be undone at now.
+func (u *undoEntry) live(now time.Time) bool {
+	return u != nil && len(u.agents) > 0 && now.Sub(u.at) < undoWindow
+}
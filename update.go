@@ -283,11 +283,13 @@ func crossFSReplace(src, dst string) error {
 	return nil
 }
 
-// reExec replaces the current process with a fresh invocation of the binary.
+// reExec replaces the current process with a fresh invocation of the binary,
+// on the same board.
 func reExec() error {
 	exe, err := os.Executable()
 	if err != nil {
 		return err
 	}
-	return syscall.Exec(exe, os.Args, os.Environ())
+	args := append(append([]string{os.Args[0]}, boardArgs()...), os.Args[1:]...)
+	return syscall.Exec(exe, args, os.Environ())
 }
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sns45/tickettok/ui"
+)
+
+// Usage is an agent's token spend as far as its pane or transcript shows.
+type Usage struct {
+	Tokens int64   // tokens consumed, 0 if never reported
+	Cost   float64 // USD, 0 if the backend doesn't report cost
+}
+
+// Max merges two readings of the same agent. Counters only grow, so the
+// larger value wins — this keeps a total once /cost output scrolls away.
+func (u Usage) Max(o Usage) Usage {
+	if o.Tokens > u.Tokens {
+		u.Tokens = o.Tokens
+	}
+	if o.Cost > u.Cost {
+		u.Cost = o.Cost
+	}
+	return u
+}
+
+// Info converts the usage for card rendering.
+func (u Usage) Info() ui.UsageInfo {
+	return ui.UsageInfo{Tokens: u.Tokens, Cost: u.Cost}
+}
+
+// tokenNum matches a count like "12,345", "12.3k" or "1.2M".
+const tokenNum = `(\d[\d,]*(?:\.\d+)?[kKmM]?)`
+
+// parseTokenCount parses a count matched by tokenNum.
+func parseTokenCount(s string) (int64, bool) {
+	s = strings.ReplaceAll(s, ",", "")
+	mult := 1.0
+	switch {
+	case strings.HasSuffix(s, "k"), strings.HasSuffix(s, "K"):
+		mult, s = 1e3, s[:len(s)-1]
+	case strings.HasSuffix(s, "m"), strings.HasSuffix(s, "M"):
+		mult, s = 1e6, s[:len(s)-1]
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int64(f*mult + 0.5), true
+}
+
+// lastTokens returns the count captured by the last match of any pattern,
+// preferring earlier patterns. Each pattern's first group is the count.
+func lastTokens(content string, patterns ...*regexp.Regexp) int64 {
+	for _, re := range patterns {
+		all := re.FindAllStringSubmatch(content, -1)
+		if len(all) == 0 {
+			continue
+		}
+		if n, ok := parseTokenCount(all[len(all)-1][1]); ok {
+			return n
+		}
+	}
+	return 0
+}
+
+var costRe = regexp.MustCompile(`(?i)total cost:\s*\$(\d+(?:\.\d+)?)`)
+
+// lastCost returns the last "Total cost: $X" figure in content.
+func lastCost(content string) float64 {
+	all := costRe.FindAllStringSubmatch(content, -1)
+	if len(all) == 0 {
+		return 0
+	}
+	f, _ := strconv.ParseFloat(all[len(all)-1][1], 64)
+	return f
+}
+
+// transcriptCache remembers how far each transcript has been read so a
+// growing file is only scanned once.
+var transcriptCache = struct {
+	sync.Mutex
+	states map[string]*transcriptState
+}{states: map[string]*transcriptState{}}
+
+// transcriptState is what a session transcript has said so far.
+type transcriptState struct {
+	offset int64
+	tokens int64
+	todos  TodoProgress
+	text   []string // lines of the last assistant message
+	tool   string   // tool call awaiting its result, "" if none
+	subs   []Subagent
+}
+
+// transcriptLine is the part of a Claude Code transcript entry tickettok
+// reads: API usage and tool calls.
+type transcriptLine struct {
+	Type      string    `json:"type"` // user, assistant, or bookkeeping entries
+	Timestamp time.Time `json:"timestamp"`
+	Message   struct {
+		Content []struct {
+			Type      string          `json:"type"`
+			Text      string          `json:"text"`
+			ID        string          `json:"id"`
+			Name      string          `json:"name"`
+			Input     json.RawMessage `json:"input"`
+			ToolUseID string          `json:"tool_use_id"`
+			IsError   bool            `json:"is_error"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens         int64 `json:"input_tokens"`
+			OutputTokens        int64 `json:"output_tokens"`
+			CacheCreationTokens int64 `json:"cache_creation_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// readTranscript brings a session transcript's state up to date. Only
+// lines appended since the last call are read.
+func readTranscript(path string) transcriptState {
+	transcriptCache.Lock()
+	defer transcriptCache.Unlock()
+
+	st := transcriptCache.states[path]
+	if st == nil {
+		st = &transcriptState{}
+		transcriptCache.states[path] = st
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return st.snapshot()
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Size() < st.offset {
+		// Truncated or replaced: start over
+		*st = transcriptState{}
+	}
+	if _, err := f.Seek(st.offset, io.SeekStart); err != nil {
+		return st.snapshot()
+	}
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			// Leave a partial trailing line for the next call
+			break
+		}
+		st.offset += int64(len(line))
+		var tl transcriptLine
+		if json.Unmarshal(line, &tl) != nil {
+			continue
+		}
+		u := tl.Message.Usage
+		st.tokens += u.InputTokens + u.CacheCreationTokens + u.OutputTokens
+		switch tl.Type {
+		case "user":
+			// A prompt or the result of the pending tool call
+			st.tool = ""
+			for _, c := range tl.Message.Content {
+				if c.Type == "tool_result" {
+					finishSubagent(st.subs, c.ToolUseID, c.IsError, tl.Timestamp)
+				}
+			}
+		case "assistant":
+			st.tool = ""
+			for _, c := range tl.Message.Content {
+				switch c.Type {
+				case "text":
+					if t := strings.TrimSpace(c.Text); t != "" {
+						st.text = strings.Split(t, "\n")
+					}
+				case "tool_use":
+					st.tool = c.Name
+					if p, ok := parseTodoWrite(c.Input); c.Name == todoWriteTool && ok {
+						st.todos = p
+					}
+					if taskTools[c.Name] {
+						st.subs = append(st.subs, newSubagent(c.ID, c.Input, tl.Timestamp))
+					}
+				}
+			}
+		}
+	}
+	return st.snapshot()
+}
+
+// snapshot copies the state so callers don't share slices with the cache.
+func (t *transcriptState) snapshot() transcriptState {
+	out := *t
+	out.subs = append([]Subagent(nil), t.subs...)
+	return out
+}
+
+// preview returns up to n lines for a card: the end of the last assistant
+// message, then the tool call in progress.
+func (t transcriptState) preview(n int) []string {
+	var lines []string
+	for _, l := range t.text {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if t.tool != "" {
+		lines = append(lines, "⏺ "+t.tool)
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// transcriptTokens sums input, cache-write and output tokens over a session
+// transcript (JSONL). Cache reads are left out: they dwarf everything else
+// and cost a fraction as much.
+func transcriptTokens(path string) int64 {
+	return readTranscript(path).tokens
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTokenCount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"950", 950},
+		{"12,345", 12345},
+		{"12.3k", 12300},
+		{"12.3K", 12300},
+		{"1.2M", 1200000},
+	}
+	for _, tt := range tests {
+		if got, ok := parseTokenCount(tt.in); !ok || got != tt.want {
+			t.Errorf("parseTokenCount(%q) = %d, %v, want %d", tt.in, got, ok, tt.want)
+		}
+	}
+	if _, ok := parseTokenCount("k"); ok {
+		t.Error(`parseTokenCount("k") should fail`)
+	}
+}
+
+func TestUsageMax(t *testing.T) {
+	got := Usage{Tokens: 500, Cost: 0.2}.Max(Usage{Tokens: 300, Cost: 0.5})
+	if got != (Usage{Tokens: 500, Cost: 0.5}) {
+		t.Errorf("Max = %+v, want the larger of each counter", got)
+	}
+}
+
+func TestTranscriptTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	write := func(s string, flag int) {
+		f, err := os.OpenFile(path, flag|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{"type":"user","message":{"role":"user"}}
+{"type":"assistant","message":{"usage":{"input_tokens":100,"output_tokens":20,"cache_creation_input_tokens":30,"cache_read_input_tokens":9000}}}
+{"type":"assistant","message":{"usage":{"input_tokens":`, os.O_CREATE|os.O_TRUNC)
+	if got := transcriptTokens(path); got != 150 {
+		t.Errorf("first read = %d, want 150 (cache reads and partial line excluded)", got)
+	}
+
+	write(`5,"output_tokens":5}}}
+`, os.O_APPEND)
+	if got := transcriptTokens(path); got != 160 {
+		t.Errorf("after append = %d, want 160", got)
+	}
+
+	write(`{"message":{"usage":{"input_tokens":7}}}
+`, os.O_TRUNC)
+	if got := transcriptTokens(path); got != 7 {
+		t.Errorf("after truncation = %d, want 7", got)
+	}
+
+	if got := transcriptTokens(filepath.Join(t.TempDir(), "missing.jsonl")); got != 0 {
+		t.Errorf("missing transcript = %d, want 0", got)
+	}
+}
+
+func TestTranscriptPreview(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	data := `{"type":"assistant","message":{"content":[{"type":"text","text":"Old message"}]}}
+{"type":"assistant","message":{"content":[{"type":"text","text":"The parser drops the last token.\n\nFixing it now."}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Edit","input":{}}]}}
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got := readTranscript(path).preview(5)
+	want := []string{"The parser drops the last token.", "Fixing it now.", "⏺ Edit"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("preview = %q, want %q", got, want)
+	}
+	if got := readTranscript(path).preview(1); len(got) != 1 || got[0] != "⏺ Edit" {
+		t.Errorf("preview(1) = %q, want just the tool", got)
+	}
+
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"type":"user","message":{"content":[{"type":"tool_result"}]}}` + "\n")
+	f.Close()
+	if got := readTranscript(path).tool; got != "" {
+		t.Errorf("tool after its result = %q, want none", got)
+	}
+}
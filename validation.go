@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sns45/tickettok/ui"
+)
+
+// validationTimeout bounds a validation command, long enough for a test
+// suite.
+const validationTimeout = 10 * time.Minute
+
+// maxValidationFeedback caps failed validations sent back to an agent in
+// a row, so one that can't make them pass isn't kept at it forever.
+const maxValidationFeedback = 3
+
+// validationFeedbackLines is how much of a failed validation's output is
+// sent back to the agent.
+const validationFeedbackLines = 30
+
+// Validation is a project's check on its agents' work, run each time one
+// goes IDLE.
+type Validation struct {
+	Command string `json:"command"`
+	// Feedback sends the output of a failed run back to the agent as its
+	// next prompt.
+	Feedback bool `json:"feedback,omitempty"`
+}
+
+// projectDir returns the directory an agent's work belongs to: its own,
+// or for an agent in a worktree, the matching one in the original checkout.
+func projectDir(a *Agent) string {
+	if wt := a.Worktree; wt != nil {
+		if rel, err := filepath.Rel(wt.Path, a.Dir); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.Join(wt.Repo, rel)
+		}
+	}
+	return a.Dir
+}
+
+// validationFor returns the validation of the project an agent works in:
+// that of the deepest configured directory containing it.
+func validationFor(projects map[string]Validation, a *Agent) (Validation, bool) {
+	dir := filepath.Clean(projectDir(a))
+	var best Validation
+	bestLen := -1
+	for p, v := range projects {
+		if strings.HasPrefix(p, "~/") {
+			home, _ := os.UserHomeDir()
+			p = filepath.Join(home, p[2:])
+		}
+		p = filepath.Clean(p)
+		if dir != p && !strings.HasPrefix(dir, p+string(filepath.Separator)) {
+			continue
+		}
+		if len(p) > bestLen && strings.TrimSpace(v.Command) != "" {
+			best, bestLen = v, len(p)
+		}
+	}
+	return best, bestLen >= 0
+}
+
+// validationResult is the outcome of an agent's latest validation.
+type validationResult struct {
+	Command string
+	Running bool
+	Passed  bool
+	Output  string // combined output, or why it couldn't run
+	Fed     int    // failures sent back to the agent in a row
+}
+
+// lastLine returns the last non-empty line of out.
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// Info converts the result for rendering.
+func (r validationResult) Info() ui.CheckInfo {
+	switch {
+	case r.Command == "":
+		return ui.CheckInfo{}
+	case r.Running:
+		return ui.CheckInfo{Text: r.Command + " running…", Running: true}
+	case r.Passed:
+		return ui.CheckInfo{Text: r.Command + " passed"}
+	}
+	text := r.Command + " failed"
+	if l := lastLine(r.Output); l != "" {
+		text += ": " + l
+	}
+	return ui.CheckInfo{Text: text, Failed: true}
+}
+
+// runValidation runs command through sh in dir.
+func runValidation(command, name, dir string) (passed bool, out string) {
+	ctx, cancel := context.WithTimeout(context.Background(), validationTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "TICKETTOK_AGENT="+name)
+	data, err := cmd.CombinedOutput()
+	out = strings.TrimRight(string(data), "\n")
+	if err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("timed out after %s", validationTimeout)
+		}
+		if out == "" {
+			out = err.Error()
+		}
+		return false, out
+	}
+	return true, out
+}
+
+// validationMsg reports a validation that finished.
+type validationMsg struct {
+	id     string
+	name   string
+	passed bool
+	out    string
+}
+
+// validationCmd validates an agent's work in the background.
+func validationCmd(v Validation, a *Agent) tea.Cmd {
+	id, name, dir := a.ID, a.Name, a.Dir
+	return func() tea.Msg {
+		passed, out := runValidation(v.Command, name, dir)
+		return validationMsg{id: id, name: name, passed: passed, out: out}
+	}
+}
+
+// validationFeedback is the prompt sending a failed validation back to
+// the agent: the command and the end of its output.
+func validationFeedback(command, out string) string {
+	lines := strings.Split(out, "\n")
+	if len(lines) > validationFeedbackLines {
+		lines = lines[len(lines)-validationFeedbackLines:]
+	}
+	return fmt.Sprintf("`%s` failed after your changes:\n\n```\n%s\n```\n\nPlease fix it.", command, strings.Join(lines, "\n"))
+}
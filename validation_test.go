@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidationFor(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	projects := map[string]Validation{
+		"/src/api":          {Command: "go test ./..."},
+		"/src/api/web":      {Command: "npm test"},
+		"~/notes":           {Command: "make check"},
+		"/src/empty":        {},
+		"/src/api-gateway/": {Command: "cargo test"},
+	}
+	tests := []struct {
+		agent *Agent
+		want  string
+	}{
+		{&Agent{Dir: "/src/api"}, "go test ./..."},
+		{&Agent{Dir: "/src/api/internal/db"}, "go test ./..."},
+		{&Agent{Dir: "/src/api/web/src"}, "npm test"},
+		{&Agent{Dir: "/src/api-gateway"}, "cargo test"},
+		{&Agent{Dir: filepath.Join(home, "notes")}, "make check"},
+		{&Agent{Dir: "/src/empty"}, ""},
+		{&Agent{Dir: "/src/other"}, ""},
+		{&Agent{Dir: "/wt/api-7/internal", Worktree: &Worktree{Repo: "/src/api", Path: "/wt/api-7"}}, "go test ./..."},
+	}
+	for _, tt := range tests {
+		v, ok := validationFor(projects, tt.agent)
+		if v.Command != tt.want || ok != (tt.want != "") {
+			t.Errorf("validationFor(%s) = %q, %v, want %q", tt.agent.Dir, v.Command, ok, tt.want)
+		}
+	}
+}
+
+func TestRunValidation(t *testing.T) {
+	dir := t.TempDir()
+	if passed, out := runValidation(`echo "checking $TICKETTOK_AGENT"`, "api", dir); !passed || out != "checking api" {
+		t.Errorf("runValidation() = %v, %q, want a pass", passed, out)
+	}
+	if passed, out := runValidation("echo 'FAIL: TestParse'; exit 1", "api", dir); passed || out != "FAIL: TestParse" {
+		t.Errorf("runValidation() = %v, %q, want a failure with its output", passed, out)
+	}
+}
+
+func TestValidationResultInfo(t *testing.T) {
+	tests := []struct {
+		r    validationResult
+		want string
+	}{
+		{validationResult{}, ""},
+		{validationResult{Command: "go test ./...", Running: true}, "go test ./... running…"},
+		{validationResult{Command: "go test ./...", Passed: true, Output: "ok"}, "go test ./... passed"},
+		{validationResult{Command: "go test ./...", Output: "--- FAIL: TestX\nFAIL\n"}, "go test ./... failed: FAIL"},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Info().Text; got != tt.want {
+			t.Errorf("Info() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestValidationFeedback(t *testing.T) {
+	var lines []string
+	for i := 0; i < validationFeedbackLines+10; i++ {
+		lines = append(lines, "line")
+	}
+	lines = append(lines, "FAIL")
+	got := validationFeedback("go test ./...", strings.Join(lines, "\n"))
+	if !strings.HasPrefix(got, "`go test ./...` failed") || !strings.Contains(got, "FAIL\n```") {
+		t.Errorf("validationFeedback() = %q", got)
+	}
+	if n := strings.Count(got, "line\n"); n != validationFeedbackLines-1 {
+		t.Errorf("kept %d lines of output, want %d", n+1, validationFeedbackLines)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Exit codes of `tickettok wait`
+const (
+	waitReached  = 0
+	waitFailed   = 1 // no such agent, or it exited first
+	waitTimedOut = 2
+)
+
+// parseWaitStatuses reads the comma-separated statuses given to --for.
+// An agent that stopped on a question is IDLE too, so waiting for IDLE
+// also accepts ASK.
+func parseWaitStatuses(s string) (map[AgentStatus]bool, error) {
+	want := make(map[AgentStatus]bool)
+	for _, name := range strings.Split(s, ",") {
+		st, ok := ParseStatus(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown status: %s", name)
+		}
+		want[st] = true
+	}
+	if want[StatusIdle] {
+		want[StatusAsk] = true
+	}
+	return want, nil
+}
+
+// waitForStatus polls the agent target names every interval until its
+// status is one of want, returning the line to print and the exit code.
+// poll returns nil if the agent is gone. A timeout of zero waits for as
+// long as it takes.
+func waitForStatus(target string, poll func() (*Agent, AgentStatus), want map[AgentStatus]bool, timeout, interval time.Duration) (string, int) {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	for {
+		agent, status := poll()
+		if agent == nil {
+			return "Agent not found: " + target, waitFailed
+		}
+		if want[status] {
+			return fmt.Sprintf("%s: %s", agent.Name, status), waitReached
+		}
+		if status == StatusDone || status == StatusError {
+			return fmt.Sprintf("%s exited before reaching the requested status", agent.Name), waitFailed
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return fmt.Sprintf("Timed out waiting for %s (still %s)", agent.Name, status), waitTimedOut
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWaitStatuses(t *testing.T) {
+	want, err := parseWaitStatuses("idle, waiting")
+	if err != nil || len(want) != 3 || !want[StatusIdle] || !want[StatusAsk] || !want[StatusWaiting] {
+		t.Errorf("parseWaitStatuses = %v, %v", want, err)
+	}
+	if want, _ := parseWaitStatuses("DONE"); len(want) != 1 || !want[StatusDone] {
+		t.Errorf("parseWaitStatuses(DONE) = %v", want)
+	}
+	if _, err := parseWaitStatuses("IDLE,SLEEPY"); err == nil {
+		t.Error("no error for an unknown status")
+	}
+}
+
+func TestWaitForStatus(t *testing.T) {
+	idle := map[AgentStatus]bool{StatusIdle: true, StatusAsk: true}
+	tests := []struct {
+		name     string
+		statuses []AgentStatus // one per poll, the last repeating; "" is no agent
+		want     map[AgentStatus]bool
+		timeout  time.Duration
+		msg      string
+		code     int
+	}{
+		{"already there", []AgentStatus{StatusIdle}, idle, 0, "api: IDLE", waitReached},
+		{"gets there", []AgentStatus{StatusRunning, StatusRunning, StatusAsk}, idle, 0, "api: ASK", waitReached},
+		{"waits for done", []AgentStatus{StatusIdle, StatusDone}, map[AgentStatus]bool{StatusDone: true}, 0, "api: DONE", waitReached},
+		{"exits first", []AgentStatus{StatusRunning, StatusDone}, idle, 0, "api exited before reaching the requested status", waitFailed},
+		{"errors first", []AgentStatus{StatusError}, idle, 0, "api exited before reaching the requested status", waitFailed},
+		{"unknown agent", []AgentStatus{""}, idle, 0, "Agent not found: api", waitFailed},
+		{"killed while waiting", []AgentStatus{StatusRunning, ""}, idle, 0, "Agent not found: api", waitFailed},
+		{"times out", []AgentStatus{StatusRunning}, idle, 5 * time.Millisecond, "Timed out waiting for api (still RUNNING)", waitTimedOut},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			poll := func() (*Agent, AgentStatus) {
+				st := tt.statuses[min(polls, len(tt.statuses)-1)]
+				polls++
+				if st == "" {
+					return nil, ""
+				}
+				return &Agent{Name: "api"}, st
+			}
+			msg, code := waitForStatus("api", poll, tt.want, tt.timeout, time.Millisecond)
+			if msg != tt.msg || code != tt.code {
+				t.Errorf("waitForStatus = %q, %d; want %q, %d", msg, code, tt.msg, tt.code)
+			}
+		})
+	}
+}
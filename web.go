@@ -53,6 +53,10 @@ type WebServer struct {
 	manager *AgentManager
 	token   string
 	port    int
+	events  *EventLog // remote spawns and kills land in the TUI's feed
+
+	maxRunning    int    // spawns past this many RUNNING agents are queued
+	transcriptDir string // killed agents' scrollback is saved here, "" for off
 
 	mu      sync.Mutex
 	clients []*wsClient
@@ -395,11 +399,15 @@ func (ws *WebServer) handleKill(msg *wsMessage) {
 	if agent == nil {
 		return
 	}
+	_ = archiveSession(ws.transcriptDir, agent, "killed (remote)")
+	_ = archiveAgents([]*Agent{agent}, "killed (remote)", ws.transcriptDir)
 	_ = ws.manager.Kill(agent.ID)
 	if agent.SessionName != "" {
 		_ = KillBySession(agent.SessionName)
 	}
 	ws.store.Remove(agent.ID)
+	ws.events.Add(EventKill, agent.Name, "(remote)"+worktreeNote(removeWorktrees([]*Agent{agent})))
+	ws.manager.fireLifecycle(LifecycleKill, agent)
 }
 
 // handleSend sends a message (with Enter) to an agent.
@@ -417,6 +425,7 @@ func (ws *WebServer) handleSend(msg *wsMessage) {
 		return
 	}
 	exec.Command("tmux", "send-keys", "-t", sessName, "Enter").Run()
+	ws.store.LogPrompt(agent.ID, "send (remote)", msg.Message)
 }
 
 // handleSendKeys sends raw keystrokes to an agent.
@@ -425,6 +434,10 @@ func (ws *WebServer) handleSendKeys(msg *wsMessage) {
 	if agent == nil {
 		return
 	}
+	// New work brings an agent detached by idle shutdown back to the board
+	if agent.Detached {
+		ws.store.SetDetached(agent.ID, false)
+	}
 	sessName := agent.SessionName
 	if sessName == "" {
 		sessName = SessionName(agent.ID)
@@ -449,14 +462,16 @@ func (ws *WebServer) handleSpawn(msg *wsMessage) {
 	}
 
 	name := deriveNameFromDir(dir)
-	agent := ws.store.Add(name, dir)
-
-	if msg.Backend != "" {
-		if GetBackend(msg.Backend) != nil {
-			agent.BackendID = msg.Backend
-		}
-	}
+	queued := !hasCapacity(ws.store.List(), ws.maxRunning)
+	agent := ws.store.AddWithBackend(name, dir, msg.Backend)
 	agent.AutoApprove = msg.AutoApprove
+	agent.Prompt = msg.Prompt
+	if queued {
+		ws.store.Update(agent.ID, StatusPending)
+		ws.store.AddRecentDir(dir)
+		ws.events.Add(EventSpawn, name, fmt.Sprintf("%s in %s (remote), queued", agent.Backend().Name(), dir))
+		return
+	}
 
 	var extraArgs []string
 	if agent.AutoApprove {
@@ -471,8 +486,11 @@ func (ws *WebServer) handleSpawn(msg *wsMessage) {
 
 	ws.store.UpdateSessionName(agent.ID, agent.SessionName)
 	ws.store.Save()
+	ws.store.AddRecentDir(dir)
+	ws.events.Add(EventSpawn, name, fmt.Sprintf("%s in %s (remote)", agent.Backend().Name(), dir))
 
 	if msg.Prompt != "" {
+		ws.store.MarkPromptSent(agent.ID, time.Now().Add(promptStartupDelay))
 		go SendPromptAfterDelay(agent.SessionName, msg.Prompt)
 	}
 }
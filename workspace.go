@@ -7,16 +7,37 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
 // WorkspaceAgent is a saved agent template (no live state).
 type WorkspaceAgent struct {
-	Name        string `json:"name"`
-	Dir         string `json:"dir"`
-	BackendID   string `json:"backend,omitempty"`
-	AutoApprove bool   `json:"auto_approve,omitempty"`
-	SessionID   string `json:"session_id,omitempty"`
+	Name        string   `json:"name"`
+	Dir         string   `json:"dir"`
+	BackendID   string   `json:"backend,omitempty"`
+	AutoApprove bool     `json:"auto_approve,omitempty"`
+	KeepAlive   bool     `json:"keep_alive,omitempty"`
+	Checkpoint  bool     `json:"checkpoint,omitempty"`
+	Branch      bool     `json:"branch,omitempty"`   // check out a new branch in Dir's repo
+	Worktree    bool     `json:"worktree,omitempty"` // spawn in a new worktree of Dir's repo
+	SessionID   string   `json:"session_id,omitempty"`
+	Prompt      string   `json:"prompt,omitempty"` // initial task; the agent starts a fresh conversation
+	After       string   `json:"after,omitempty"`  // name of the template whose finish releases Prompt
+	Swarm       string   `json:"swarm,omitempty"`  // swarm the agent belongs to
+	Tags        []string `json:"tags,omitempty"`
+	Rules       []string `json:"rules,omitempty"` // approval rules
+}
+
+// branchMode is the git setup the template asks for at spawn.
+func (t WorkspaceAgent) branchMode() branchMode {
+	switch {
+	case t.Worktree:
+		return branchWorktree
+	case t.Branch:
+		return branchNew
+	}
+	return branchNone
 }
 
 // WorkspaceFile represents a saved workspace containing agent templates.
@@ -70,8 +91,7 @@ func lookupClaudeSessionID(dir string) string {
 }
 
 func workspaceDir() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".tickettok", "workspaces")
+	return filepath.Join(stateDir(), "workspaces")
 }
 
 func workspacePath(name string) string {
@@ -80,10 +100,6 @@ func workspacePath(name string) string {
 
 // SaveWorkspace extracts templates from live agents and writes a workspace file.
 func SaveWorkspace(name string, agents []*Agent) error {
-	if err := os.MkdirAll(workspaceDir(), 0755); err != nil {
-		return fmt.Errorf("create workspace dir: %w", err)
-	}
-
 	var templates []WorkspaceAgent
 	for _, a := range agents {
 		wa := WorkspaceAgent{
@@ -91,8 +107,20 @@ func SaveWorkspace(name string, agents []*Agent) error {
 			Dir:         a.Dir,
 			BackendID:   a.BackendID,
 			AutoApprove: a.AutoApprove,
+			KeepAlive:   a.KeepAlive,
+			Checkpoint:  a.Checkpoint,
+			Branch:      a.Branch != "" && a.Worktree == nil,
+			Swarm:       a.Swarm,
+			Tags:        a.Tags,
+			Rules:       a.Rules,
 		}
-		if a.BackendID == "claude" || a.BackendID == "" {
+		if a.Worktree != nil {
+			// The worktree goes with the agent; a load makes a fresh one
+			wa.Dir = worktreeSourceDir(a)
+			wa.Worktree = true
+		} else if a.SessionID != "" {
+			wa.SessionID = a.SessionID
+		} else if a.BackendID == "claude" || a.BackendID == "" {
 			wa.SessionID = lookupClaudeSessionID(a.Dir)
 		}
 		templates = append(templates, wa)
@@ -101,17 +129,22 @@ func SaveWorkspace(name string, agents []*Agent) error {
 		templates = []WorkspaceAgent{}
 	}
 
-	wf := WorkspaceFile{
+	return writeWorkspace(&WorkspaceFile{
 		Name:      name,
 		Agents:    templates,
 		CreatedAt: time.Now(),
-	}
+	})
+}
 
+func writeWorkspace(wf *WorkspaceFile) error {
+	if err := os.MkdirAll(workspaceDir(), 0755); err != nil {
+		return fmt.Errorf("create workspace dir: %w", err)
+	}
 	data, err := json.MarshalIndent(wf, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal workspace: %w", err)
 	}
-	return os.WriteFile(workspacePath(name), data, 0644)
+	return os.WriteFile(workspacePath(wf.Name), data, 0644)
 }
 
 // LoadWorkspace reads and parses a workspace file.
@@ -160,36 +193,32 @@ func WorkspaceExists(name string) bool {
 	return err == nil
 }
 
-// AddAgentToWorkspace appends an agent template to an existing workspace.
+// AddAgentToWorkspace appends an agent template to an existing workspace,
+// keeping the other templates as written.
 func AddAgentToWorkspace(name string, agent WorkspaceAgent) error {
 	wf, err := LoadWorkspace(name)
 	if err != nil {
 		return err
 	}
+	wf.Name = name
 	wf.Agents = append(wf.Agents, agent)
-	return SaveWorkspace(name, agentsFromTemplates(wf))
+	return writeWorkspace(wf)
 }
 
-// agentsFromTemplates converts workspace templates back to Agent pointers
-// so SaveWorkspace can re-serialize them.
-func agentsFromTemplates(wf *WorkspaceFile) []*Agent {
-	agents := make([]*Agent, len(wf.Agents))
-	for i, t := range wf.Agents {
-		agents[i] = &Agent{
-			Name:        t.Name,
-			Dir:         t.Dir,
-			BackendID:   t.BackendID,
-			AutoApprove: t.AutoApprove,
-		}
+// spawnWorkspaceAgents spawns agents from workspace templates, returning the
+// count of successfully started agents and those whose prompt should be
+// sent now. Prompts of templates with an After are held in the store until
+// the TUI sees that agent finish; agents past maxRunning are queued as
+// PENDING for the TUI to start.
+func spawnWorkspaceAgents(wf *WorkspaceFile, store *Store, manager *AgentManager, maxRunning int) (int, []*Agent) {
+	deps, errs := resolveAfter(wf.Agents)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Workspace %q: %v\n", wf.Name, err)
 	}
-	return agents
-}
 
-// spawnWorkspaceAgents spawns agents from workspace templates, returning the
-// count of successfully started agents.
-func spawnWorkspaceAgents(wf *WorkspaceFile, store *Store, manager *AgentManager) int {
 	count := 0
-	for _, t := range wf.Agents {
+	spawned := make(map[int]*Agent)
+	for i, t := range wf.Agents {
 		dir := t.Dir
 		if strings.HasPrefix(dir, "~/") {
 			home, _ := os.UserHomeDir()
@@ -201,24 +230,37 @@ func spawnWorkspaceAgents(wf *WorkspaceFile, store *Store, manager *AgentManager
 			name = deriveNameFromDir(dir)
 		}
 
-		agent := store.Add(name, dir)
-
-		if t.BackendID != "" {
-			agent.BackendID = t.BackendID
+		queued := !hasCapacity(store.List(), maxRunning)
+		agent := store.AddWithBackend(name, dir, t.BackendID)
+		if err := prepareGit(store, agent, t.branchMode()); err != nil {
+			store.Remove(agent.ID)
+			fmt.Fprintf(os.Stderr, "Failed to spawn %q: %v\n", name, err)
+			continue
 		}
 		agent.AutoApprove = t.AutoApprove
+		agent.KeepAlive = t.KeepAlive
+		agent.Checkpoint = t.Checkpoint
+		agent.SessionID = t.SessionID
+		agent.Prompt = t.Prompt
+		agent.Swarm = t.Swarm
+		agent.Tags = normalizeTags(t.Tags)
+		agent.Rules = t.Rules
 
-		// Use exact session ID when available, otherwise fall back to --continue.
+		// Exact session when saved, otherwise the backend's latest; a new
+		// task gets a new conversation
 		var extraArgs []string
-		if t.SessionID != "" {
-			extraArgs = []string{"--resume", t.SessionID}
-		} else {
-			extraArgs = agent.Backend().ResumeArgs()
+		if agent.Prompt == "" && !t.Worktree {
+			extraArgs = resumeArgs(agent)
 		}
 		if agent.AutoApprove {
 			extraArgs = append(extraArgs, agent.Backend().AutoApproveArgs()...)
 		}
 
+		if queued {
+			store.Update(agent.ID, StatusPending)
+			spawned[i] = agent
+			continue
+		}
 		if err := manager.SpawnAgent(agent, extraArgs); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to spawn %q: %v\n", name, err)
 			continue
@@ -226,7 +268,44 @@ func spawnWorkspaceAgents(wf *WorkspaceFile, store *Store, manager *AgentManager
 
 		store.UpdateSessionName(agent.ID, agent.SessionName)
 		store.Save()
+		spawned[i] = agent
 		count++
 	}
-	return count
+
+	var send []*Agent
+	for i := range wf.Agents {
+		agent := spawned[i]
+		if agent == nil || agent.Prompt == "" {
+			continue
+		}
+		if j, ok := deps[i]; ok {
+			if spawned[j] == nil {
+				fmt.Fprintf(os.Stderr, "Not sending %q its task: %q didn't start\n", agent.Name, wf.Agents[j].Name)
+				continue
+			}
+			store.SetAfter(agent.ID, spawned[j].ID)
+			continue
+		}
+		if agent.Status == StatusPending {
+			// Sent when the TUI starts it
+			continue
+		}
+		store.MarkPromptSent(agent.ID, time.Now().Add(promptStartupDelay))
+		send = append(send, agent)
+	}
+	return count, send
+}
+
+// sendInitialPrompts types each agent's prompt once its CLI has started,
+// returning when all are sent.
+func sendInitialPrompts(agents []*Agent) {
+	var wg sync.WaitGroup
+	for _, a := range agents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			SendPromptAfterDelay(a.SessionName, a.Prompt)
+		}()
+	}
+	wg.Wait()
 }
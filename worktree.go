@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Worktree is a git worktree tickettok created for one agent, so agents
+// working on the same repo don't edit each other's checkout.
+type Worktree struct {
+	Repo string `json:"repo"` // top level of the checkout it was added from
+	Path string `json:"path"` // top level of the worktree itself
+}
+
+// worktreesDir holds every worktree tickettok creates.
+func worktreesDir() string {
+	return filepath.Join(stateDir(), "worktrees")
+}
+
+// git runs a git command in dir and returns its trimmed output. Errors
+// carry git's own message.
+func git(dir string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	msg := strings.TrimSpace(string(out))
+	if err != nil {
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return msg, nil
+}
+
+// addWorktree checks out a new branch from the HEAD of repo into path. It
+// returns the directory inside the worktree matching dir, so an agent
+// spawned in a subdirectory of the repo stays there.
+func addWorktree(repo, dir, path, branch string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if _, err := git(repo, "worktree", "add", "-b", branch, path); err != nil {
+		return "", err
+	}
+
+	// rev-parse resolves symlinks, so compare against the resolved dir
+	sub := path
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		if rel, err := filepath.Rel(repo, real); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			sub = filepath.Join(path, rel)
+		}
+	}
+	return sub, nil
+}
+
+// removeWorktree deletes a worktree's checkout, keeping its branch so any
+// commits the agent made can still be merged. A worktree with uncommitted
+// or untracked files is left alone and reported as an error.
+func removeWorktree(wt *Worktree) error {
+	if _, err := os.Stat(wt.Path); os.IsNotExist(err) {
+		_, err := git(wt.Repo, "worktree", "prune")
+		return err
+	}
+	if status, err := git(wt.Path, "status", "--porcelain"); err != nil {
+		return err
+	} else if status != "" {
+		return fmt.Errorf("%s has uncommitted changes", wt.Path)
+	}
+	_, err := git(wt.Repo, "worktree", "remove", wt.Path)
+	return err
+}
+
+// restoreWorktree checks branch out again at a removed worktree's path,
+// for agents brought back by undo or resumed after a kill.
+func restoreWorktree(wt *Worktree, branch string) error {
+	if _, err := os.Stat(wt.Path); err == nil {
+		return nil
+	}
+	if _, err := git(wt.Repo, "worktree", "prune"); err != nil {
+		return err
+	}
+	_, err := git(wt.Repo, "worktree", "add", wt.Path, branch)
+	return err
+}
+
+// isolateAgent moves a just-added agent into a worktree of its own under
+// worktreesDir, on a branch named after it.
+func isolateAgent(store *Store, agent *Agent) error {
+	repo, err := repoToplevel(agent.Dir)
+	if err != nil {
+		return err
+	}
+	branch := freeBranch(repo, agentBranch(agent))
+	path := filepath.Join(worktreesDir(), filepath.Base(repo)+"-"+strings.TrimPrefix(branch, "tickettok/"))
+	dir, err := addWorktree(repo, agent.Dir, path, branch)
+	if err != nil {
+		return err
+	}
+	store.SetBranch(agent.ID, AgentBranch{Repo: repo, Name: branch})
+	store.SetWorktree(agent.ID, &Worktree{Repo: repo, Path: path}, dir)
+	return nil
+}
+
+// worktreeSourceDir maps an isolated agent's directory back into the
+// checkout its worktree was added from.
+func worktreeSourceDir(a *Agent) string {
+	rel, err := filepath.Rel(a.Worktree.Path, a.Dir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return a.Worktree.Repo
+	}
+	return filepath.Join(a.Worktree.Repo, rel)
+}
+
+// removeWorktrees removes the worktrees of agents leaving the board and
+// returns why any were kept.
+func removeWorktrees(agents []*Agent) []error {
+	var errs []error
+	for _, a := range agents {
+		if a.Worktree == nil {
+			continue
+		}
+		if err := removeWorktree(a.Worktree); err != nil {
+			errs = append(errs, fmt.Errorf("kept worktree of %s: %w", a.Name, err))
+		}
+	}
+	return errs
+}
+
+// worktreeNote summarizes removeWorktrees errors for a status message.
+func worktreeNote(errs []error) string {
+	switch len(errs) {
+	case 0:
+		return ""
+	case 1:
+		return "; " + errs[0].Error()
+	}
+	return fmt.Sprintf("; kept %d worktrees with changes", len(errs))
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorktreeLifecycle(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo, _ := filepath.EvalSymlinks(t.TempDir())
+	run := func(dir string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run(repo, "init", "-q")
+	if err := os.MkdirAll(filepath.Join(repo, "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "web", "a.txt"), []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(repo, "add", ".")
+	run(repo, "commit", "-qm", "init")
+
+	path := filepath.Join(t.TempDir(), "wt")
+	dir, err := addWorktree(repo, filepath.Join(repo, "web"), path, "tickettok/agent-1-web")
+	if err != nil {
+		t.Fatalf("addWorktree: %v", err)
+	}
+	if dir != filepath.Join(path, "web") {
+		t.Fatalf("addWorktree dir = %q", dir)
+	}
+	wt := &Worktree{Repo: repo, Path: path}
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); err != nil {
+		t.Fatalf("worktree not checked out: %v", err)
+	}
+	a := &Agent{Dir: dir, Worktree: wt}
+	if got := worktreeSourceDir(a); got != filepath.Join(repo, "web") {
+		t.Errorf("worktreeSourceDir = %q", got)
+	}
+
+	// Uncommitted work keeps the worktree around
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := removeWorktree(wt); err == nil {
+		t.Fatal("removeWorktree removed a dirty worktree")
+	}
+	run(path, "add", ".")
+	run(path, "commit", "-qm", "wip")
+
+	if err := removeWorktree(wt); err != nil {
+		t.Fatalf("removeWorktree: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("worktree still on disk: %v", err)
+	}
+	if err := restoreWorktree(wt, "tickettok/agent-1-web"); err != nil {
+		t.Fatalf("restoreWorktree: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.txt")); err != nil {
+		t.Errorf("restored worktree lost the branch's commit: %v", err)
+	}
+}
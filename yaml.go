@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAML reads a tmux session manager's project file. Mappings come
+// back as map[string]any and sequences as []any; scalars stay the strings
+// they were written as — a window named 1 or a pane running true is a
+// name or a command, not a number or a boolean — except null and ~,
+// which are nil. Aliases and << merge keys are resolved.
+func parseYAML(src string) (any, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(src), &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return yamlValue(doc.Content[0])
+}
+
+// yamlValue converts a node of a parsed document.
+func yamlValue(n *yaml.Node) (any, error) {
+	switch n.Kind {
+	case yaml.AliasNode:
+		return yamlValue(n.Alias)
+	case yaml.ScalarNode:
+		if n.ShortTag() == "!!null" {
+			return nil, nil
+		}
+		return n.Value, nil
+	case yaml.SequenceNode:
+		out := make([]any, 0, len(n.Content))
+		for _, c := range n.Content {
+			v, err := yamlValue(c)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case yaml.MappingNode:
+		out := map[string]any{}
+		if err := yamlMapping(n, out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("line %d: unexpected YAML node", n.Line)
+}
+
+// yamlMapping adds a mapping node's pairs to out. Keys merged in with <<
+// don't replace the mapping's own, whichever comes first.
+func yamlMapping(n *yaml.Node, out map[string]any) error {
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Kind == yaml.ScalarNode && k.ShortTag() == "!!merge" {
+			merges = append(merges, v)
+			continue
+		}
+		if k.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: mapping keys must be scalars", k.Line)
+		}
+		val, err := yamlValue(v)
+		if err != nil {
+			return err
+		}
+		out[k.Value] = val
+	}
+	for _, m := range merges {
+		if m.Kind == yaml.AliasNode {
+			m = m.Alias
+		}
+		sources := []*yaml.Node{m}
+		if m.Kind == yaml.SequenceNode {
+			sources = m.Content
+		}
+		for _, s := range sources {
+			if s.Kind == yaml.AliasNode {
+				s = s.Alias
+			}
+			if s.Kind != yaml.MappingNode {
+				return fmt.Errorf("line %d: << merges a mapping", s.Line)
+			}
+			merged := map[string]any{}
+			if err := yamlMapping(s, merged); err != nil {
+				return err
+			}
+			for k, v := range merged {
+				if _, ok := out[k]; !ok {
+					out[k] = v
+				}
+			}
+		}
+	}
+	return nil
+}
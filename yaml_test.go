@@ -0,0 +1,67 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	src := `---
+# project
+name: shop   # trailing comment
+root: "~/code/shop"
+empty:
+windows:
+  - editor:
+      layout: main-vertical
+      panes:
+        - claude
+        - 'it''s # not a comment'
+  - server: bundle exec rails s
+tags: [a, "b c", {k: v}]
+list:
+- one
+- - nested
+script: |
+  echo one
+  echo two
+defaults: &defaults
+  layout: tiled
+  panes: [claude]
+server:
+  <<: *defaults
+  layout: even-horizontal
+port: 3000
+`
+	got, err := parseYAML(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"name":  "shop",
+		"root":  "~/code/shop",
+		"empty": nil,
+		"windows": []any{
+			map[string]any{"editor": map[string]any{
+				"layout": "main-vertical",
+				"panes":  []any{"claude", "it's # not a comment"},
+			}},
+			map[string]any{"server": "bundle exec rails s"},
+		},
+		"tags":     []any{"a", "b c", map[string]any{"k": "v"}},
+		"list":     []any{"one", []any{"nested"}},
+		"script":   "echo one\necho two\n",
+		"defaults": map[string]any{"layout": "tiled", "panes": []any{"claude"}},
+		"server":   map[string]any{"layout": "even-horizontal", "panes": []any{"claude"}},
+		"port":     "3000",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseYAML =\n%#v\nwant\n%#v", got, want)
+	}
+
+	for _, bad := range []string{"a: b\n   c: d", "a: [b", "a:\n\t- b", "a: b\nnot a pair", "a: *missing"} {
+		if _, err := parseYAML(bad); err == nil {
+			t.Errorf("parseYAML(%q) should fail", bad)
+		}
+	}
+}
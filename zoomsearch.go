@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// findMatches returns the indices of lines in content that contain query,
+// ignoring case and ANSI escapes.
+func findMatches(content, query string) []int {
+	if query == "" {
+		return nil
+	}
+	q := strings.ToLower(query)
+	var matches []int
+	for i, line := range strings.Split(content, "\n") {
+		if strings.Contains(strings.ToLower(stripAnsiStr(line)), q) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// highlightMatches renders line with every occurrence of query wrapped in
+// style. The line's own colors are dropped so the highlight stays readable.
+func highlightMatches(line, query string, style lipgloss.Style) string {
+	plain := stripAnsiStr(line)
+	if query == "" {
+		return line
+	}
+	haystack, needle := strings.ToLower(plain), strings.ToLower(query)
+	if len(haystack) != len(plain) || len(needle) != len(query) {
+		// Lowercasing changed byte lengths; offsets would not line up
+		haystack, needle = plain, query
+	}
+	if !strings.Contains(haystack, needle) {
+		return line
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(haystack, needle)
+		if i < 0 {
+			break
+		}
+		b.WriteString(plain[:i])
+		b.WriteString(style.Render(plain[i : i+len(needle)]))
+		plain, haystack = plain[i+len(needle):], haystack[i+len(needle):]
+	}
+	b.WriteString(plain)
+	return b.String()
+}
+
+// matchScrollOffset returns the zoom scroll offset that centers line in a
+// window of maxLines, clamped to the scrollable range.
+func matchScrollOffset(totalLines, line, maxLines, maxScroll int) int {
+	off := totalLines - line - maxLines/2 - 1
+	if off > maxScroll {
+		off = maxScroll
+	}
+	if off < 0 {
+		off = 0
+	}
+	return off
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFindMatches(t *testing.T) {
+	content := "build ok\n\x1b[31mERROR\x1b[0m: disk full\nretrying\nerror again"
+
+	got := findMatches(content, "error")
+	if want := []int{1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("findMatches = %v, want %v", got, want)
+	}
+	if got := findMatches(content, ""); got != nil {
+		t.Errorf("empty query should match nothing, got %v", got)
+	}
+	if got := findMatches(content, "31m"); got != nil {
+		t.Errorf("ANSI codes should not match, got %v", got)
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	style := lipgloss.NewStyle().Bold(true)
+
+	got := highlightMatches("Error then error", "error", style)
+	if stripAnsiStr(got) != "Error then error" {
+		t.Errorf("highlight changed the text: %q", stripAnsiStr(got))
+	}
+	if n := strings.Count(got, style.Render("Error")) + strings.Count(got, style.Render("error")); n != 2 {
+		t.Errorf("expected both occurrences highlighted, got %q", got)
+	}
+
+	if got := highlightMatches("\x1b[32mok\x1b[0m", "missing", style); got != "\x1b[32mok\x1b[0m" {
+		t.Errorf("non-matching line should be unchanged, got %q", got)
+	}
+}
+
+func TestMatchScrollOffset(t *testing.T) {
+	tests := []struct {
+		name                             string
+		total, line, maxLines, maxScroll int
+		want                             int
+	}{
+		{"centered", 100, 50, 20, 80, 39},
+		{"near bottom clamps to 0", 100, 98, 20, 80, 0},
+		{"near top clamps to max", 100, 2, 20, 80, 80},
+	}
+	for _, tt := range tests {
+		if got := matchScrollOffset(tt.total, tt.line, tt.maxLines, tt.maxScroll); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}